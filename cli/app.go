@@ -22,31 +22,62 @@ const (
 	// TODO: RSDK-6683.
 	quietFlag = "quiet"
 
+	outputFlag = "output"
+	formatFlag = "format"
+
+	profileFlag = "profile"
+
+	timeoutFlag = "timeout"
+
+	listRobotsFlagAll = "all"
+
+	listOrganizationsFlagExtended = "extended"
+
+	organizationFlagName = "name"
+
 	logsFlagErrors = "errors"
 	logsFlagTail   = "tail"
+	logsFlagLevel  = "level"
+	logsFlagSince  = "since"
+	logsFlagGrep   = "grep"
 
-	runFlagData   = "data"
-	runFlagStream = "stream"
+	runFlagData       = "data"
+	runFlagStream     = "stream"
+	runFlagOutputFile = "output-file"
+	runFlagTruncate   = "truncate"
+
+	restartFlagWait = "wait"
 
 	loginFlagDisableBrowser = "disable-browser-open"
 	loginFlagKeyID          = "key-id"
 	loginFlagKey            = "key"
 
+	// envAPIKeyID and envAPIKey hold an api key to authenticate with instead of the cached credentials, so
+	// that the key does not appear in shell history or process listings.
+	envAPIKeyID = "VIAM_API_KEY_ID"
+	envAPIKey   = "VIAM_API_KEY"
+
 	// Flags shared by api-key, module and data subcommands.
 	generalFlagOrgID        = "org-id"
 	generalFlagLocationID   = "location-id"
 	generalFlagMachineID    = "machine-id"
 	generalFlagAliasRobotID = "robot-id"
+	generalFlagYes          = "yes"
 
 	apiKeyCreateFlagName = "name"
 
+	locationFlagName = "name"
+
 	moduleFlagName            = "name"
 	moduleFlagPublicNamespace = "public-namespace"
 	moduleFlagPath            = "module"
 	moduleFlagVersion         = "version"
 	moduleFlagPlatform        = "platform"
 	moduleFlagForce           = "force"
+	moduleFlagLocal           = "local"
 	moduleFlagBinary          = "binary"
+	moduleFlagID              = "id"
+	moduleFlagDestination     = "destination"
 
 	moduleBuildFlagPath     = "module"
 	moduleBuildFlagRef      = "ref"
@@ -55,6 +86,8 @@ const (
 	moduleBuildFlagBuildID  = "id"
 	moduleBuildFlagPlatform = "platform"
 	moduleBuildFlagWait     = "wait"
+	moduleBuildFlagFollow   = "follow"
+	moduleBuildFlagCopyTo   = "copy-to"
 
 	dataFlagDestination                    = "destination"
 	dataFlagDataType                       = "data-type"
@@ -71,10 +104,23 @@ const (
 	dataFlagStart                          = "start"
 	dataFlagEnd                            = "end"
 	dataFlagParallelDownloads              = "parallel"
+	dataFlagResume                         = "resume"
+	dataFlagLayout                         = "layout"
+	dataFlagConvert                        = "convert"
+	dataFlagIncludeMetadata                = "include-metadata"
+	dataFlagDryRun                         = "dry-run"
 	dataFlagTags                           = "tags"
 	dataFlagBboxLabels                     = "bbox-labels"
 	dataFlagDeleteTabularDataOlderThanDays = "delete-older-than-days"
 	dataFlagDatabasePassword               = "password"
+	dataFlagRetries                        = "retries"
+
+	versionFlagCheck = "check"
+
+	configCacheFlagPartID = "part-id"
+	configCacheFlagAll    = "all"
+
+	partCopyFlagRecursive = "recursive"
 )
 
 // createUsageText is a helper for formatting UsageTexts. The created UsageText
@@ -105,7 +151,8 @@ var app = &cli.App{
 		&cli.StringFlag{
 			Name:    configFlag,
 			Aliases: []string{"c"},
-			Usage:   "load configuration from `FILE`",
+			Usage: "load configuration from `FILE`. Its credentials section, with api_key_id, api_key, base_url, org_id " +
+				"and location_id, lets every command run without logging in",
 		},
 		&cli.BoolFlag{
 			Name:    debugFlag,
@@ -118,8 +165,32 @@ var app = &cli.App{
 			Aliases: []string{"q"},
 			Usage:   "suppress warnings",
 		},
+		&cli.StringFlag{
+			Name:        outputFlag,
+			Aliases:     []string{"o"},
+			DefaultText: outputFormatText,
+			Usage:       "output format of list commands: text or json",
+		},
+		&cli.StringFlag{
+			Name:  profileFlag,
+			Usage: "use the cached credentials of profile `NAME` instead of the default ones",
+		},
+		&cli.DurationFlag{
+			Name: timeoutFlag,
+			Usage: "abort the command if it takes longer than `DURATION`, ex: 30s. 0 means no timeout. " +
+				"Long-running commands (data and dataset export, machine logs, part shell, cp and run, " +
+				"and module build logs) apply it to each request instead",
+		},
 	},
-	Commands: []*cli.Command{
+	Before: func(c *cli.Context) error {
+		if err := validateProfile(c.String(profileFlag)); err != nil {
+			return err
+		}
+		return loadCLIConfigFile(c)
+	},
+	// Command actions are aborted once the global --timeout elapses, except for the long-running commands
+	// marked with perRequestTimeout.
+	Commands: wrapActionsWithTimeout([]*cli.Command{
 		{
 			Name: "login",
 			// NOTE(benjirewis): maintain `auth` as an alias for backward compatibility.
@@ -135,6 +206,11 @@ var app = &cli.App{
 			Action: LoginAction,
 			After:  CheckUpdateAction,
 			Subcommands: []*cli.Command{
+				{
+					Name:   "status",
+					Usage:  "print the current credentials and when they expire",
+					Action: LoginStatusAction,
+				},
 				{
 					Name:   "print-access-token",
 					Usage:  "print the access token associated with current credentials",
@@ -144,15 +220,23 @@ var app = &cli.App{
 					Name:      "api-key",
 					Usage:     "authenticate with an api key",
 					UsageText: createUsageText("login api-key", []string{loginFlagKeyID, loginFlagKey}, false),
+					Description: `The key can also be passed with the ` + envAPIKeyID + ` and ` + envAPIKey + ` environment variables,
+which keep it out of shell history and process listings. The flags take precedence over the environment variables.
+
+To authenticate a single command with an api key without logging in, such as in CI, set both environment variables
+when running it. They take precedence over the cached credentials, and are not written to the credential cache;
+only 'login api-key' caches a key.`,
 					Flags: []cli.Flag{
 						&cli.StringFlag{
 							Name:     loginFlagKeyID,
 							Required: true,
+							EnvVars:  []string{envAPIKeyID},
 							Usage:    "id of the key to authenticate with",
 						},
 						&cli.StringFlag{
 							Name:     loginFlagKey,
 							Required: true,
+							EnvVars:  []string{envAPIKey},
 							Usage:    "key to authenticate with",
 						},
 					},
@@ -177,10 +261,63 @@ var app = &cli.App{
 			HideHelpCommand: true,
 			Subcommands: []*cli.Command{
 				{
-					Name:   "list",
-					Usage:  "list organizations for the current user",
+					Name:  "list",
+					Usage: "list organizations for the current user",
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:        outputFlag,
+							Aliases:     []string{"o"},
+							DefaultText: outputFormatText,
+							Usage:       "output format: text or json",
+						},
+						&cli.BoolFlag{
+							Name:  listOrganizationsFlagExtended,
+							Usage: "also show each organization's machine count, billing tier and usage this month, where available",
+						},
+					},
 					Action: ListOrganizationsAction,
 				},
+				{
+					Name:      "rename",
+					Usage:     "rename an organization",
+					UsageText: createUsageText("organizations rename", []string{generalFlagOrgID, organizationFlagName}, false),
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:  generalFlagOrgID,
+							Usage: "the organization to rename",
+						},
+						&cli.StringFlag{
+							Name:     organizationFlagName,
+							Required: true,
+							Usage:    "the new name of the organization",
+						},
+					},
+					Action: OrganizationRenameAction,
+				},
+				{
+					Name:  "members",
+					Usage: "work with an organization's members",
+					Subcommands: []*cli.Command{
+						{
+							Name:      "list",
+							Usage:     "list the members of an organization and their roles",
+							UsageText: createUsageText("organizations members list", []string{generalFlagOrgID}, true),
+							Flags: []cli.Flag{
+								&cli.StringFlag{
+									Name:  generalFlagOrgID,
+									Usage: "the organization to list the members of",
+								},
+								&cli.StringFlag{
+									Name:        outputFlag,
+									Aliases:     []string{"o"},
+									DefaultText: outputFormatText,
+									Usage:       "output format: text or json",
+								},
+							},
+							Action: OrganizationMembersListAction,
+						},
+					},
+				},
 				{
 					Name:      "api-key",
 					Usage:     "work with an organization's api keys",
@@ -191,9 +328,8 @@ var app = &cli.App{
 							Usage: "create an api key for your organization",
 							Flags: []cli.Flag{
 								&cli.StringFlag{
-									Name:     generalFlagOrgID,
-									Required: true,
-									Usage:    "the org to create an api key for",
+									Name:  generalFlagOrgID,
+									Usage: "the org to create an api key for",
 								},
 								&cli.StringFlag{
 									Name:  apiKeyCreateFlagName,
@@ -216,7 +352,49 @@ var app = &cli.App{
 					Name:      "list",
 					Usage:     "list locations for the current user",
 					ArgsUsage: "[organization]",
-					Action:    ListLocationsAction,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:        outputFlag,
+							Aliases:     []string{"o"},
+							DefaultText: outputFormatText,
+							Usage:       "output format: text or json",
+						},
+					},
+					Action: ListLocationsAction,
+				},
+				{
+					Name:      "create",
+					Usage:     "create a location in an organization",
+					UsageText: createUsageText("locations create", []string{generalFlagOrgID, locationFlagName}, false),
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:  generalFlagOrgID,
+							Usage: "the organization to create the location in",
+						},
+						&cli.StringFlag{
+							Name:     locationFlagName,
+							Required: true,
+							Usage:    "the name of the location",
+						},
+					},
+					Action: CreateLocationAction,
+				},
+				{
+					Name:      "delete",
+					Usage:     "delete a location",
+					UsageText: createUsageText("locations delete", []string{generalFlagLocationID}, false),
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:  generalFlagLocationID,
+							Usage: "the location to delete",
+						},
+						&cli.BoolFlag{
+							Name:    generalFlagYes,
+							Aliases: []string{"y"},
+							Usage:   "skip the confirmation prompt",
+						},
+					},
+					Action: DeleteLocationAction,
 				},
 				{
 					Name:  "api-key",
@@ -228,9 +406,8 @@ var app = &cli.App{
 							UsageText: createUsageText("api-key create", []string{generalFlagOrgID}, true),
 							Flags: []cli.Flag{
 								&cli.StringFlag{
-									Name:     generalFlagLocationID,
-									Required: true,
-									Usage:    "the location to create an api-key for",
+									Name:  generalFlagLocationID,
+									Usage: "the location to create an api-key for",
 								},
 								&cli.StringFlag{
 									Name:  apiKeyCreateFlagName,
@@ -253,11 +430,18 @@ var app = &cli.App{
 			Usage:           "work with data",
 			HideHelpCommand: true,
 			Subcommands: []*cli.Command{
-				{
+				perRequestTimeout(&cli.Command{
 					Name:      "export",
 					Usage:     "download data from Viam cloud",
 					UsageText: createUsageText("data export", []string{dataFlagDestination, dataFlagDataType}, true),
-					Flags: []cli.Flag{
+					Description: `--component-type and --component-name accept glob patterns (e.g. camera*), which are matched
+client-side and ANDed with all other filters.
+
+--convert converts binary data as it is downloaded:
+  raw depth maps (image/vnd.viam.dep)  -> 16-bit grayscale PNG, depth in millimeters
+  raw RGBA images (image/vnd.viam.rgba) -> PNG
+Point clouds are already captured as PCD. All other data is downloaded unchanged.`,
+					Flags: append([]cli.Flag{
 						&cli.PathFlag{
 							Name:     dataFlagDestination,
 							Required: true,
@@ -268,78 +452,37 @@ var app = &cli.App{
 							Required: true,
 							Usage:    "data type to be downloaded: either binary or tabular",
 						},
-						&cli.StringSliceFlag{
-							Name:  dataFlagOrgIDs,
-							Usage: "orgs filter",
-						},
-						&cli.StringSliceFlag{
-							Name:  dataFlagLocationIDs,
-							Usage: "locations filter",
-						},
-						&AliasStringFlag{
-							cli.StringFlag{
-								Name:    generalFlagMachineID,
-								Aliases: []string{generalFlagAliasRobotID},
-								Usage:   "machine id filter",
-							},
-						},
-						&cli.StringFlag{
-							Name:  dataFlagPartID,
-							Usage: "part id filter",
-						},
-						&AliasStringFlag{
-							cli.StringFlag{
-								Name:    dataFlagMachineName,
-								Aliases: []string{dataFlagAliasRobotName},
-								Usage:   "machine name filter",
-							},
-						},
-						&cli.StringFlag{
-							Name:  dataFlagPartName,
-							Usage: "part name filter",
-						},
-						&cli.StringFlag{
-							Name:  dataFlagComponentType,
-							Usage: "component type filter",
-						},
-						&cli.StringFlag{
-							Name:  dataFlagComponentName,
-							Usage: "component name filter",
-						},
-						&cli.StringFlag{
-							Name:  dataFlagMethod,
-							Usage: "method filter",
-						},
-						&cli.StringSliceFlag{
-							Name:  dataFlagMimeTypes,
-							Usage: "mime types filter",
-						},
 						&cli.UintFlag{
 							Name:  dataFlagParallelDownloads,
-							Usage: "number of download requests to make in parallel",
+							Usage: fmt.Sprintf("number of download requests to make in parallel, at most %d", maxParallelDownloads),
 							Value: 100,
 						},
-						&cli.StringFlag{
-							Name:  dataFlagStart,
-							Usage: "ISO-8601 timestamp indicating the start of the interval filter",
+						&cli.BoolFlag{
+							Name:  dataFlagResume,
+							Usage: "skip binary files already downloaded to the destination by a previous export with --resume",
 						},
 						&cli.StringFlag{
-							Name:  dataFlagEnd,
-							Usage: "ISO-8601 timestamp indicating the end of the interval filter",
+							Name: dataFlagLayout,
+							Usage: "how to organize downloaded binary files into subdirectories: flat, by-component (component type and name), " +
+								"by-date (UTC date requested) or by-part (machine and part name)",
+							Value: string(exportLayoutFlat),
 						},
-						&cli.StringSliceFlag{
-							Name: dataFlagTags,
-							Usage: "tags filter. " +
-								"accepts tagged for all tagged data, untagged for all untagged data, or a list of tags for all data matching any of the tags",
+						&cli.BoolFlag{
+							Name:  dataFlagConvert,
+							Usage: "convert binary data in Viam-specific encodings to common formats as it is downloaded",
 						},
-						&cli.StringSliceFlag{
-							Name: dataFlagBboxLabels,
-							Usage: "bbox labels filter. " +
-								"accepts string labels corresponding to bounding boxes within images",
+						&cli.BoolFlag{
+							Name: dataFlagIncludeMetadata,
+							Usage: "also write the capture metadata of each binary file, such as its component, method, timestamps, " +
+								"tags and bounding boxes, to a <file>.json sidecar next to it",
 						},
-					},
+						&cli.BoolFlag{
+							Name:  dataFlagDryRun,
+							Usage: "print a summary of the data matching the filters without downloading it",
+						},
+					}, sharedDataFilterFlags()...),
 					Action: DataExportAction,
-				},
+				}),
 				{
 					Name:            "delete",
 					Usage:           "delete data from Viam cloud",
@@ -349,81 +492,168 @@ var app = &cli.App{
 							Name:      "binary",
 							Usage:     "delete binary data from Viam cloud",
 							UsageText: createUsageText("data delete binary", nil, true),
-							Flags: []cli.Flag{
-								&cli.StringSliceFlag{
-									Name:  dataFlagOrgIDs,
-									Usage: "orgs filter",
-								},
-								&cli.StringSliceFlag{
-									Name:  dataFlagLocationIDs,
-									Usage: "locations filter",
+							Flags: append([]cli.Flag{
+								&cli.IntFlag{
+									Name:  dataFlagRetries,
+									Usage: "number of times to retry the delete if it fails with a transient network error",
+									Value: defaultDataRetries,
 								},
-								&AliasStringFlag{
-									cli.StringFlag{
-										Name:    generalFlagMachineID,
-										Aliases: []string{generalFlagAliasRobotID},
-										Usage:   "machine id filter",
-									},
+								&cli.BoolFlag{
+									Name:    generalFlagYes,
+									Aliases: []string{"y"},
+									Usage:   "skip the confirmation prompt",
 								},
+							}, sharedDataFilterFlags()...),
+							Action: DataDeleteBinaryAction,
+						},
+						{
+							Name:      "tabular",
+							Usage:     "delete tabular data from Viam cloud",
+							UsageText: createUsageText("data delete tabular", []string{generalFlagOrgID, dataFlagDeleteTabularDataOlderThanDays}, false),
+							Flags: []cli.Flag{
 								&cli.StringFlag{
-									Name:  dataFlagPartID,
-									Usage: "part id filter",
+									Name:  generalFlagOrgID,
+									Usage: "org",
 								},
-								&AliasStringFlag{
-									cli.StringFlag{
-										Name:    dataFlagMachineName,
-										Aliases: []string{dataFlagAliasRobotName},
-										Usage:   "machine name filter",
-									},
+								&cli.IntFlag{
+									Name:     dataFlagDeleteTabularDataOlderThanDays,
+									Usage:    "delete any tabular data that is older than X calendar days before now. 0 deletes all data.",
+									Required: true,
+								},
+								&cli.IntFlag{
+									Name:  dataFlagRetries,
+									Usage: "number of times to retry the delete if it fails with a transient network error",
+									Value: defaultDataRetries,
 								},
+								&cli.BoolFlag{
+									Name:    generalFlagYes,
+									Aliases: []string{"y"},
+									Usage:   "skip the confirmation prompt",
+								},
+							},
+							Action: DataDeleteTabularAction,
+						},
+					},
+				},
+				{
+					Name:      "tag",
+					Usage:     "add tags to the binary data matching a filter",
+					UsageText: createUsageText("data tag", []string{dataFlagTags}, true),
+					Flags: append([]cli.Flag{
+						&cli.StringSliceFlag{
+							Name:     dataFlagTags,
+							Required: true,
+							Usage:    "tags to add to the matching data",
+						},
+						&cli.BoolFlag{
+							Name:  dataFlagDryRun,
+							Usage: "print how many files match the filters without tagging them",
+						},
+					}, dataFilterFlagsWithout(dataFlagTags)...),
+					Action: DataTagAction,
+				},
+				{
+					Name:      "untag",
+					Usage:     "remove tags from the binary data matching a filter",
+					UsageText: createUsageText("data untag", []string{dataFlagTags}, true),
+					Flags: append([]cli.Flag{
+						&cli.StringSliceFlag{
+							Name:     dataFlagTags,
+							Required: true,
+							Usage:    "tags to remove from the matching data",
+						},
+						&cli.BoolFlag{
+							Name:  dataFlagDryRun,
+							Usage: "print how many files have the tags and match the filters without untagging them",
+						},
+					}, dataFilterFlagsWithout(dataFlagTags)...),
+					Action: DataUntagAction,
+				},
+				{
+					Name:            "bbox",
+					Usage:           "add or remove bounding boxes on images",
+					HideHelpCommand: true,
+					Subcommands: []*cli.Command{
+						{
+							Name:  "add",
+							Usage: "adds a labeled bounding box to an image",
+							UsageText: createUsageText("data bbox add", []string{
+								generalFlagOrgID, dataFlagLocationID, dataFlagFileID, dataFlagBboxLabel,
+								dataFlagXMin, dataFlagYMin, dataFlagXMax, dataFlagYMax,
+							}, false),
+							Flags: []cli.Flag{
 								&cli.StringFlag{
-									Name:  dataFlagPartName,
-									Usage: "part name filter",
+									Name:  generalFlagOrgID,
+									Usage: "org ID to which the file belongs",
 								},
 								&cli.StringFlag{
-									Name:  dataFlagComponentType,
-									Usage: "component type filter",
+									Name:     dataFlagLocationID,
+									Usage:    "location ID to which the file belongs",
+									Required: true,
 								},
 								&cli.StringFlag{
-									Name:  dataFlagComponentName,
-									Usage: "component name filter",
+									Name:     dataFlagFileID,
+									Usage:    "file ID of the image",
+									Required: true,
 								},
 								&cli.StringFlag{
-									Name:  dataFlagMethod,
-									Usage: "method filter",
+									Name:     dataFlagBboxLabel,
+									Usage:    "label of the bounding box",
+									Required: true,
 								},
-								&cli.StringSliceFlag{
-									Name:  dataFlagMimeTypes,
-									Usage: "mime types filter",
+								&cli.Float64Flag{
+									Name:     dataFlagXMin,
+									Usage:    "left edge of the bounding box, from 0 to 1 of the image width",
+									Required: true,
 								},
-								&cli.StringFlag{
-									Name:  dataFlagStart,
-									Usage: "ISO-8601 timestamp indicating the start of the interval filter",
+								&cli.Float64Flag{
+									Name:     dataFlagYMin,
+									Usage:    "top edge of the bounding box, from 0 to 1 of the image height",
+									Required: true,
 								},
-								&cli.StringFlag{
-									Name:  dataFlagEnd,
-									Usage: "ISO-8601 timestamp indicating the end of the interval filter",
+								&cli.Float64Flag{
+									Name:     dataFlagXMax,
+									Usage:    "right edge of the bounding box, from 0 to 1 of the image width",
+									Required: true,
+								},
+								&cli.Float64Flag{
+									Name:     dataFlagYMax,
+									Usage:    "bottom edge of the bounding box, from 0 to 1 of the image height",
+									Required: true,
 								},
 							},
-							Action: DataDeleteBinaryAction,
+							Action: DataAddBboxAction,
 						},
 						{
-							Name:      "tabular",
-							Usage:     "delete tabular data from Viam cloud",
-							UsageText: createUsageText("data delete tabular", []string{generalFlagOrgID, dataFlagDeleteTabularDataOlderThanDays}, false),
+							Name:  "remove",
+							Usage: "removes a bounding box, or all bounding boxes with a label, from an image",
+							UsageText: createUsageText("data bbox remove",
+								[]string{generalFlagOrgID, dataFlagLocationID, dataFlagFileID}, true),
 							Flags: []cli.Flag{
 								&cli.StringFlag{
-									Name:     generalFlagOrgID,
-									Usage:    "org",
+									Name:  generalFlagOrgID,
+									Usage: "org ID to which the file belongs",
+								},
+								&cli.StringFlag{
+									Name:     dataFlagLocationID,
+									Usage:    "location ID to which the file belongs",
 									Required: true,
 								},
-								&cli.IntFlag{
-									Name:     dataFlagDeleteTabularDataOlderThanDays,
-									Usage:    "delete any tabular data that is older than X calendar days before now. 0 deletes all data.",
+								&cli.StringFlag{
+									Name:     dataFlagFileID,
+									Usage:    "file ID of the image",
 									Required: true,
 								},
+								&cli.StringFlag{
+									Name:  dataFlagBboxID,
+									Usage: "ID of the bounding box to remove",
+								},
+								&cli.StringFlag{
+									Name:  dataFlagBboxLabel,
+									Usage: "label of the bounding boxes to remove",
+								},
 							},
-							Action: DataDeleteTabularAction,
+							Action: DataRemoveBboxAction,
 						},
 					},
 				},
@@ -438,9 +668,8 @@ var app = &cli.App{
 							UsageText: createUsageText("data database configure", []string{generalFlagOrgID, dataFlagDatabasePassword}, false),
 							Flags: []cli.Flag{
 								&cli.StringFlag{
-									Name:     generalFlagOrgID,
-									Usage:    "org ID for the database user being configured",
-									Required: true,
+									Name:  generalFlagOrgID,
+									Usage: "org ID for the database user being configured",
 								},
 								&cli.StringFlag{
 									Name:     dataFlagDatabasePassword,
@@ -456,9 +685,8 @@ var app = &cli.App{
 							UsageText: createUsageText("data database hostname", []string{generalFlagOrgID}, false),
 							Flags: []cli.Flag{
 								&cli.StringFlag{
-									Name:     generalFlagOrgID,
-									Usage:    "org ID for the database user",
-									Required: true,
+									Name:  generalFlagOrgID,
+									Usage: "org ID for the database user",
 								},
 							},
 							Action: DataGetDatabaseConnection,
@@ -488,9 +716,8 @@ var app = &cli.App{
 											Required: true,
 										},
 										&cli.StringFlag{
-											Name:     generalFlagOrgID,
-											Usage:    "org ID to which data belongs",
-											Required: true,
+											Name:  generalFlagOrgID,
+											Usage: "org ID to which data belongs",
 										},
 										&cli.StringFlag{
 											Name:     dataFlagLocationID,
@@ -509,77 +736,22 @@ var app = &cli.App{
 								{
 									Name:      "filter",
 									UsageText: createUsageText("data dataset add filter", []string{datasetFlagDatasetID}, true),
-									Flags: []cli.Flag{
+									Flags: append([]cli.Flag{
 										&cli.StringFlag{
 											Name:     datasetFlagDatasetID,
 											Usage:    "dataset ID to which data will be added",
 											Required: true,
 										},
-										&cli.StringSliceFlag{
-											Name:  dataFlagOrgIDs,
-											Usage: "orgs filter",
-										},
-										&cli.StringSliceFlag{
-											Name:  dataFlagLocationIDs,
-											Usage: "locations filter",
-										},
-										&AliasStringFlag{
-											cli.StringFlag{
-												Name:    generalFlagMachineID,
-												Aliases: []string{generalFlagAliasRobotID},
-												Usage:   "machine id filter",
-											},
-										},
-										&cli.StringFlag{
-											Name:  dataFlagPartID,
-											Usage: "part id filter",
-										},
-										&AliasStringFlag{
-											cli.StringFlag{
-												Name:    dataFlagMachineName,
-												Aliases: []string{dataFlagAliasRobotName},
-												Usage:   "machine name filter",
-											},
-										},
-										&cli.StringFlag{
-											Name:  dataFlagPartName,
-											Usage: "part name filter",
-										},
-										&cli.StringFlag{
-											Name:  dataFlagComponentType,
-											Usage: "component type filter",
-										},
-										&cli.StringFlag{
-											Name:  dataFlagComponentName,
-											Usage: "component name filter",
-										},
-										&cli.StringFlag{
-											Name:  dataFlagMethod,
-											Usage: "method filter",
-										},
-										&cli.StringSliceFlag{
-											Name:  dataFlagMimeTypes,
-											Usage: "mime types filter",
-										},
-										&cli.StringFlag{
-											Name:  dataFlagStart,
-											Usage: "ISO-8601 timestamp indicating the start of the interval filter",
-										},
-										&cli.StringFlag{
-											Name:  dataFlagEnd,
-											Usage: "ISO-8601 timestamp indicating the end of the interval filter",
+										&cli.IntFlag{
+											Name:  dataFlagRetries,
+											Usage: "number of times to retry adding each batch of files if it fails with a transient network error",
+											Value: defaultDataRetries,
 										},
-										&cli.StringSliceFlag{
-											Name: dataFlagTags,
-											Usage: "tags filter. " +
-												"accepts tagged for all tagged data, untagged for all untagged data, or a list of tags for all data matching any of the tags",
-										},
-										&cli.StringSliceFlag{
-											Name: dataFlagBboxLabels,
-											Usage: "bbox labels filter. " +
-												"accepts string labels corresponding to bounding boxes within images",
+										&cli.BoolFlag{
+											Name:  dataFlagDryRun,
+											Usage: "print how many files match the filters without adding them",
 										},
-									},
+									}, sharedDataFilterFlags()...),
 									Action: DataAddToDatasetByFilter,
 								},
 							},
@@ -596,9 +768,8 @@ var app = &cli.App{
 									Required: true,
 								},
 								&cli.StringFlag{
-									Name:     generalFlagOrgID,
-									Usage:    "org ID to which data belongs",
-									Required: true,
+									Name:  generalFlagOrgID,
+									Usage: "org ID to which data belongs",
 								},
 								&cli.StringFlag{
 									Name:     dataFlagLocationID,
@@ -628,9 +799,8 @@ var app = &cli.App{
 					UsageText: createUsageText("dataset create", []string{generalFlagOrgID, datasetFlagName}, false),
 					Flags: []cli.Flag{
 						&cli.StringFlag{
-							Name:     generalFlagOrgID,
-							Required: true,
-							Usage:    "org ID for which dataset will be created",
+							Name:  generalFlagOrgID,
+							Usage: "org ID for which dataset will be created",
 						},
 						&cli.StringFlag{
 							Name:     datasetFlagName,
@@ -661,7 +831,7 @@ var app = &cli.App{
 				},
 				{
 					Name:  "list",
-					Usage: "list dataset information from specified IDs or for an org ID",
+					Usage: "list datasets from specified IDs or for an org ID, with when each was created and how many items it holds",
 					UsageText: fmt.Sprintf("viam dataset list [--%s=<%s> | --%s=<%s>]",
 						datasetFlagDatasetIDs, datasetFlagDatasetIDs, generalFlagOrgID, generalFlagOrgID),
 					Flags: []cli.Flag{
@@ -686,9 +856,37 @@ var app = &cli.App{
 							Required: true,
 							Usage:    "ID of the dataset to be deleted",
 						},
+						&cli.BoolFlag{
+							Name:    generalFlagYes,
+							Aliases: []string{"y"},
+							Usage:   "skip the confirmation prompt",
+						},
 					},
-					Action: DatasetCreateAction,
+					Action: DatasetDeleteAction,
 				},
+				perRequestTimeout(&cli.Command{
+					Name:      "export",
+					Usage:     "download all binary data in a dataset",
+					UsageText: createUsageText("dataset export", []string{dataFlagDestination, datasetFlagDatasetID}, true),
+					Flags: []cli.Flag{
+						&cli.PathFlag{
+							Name:     dataFlagDestination,
+							Required: true,
+							Usage:    "output directory for downloaded data",
+						},
+						&cli.StringFlag{
+							Name:     datasetFlagDatasetID,
+							Required: true,
+							Usage:    "ID of the dataset to be exported",
+						},
+						&cli.UintFlag{
+							Name:  dataFlagParallelDownloads,
+							Usage: "number of download requests to make in parallel",
+							Value: 100,
+						},
+					},
+					Action: DatasetExportAction,
+				}),
 			},
 		},
 		{
@@ -701,11 +899,14 @@ var app = &cli.App{
 					Usage: "submits training job on data in Viam cloud",
 					UsageText: createUsageText("train submit",
 						[]string{datasetFlagDatasetID, trainFlagModelOrgID, trainFlagModelName, trainFlagModelType, trainFlagModelLabels}, true),
-					Flags: []cli.Flag{
+					Description: `Trains on the dataset passed with --` + datasetFlagDatasetID + `. To train on data without first creating a dataset,
+pass data filter flags instead: a dataset holding the binary data matching them is created in the --` + trainFlagModelOrgID + `
+org for the job. The dataset is deleted if the job cannot be submitted, but otherwise kept because the job trains on it;
+delete it with 'viam dataset delete' once the job has finished.`,
+					Flags: append([]cli.Flag{
 						&cli.StringFlag{
-							Name:     datasetFlagDatasetID,
-							Usage:    "dataset ID",
-							Required: true,
+							Name:  datasetFlagDatasetID,
+							Usage: "dataset ID. required unless data filter flags are passed",
 						},
 						&cli.StringFlag{
 							Name:     trainFlagModelOrgID,
@@ -733,7 +934,11 @@ var app = &cli.App{
 							Name:  trainFlagModelVersion,
 							Usage: "version of ML model. defaults to current timestamp if unspecified.",
 						},
-					},
+						&cli.BoolFlag{
+							Name:  trainFlagWait,
+							Usage: "wait for the training job to finish, as 'viam train wait' does",
+						},
+					}, sharedDataFilterFlags()...),
 					Action: DataSubmitTrainingJob,
 				},
 				{
@@ -749,6 +954,22 @@ var app = &cli.App{
 					},
 					Action: DataGetTrainingJob,
 				},
+				{
+					Name:      "wait",
+					Usage:     "waits for a training job to complete, fail or be canceled",
+					UsageText: createUsageText("train wait", []string{trainFlagJobID}, false),
+					Description: `Prints the status of the training job each time it changes, and exits once the job reaches a final
+status: successfully if it completed, and with an error if it failed or was canceled. Pass the global --` + timeoutFlag + `
+flag to stop waiting after a given time, ex: 'viam --` + timeoutFlag + `=2h train wait --` + trainFlagJobID + `=<id>'.`,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:     trainFlagJobID,
+							Usage:    "training job ID",
+							Required: true,
+						},
+					},
+					Action: DataWaitForTrainingJob,
+				},
 				{
 					Name:      "cancel",
 					Usage:     "cancels training job in Viam cloud based on training job ID",
@@ -768,9 +989,8 @@ var app = &cli.App{
 					UsageText: createUsageText("train list", []string{generalFlagOrgID, trainFlagJobStatus}, false),
 					Flags: []cli.Flag{
 						&cli.StringFlag{
-							Name:     generalFlagOrgID,
-							Usage:    "org ID",
-							Required: true,
+							Name:  generalFlagOrgID,
+							Usage: "org ID",
 						},
 						&cli.StringFlag{
 							Name:     trainFlagJobStatus,
@@ -800,6 +1020,16 @@ var app = &cli.App{
 							Name:        locationFlag,
 							DefaultText: "first location alphabetically",
 						},
+						&cli.BoolFlag{
+							Name:  listRobotsFlagAll,
+							Usage: "list machines in every location of the organization",
+						},
+						&cli.StringFlag{
+							Name:        outputFlag,
+							Aliases:     []string{"o"},
+							DefaultText: outputFormatText,
+							Usage:       "output format: text or json",
+						},
 					},
 					Action: ListRobotsAction,
 				},
@@ -854,10 +1084,15 @@ var app = &cli.App{
 								Required: true,
 							},
 						},
+						&cli.StringFlag{
+							Name:        formatFlag,
+							DefaultText: formatTable,
+							Usage:       "output format: table, json or csv. Defaults to json if --" + outputFlag + " is json",
+						},
 					},
 					Action: RobotsStatusAction,
 				},
-				{
+				perRequestTimeout(&cli.Command{
 					Name:      "logs",
 					Aliases:   []string{"log"},
 					Usage:     "display machine logs",
@@ -882,9 +1117,27 @@ var app = &cli.App{
 							Name:  logsFlagErrors,
 							Usage: "show only errors",
 						},
+						&cli.StringFlag{
+							Name:        logsFlagLevel,
+							DefaultText: "debug",
+							Usage:       "show only logs at or above `LEVEL`: debug, info, warn or error",
+						},
+						&cli.DurationFlag{
+							Name:  logsFlagSince,
+							Usage: "show only logs from the last `DURATION`, ex: 1h",
+						},
+						&cli.StringFlag{
+							Name:  logsFlagGrep,
+							Usage: "show only logs whose message matches the regular expression `PATTERN`",
+						},
+						&cli.BoolFlag{
+							Name:    logsFlagTail,
+							Aliases: []string{"f"},
+							Usage:   "follow logs of all parts",
+						},
 					},
 					Action: RobotsLogsAction,
-				},
+				}),
 				{
 					Name:            "part",
 					Usage:           "work with a machine part",
@@ -914,10 +1167,15 @@ var app = &cli.App{
 									Name:     partFlag,
 									Required: true,
 								},
+								&cli.StringFlag{
+									Name:        formatFlag,
+									DefaultText: formatTable,
+									Usage:       "output format: table, json or csv. Defaults to json if --" + outputFlag + " is json",
+								},
 							},
 							Action: RobotsPartStatusAction,
 						},
-						{
+						perRequestTimeout(&cli.Command{
 							Name:      "logs",
 							Aliases:   []string{"log"},
 							Usage:     "display part logs",
@@ -946,6 +1204,19 @@ var app = &cli.App{
 									Name:  logsFlagErrors,
 									Usage: "show only errors",
 								},
+								&cli.StringFlag{
+									Name:        logsFlagLevel,
+									DefaultText: "debug",
+									Usage:       "show only logs at or above `LEVEL`: debug, info, warn or error",
+								},
+								&cli.DurationFlag{
+									Name:  logsFlagSince,
+									Usage: "show only logs from the last `DURATION`, ex: 1h",
+								},
+								&cli.StringFlag{
+									Name:  logsFlagGrep,
+									Usage: "show only logs whose message matches the regular expression `PATTERN`",
+								},
 								&cli.BoolFlag{
 									Name:    logsFlagTail,
 									Aliases: []string{"f"},
@@ -953,8 +1224,76 @@ var app = &cli.App{
 								},
 							},
 							Action: RobotsPartLogsAction,
+						}),
+						{
+							Name:      "restart",
+							Usage:     "restart a machine part",
+							UsageText: createUsageText("machines part restart", []string{machineFlag, partFlag}, true),
+							Flags: []cli.Flag{
+								&cli.StringFlag{
+									Name:        organizationFlag,
+									DefaultText: "first organization alphabetically",
+								},
+								&cli.StringFlag{
+									Name:        locationFlag,
+									DefaultText: "first location alphabetically",
+								},
+								&AliasStringFlag{
+									cli.StringFlag{
+										Name:     machineFlag,
+										Aliases:  []string{aliasRobotFlag},
+										Required: true,
+									},
+								},
+								&cli.StringFlag{
+									Name:     partFlag,
+									Required: true,
+								},
+								&cli.BoolFlag{
+									Name:  restartFlagWait,
+									Usage: "wait for the part to shut down, i.e. go 10 seconds without accessing app, and come back online",
+								},
+							},
+							Action: RobotsPartRestartAction,
 						},
 						{
+							Name:  "reload-module",
+							Usage: "restart a module on a machine part without restarting the rest of the part",
+							UsageText: createUsageText("machines part reload-module",
+								[]string{machineFlag, partFlag, reloadModuleFlagName}, true),
+							Description: `Modifies the machine part config: sets the ` + moduleReloadEnvVar + ` environment variable
+in the config of the module to the time of the reload, which makes the part restart the module when it next checks
+for config, typically within seconds. The variable stays in the config; it can be left there or removed. The whole
+part config is read and written back, so edits made to it by others in the meantime are overwritten.`,
+							Flags: []cli.Flag{
+								&cli.StringFlag{
+									Name:        organizationFlag,
+									DefaultText: "first organization alphabetically",
+								},
+								&cli.StringFlag{
+									Name:        locationFlag,
+									DefaultText: "first location alphabetically",
+								},
+								&AliasStringFlag{
+									cli.StringFlag{
+										Name:     machineFlag,
+										Aliases:  []string{aliasRobotFlag},
+										Required: true,
+									},
+								},
+								&cli.StringFlag{
+									Name:     partFlag,
+									Required: true,
+								},
+								&cli.StringFlag{
+									Name:     reloadModuleFlagName,
+									Usage:    "name of the module to reload, as configured on the part",
+									Required: true,
+								},
+							},
+							Action: RobotsPartReloadModuleAction,
+						},
+						perRequestTimeout(&cli.Command{
 							Name:  "run",
 							Usage: "run a command on a machine part",
 							UsageText: createUsageText("machines part run", []string{
@@ -962,12 +1301,10 @@ var app = &cli.App{
 							}, true, "<service.method>"),
 							Flags: []cli.Flag{
 								&cli.StringFlag{
-									Name:     organizationFlag,
-									Required: true,
+									Name: organizationFlag,
 								},
 								&cli.StringFlag{
-									Name:     locationFlag,
-									Required: true,
+									Name: locationFlag,
 								},
 								&AliasStringFlag{
 									cli.StringFlag{
@@ -988,10 +1325,18 @@ var app = &cli.App{
 									Name:    runFlagStream,
 									Aliases: []string{"s"},
 								},
+								&cli.PathFlag{
+									Name:  runFlagOutputFile,
+									Usage: "append each response to `FILE` as newline-delimited JSON instead of printing it",
+								},
+								&cli.BoolFlag{
+									Name:  runFlagTruncate,
+									Usage: "truncate the output file instead of appending to it",
+								},
 							},
 							Action: RobotsPartRunAction,
-						},
-						{
+						}),
+						perRequestTimeout(&cli.Command{
 							Name:        "shell",
 							Usage:       "start a shell on a machine part",
 							Description: `In order to use the shell command, the machine must have a valid shell type service.`,
@@ -1014,7 +1359,42 @@ var app = &cli.App{
 								},
 							},
 							Action: RobotsPartShellAction,
-						},
+						}),
+						perRequestTimeout(&cli.Command{
+							Name:  "cp",
+							Usage: "copy files to or from a machine part",
+							Description: `Copies files over the machine part's shell type service, which the machine must have.
+Exactly one of <source> and <destination> is a path on the machine part, written with a "part:" prefix.
+If <destination> is an existing directory, <source> is copied into it.
+
+Ex: 'viam machines part cp --part=my-part ./config.json part:/home/viam/'
+    'viam machines part cp --part=my-part -r part:/home/viam/logs ./logs'`,
+							UsageText: createUsageText("machines part cp", []string{organizationFlag, locationFlag, machineFlag, partFlag},
+								true, "<source>", "<destination>"),
+							Flags: []cli.Flag{
+								&cli.StringFlag{
+									Name: organizationFlag,
+								},
+								&cli.StringFlag{
+									Name: locationFlag,
+								},
+								&AliasStringFlag{
+									cli.StringFlag{
+										Name:    machineFlag,
+										Aliases: []string{aliasRobotFlag},
+									},
+								},
+								&cli.StringFlag{
+									Name: partFlag,
+								},
+								&cli.BoolFlag{
+									Name:    partCopyFlagRecursive,
+									Aliases: []string{"r"},
+									Usage:   "copy directories recursively",
+								},
+							},
+							Action: RobotsPartCopyAction,
+						}),
 					},
 				},
 			},
@@ -1035,7 +1415,11 @@ If your org has set a namespace in app.viam.com then your module name will be 'm
 you won't have to pass a namespace or org-id in future commands. Otherwise there will be no namespace
 and you will have to provide the org-id to future cli commands. You cannot make your module public until you claim an org-id.
 
-After creation, use 'viam module update' to push your new module to app.viam.com.`,
+After creation, use 'viam module update' to push your new module to app.viam.com.
+
+With --local, only the meta.json is written, without contacting app.viam.com, so you can start a module offline.
+The name and namespace or org-id are only checked for their format, and the meta.json is marked as unregistered.
+Run 'viam module create' again without --local from the same directory to register the module once you are online.`,
 					UsageText: createUsageText("module create", []string{moduleFlagName}, true),
 					Flags: []cli.Flag{
 						&cli.StringFlag{
@@ -1051,6 +1435,10 @@ After creation, use 'viam module update' to push your new module to app.viam.com
 							Name:  generalFlagOrgID,
 							Usage: "id of the organization that will host the module",
 						},
+						&cli.BoolFlag{
+							Name:  moduleFlagLocal,
+							Usage: "only write meta.json, without registering the module on app.viam.com",
+						},
 					},
 					Action: CreateModuleAction,
 				},
@@ -1098,11 +1486,23 @@ Example uploading a whole directory:
 viam module upload --version "0.1.0" --platform "linux/amd64" ./bin
 (this example requires the entrypoint in the meta.json to be inside the bin directory like "./bin/[your path here]")
 
+When uploading a directory, paths matching the patterns in a .viamignore file (same syntax as .gitignore)
+in the root of the directory are left out of the archive.
+
 Example uploading a custom tarball of your module:
 tar -czf packaged-module.tar.gz ./src requirements.txt run.sh
 viam module upload --version "0.1.0" --platform "linux/amd64" packaged-module.tar.gz
+
+Example uploading tarballs for several platforms at once, leaving out --platform:
+viam module upload --version "0.1.0" ./dist
+viam module upload --version "0.1.0" my-module-linux-amd64.tar.gz my-module-linux-arm64.tar.gz
+(the platform of each tarball is inferred from the end of its file name, ex: linux-arm64, darwin_amd64 or any.
+Every platform is uploaded even if some fail, and the result of each is printed)
+
+Before anything is uploaded, the meta.json is checked for missing or malformed fields, and, unless --force is
+passed, uploaded files and directories are checked for an executable file at the entrypoint.
                       `,
-					UsageText: createUsageText("module upload", []string{moduleFlagVersion, moduleFlagPlatform}, true, "<packaged-module.tag.gz>"),
+					UsageText: createUsageText("module upload", []string{moduleFlagVersion}, true, "<packaged-module.tag.gz>..."),
 					Flags: []cli.Flag{
 						&cli.StringFlag{
 							Name:      moduleFlagPath,
@@ -1140,8 +1540,8 @@ viam module upload --version "0.1.0" --platform "linux/amd64" packaged-module.ta
                       linux/arm32v7
                       linux/arm32v6
                       darwin/amd64  (Intel macs)
-                      darwin/arm64  (Apple silicon macs)`,
-							Required: true,
+                      darwin/arm64  (Apple silicon macs)
+                      Required unless uploading tarballs whose file names end in their platform`,
 						},
 						&cli.BoolFlag{
 							Name:  moduleFlagForce,
@@ -1150,6 +1550,57 @@ viam module upload --version "0.1.0" --platform "linux/amd64" packaged-module.ta
 					},
 					Action: UploadModuleAction,
 				},
+				{
+					Name:  "download",
+					Usage: "download a version of a module that was uploaded to the registry",
+					Description: `Download the tarball of a module version for a specified platform.
+The module is identified by --id, by --name together with --org-id or --public-namespace, or by the module_id in the meta.json.
+Example:
+viam module download --id "my-namespace:my-module" --version "0.1.0" --platform "linux/amd64" --destination ./modules
+                      `,
+					UsageText: createUsageText("module download", []string{moduleFlagPlatform}, true),
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:      moduleFlagPath,
+							Usage:     "path to meta.json",
+							Value:     "./meta.json",
+							TakesFile: true,
+						},
+						&cli.StringFlag{
+							Name:  moduleFlagID,
+							Usage: "id of the module to download in the form 'public-namespace:module-name' or 'org-id:module-name'",
+						},
+						&cli.StringFlag{
+							Name:  moduleFlagPublicNamespace,
+							Usage: "the public namespace where the module resides (alternative way of specifying the org id)",
+						},
+						&cli.StringFlag{
+							Name:  generalFlagOrgID,
+							Usage: "id of the organization that hosts the module",
+						},
+						&cli.StringFlag{
+							Name:  moduleFlagName,
+							Usage: "name of the module (used if you don't have a meta.json)",
+						},
+						&cli.StringFlag{
+							Name:        moduleFlagVersion,
+							Usage:       "version of the module to download (semver2.0) ex: \"0.1.0\"",
+							DefaultText: "latest",
+						},
+						&cli.StringFlag{
+							Name:     moduleFlagPlatform,
+							Usage:    "platform of the upload to download ex: \"linux/amd64\"",
+							Required: true,
+						},
+						&cli.StringFlag{
+							Name:      moduleFlagDestination,
+							Usage:     "directory to write the tarball to",
+							Value:     ".",
+							TakesFile: true,
+						},
+					},
+					Action: DownloadModuleAction,
+				},
 				{
 					Name:  "build",
 					Usage: "build your module for different architectures using cloud runners",
@@ -1171,6 +1622,8 @@ Example:
 						{
 							Name:  "local",
 							Usage: "run your meta.json build command locally",
+							Description: `Runs the setup and build commands in your meta.json, then checks that the build produced the
+file at the "path" in the "build" section, and prints its absolute path and size.`,
 							Flags: []cli.Flag{
 								&cli.StringFlag{
 									Name:      moduleFlagPath,
@@ -1178,6 +1631,11 @@ Example:
 									Value:     "./meta.json",
 									TakesFile: true,
 								},
+								&cli.StringFlag{
+									Name:      moduleBuildFlagCopyTo,
+									Usage:     "file or directory to copy the built module to",
+									TakesFile: true,
+								},
 							},
 							Action: ModuleBuildLocalAction,
 						},
@@ -1208,6 +1666,9 @@ Example:
 						{
 							Name:  "list",
 							Usage: "check on the status of your cloud builds",
+							Description: `Lists your cloud builds, each with its overall status and the status of each of its platforms.
+The overall status is Done once every platform is done, and Failed if any platform failed.
+Ex: 'viam module build list --id <build id> --output json' to check from CI that every platform succeeded.`,
 							Flags: []cli.Flag{
 								&cli.StringFlag{
 									Name:      moduleFlagPath,
@@ -1225,10 +1686,20 @@ Example:
 									Name:  moduleBuildFlagBuildID,
 									Usage: "restrict output to just return builds that match this id",
 								},
+								&cli.StringFlag{
+									Name:  moduleBuildFlagPlatform,
+									Usage: "restrict output to the jobs of builds for this platform. Ex: linux/arm64",
+								},
+								&cli.StringFlag{
+									Name:        outputFlag,
+									Aliases:     []string{"o"},
+									DefaultText: outputFormatText,
+									Usage:       "output format: text or json",
+								},
 							},
 							Action: ModuleBuildListAction,
 						},
-						{
+						perRequestTimeout(&cli.Command{
 							Name:      "logs",
 							Aliases:   []string{"log"},
 							Usage:     "get the logs from one of your cloud builds",
@@ -1247,19 +1718,91 @@ Example:
 									Name:  moduleBuildFlagWait,
 									Usage: "wait for the build to finish before outputting any logs",
 								},
+								&cli.BoolFlag{
+									Name:    moduleBuildFlagFollow,
+									Aliases: []string{"f"},
+									Usage:   "stream logs as they are produced until the build finishes. If a platform is not provided, follows each platform in turn",
+								},
 							},
 							Action: ModuleBuildLogsAction,
+						}),
+					},
+				},
+			},
+		},
+		{
+			Name:            "config",
+			Usage:           "work with machine configs",
+			HideHelpCommand: true,
+			Subcommands: []*cli.Command{
+				{
+					Name:  "validate",
+					Usage: "check a machine config for errors without starting or connecting to a machine",
+					Description: `Parses, processes, and validates the config the same way viam-server does when it starts,
+without fetching anything from the cloud. Each error is reported with the JSON path, and line and column
+where possible, of the part of the config it is about. Exits with an error if the config is invalid.
+Resource attributes are not checked, since they are validated by the model, built-in or modular, that uses them.
+
+Pass - instead of a file to read the config from stdin. Relative includes are then resolved against
+the current directory.`,
+					UsageText: createUsageText("config validate", nil, false, "<file|->"),
+					Action:    ConfigValidateAction,
+				},
+				{
+					Name:            "cache",
+					Usage:           "work with the cloud configs machine parts on this computer have cached",
+					HideHelpCommand: true,
+					Subcommands: []*cli.Command{
+						{
+							Name:  "list",
+							Usage: "list the cached configs with their part IDs, sizes, and when they were last updated",
+							Flags: []cli.Flag{
+								&cli.StringFlag{
+									Name:        outputFlag,
+									Aliases:     []string{"o"},
+									DefaultText: outputFormatText,
+									Usage:       "output format: text or json",
+								},
+							},
+							Action: ConfigCacheListAction,
+						},
+						{
+							Name:      "clear",
+							Usage:     "delete cached configs, so that parts fetch their config from the cloud when they next start",
+							UsageText: createUsageText("config cache clear", nil, true),
+							Flags: []cli.Flag{
+								&cli.StringFlag{
+									Name:  configCacheFlagPartID,
+									Usage: "part whose cached config to delete",
+								},
+								&cli.BoolFlag{
+									Name:  configCacheFlagAll,
+									Usage: "delete every cached config",
+								},
+								&cli.BoolFlag{
+									Name:    generalFlagYes,
+									Aliases: []string{"y"},
+									Usage:   "skip the confirmation prompt",
+								},
+							},
+							Action: ConfigCacheClearAction,
 						},
 					},
 				},
 			},
 		},
 		{
-			Name:   "version",
-			Usage:  "print version info for this program",
+			Name:  "version",
+			Usage: "print version info for this program",
+			Flags: []cli.Flag{
+				&cli.BoolFlag{
+					Name:  versionFlagCheck,
+					Usage: "check whether a newer version has been released, exiting with an error if so",
+				},
+			},
 			Action: VersionAction,
 		},
-	},
+	}),
 }
 
 // NewApp returns a new app with the CLI API, Writer set to out, and ErrWriter
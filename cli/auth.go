@@ -117,6 +117,21 @@ type apiKey struct {
 	KeyCrypto string `json:"key_crypto"`
 }
 
+// apiKeyFromEnv returns the api key set by the VIAM_API_KEY_ID and VIAM_API_KEY environment variables, or
+// nil if neither is set.
+func apiKeyFromEnv() (*apiKey, error) {
+	key := apiKey{KeyID: os.Getenv(envAPIKeyID), KeyCrypto: os.Getenv(envAPIKey)}
+	switch {
+	case key.KeyID == "" && key.KeyCrypto == "":
+		return nil, nil
+	case key.KeyID == "":
+		return nil, errors.Errorf("%s is set but %s is not; set both to authenticate with an api key", envAPIKey, envAPIKeyID)
+	case key.KeyCrypto == "":
+		return nil, errors.Errorf("%s is set but %s is not; set both to authenticate with an api key", envAPIKeyID, envAPIKey)
+	}
+	return &key, nil
+}
+
 // LoginAction is the corresponding Action for 'login'.
 func LoginAction(cCtx *cli.Context) error {
 	c, err := newViamClient(cCtx)
@@ -224,15 +239,48 @@ func (c *viamClient) printAccessTokenAction(cCtx *cli.Context) error {
 	return nil
 }
 
+// LoginStatusAction is the corresponding Action for 'login status'.
+func LoginStatusAction(cCtx *cli.Context) error {
+	c, err := newViamClient(cCtx)
+	if err != nil {
+		return err
+	}
+	return c.loginStatusAction(cCtx)
+}
+
+// loginStatusAction prints the cached credentials without making any network calls.
+func (c *viamClient) loginStatusAction(cCtx *cli.Context) error {
+	switch auth := c.conf.Auth.(type) {
+	case *token:
+		printf(cCtx.App.Writer, "Logged in as %q with a browser login", auth.User.Email)
+		printf(cCtx.App.Writer, "Base URL: %s", c.conf.BaseURL)
+		expiry := auth.ExpiresAt.Format("Mon Jan 2 15:04:05 MST 2006")
+		if auth.isExpired() {
+			if auth.canRefresh() {
+				printf(cCtx.App.Writer, "Access token expired %s; it will be refreshed on the next command", expiry)
+			} else {
+				printf(cCtx.App.Writer, "Access token expired %s and cannot be refreshed", expiry)
+				return errors.New("credentials expired: run the following command to login:\n\tviam login")
+			}
+		} else {
+			printf(cCtx.App.Writer, "Access token valid, expires %s", expiry)
+		}
+	case *apiKey:
+		printf(cCtx.App.Writer, "Logged in with api key %q", auth.KeyID)
+		printf(cCtx.App.Writer, "Base URL: %s", c.conf.BaseURL)
+		printf(cCtx.App.Writer, "API keys do not expire")
+	default:
+		return errors.New("not logged in: run the following command to login:\n\tviam login")
+	}
+	return nil
+}
+
 // LogoutAction is the corresponding Action for 'logout'.
 func LogoutAction(cCtx *cli.Context) error {
-	// Create basic viam client; no need to check base URL.
-	conf, err := configFromCache()
+	// Create basic viam client; no need to check the base URL, only to find its credentials.
+	conf, err := configForBaseURL(cCtx.String(profileFlag), cCtx.String(baseURLFlag))
 	if err != nil {
-		if !os.IsNotExist(err) {
-			return err
-		}
-		conf = &config{}
+		return err
 	}
 
 	vc := &viamClient{
@@ -282,6 +330,9 @@ func (c *viamClient) generateDefaultKeyName() string {
 
 // OrganizationsAPIKeyCreateAction corresponds to `organizations api-key create`.
 func OrganizationsAPIKeyCreateAction(cCtx *cli.Context) error {
+	if _, err := requiredStringFlagOrConfigFile(cCtx, generalFlagOrgID); err != nil {
+		return err
+	}
 	c, err := newViamClient(cCtx)
 	if err != nil {
 		return err
@@ -293,7 +344,7 @@ func (c *viamClient) organizationsAPIKeyCreateAction(cCtx *cli.Context) error {
 	if err := c.ensureLoggedIn(); err != nil {
 		return err
 	}
-	orgID := cCtx.String(generalFlagOrgID)
+	orgID := stringFlagOrConfigFile(cCtx, generalFlagOrgID)
 	keyName := cCtx.String(apiKeyCreateFlagName)
 	if keyName == "" {
 		keyName = c.generateDefaultKeyName()
@@ -334,6 +385,9 @@ func (c *viamClient) createOrganizationAPIKey(orgID, keyName string) (*apppb.Cre
 
 // LocationAPIKeyCreateAction corresponds to `location api-key create`.
 func LocationAPIKeyCreateAction(cCtx *cli.Context) error {
+	if _, err := requiredStringFlagOrConfigFile(cCtx, generalFlagLocationID); err != nil {
+		return err
+	}
 	c, err := newViamClient(cCtx)
 	if err != nil {
 		return err
@@ -348,8 +402,8 @@ func (c *viamClient) locationAPIKeyCreateAction(cCtx *cli.Context) error {
 		return err
 	}
 
-	locationID := cCtx.String(generalFlagLocationID)
-	orgID := cCtx.String(generalFlagOrgID)
+	locationID := stringFlagOrConfigFile(cCtx, generalFlagLocationID)
+	orgID := stringFlagOrConfigFile(cCtx, generalFlagOrgID)
 	keyName := cCtx.String(apiKeyCreateFlagName)
 
 	if locationID == "" {
@@ -409,7 +463,7 @@ func (c *viamClient) robotAPIKeyCreateAction(cCtx *cli.Context) error {
 
 	robotID := cCtx.String(generalFlagMachineID)
 	keyName := cCtx.String(apiKeyCreateFlagName)
-	orgID := cCtx.String(generalFlagOrgID)
+	orgID := stringFlagOrConfigFile(cCtx, generalFlagOrgID)
 
 	if robotID == "" {
 		return errors.New("cannot create an api-key for a machine without an ID")
@@ -498,6 +552,7 @@ func (c *viamClient) ensureLoggedIn() error {
 	}
 
 	c.client = apppb.NewAppServiceClient(conn)
+	c.billingClient = apppb.NewBillingServiceClient(conn)
 	c.dataClient = datapb.NewDataServiceClient(conn)
 	c.packageClient = packagepb.NewPackageServiceClient(conn)
 	c.datasetClient = datasetpb.NewDatasetServiceClient(conn)
@@ -509,10 +564,10 @@ func (c *viamClient) ensureLoggedIn() error {
 
 // logout logs out the client and clears the config.
 func (c *viamClient) logout() error {
-	if err := removeConfigFromCache(); err != nil && !os.IsNotExist(err) {
+	if err := removeConfigFromCache(c.conf); err != nil && !os.IsNotExist(err) {
 		return err
 	}
-	c.conf = &config{}
+	c.conf = &config{profile: c.conf.profile}
 	return nil
 }
 
@@ -5,8 +5,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	apppb "go.viam.com/api/app/v1"
 	"go.viam.com/test"
@@ -34,6 +38,57 @@ func TestAPIKeyAuth(t *testing.T) {
 	test.That(t, APIKey.KeyCrypto, test.ShouldEqual, testKeyCrypto)
 }
 
+func TestAPIKeyFromEnv(t *testing.T) {
+	origViamDotDir := viamDotDir
+	viamDotDir = t.TempDir()
+	t.Cleanup(func() {
+		viamDotDir = origViamDotDir
+	})
+
+	t.Setenv(envAPIKeyID, "")
+	t.Setenv(envAPIKey, "")
+	key, err := apiKeyFromEnv()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, key, test.ShouldBeNil)
+
+	t.Setenv(envAPIKeyID, "env-key-id")
+	_, err = apiKeyFromEnv()
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, envAPIKey+" is not")
+
+	t.Setenv(envAPIKey, "env-key")
+	key, err = apiKeyFromEnv()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, key, test.ShouldResemble, &apiKey{KeyID: "env-key-id", KeyCrypto: "env-key"})
+
+	// the env key replaces the cached credentials for the command but is never cached itself.
+	cached := &token{AccessToken: testToken, ExpiresAt: time.Now().Add(time.Hour), User: userData{Email: testEmail}}
+	// newViamClient checks that the base URL is reachable.
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	defer listener.Close()
+	test.That(t, storeConfigToCache(&config{BaseURL: "http://" + listener.Addr().String(), Auth: cached}), test.ShouldBeNil)
+	cCtx, _, _, _ := setup(nil, nil, nil, nil, "")
+	c, err := newViamClient(cCtx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, c.conf.Auth, test.ShouldResemble, key)
+	c.conf.LatestVersion = "v1.2.3"
+	test.That(t, storeConfigToCache(c.conf), test.ShouldBeNil)
+	conf, err := configFromCache("")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, conf.LatestVersion, test.ShouldEqual, "v1.2.3")
+	cachedToken, ok := conf.Auth.(*token)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, cachedToken.AccessToken, test.ShouldEqual, testToken)
+
+	// logging in with the key from the environment caches it.
+	c.conf.Auth = &apiKey{KeyID: key.KeyID, KeyCrypto: key.KeyCrypto}
+	test.That(t, storeConfigToCache(c.conf), test.ShouldBeNil)
+	conf, err = configFromCache("")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, conf.Auth, test.ShouldResemble, key)
+}
+
 func TestPrintAccessTokenAction(t *testing.T) {
 	// AppServiceClient needed for any Action that calls ensureLoggedIn.
 	cCtx, ac, out, errOut := setup(&inject.AppServiceClient{}, nil, nil, nil, "token")
@@ -211,6 +266,30 @@ func TestLocationAPIKeyCreateAction(t *testing.T) {
 		fmt.Sprintf("cannot create api-key for location: %s as there are multiple orgs on the location", fakeLocID))
 }
 
+func TestLoginStatusAction(t *testing.T) {
+	cCtx, ac, out, errOut := setup(nil, nil, nil, nil, "token")
+	test.That(t, ac.loginStatusAction(cCtx), test.ShouldBeNil)
+	test.That(t, len(errOut.messages), test.ShouldEqual, 0)
+	test.That(t, out.messages[0], test.ShouldContainSubstring, fmt.Sprintf("Logged in as %q with a browser login", testEmail))
+	test.That(t, strings.Join(out.messages, ""), test.ShouldContainSubstring, "Access token valid, expires")
+
+	ac.conf.Auth.(*token).ExpiresAt = time.Now().Add(-time.Hour)
+	out.messages = nil
+	err := ac.loginStatusAction(cCtx)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "credentials expired")
+	test.That(t, strings.Join(out.messages, ""), test.ShouldContainSubstring, "cannot be refreshed")
+
+	cCtx, ac, out, _ = setup(nil, nil, nil, nil, "apiKey")
+	test.That(t, ac.loginStatusAction(cCtx), test.ShouldBeNil)
+	test.That(t, out.messages[0], test.ShouldContainSubstring, fmt.Sprintf("Logged in with api key %q", testKeyID))
+
+	cCtx, ac, _, _ = setup(nil, nil, nil, nil, "")
+	err = ac.loginStatusAction(cCtx)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "not logged in")
+}
+
 func TestLogoutAction(t *testing.T) {
 	cCtx, ac, out, errOut := setup(nil, nil, nil, nil, "token")
 
@@ -230,6 +309,110 @@ func TestWhoAmIAction(t *testing.T) {
 	test.That(t, out.messages[0], test.ShouldContainSubstring, testEmail)
 }
 
+func TestProfiles(t *testing.T) {
+	origViamDotDir := viamDotDir
+	viamDotDir = t.TempDir()
+	defer func() {
+		viamDotDir = origViamDotDir
+	}()
+
+	test.That(t, getCLICachePath(""), test.ShouldEqual, filepath.Join(viamDotDir, "cached_cli_config.json"))
+	test.That(t, getCLICachePath("work"), test.ShouldEqual,
+		filepath.Join(viamDotDir, "profiles", "work", "cached_cli_config.json"))
+
+	test.That(t, validateProfile(""), test.ShouldBeNil)
+	test.That(t, validateProfile("work"), test.ShouldBeNil)
+	test.That(t, validateProfile(".."), test.ShouldBeError)
+	test.That(t, validateProfile("../work"), test.ShouldBeError)
+
+	defaultConf := &config{Auth: &token{User: userData{Email: "default@viam.com"}}}
+	workConf := &config{Auth: &apiKey{KeyID: "work-key"}, profile: "work"}
+	test.That(t, storeConfigToCache(defaultConf), test.ShouldBeNil)
+	test.That(t, storeConfigToCache(workConf), test.ShouldBeNil)
+
+	conf, err := configFromCache("")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, conf.profile, test.ShouldEqual, "")
+	test.That(t, conf.Auth.(*token).User.Email, test.ShouldEqual, "default@viam.com")
+	conf, err = configFromCache("work")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, conf.profile, test.ShouldEqual, "work")
+	test.That(t, conf.Auth.(*apiKey).KeyID, test.ShouldEqual, "work-key")
+
+	// logging out of one profile leaves the others logged in.
+	cCtx, ac, out, _ := setup(nil, nil, nil, nil, "")
+	ac.conf = conf
+	test.That(t, ac.logoutAction(cCtx), test.ShouldBeNil)
+	test.That(t, out.messages[0], test.ShouldContainSubstring, "Logged out")
+	test.That(t, ac.conf.profile, test.ShouldEqual, "work")
+	_, err = configFromCache("work")
+	test.That(t, os.IsNotExist(err), test.ShouldBeTrue)
+	conf, err = configFromCache("")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, conf.Auth.(*token).User.Email, test.ShouldEqual, "default@viam.com")
+}
+
+func TestConfigForBaseURL(t *testing.T) {
+	origViamDotDir := viamDotDir
+	viamDotDir = t.TempDir()
+	defer func() {
+		viamDotDir = origViamDotDir
+	}()
+
+	const stagingURL = "https://app.staging.viam.dev:443"
+	test.That(t, getCLIBaseURLCachePath("", "app.staging.viam.dev"), test.ShouldEqual,
+		filepath.Join(viamDotDir, "base_urls", "https_app.staging.viam.dev_443", "cached_cli_config.json"))
+	test.That(t, getCLIBaseURLCachePath("work", stagingURL), test.ShouldEqual,
+		filepath.Join(viamDotDir, "profiles", "work", "base_urls", "https_app.staging.viam.dev_443", "cached_cli_config.json"))
+
+	// not logged in anywhere, so the flag's base URL is used with the profile's main config.
+	conf, err := configForBaseURL("", stagingURL)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, conf.cachedForBaseURL, test.ShouldBeFalse)
+	test.That(t, conf.Auth, test.ShouldBeNil)
+
+	prodConf := &config{BaseURL: defaultBaseURL, Auth: &token{User: userData{Email: "prod@viam.com"}}}
+	test.That(t, storeConfigToCache(prodConf), test.ShouldBeNil)
+
+	// the cached login is used without the flag, or with a flag naming the same base URL.
+	for _, baseURLArg := range []string{"", defaultBaseURL, "app.viam.com"} {
+		conf, err = configForBaseURL("", baseURLArg)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, conf.BaseURL, test.ShouldEqual, defaultBaseURL)
+		test.That(t, conf.Auth.(*token).User.Email, test.ShouldEqual, "prod@viam.com")
+	}
+
+	// another base URL does not reuse the prod token.
+	conf, err = configForBaseURL("", stagingURL)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, conf.BaseURL, test.ShouldEqual, stagingURL)
+	test.That(t, conf.cachedForBaseURL, test.ShouldBeTrue)
+	test.That(t, conf.Auth, test.ShouldBeNil)
+
+	// logging in to it caches its credentials separately.
+	conf.Auth = &apiKey{KeyID: "staging-key"}
+	test.That(t, storeConfigToCache(conf), test.ShouldBeNil)
+	conf, err = configForBaseURL("", "app.staging.viam.dev")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, conf.Auth.(*apiKey).KeyID, test.ShouldEqual, "staging-key")
+	conf, err = configForBaseURL("", "")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, conf.Auth.(*token).User.Email, test.ShouldEqual, "prod@viam.com")
+
+	// logging out of the other base URL keeps the main login.
+	conf, err = configForBaseURL("", stagingURL)
+	test.That(t, err, test.ShouldBeNil)
+	cCtx, ac, _, _ := setup(nil, nil, nil, nil, "")
+	ac.conf = conf
+	test.That(t, ac.logoutAction(cCtx), test.ShouldBeNil)
+	conf, err = configForBaseURL("", stagingURL)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, conf.Auth, test.ShouldBeNil)
+	conf, err = configForBaseURL("", "")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, conf.Auth.(*token).User.Email, test.ShouldEqual, "prod@viam.com")
+}
+
 func TestConfigMarshalling(t *testing.T) {
 	t.Run("token config", func(t *testing.T) {
 		conf := config{
@@ -11,17 +11,24 @@ import (
 	"net/url"
 	"os"
 	"os/exec"
+	"os/signal"
+	"regexp"
 	"runtime/debug"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/fullstorydev/grpcurl"
+	"github.com/golang/protobuf/proto"
 	"github.com/google/uuid"
 	"github.com/jhump/protoreflect/grpcreflect"
 	"github.com/pkg/errors"
 	"github.com/urfave/cli/v2"
+	"go.uber.org/multierr"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 	buildpb "go.viam.com/api/app/build/v1"
 	datapb "go.viam.com/api/app/data/v1"
 	datasetpb "go.viam.com/api/app/dataset/v1"
@@ -44,15 +51,21 @@ import (
 )
 
 const (
-	rdkReleaseURL = "https://api.github.com/repos/viamrobotics/rdk/releases/latest"
+	// latestVersionCacheDuration is how long the result of 'version --check' is reused for, to stay clear of
+	// the GitHub API rate limits.
+	latestVersionCacheDuration = time.Hour
 )
 
+// rdkReleaseURL is where the latest release is looked up; it is a var so tests can replace it.
+var rdkReleaseURL = "https://api.github.com/repos/viamrobotics/rdk/releases/latest"
+
 // viamClient wraps a cli.Context and provides all the CLI command functionality
 // needed to talk to the app and data services but not directly to robot parts.
 type viamClient struct {
 	c                *cli.Context
 	conf             *config
 	client           apppb.AppServiceClient
+	billingClient    apppb.BillingServiceClient
 	dataClient       datapb.DataServiceClient
 	packageClient    packagepb.PackageServiceClient
 	datasetClient    datasetpb.DatasetServiceClient
@@ -79,59 +92,218 @@ func ListOrganizationsAction(cCtx *cli.Context) error {
 	return c.listOrganizationsAction(cCtx)
 }
 
+// organizationOutput is the JSON output of 'organizations list'.
+type organizationOutput struct {
+	ID              string     `json:"id"`
+	Name            string     `json:"name"`
+	PublicNamespace string     `json:"public_namespace,omitempty"`
+	CreatedOn       *time.Time `json:"created_on,omitempty"`
+}
+
 func (c *viamClient) listOrganizationsAction(cCtx *cli.Context) error {
+	format, err := outputFormat(cCtx)
+	if err != nil {
+		return err
+	}
 	orgs, err := c.listOrganizations()
 	if err != nil {
 		return errors.Wrap(err, "could not list organizations")
 	}
-	for i, org := range orgs {
-		if i == 0 {
-			printf(cCtx.App.Writer, "Organizations for %q:", c.conf.Auth)
+	var extended []organizationExtendedInfo
+	if cCtx.Bool(listOrganizationsFlagExtended) {
+		extended = c.organizationsExtendedInfo(orgs)
+	}
+	if format == outputFormatJSON {
+		if extended != nil {
+			out := make([]extendedOrganizationOutput, 0, len(orgs))
+			for i, org := range orgs {
+				out = append(out, extendedOrganizationOutput{
+					organizationOutput:       newOrganizationOutput(org),
+					organizationExtendedInfo: extended[i],
+				})
+			}
+			return printJSON(cCtx.App.Writer, out)
+		}
+		out := make([]organizationOutput, 0, len(orgs))
+		for _, org := range orgs {
+			out = append(out, newOrganizationOutput(org))
 		}
-		idInfo := fmt.Sprintf("(id: %s)", org.Id)
-		namespaceInfo := ""
-		if org.PublicNamespace != "" {
-			namespaceInfo = fmt.Sprintf(" (namespace: %s)", org.PublicNamespace)
+		return printJSON(cCtx.App.Writer, out)
+	}
+	header := []string{"NAME", "ID", "NAMESPACE"}
+	if extended != nil {
+		header = append(header, organizationExtendedInfoHeader...)
+	}
+	tbl := newTable(header...).keepWhole("ID")
+	for i, org := range orgs {
+		row := []string{org.Name, org.Id, org.PublicNamespace}
+		if extended != nil {
+			row = append(row, extended[i].tableCells()...)
 		}
-		printf(cCtx.App.Writer, "\t%s %s%s", org.Name, idInfo, namespaceInfo)
+		tbl.addRow(row...)
+	}
+	opts := tableOptionsFor(cCtx)
+	if len(orgs) > 0 && !opts.quiet {
+		printf(cCtx.App.Writer, "Organizations for %q:", c.conf.Auth)
 	}
+	tbl.render(cCtx.App.Writer, opts)
 	return nil
 }
 
+func newOrganizationOutput(org *apppb.Organization) organizationOutput {
+	return organizationOutput{
+		ID:              org.Id,
+		Name:            org.Name,
+		PublicNamespace: org.PublicNamespace,
+		CreatedOn:       timestampOrNil(org.CreatedOn),
+	}
+}
+
+// locationOutput is the JSON output of 'locations list'.
+type locationOutput struct {
+	ID             string     `json:"id"`
+	Name           string     `json:"name"`
+	OrganizationID string     `json:"organization_id"`
+	CreatedOn      *time.Time `json:"created_on,omitempty"`
+}
+
 // ListLocationsAction is the corresponding Action for 'locations list'.
 func ListLocationsAction(c *cli.Context) error {
 	client, err := newViamClient(c)
 	if err != nil {
 		return err
 	}
+	format, err := outputFormat(c)
+	if err != nil {
+		return err
+	}
 	orgStr := c.Args().First()
-	listLocations := func(orgID string) error {
-		locs, err := client.listLocations(orgID)
+	jsonOut := []locationOutput{}
+	// locations of every organization are listed with the name of their organization.
+	tbl := newTable("NAME", "ID").keepWhole("ID")
+	if orgStr == "" {
+		tbl = newTable("ORGANIZATION", "NAME", "ID").keepWhole("ID")
+	}
+	listLocations := func(org *apppb.Organization) error {
+		locs, err := client.listLocations(org.Id)
 		if err != nil {
 			return errors.Wrap(err, "could not list locations")
 		}
 		for _, loc := range locs {
-			printf(c.App.Writer, "\t%s (id: %s)", loc.Name, loc.Id)
+			if format == outputFormatJSON {
+				jsonOut = append(jsonOut, locationOutput{
+					ID:             loc.Id,
+					Name:           loc.Name,
+					OrganizationID: org.Id,
+					CreatedOn:      timestampOrNil(loc.CreatedOn),
+				})
+				continue
+			}
+			if orgStr == "" {
+				tbl.addRow(org.Name, loc.Name, loc.Id)
+			} else {
+				tbl.addRow(loc.Name, loc.Id)
+			}
 		}
 		return nil
 	}
+	opts := tableOptionsFor(c)
 	if orgStr == "" {
 		orgs, err := client.listOrganizations()
 		if err != nil {
 			return errors.Wrap(err, "could not list organizations")
 		}
-		for i, org := range orgs {
-			if i == 0 {
-				printf(c.App.Writer, "Locations for %q:", client.conf.Auth)
-			}
-			printf(c.App.Writer, "%s:", org.Name)
-			if err := listLocations(org.Id); err != nil {
+		if len(orgs) > 0 && format == outputFormatText && !opts.quiet {
+			printf(c.App.Writer, "Locations for %q:", client.conf.Auth)
+		}
+		for _, org := range orgs {
+			if err := listLocations(org); err != nil {
 				return err
 			}
 		}
-		return nil
+	} else if err := listLocations(&apppb.Organization{Id: orgStr}); err != nil {
+		return err
+	}
+	if format == outputFormatJSON {
+		return printJSON(c.App.Writer, jsonOut)
+	}
+	tbl.render(c.App.Writer, opts)
+	return nil
+}
+
+// CreateLocationAction is the corresponding Action for 'locations create'.
+func CreateLocationAction(c *cli.Context) error {
+	orgID, err := requiredStringFlagOrConfigFile(c, generalFlagOrgID)
+	if err != nil {
+		return err
+	}
+	client, err := newViamClient(c)
+	if err != nil {
+		return err
+	}
+	loc, err := client.createLocation(orgID, c.String(locationFlagName))
+	if err != nil {
+		return err
+	}
+	printf(c.App.Writer, "Created location %s (id: %s)", loc.Name, loc.Id)
+	return nil
+}
+
+// createLocation creates a location named name in the organization with the given ID.
+func (c *viamClient) createLocation(orgID, name string) (*apppb.Location, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, errors.Errorf("--%s must not be empty", locationFlagName)
+	}
+	if err := c.ensureLoggedIn(); err != nil {
+		return nil, err
 	}
-	return listLocations(orgStr)
+	resp, err := c.client.CreateLocation(c.c.Context, &apppb.CreateLocationRequest{OrganizationId: orgID, Name: name})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create location")
+	}
+	return resp.Location, nil
+}
+
+// DeleteLocationAction is the corresponding Action for 'locations delete'.
+func DeleteLocationAction(c *cli.Context) error {
+	locationID, err := requiredStringFlagOrConfigFile(c, generalFlagLocationID)
+	if err != nil {
+		return err
+	}
+	if err := confirmDestructiveAction(c, fmt.Sprintf("This will permanently delete the location with ID %s",
+		locationID)); err != nil {
+		return err
+	}
+	client, err := newViamClient(c)
+	if err != nil {
+		return err
+	}
+	if err := client.deleteLocation(locationID); err != nil {
+		return err
+	}
+	printf(c.App.Writer, "Deleted location %s", locationID)
+	return nil
+}
+
+// deleteLocation deletes the location with the given ID.
+func (c *viamClient) deleteLocation(locationID string) error {
+	if err := c.ensureLoggedIn(); err != nil {
+		return err
+	}
+	if _, err := c.client.DeleteLocation(c.c.Context, &apppb.DeleteLocationRequest{LocationId: locationID}); err != nil {
+		return errors.Wrap(err, "could not delete location")
+	}
+	return nil
+}
+
+// robotOutput is the JSON output of 'machines list'.
+type robotOutput struct {
+	ID           string     `json:"id"`
+	Name         string     `json:"name"`
+	LocationID   string     `json:"location_id"`
+	LocationName string     `json:"location_name,omitempty"`
+	LastAccess   *time.Time `json:"last_access,omitempty"`
+	CreatedOn    *time.Time `json:"created_on,omitempty"`
 }
 
 // ListRobotsAction is the corresponding Action for 'machines list'.
@@ -140,32 +312,143 @@ func ListRobotsAction(c *cli.Context) error {
 	if err != nil {
 		return err
 	}
-	orgStr := c.String(organizationFlag)
-	locStr := c.String(locationFlag)
-	robots, err := client.listRobots(orgStr, locStr)
+	return client.listRobotsAction(c)
+}
+
+func (c *viamClient) listRobotsAction(cCtx *cli.Context) error {
+	format, err := outputFormat(cCtx)
+	if err != nil {
+		return err
+	}
+	orgStr := stringFlagOrConfigFile(cCtx, organizationFlag)
+	locStr := stringFlagOrConfigFile(cCtx, locationFlag)
+	if cCtx.Bool(listRobotsFlagAll) {
+		if locStr != "" {
+			return errors.Errorf("cannot use --%s with --%s", listRobotsFlagAll, locationFlag)
+		}
+		return c.listAllRobotsAction(cCtx, orgStr, format)
+	}
+	robots, err := c.listRobots(orgStr, locStr)
 	if err != nil {
 		return errors.Wrap(err, "could not list machines")
 	}
 
-	if orgStr == "" || locStr == "" {
-		printf(c.App.Writer, "%s -> %s", client.selectedOrg.Name, client.selectedLoc.Name)
+	if format == outputFormatJSON {
+		out := make([]robotOutput, 0, len(robots))
+		for _, robot := range robots {
+			out = append(out, robotOutput{
+				ID:         robot.Id,
+				Name:       robot.Name,
+				LocationID: robot.Location,
+				LastAccess: timestampOrNil(robot.LastAccess),
+				CreatedOn:  timestampOrNil(robot.CreatedOn),
+			})
+		}
+		return printJSON(cCtx.App.Writer, out)
+	}
+
+	opts := tableOptionsFor(cCtx)
+	if (orgStr == "" || locStr == "") && !opts.quiet {
+		printf(cCtx.App.Writer, "%s -> %s", c.selectedOrg.Name, c.selectedLoc.Name)
 	}
 
+	tbl := newTable("NAME", "ID").keepWhole("ID")
 	for _, robot := range robots {
-		printf(c.App.Writer, "%s (id: %s)", robot.Name, robot.Id)
+		tbl.addRow(robot.Name, robot.Id)
 	}
+	tbl.render(cCtx.App.Writer, opts)
 	return nil
 }
 
+// listAllRobotsAction prints the machines of every location in the organization, sorted by location and then
+// machine name.
+func (c *viamClient) listAllRobotsAction(cCtx *cli.Context, orgStr, format string) error {
+	locs, err := c.listLocations(orgStr)
+	if err != nil {
+		return errors.Wrap(err, "could not list locations")
+	}
+	locs = append([]*apppb.Location{}, locs...)
+	sort.SliceStable(locs, func(i, j int) bool { return locs[i].Name < locs[j].Name })
+
+	out := []robotOutput{}
+	tbl := newTable("LOCATION", "NAME", "ID").keepWhole("ID")
+	// machines are requested one location at a time so no single response has to hold the whole organization.
+	for _, loc := range locs {
+		resp, err := c.client.ListRobots(c.c.Context, &apppb.ListRobotsRequest{LocationId: loc.Id})
+		if err != nil {
+			return errors.Wrapf(err, "could not list machines in location %q", loc.Name)
+		}
+		robots := resp.Robots
+		sort.SliceStable(robots, func(i, j int) bool { return robots[i].Name < robots[j].Name })
+		for _, robot := range robots {
+			if format == outputFormatJSON {
+				out = append(out, robotOutput{
+					ID:           robot.Id,
+					Name:         robot.Name,
+					LocationID:   loc.Id,
+					LocationName: loc.Name,
+					LastAccess:   timestampOrNil(robot.LastAccess),
+					CreatedOn:    timestampOrNil(robot.CreatedOn),
+				})
+				continue
+			}
+			tbl.addRow(loc.Name, robot.Name, robot.Id)
+		}
+	}
+	if format == outputFormatJSON {
+		return printJSON(cCtx.App.Writer, out)
+	}
+	opts := tableOptionsFor(cCtx)
+	if !opts.quiet {
+		printf(cCtx.App.Writer, "%s:", c.selectedOrg.Name)
+	}
+	tbl.render(cCtx.App.Writer, opts)
+	return nil
+}
+
+// machineStatusOutput is the JSON output of 'machines status'.
+type machineStatusOutput struct {
+	ID         string                    `json:"id"`
+	Name       string                    `json:"name"`
+	LastAccess *time.Time                `json:"last_access,omitempty"`
+	Parts      []machinePartStatusOutput `json:"parts"`
+}
+
+// machinePartStatusOutput is the JSON output of 'machines part status' and a CSV row of both it and
+// 'machines status'.
+type machinePartStatusOutput struct {
+	MachineID   string     `json:"machine_id"`
+	MachineName string     `json:"machine_name"`
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	MainPart    bool       `json:"main_part"`
+	LastAccess  *time.Time `json:"last_access,omitempty"`
+}
+
+func newMachinePartStatusOutput(robot *apppb.Robot, part *apppb.RobotPart) machinePartStatusOutput {
+	return machinePartStatusOutput{
+		MachineID:   robot.Id,
+		MachineName: robot.Name,
+		ID:          part.Id,
+		Name:        part.Name,
+		MainPart:    part.MainPart,
+		LastAccess:  timestampOrNil(part.LastAccess),
+	}
+}
+
 // RobotsStatusAction is the corresponding Action for 'machines status'.
 func RobotsStatusAction(c *cli.Context) error {
+	format, err := recordFormat(c)
+	if err != nil {
+		return err
+	}
 	client, err := newViamClient(c)
 	if err != nil {
 		return err
 	}
 
-	orgStr := c.String(organizationFlag)
-	locStr := c.String(locationFlag)
+	orgStr := stringFlagOrConfigFile(c, organizationFlag)
+	locStr := stringFlagOrConfigFile(c, locationFlag)
 	robot, err := client.robot(orgStr, locStr, c.String(machineFlag))
 	if err != nil {
 		return err
@@ -175,52 +458,66 @@ func RobotsStatusAction(c *cli.Context) error {
 		return errors.Wrap(err, "could not get machine parts")
 	}
 
-	if orgStr == "" || locStr == "" {
-		printf(c.App.Writer, "%s -> %s", client.selectedOrg.Name, client.selectedLoc.Name)
+	out := machineStatusOutput{
+		ID:         robot.Id,
+		Name:       robot.Name,
+		LastAccess: timestampOrNil(robot.LastAccess),
+		Parts:      make([]machinePartStatusOutput, 0, len(parts)),
 	}
-
-	printf(
-		c.App.Writer,
-		"ID: %s\nName: %s\nLast Access: %s (%s ago)",
-		robot.Id,
-		robot.Name,
-		robot.LastAccess.AsTime().Format(time.UnixDate),
-		time.Since(robot.LastAccess.AsTime()),
-	)
-
-	if len(parts) != 0 {
-		printf(c.App.Writer, "Parts:")
+	for _, part := range parts {
+		out.Parts = append(out.Parts, newMachinePartStatusOutput(robot, part))
 	}
-	for i, part := range parts {
-		name := part.Name
-		if part.MainPart {
-			name += " (main)"
+
+	return printRecords(c.App.Writer, format, out, out.Parts, func() {
+		if orgStr == "" || locStr == "" {
+			printf(c.App.Writer, "%s -> %s", client.selectedOrg.Name, client.selectedLoc.Name)
 		}
+
 		printf(
 			c.App.Writer,
-			"\tID: %s\n\tName: %s\n\tLast Access: %s (%s ago)",
-			part.Id,
-			name,
-			part.LastAccess.AsTime().Format(time.UnixDate),
-			time.Since(part.LastAccess.AsTime()),
+			"ID: %s\nName: %s\nLast Access: %s (%s ago)",
+			robot.Id,
+			robot.Name,
+			robot.LastAccess.AsTime().Format(time.UnixDate),
+			time.Since(robot.LastAccess.AsTime()),
 		)
-		if i != len(parts)-1 {
-			printf(c.App.Writer, "")
-		}
-	}
 
-	return nil
+		if len(parts) != 0 {
+			printf(c.App.Writer, "Parts:")
+		}
+		for i, part := range parts {
+			name := part.Name
+			if part.MainPart {
+				name += " (main)"
+			}
+			printf(
+				c.App.Writer,
+				"\tID: %s\n\tName: %s\n\tLast Access: %s (%s ago)",
+				part.Id,
+				name,
+				part.LastAccess.AsTime().Format(time.UnixDate),
+				time.Since(part.LastAccess.AsTime()),
+			)
+			if i != len(parts)-1 {
+				printf(c.App.Writer, "")
+			}
+		}
+	})
 }
 
 // RobotsLogsAction is the corresponding Action for 'machines logs'.
 func RobotsLogsAction(c *cli.Context) error {
+	filter, err := newRobotPartLogFilter(c)
+	if err != nil {
+		return err
+	}
 	client, err := newViamClient(c)
 	if err != nil {
 		return err
 	}
 
-	orgStr := c.String(organizationFlag)
-	locStr := c.String(locationFlag)
+	orgStr := stringFlagOrConfigFile(c, organizationFlag)
+	locStr := stringFlagOrConfigFile(c, locationFlag)
 	robotStr := c.String(machineFlag)
 	robot, err := client.robot(orgStr, locStr, robotStr)
 	if err != nil {
@@ -232,6 +529,14 @@ func RobotsLogsAction(c *cli.Context) error {
 		return errors.Wrap(err, "could not get machine parts")
 	}
 
+	if c.Bool(logsFlagTail) {
+		var header string
+		if orgStr == "" || locStr == "" || robotStr == "" {
+			header = fmt.Sprintf("%s -> %s -> %s", client.selectedOrg.Name, client.selectedLoc.Name, robot.Name)
+		}
+		return client.tailRobotLogs(parts, filter, header)
+	}
+
 	for i, part := range parts {
 		if i != 0 {
 			printf(c.App.Writer, "")
@@ -245,7 +550,7 @@ func RobotsLogsAction(c *cli.Context) error {
 		}
 		if err := client.printRobotPartLogs(
 			orgStr, locStr, robotStr, part.Id,
-			c.Bool(logsFlagErrors),
+			filter,
 			"\t",
 			header,
 		); err != nil {
@@ -258,13 +563,17 @@ func RobotsLogsAction(c *cli.Context) error {
 
 // RobotsPartStatusAction is the corresponding Action for 'machines part status'.
 func RobotsPartStatusAction(c *cli.Context) error {
+	format, err := recordFormat(c)
+	if err != nil {
+		return err
+	}
 	client, err := newViamClient(c)
 	if err != nil {
 		return err
 	}
 
-	orgStr := c.String(organizationFlag)
-	locStr := c.String(locationFlag)
+	orgStr := stringFlagOrConfigFile(c, organizationFlag)
+	locStr := stringFlagOrConfigFile(c, locationFlag)
 	robotStr := c.String(machineFlag)
 	robot, err := client.robot(orgStr, locStr, robotStr)
 	if err != nil {
@@ -276,35 +585,44 @@ func RobotsPartStatusAction(c *cli.Context) error {
 		return errors.Wrap(err, "could not get machine part")
 	}
 
-	if orgStr == "" || locStr == "" || robotStr == "" {
-		printf(c.App.Writer, "%s -> %s -> %s", client.selectedOrg.Name, client.selectedLoc.Name, robot.Name)
-	}
+	out := newMachinePartStatusOutput(robot, part)
+	return printRecords(c.App.Writer, format, out, []machinePartStatusOutput{out}, func() {
+		if orgStr == "" || locStr == "" || robotStr == "" {
+			printf(c.App.Writer, "%s -> %s -> %s", client.selectedOrg.Name, client.selectedLoc.Name, robot.Name)
+		}
+
+		printRobotPartStatus(c.App.Writer, part)
+	})
+}
 
+func printRobotPartStatus(w io.Writer, part *apppb.RobotPart) {
 	name := part.Name
 	if part.MainPart {
 		name += " (main)"
 	}
 	printf(
-		c.App.Writer,
+		w,
 		"ID: %s\nName: %s\nLast Access: %s (%s ago)",
 		part.Id,
 		name,
 		part.LastAccess.AsTime().Format(time.UnixDate),
 		time.Since(part.LastAccess.AsTime()),
 	)
-
-	return nil
 }
 
 // RobotsPartLogsAction is the corresponding Action for 'machines part logs'.
 func RobotsPartLogsAction(c *cli.Context) error {
+	filter, err := newRobotPartLogFilter(c)
+	if err != nil {
+		return err
+	}
 	client, err := newViamClient(c)
 	if err != nil {
 		return err
 	}
 
-	orgStr := c.String(organizationFlag)
-	locStr := c.String(locationFlag)
+	orgStr := stringFlagOrConfigFile(c, organizationFlag)
+	locStr := stringFlagOrConfigFile(c, locationFlag)
 	robotStr := c.String(machineFlag)
 	robot, err := client.robot(orgStr, locStr, robotStr)
 	if err != nil {
@@ -318,25 +636,119 @@ func RobotsPartLogsAction(c *cli.Context) error {
 	if c.Bool(logsFlagTail) {
 		return client.tailRobotPartLogs(
 			orgStr, locStr, robotStr, c.String(partFlag),
-			c.Bool(logsFlagErrors),
+			filter,
 			"",
 			header,
 		)
 	}
 	return client.printRobotPartLogs(
 		orgStr, locStr, robotStr, c.String(partFlag),
-		c.Bool(logsFlagErrors),
+		filter,
 		"",
 		header,
 	)
 }
 
+// RobotsPartRestartAction is the corresponding Action for 'machines part restart'.
+func RobotsPartRestartAction(c *cli.Context) error {
+	client, err := newViamClient(c)
+	if err != nil {
+		return err
+	}
+
+	return client.restartRobotPart(
+		stringFlagOrConfigFile(c, organizationFlag),
+		stringFlagOrConfigFile(c, locationFlag),
+		c.String(machineFlag),
+		c.String(partFlag),
+		c.Bool(restartFlagWait),
+	)
+}
+
+const (
+	// partOfflineThreshold is how long a part can go without accessing app before it is considered offline.
+	partOfflineThreshold = time.Minute
+	// partRestartDownThreshold is how long a part must go without accessing app while waiting for it to
+	// restart for it to be considered shut down. Running parts check whether they need to restart every
+	// few seconds, so they access app more often than this.
+	partRestartDownThreshold = 10 * time.Second
+	// partRestartPollInterval is how often a part's status is checked while waiting for it to restart.
+	partRestartPollInterval = 2 * time.Second
+	// partRestartTimeout is how long to wait for a part to come back online after requesting a restart.
+	partRestartTimeout = 5 * time.Minute
+)
+
+// restartRobotPart marks the part for restart and prints its status. Parts check for restart requests
+// periodically, so if wait is true this blocks until the part has been seen to shut down, by going at least
+// partRestartDownThreshold without accessing app, and has then accessed app again.
+func (c *viamClient) restartRobotPart(orgStr, locStr, robotStr, partStr string, wait bool) error {
+	part, err := c.robotPart(orgStr, locStr, robotStr, partStr)
+	if err != nil {
+		return errors.Wrap(err, "could not get machine part")
+	}
+	if lastAccess := part.LastAccess.AsTime(); time.Since(lastAccess) > partOfflineThreshold {
+		return errors.Errorf("machine part %q is offline (last access %s ago) and cannot be restarted",
+			part.Name, time.Since(lastAccess).Round(time.Second))
+	}
+
+	if _, err := c.client.MarkPartForRestart(c.c.Context, &apppb.MarkPartForRestartRequest{PartId: part.Id}); err != nil {
+		return errors.Wrap(err, "could not restart machine part")
+	}
+	printf(c.c.App.Writer, "Requested restart of machine part %q", part.Name)
+	if !wait {
+		printRobotPartStatus(c.c.App.Writer, part)
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(c.c.Context, partRestartTimeout)
+	defer cancel()
+	ticker := time.NewTicker(partRestartPollInterval)
+	defer ticker.Stop()
+	// the part accesses app when it picks up the restart request, so only an access after it was seen to
+	// shut down shows that it restarted.
+	var shutDownAt time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			if shutDownAt.IsZero() {
+				return errors.Errorf("timed out waiting for machine part %q to restart: it never stopped accessing app", part.Name)
+			}
+			return errors.Errorf("timed out waiting for machine part %q to come back online after shutting down", part.Name)
+		case <-ticker.C:
+		}
+		resp, err := c.client.GetRobotPart(ctx, &apppb.GetRobotPartRequest{Id: part.Id})
+		if err != nil {
+			return errors.Wrap(err, "could not get machine part")
+		}
+		lastAccess := resp.GetPart().GetLastAccess().AsTime()
+		switch {
+		case shutDownAt.IsZero():
+			if time.Since(lastAccess) > partRestartDownThreshold {
+				shutDownAt = lastAccess
+				printf(c.c.App.Writer, "Machine part %q has shut down, waiting for it to come back online", part.Name)
+			}
+		case lastAccess.After(shutDownAt):
+			printf(c.c.App.Writer, "Machine part %q is back online", part.Name)
+			printRobotPartStatus(c.c.App.Writer, resp.GetPart())
+			return nil
+		}
+	}
+}
+
 // RobotsPartRunAction is the corresponding Action for 'machines part run'.
 func RobotsPartRunAction(c *cli.Context) error {
 	svcMethod := c.Args().First()
 	if svcMethod == "" {
 		return errors.New("service method required")
 	}
+	orgStr, err := requiredStringFlagOrConfigFile(c, organizationFlag)
+	if err != nil {
+		return err
+	}
+	locStr, err := requiredStringFlagOrConfigFile(c, locationFlag)
+	if err != nil {
+		return err
+	}
 
 	client, err := newViamClient(c)
 	if err != nil {
@@ -350,13 +762,15 @@ func RobotsPartRunAction(c *cli.Context) error {
 	}
 
 	return client.runRobotPartCommand(
-		c.String(organizationFlag),
-		c.String(locationFlag),
+		orgStr,
+		locStr,
 		c.String(machineFlag),
 		c.String(partFlag),
 		svcMethod,
 		c.String(runFlagData),
 		c.Duration(runFlagStream),
+		c.Path(runFlagOutputFile),
+		c.Bool(runFlagTruncate),
 		c.Bool(debugFlag),
 		logger,
 	)
@@ -378,46 +792,107 @@ func RobotsPartShellAction(c *cli.Context) error {
 	}
 
 	return client.startRobotPartShell(
-		c.String(organizationFlag),
-		c.String(locationFlag),
+		stringFlagOrConfigFile(c, organizationFlag),
+		stringFlagOrConfigFile(c, locationFlag),
+		c.String(machineFlag),
+		c.String(partFlag),
+		c.Bool(debugFlag),
+		logger,
+	)
+}
+
+// RobotsPartCopyAction is the corresponding Action for 'machines part cp'.
+func RobotsPartCopyAction(c *cli.Context) error {
+	if c.Args().Len() != 2 {
+		return errors.New("expected a source and a destination")
+	}
+	src, dst := c.Args().Get(0), c.Args().Get(1)
+	toPart := strings.HasPrefix(dst, shellCopyPartPrefix)
+	if toPart == strings.HasPrefix(src, shellCopyPartPrefix) {
+		return errors.Errorf("exactly one of the source and destination must be a path on the machine part starting with %q",
+			shellCopyPartPrefix)
+	}
+	if strings.ContainsAny(src+dst, "\r\n") {
+		return errors.New("paths must not contain line breaks")
+	}
+
+	client, err := newViamClient(c)
+	if err != nil {
+		return err
+	}
+
+	// Create logger based on presence of debugFlag.
+	logger := logging.FromZapCompatible(zap.NewNop().Sugar())
+	if c.Bool(debugFlag) {
+		logger = logging.NewDebugLogger("cli")
+	}
+
+	shellSvc, closeClient, err := client.dialShellService(
+		stringFlagOrConfigFile(c, organizationFlag),
+		stringFlagOrConfigFile(c, locationFlag),
 		c.String(machineFlag),
 		c.String(partFlag),
 		c.Bool(debugFlag),
 		logger,
 	)
+	if err != nil {
+		return err
+	}
+	defer closeClient()
+
+	session, err := newShellSession(c.Context, shellSvc)
+	if err != nil {
+		return errors.Wrap(err, "could not start shell on machine part")
+	}
+	defer utils.UncheckedErrorFunc(session.Close)
+
+	recursive := c.Bool(partCopyFlagRecursive)
+	if toPart {
+		return session.copyToPart(c.Context, src, strings.TrimPrefix(dst, shellCopyPartPrefix), recursive, c.App.Writer)
+	}
+	return session.copyFromPart(c.Context, strings.TrimPrefix(src, shellCopyPartPrefix), dst, recursive, c.App.Writer)
 }
 
-// checkUpdateResponse holds the values used to hold release information.
+// getLatestReleaseResponse holds the values used to hold release information.
 type getLatestReleaseResponse struct {
 	Name       string `json:"name"`
 	TagName    string `json:"tag_name"`
 	TarballURL string `json:"tarball_url"`
+	HTMLURL    string `json:"html_url"`
 }
 
-func getLatestReleaseVersion() (string, error) {
+func getLatestRelease() (*getLatestReleaseResponse, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
 
-	resp := getLatestReleaseResponse{}
-
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rdkReleaseURL, nil)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	client := http.DefaultClient
 	res, err := client.Do(req)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
+	defer utils.UncheckedErrorFunc(res.Body.Close)
 
-	err = json.NewDecoder(res.Body).Decode(&resp)
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unexpected status %s", res.Status)
+	}
+	resp := getLatestReleaseResponse{}
+	if err := json.NewDecoder(res.Body).Decode(&resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func getLatestReleaseVersion() (string, error) {
+	resp, err := getLatestRelease()
 	if err != nil {
 		return "", err
 	}
-
-	defer utils.UncheckedError(res.Body.Close())
-	return resp.TagName, err
+	return resp.TagName, nil
 }
 
 // CheckUpdateAction is the corresponding Action for 'check-update'.
@@ -444,13 +919,14 @@ func CheckUpdateAction(c *cli.Context) error {
 		return nil
 	}
 
-	conf, err := configFromCache()
+	profile := c.String(profileFlag)
+	conf, err := configFromCache(profile)
 	if err != nil {
 		if !os.IsNotExist(err) {
 			utils.UncheckedError(err)
 			return nil
 		}
-		conf = &config{}
+		conf = &config{profile: profile}
 	}
 
 	var lastCheck time.Time
@@ -510,6 +986,80 @@ func CheckUpdateAction(c *cli.Context) error {
 	return nil
 }
 
+// latestVersion returns the latest released CLI version and the URL it can be downloaded from. The result is
+// cached in the profile's config for latestVersionCacheDuration.
+func latestVersion(c *cli.Context) (*semver.Version, string, error) {
+	profile := c.String(profileFlag)
+	conf, err := configFromCache(profile)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return nil, "", err
+		}
+		conf = &config{profile: profile}
+	}
+
+	if conf.LatestVersion != "" && conf.LatestVersionURL != "" && conf.LatestVersionCheckedAt != "" {
+		checkedAt, err := time.Parse(time.RFC3339, conf.LatestVersionCheckedAt)
+		if err == nil && time.Since(checkedAt) < latestVersionCacheDuration {
+			if version, err := semver.NewVersion(conf.LatestVersion); err == nil {
+				return version, conf.LatestVersionURL, nil
+			}
+		}
+	}
+
+	release, err := getLatestRelease()
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to get latest release information")
+	}
+	version, err := semver.NewVersion(release.TagName)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to parse latest version")
+	}
+	downloadURL := release.HTMLURL
+	if downloadURL == "" {
+		downloadURL = "https://github.com/viamrobotics/rdk/releases/tag/" + release.TagName
+	}
+
+	conf.LatestVersion = version.String()
+	conf.LatestVersionURL = downloadURL
+	conf.LatestVersionCheckedAt = time.Now().Format(time.RFC3339)
+	utils.UncheckedError(storeConfigToCache(conf))
+	return version, downloadURL, nil
+}
+
+// checkVersion reports whether a newer CLI than this one has been released, returning an error if so.
+func checkVersion(c *cli.Context) error {
+	quiet := c.Bool(quietFlag)
+	latest, downloadURL, err := latestVersion(c)
+	if err != nil {
+		return err
+	}
+
+	appVersion := rconfig.Version
+	if appVersion == "" {
+		if !quiet {
+			printf(c.App.Writer, "Development build; the latest release is %s (%s)", latest.Original(), downloadURL)
+		}
+		return nil
+	}
+	localVersion, err := semver.NewVersion(appVersion)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse compiled version")
+	}
+
+	if !localVersion.LessThan(latest) {
+		if !quiet {
+			printf(c.App.Writer, "Up to date: %s is the latest release", localVersion.Original())
+		}
+		return nil
+	}
+	if !quiet {
+		printf(c.App.Writer, "Update available: %s (current %s). Download it from %s",
+			latest.Original(), localVersion.Original(), downloadURL)
+	}
+	return errors.Errorf("CLI version %s is out of date, latest is %s", localVersion.Original(), latest.Original())
+}
+
 // VersionAction is the corresponding Action for 'version'.
 func VersionAction(c *cli.Context) error {
 	info, ok := debug.ReadBuildInfo()
@@ -544,6 +1094,9 @@ func VersionAction(c *cli.Context) error {
 		appVersion = "(dev)"
 	}
 	printf(c.App.Writer, "Version %s Git=%s API=%s", appVersion, version, apiVersion)
+	if c.Bool(versionFlagCheck) {
+		return checkVersion(c)
+	}
 	return nil
 }
 
@@ -602,25 +1155,41 @@ func isProdBaseURL(baseURL *url.URL) bool {
 }
 
 func newViamClient(c *cli.Context) (*viamClient, error) {
-	conf, err := configFromCache()
-	if err != nil {
-		if !os.IsNotExist(err) {
+	var fileConf *cliConfigFile
+	if path := c.String(configFlag); path != "" {
+		var err error
+		if fileConf, err = readCLIConfigFile(path); err != nil {
 			return nil, err
 		}
-		conf = &config{}
 	}
 
-	// If base URL was not specified, assume cached base URL. If no base URL is
-	// cached, assume default base URL.
+	// See configForBaseURL for which base URL and credentials are used.
 	baseURLArg := c.String(baseURLFlag)
+	if baseURLArg == "" && fileConf != nil && fileConf.Credentials != nil {
+		baseURLArg = fileConf.Credentials.BaseURL
+	}
+	conf, err := configForBaseURL(c.String(profileFlag), baseURLArg)
+	if err != nil {
+		return nil, err
+	}
+	// an api key in the --config file or the environment is used for this command only, in place of the
+	// cached credentials.
+	overrideKey := fileConf.apiKey()
+	if overrideKey == nil {
+		if overrideKey, err = apiKeyFromEnv(); err != nil {
+			return nil, err
+		}
+	}
+	if overrideKey != nil {
+		conf.overrideAuth = overrideKey
+		conf.cachedAuth = conf.Auth
+		conf.Auth = overrideKey
+	}
 	switch {
 	case conf.BaseURL == "" && baseURLArg == "":
 		conf.BaseURL = defaultBaseURL
-	case conf.BaseURL == "" && baseURLArg != "":
+	case conf.BaseURL == "":
 		conf.BaseURL = baseURLArg
-	case baseURLArg != "" && conf.BaseURL != "" && conf.BaseURL != baseURLArg:
-		return nil, fmt.Errorf("cached base URL for this session is %q. "+
-			"Please logout and login again to use provided base URL %q", conf.BaseURL, baseURLArg)
 	}
 
 	if conf.BaseURL != defaultBaseURL {
@@ -630,6 +1199,9 @@ func newViamClient(c *cli.Context) (*viamClient, error) {
 	if err != nil {
 		return nil, err
 	}
+	if timeout := c.Duration(timeoutFlag); timeout > 0 {
+		rpcOpts = append(rpcOpts, rpc.WithUnaryClientInterceptor(perRequestTimeoutInterceptor(timeout)))
+	}
 
 	var authFlow *authFlow
 	disableBrowserOpen := c.Bool(loginFlagDisableBrowser)
@@ -905,20 +1477,101 @@ func (c *viamClient) robotPart(orgStr, locStr, robotStr, partStr string) (*apppb
 	return nil, errors.Errorf("no machine part found for machine: %q part: %q", robotStr, partStr)
 }
 
-func (c *viamClient) robotPartLogs(orgStr, locStr, robotStr, partStr string, errorsOnly bool) ([]*commonpb.LogEntry, error) {
+// robotPartLogFilter selects which log entries of a part are shown.
+type robotPartLogFilter struct {
+	errorsOnly bool
+	// minLevel is the lowest level of the entries shown.
+	minLevel zapcore.Level
+	// since, if set, is the time of the oldest entry shown.
+	since time.Time
+	// pattern, if set, must match the message of the entries shown.
+	pattern *regexp.Regexp
+}
+
+// newRobotPartLogFilter returns the log filter requested with the logs flags.
+func newRobotPartLogFilter(c *cli.Context) (robotPartLogFilter, error) {
+	filter := robotPartLogFilter{errorsOnly: c.Bool(logsFlagErrors), minLevel: zapcore.DebugLevel}
+	if levelStr := c.String(logsFlagLevel); levelStr != "" {
+		level, err := logging.LevelFromString(levelStr)
+		if err != nil {
+			return robotPartLogFilter{}, errors.Errorf("%s must be debug, info, warn or error, got %q", logsFlagLevel, levelStr)
+		}
+		filter.minLevel = level.AsZap()
+	}
+	if since := c.Duration(logsFlagSince); since != 0 {
+		if since < 0 {
+			return robotPartLogFilter{}, errors.Errorf("%s must be positive, got %s", logsFlagSince, since)
+		}
+		filter.since = time.Now().Add(-since)
+	}
+	if grep := c.String(logsFlagGrep); grep != "" {
+		pattern, err := regexp.Compile(grep)
+		if err != nil {
+			return robotPartLogFilter{}, errors.Wrapf(err, "invalid %s pattern", logsFlagGrep)
+		}
+		filter.pattern = pattern
+	}
+	return filter, nil
+}
+
+// levels returns the levels to request from app, or nil for all of them.
+func (f robotPartLogFilter) levels() []string {
+	if f.minLevel <= zapcore.DebugLevel {
+		return nil
+	}
+	var levels []string
+	for level := f.minLevel; level <= zapcore.FatalLevel; level++ {
+		levels = append(levels, level.String())
+	}
+	return levels
+}
+
+// matches returns whether entry should be shown. Entries with a level that cannot be parsed are shown.
+func (f robotPartLogFilter) matches(entry *commonpb.LogEntry) bool {
+	var level zapcore.Level
+	if err := level.UnmarshalText([]byte(entry.Level)); err == nil && level < f.minLevel {
+		return false
+	}
+	if !f.since.IsZero() && entry.Time.AsTime().Before(f.since) {
+		return false
+	}
+	return f.pattern == nil || f.pattern.MatchString(entry.Message)
+}
+
+func (c *viamClient) robotPartLogs(orgStr, locStr, robotStr, partStr string, filter robotPartLogFilter) ([]*commonpb.LogEntry, error) {
 	part, err := c.robotPart(orgStr, locStr, robotStr, partStr)
 	if err != nil {
 		return nil, err
 	}
-	resp, err := c.client.GetRobotPartLogs(c.c.Context, &apppb.GetRobotPartLogsRequest{
+	req := &apppb.GetRobotPartLogsRequest{
 		Id:         part.Id,
-		ErrorsOnly: errorsOnly,
-	})
-	if err != nil {
-		return nil, err
+		ErrorsOnly: filter.errorsOnly,
+		Levels:     filter.levels(),
 	}
 
-	return resp.Logs, nil
+	var logs []*commonpb.LogEntry
+	for {
+		resp, err := c.client.GetRobotPartLogs(c.c.Context, req)
+		if err != nil {
+			return nil, err
+		}
+		var recent bool
+		for _, entry := range resp.Logs {
+			if filter.since.IsZero() || !entry.Time.AsTime().Before(filter.since) {
+				recent = true
+			}
+			if filter.matches(entry) {
+				logs = append(logs, entry)
+			}
+		}
+		// only the most recent page is shown unless since asks for more, in which case keep going back
+		// until a page is entirely older than it.
+		if filter.since.IsZero() || !recent || resp.NextPageToken == "" {
+			return logs, nil
+		}
+		pageToken := resp.NextPageToken
+		req.PageToken = &pageToken
+	}
 }
 
 func (c *viamClient) robotParts(orgStr, locStr, robotStr string) ([]*apppb.RobotPart, error) {
@@ -952,8 +1605,12 @@ func (c *viamClient) printRobotPartLogsInner(logs []*commonpb.LogEntry, indent s
 	}
 }
 
-func (c *viamClient) printRobotPartLogs(orgStr, locStr, robotStr, partStr string, errorsOnly bool, indent, header string) error {
-	logs, err := c.robotPartLogs(orgStr, locStr, robotStr, partStr, errorsOnly)
+func (c *viamClient) printRobotPartLogs(
+	orgStr, locStr, robotStr, partStr string,
+	filter robotPartLogFilter,
+	indent, header string,
+) error {
+	logs, err := c.robotPartLogs(orgStr, locStr, robotStr, partStr, filter)
 	if err != nil {
 		return err
 	}
@@ -970,14 +1627,18 @@ func (c *viamClient) printRobotPartLogs(orgStr, locStr, robotStr, partStr string
 }
 
 // tailRobotPartLogs tails and prints logs for the given robot part.
-func (c *viamClient) tailRobotPartLogs(orgStr, locStr, robotStr, partStr string, errorsOnly bool, indent, header string) error {
+func (c *viamClient) tailRobotPartLogs(
+	orgStr, locStr, robotStr, partStr string,
+	filter robotPartLogFilter,
+	indent, header string,
+) error {
 	part, err := c.robotPart(orgStr, locStr, robotStr, partStr)
 	if err != nil {
 		return err
 	}
 	tailClient, err := c.client.TailRobotPartLogs(c.c.Context, &apppb.TailRobotPartLogsRequest{
 		Id:         part.Id,
-		ErrorsOnly: errorsOnly,
+		ErrorsOnly: filter.errorsOnly,
 	})
 	if err != nil {
 		return err
@@ -995,7 +1656,117 @@ func (c *viamClient) tailRobotPartLogs(orgStr, locStr, robotStr, partStr string,
 			}
 			return err
 		}
-		c.printRobotPartLogsInner(resp.Logs, indent)
+		logs := make([]*commonpb.LogEntry, 0, len(resp.Logs))
+		for _, entry := range resp.Logs {
+			if filter.matches(entry) {
+				logs = append(logs, entry)
+			}
+		}
+		c.printRobotPartLogsInner(logs, indent)
+	}
+}
+
+// tailRobotLogs follows the logs of all the given parts of a machine, printing each entry prefixed with the
+// name of its part, until interrupted. Streams that drop after connecting are reconnected.
+func (c *viamClient) tailRobotLogs(parts []*apppb.RobotPart, filter robotPartLogFilter, header string) error {
+	ctx, stop := signal.NotifyContext(c.c.Context, os.Interrupt)
+	defer stop()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if header != "" {
+		printf(c.c.App.Writer, header)
+	}
+
+	var (
+		wg      sync.WaitGroup
+		printMu sync.Mutex
+		errMu   sync.Mutex
+		errs    error
+	)
+	for _, part := range parts {
+		part := part
+		wg.Add(1)
+		utils.PanicCapturingGo(func() {
+			defer wg.Done()
+			indent := part.Name + "\t"
+			err := c.followRobotPartLogs(ctx, part, filter, func(logs []*commonpb.LogEntry) {
+				printMu.Lock()
+				defer printMu.Unlock()
+				c.printRobotPartLogsInner(logs, indent)
+			}, func(delay time.Duration, err error) {
+				printMu.Lock()
+				defer printMu.Unlock()
+				warningf(c.c.App.ErrWriter, "log stream of part %q dropped, reconnecting in %s: %v", part.Name, delay, err)
+			})
+			if err != nil {
+				errMu.Lock()
+				errs = multierr.Append(errs, errors.Wrapf(err, "could not follow logs of part %q", part.Name))
+				errMu.Unlock()
+				cancel()
+			}
+		})
+	}
+	wg.Wait()
+	return errs
+}
+
+// minLogStreamReconnectDelay is how long to wait before first reconnecting a dropped log stream. It is a
+// variable so that tests can shorten it.
+var minLogStreamReconnectDelay = time.Second
+
+const maxLogStreamReconnectDelay = 30 * time.Second
+
+// followRobotPartLogs passes the matching entries of each batch of logs streamed from part to onLogs until ctx
+// is done. If the stream drops after it was first opened, onDropped is called and it is reopened with
+// exponential backoff.
+func (c *viamClient) followRobotPartLogs(
+	ctx context.Context,
+	part *apppb.RobotPart,
+	filter robotPartLogFilter,
+	onLogs func([]*commonpb.LogEntry),
+	onDropped func(delay time.Duration, err error),
+) error {
+	var connected bool
+	delay := minLogStreamReconnectDelay
+	for {
+		tailClient, err := c.client.TailRobotPartLogs(ctx, &apppb.TailRobotPartLogsRequest{
+			Id:         part.Id,
+			ErrorsOnly: filter.errorsOnly,
+		})
+		if err == nil {
+			connected = true
+			for {
+				var resp *apppb.TailRobotPartLogsResponse
+				resp, err = tailClient.Recv()
+				if err != nil {
+					break
+				}
+				delay = minLogStreamReconnectDelay
+				logs := make([]*commonpb.LogEntry, 0, len(resp.Logs))
+				for _, entry := range resp.Logs {
+					if filter.matches(entry) {
+						logs = append(logs, entry)
+					}
+				}
+				onLogs(logs)
+			}
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		if !connected {
+			return err
+		}
+
+		onDropped(delay, err)
+		if !utils.SelectContextOrWait(ctx, delay) {
+			return nil
+		}
+		delay *= 2
+		if delay > maxLogStreamReconnectDelay {
+			delay = maxLogStreamReconnectDelay
+		}
 	}
 }
 
@@ -1003,9 +1774,35 @@ func (c *viamClient) runRobotPartCommand(
 	orgStr, locStr, robotStr, partStr string,
 	svcMethod, data string,
 	streamDur time.Duration,
+	outputPath string,
+	truncate bool,
 	debug bool,
 	logger logging.Logger,
 ) error {
+	ctx := c.c.Context
+	var output *os.File
+	if outputPath != "" {
+		flags := os.O_WRONLY | os.O_CREATE | os.O_APPEND
+		if truncate {
+			flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+		}
+		//nolint:gosec
+		f, err := os.OpenFile(outputPath, flags, 0o600)
+		if err != nil {
+			return errors.Wrap(err, "could not open output file")
+		}
+		output = f
+		defer func() {
+			utils.UncheckedError(output.Sync())
+			utils.UncheckedError(output.Close())
+		}()
+
+		// stop on an interrupt so that the output file is closed cleanly.
+		var stop context.CancelFunc
+		ctx, stop = signal.NotifyContext(ctx, os.Interrupt)
+		defer stop()
+	}
+
 	dialCtx, fqdn, rpcOpts, err := c.prepareDial(orgStr, locStr, robotStr, partStr, debug)
 	if err != nil {
 		return err
@@ -1045,18 +1842,31 @@ func (c *viamClient) runRobotPartCommand(
 			Formatter:      formatter,
 			VerbosityLevel: 0,
 		}
+		var handler grpcurl.InvocationEventHandler = h
+		var outputHandler *runOutputEventHandler
+		if output != nil {
+			h.Out = io.Discard
+			outputHandler = &runOutputEventHandler{DefaultEventHandler: h, out: output, now: time.Now}
+			handler = outputHandler
+		}
 
 		if err := grpcurl.InvokeRPC(
-			c.c.Context,
+			ctx,
 			descSource,
 			conn,
 			svcMethod,
 			nil,
-			h,
+			handler,
 			rf.Next,
 		); err != nil {
+			if output != nil && errors.Is(ctx.Err(), context.Canceled) {
+				return false, nil
+			}
 			return false, err
 		}
+		if outputHandler != nil && outputHandler.err != nil {
+			return false, errors.Wrap(outputHandler.err, "could not write to output file")
+		}
 
 		if h.Status.Code() != codes.OK {
 			grpcurl.PrintStatus(c.c.App.ErrWriter, h.Status, formatter)
@@ -1076,7 +1886,7 @@ func (c *viamClient) runRobotPartCommand(
 	defer ticker.Stop()
 
 	for {
-		if err := c.c.Context.Err(); err != nil {
+		if err := ctx.Err(); err != nil {
 			if errors.Is(err, context.Canceled) {
 				return nil
 			}
@@ -1084,7 +1894,7 @@ func (c *viamClient) runRobotPartCommand(
 		}
 
 		select {
-		case <-c.c.Context.Done():
+		case <-ctx.Done():
 			return nil
 		case <-ticker.C:
 			if ok, err := invoke(); err != nil {
@@ -1096,14 +1906,50 @@ func (c *viamClient) runRobotPartCommand(
 	}
 }
 
-func (c *viamClient) startRobotPartShell(
+// runOutputEventHandler writes each response of a 'machines part run' invocation to out as a line of JSON
+// holding the time the response was received and the response itself.
+type runOutputEventHandler struct {
+	*grpcurl.DefaultEventHandler
+	out io.Writer
+	now func() time.Time
+	// err is the first error encountered formatting or writing a response.
+	err error
+}
+
+type runOutputRecord struct {
+	Time     time.Time       `json:"time"`
+	Response json.RawMessage `json:"response"`
+}
+
+func (h *runOutputEventHandler) OnReceiveResponse(resp proto.Message) {
+	h.NumResponses++
+	if h.err != nil {
+		return
+	}
+	respStr, err := h.Formatter(resp)
+	if err != nil {
+		h.err = errors.Wrapf(err, "failed to format response message %d", h.NumResponses)
+		return
+	}
+	// marshaling compacts the formatted response onto a single line.
+	line, err := json.Marshal(runOutputRecord{Time: h.now().UTC(), Response: json.RawMessage(respStr)})
+	if err != nil {
+		h.err = err
+		return
+	}
+	_, h.err = h.out.Write(append(line, '\n'))
+}
+
+// dialShellService connects to the machine part and returns its first shell service along with a function
+// closing the connection.
+func (c *viamClient) dialShellService(
 	orgStr, locStr, robotStr, partStr string,
 	debug bool,
 	logger logging.Logger,
-) error {
+) (shell.Service, func(), error) {
 	dialCtx, fqdn, rpcOpts, err := c.prepareDial(orgStr, locStr, robotStr, partStr, debug)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
 	if debug {
@@ -1111,12 +1957,11 @@ func (c *viamClient) startRobotPartShell(
 	}
 	robotClient, err := client.New(dialCtx, fqdn, logger, client.WithDialOptions(rpcOpts...))
 	if err != nil {
-		return errors.Wrap(err, "could not connect to machine part")
+		return nil, nil, errors.Wrap(err, "could not connect to machine part")
 	}
-
-	defer func() {
+	closeClient := func() {
 		utils.UncheckedError(robotClient.Close(c.c.Context))
-	}()
+	}
 
 	// Returns the first shell service found in the robot resources
 	var found *resource.Name
@@ -1128,18 +1973,34 @@ func (c *viamClient) startRobotPartShell(
 		}
 	}
 	if found == nil {
-		return errors.New("shell service is not enabled on this machine part")
+		closeClient()
+		return nil, nil, errors.New("shell service is not enabled on this machine part")
 	}
 
 	shellRes, err := robotClient.ResourceByName(*found)
 	if err != nil {
-		return errors.Wrap(err, "could not get shell service from machine part")
+		closeClient()
+		return nil, nil, errors.Wrap(err, "could not get shell service from machine part")
 	}
 
 	shellSvc, ok := shellRes.(shell.Service)
 	if !ok {
-		return errors.New("could not get shell service from machine part")
+		closeClient()
+		return nil, nil, errors.New("could not get shell service from machine part")
+	}
+	return shellSvc, closeClient, nil
+}
+
+func (c *viamClient) startRobotPartShell(
+	orgStr, locStr, robotStr, partStr string,
+	debug bool,
+	logger logging.Logger,
+) error {
+	shellSvc, closeClient, err := c.dialShellService(orgStr, locStr, robotStr, partStr, debug, logger)
+	if err != nil {
+		return err
 	}
+	defer closeClient()
 
 	input, output, err := shellSvc.Shell(c.c.Context, map[string]interface{}{})
 	if err != nil {
@@ -1,21 +1,35 @@
 package cli
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/fullstorydev/grpcurl"
 	"github.com/urfave/cli/v2"
 	buildpb "go.viam.com/api/app/build/v1"
 	datapb "go.viam.com/api/app/data/v1"
 	apppb "go.viam.com/api/app/v1"
+	commonpb "go.viam.com/api/common/v1"
 	"go.viam.com/test"
 	"go.viam.com/utils/protoutils"
+	"go.viam.com/utils/testutils"
 	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
+	rconfig "go.viam.com/rdk/config"
 	"go.viam.com/rdk/testutils/inject"
 	"go.viam.com/rdk/utils"
 )
@@ -104,11 +118,216 @@ func TestListOrganizationsAction(t *testing.T) {
 
 	test.That(t, ac.listOrganizationsAction(cCtx), test.ShouldBeNil)
 	test.That(t, len(errOut.messages), test.ShouldEqual, 0)
-	test.That(t, len(out.messages), test.ShouldEqual, 3)
+	test.That(t, len(out.messages), test.ShouldEqual, 4)
 	test.That(t, out.messages[0], test.ShouldEqual, fmt.Sprintf("Organizations for %q:\n", testEmail))
-	test.That(t, out.messages[1], test.ShouldContainSubstring, "jedi")
-	test.That(t, out.messages[1], test.ShouldContainSubstring, "anakin")
-	test.That(t, out.messages[2], test.ShouldContainSubstring, "mandalorians")
+	test.That(t, out.messages[1], test.ShouldStartWith, "NAME")
+	test.That(t, out.messages[2], test.ShouldContainSubstring, "jedi")
+	test.That(t, out.messages[2], test.ShouldContainSubstring, "anakin")
+	test.That(t, out.messages[3], test.ShouldContainSubstring, "mandalorians")
+}
+
+func TestListOrganizationsActionJSON(t *testing.T) {
+	listOrganizationsFunc := func(ctx context.Context, in *apppb.ListOrganizationsRequest,
+		opts ...grpc.CallOption,
+	) (*apppb.ListOrganizationsResponse, error) {
+		orgs := []*apppb.Organization{
+			{Id: "1", Name: "jedi", PublicNamespace: "anakin", CreatedOn: timestamppb.New(time.Unix(0, 0))},
+			{Id: "2", Name: "mandalorians"},
+		}
+		return &apppb.ListOrganizationsResponse{Organizations: orgs}, nil
+	}
+	asc := &inject.AppServiceClient{
+		ListOrganizationsFunc: listOrganizationsFunc,
+	}
+	cCtx, ac, out, errOut := setup(asc, nil, nil, &map[string]string{outputFlag: outputFormatJSON}, "token")
+
+	test.That(t, ac.listOrganizationsAction(cCtx), test.ShouldBeNil)
+	test.That(t, len(errOut.messages), test.ShouldEqual, 0)
+	test.That(t, len(out.messages), test.ShouldEqual, 1)
+	var orgs []organizationOutput
+	test.That(t, json.Unmarshal([]byte(out.messages[0]), &orgs), test.ShouldBeNil)
+	test.That(t, orgs, test.ShouldHaveLength, 2)
+	test.That(t, orgs[0].ID, test.ShouldEqual, "1")
+	test.That(t, orgs[0].PublicNamespace, test.ShouldEqual, "anakin")
+	test.That(t, orgs[0].CreatedOn.Equal(time.Unix(0, 0)), test.ShouldBeTrue)
+	test.That(t, orgs[1].Name, test.ShouldEqual, "mandalorians")
+	test.That(t, orgs[1].CreatedOn, test.ShouldBeNil)
+
+	cCtx, ac, _, _ = setup(asc, nil, nil, &map[string]string{outputFlag: "yaml"}, "token")
+	test.That(t, ac.listOrganizationsAction(cCtx), test.ShouldNotBeNil)
+}
+
+func TestListOrganizationsActionExtended(t *testing.T) {
+	asc := &inject.AppServiceClient{
+		ListOrganizationsFunc: func(ctx context.Context, in *apppb.ListOrganizationsRequest,
+			opts ...grpc.CallOption,
+		) (*apppb.ListOrganizationsResponse, error) {
+			return &apppb.ListOrganizationsResponse{Organizations: []*apppb.Organization{
+				{Id: "1", Name: "jedi"}, {Id: "2", Name: "mandalorians"},
+			}}, nil
+		},
+		ListLocationsFunc: func(ctx context.Context, in *apppb.ListLocationsRequest,
+			opts ...grpc.CallOption,
+		) (*apppb.ListLocationsResponse, error) {
+			if in.OrganizationId != "1" {
+				return nil, errors.New("permission denied")
+			}
+			return &apppb.ListLocationsResponse{Locations: []*apppb.Location{{Id: "temple"}, {Id: "council"}}}, nil
+		},
+		ListRobotsFunc: func(ctx context.Context, in *apppb.ListRobotsRequest,
+			opts ...grpc.CallOption,
+		) (*apppb.ListRobotsResponse, error) {
+			if in.LocationId == "temple" {
+				return &apppb.ListRobotsResponse{Robots: []*apppb.Robot{{Id: "r2"}, {Id: "c3po"}}}, nil
+			}
+			return &apppb.ListRobotsResponse{Robots: []*apppb.Robot{{Id: "bb8"}}}, nil
+		},
+	}
+	tier := "free"
+	billingClient := &inject.BillingServiceClient{
+		GetOrgBillingInformationFunc: func(ctx context.Context, in *apppb.GetOrgBillingInformationRequest,
+			opts ...grpc.CallOption,
+		) (*apppb.GetOrgBillingInformationResponse, error) {
+			if in.OrgId != "1" {
+				return nil, errors.New("permission denied")
+			}
+			return &apppb.GetOrgBillingInformationResponse{BillingTier: &tier}, nil
+		},
+		GetCurrentMonthUsageFunc: func(ctx context.Context, in *apppb.GetCurrentMonthUsageRequest,
+			opts ...grpc.CallOption,
+		) (*apppb.GetCurrentMonthUsageResponse, error) {
+			if in.OrgId != "1" {
+				return nil, errors.New("permission denied")
+			}
+			return &apppb.GetCurrentMonthUsageResponse{TotalUsageWithDiscount: 12.5}, nil
+		},
+	}
+
+	cCtx, ac, out, errOut := setup(asc, nil, nil, &map[string]string{listOrganizationsFlagExtended: "true"}, "token")
+	ac.billingClient = billingClient
+	test.That(t, ac.listOrganizationsAction(cCtx), test.ShouldBeNil)
+	test.That(t, out.messages, test.ShouldHaveLength, 4)
+	test.That(t, out.messages[1], test.ShouldEqual, "NAME          ID  NAMESPACE  MACHINES  BILLING TIER  USAGE THIS MONTH\n")
+	test.That(t, out.messages[2], test.ShouldEqual, "jedi          1              3         free          $12.50\n")
+	// organizations without extended information are still listed.
+	test.That(t, out.messages[3], test.ShouldEqual, "mandalorians  2              -         -             -\n")
+	test.That(t, strings.Join(errOut.messages, ""), test.ShouldContainSubstring, "unavailable for 1 of 2 organizations")
+
+	cCtx, ac, out, _ = setup(asc, nil, nil,
+		&map[string]string{listOrganizationsFlagExtended: "true", outputFlag: outputFormatJSON}, "token")
+	ac.billingClient = billingClient
+	test.That(t, ac.listOrganizationsAction(cCtx), test.ShouldBeNil)
+	var orgs []extendedOrganizationOutput
+	test.That(t, json.Unmarshal([]byte(out.messages[0]), &orgs), test.ShouldBeNil)
+	test.That(t, orgs, test.ShouldHaveLength, 2)
+	test.That(t, orgs[0].ID, test.ShouldEqual, "1")
+	test.That(t, *orgs[0].MachineCount, test.ShouldEqual, 3)
+	test.That(t, *orgs[0].BillingTier, test.ShouldEqual, "free")
+	test.That(t, *orgs[0].CurrentMonthUsage, test.ShouldEqual, 12.5)
+	test.That(t, orgs[1].Name, test.ShouldEqual, "mandalorians")
+	test.That(t, orgs[1].MachineCount, test.ShouldBeNil)
+	test.That(t, orgs[1].BillingTier, test.ShouldBeNil)
+	test.That(t, orgs[1].CurrentMonthUsage, test.ShouldBeNil)
+}
+
+func TestCreateAndDeleteLocation(t *testing.T) {
+	var deleted []string
+	asc := &inject.AppServiceClient{
+		CreateLocationFunc: func(ctx context.Context, in *apppb.CreateLocationRequest,
+			opts ...grpc.CallOption,
+		) (*apppb.CreateLocationResponse, error) {
+			if in.OrganizationId != "jedi" {
+				return nil, errors.New("organization not found")
+			}
+			return &apppb.CreateLocationResponse{Location: &apppb.Location{Id: "temple-id", Name: in.Name}}, nil
+		},
+		DeleteLocationFunc: func(ctx context.Context, in *apppb.DeleteLocationRequest,
+			opts ...grpc.CallOption,
+		) (*apppb.DeleteLocationResponse, error) {
+			deleted = append(deleted, in.LocationId)
+			return &apppb.DeleteLocationResponse{}, nil
+		},
+	}
+	_, ac, _, _ := setup(asc, nil, nil, nil, "token")
+
+	loc, err := ac.createLocation("jedi", "temple")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, loc.Id, test.ShouldEqual, "temple-id")
+	test.That(t, loc.Name, test.ShouldEqual, "temple")
+
+	_, err = ac.createLocation("jedi", " ")
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = ac.createLocation("sith", "temple")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "organization not found")
+
+	test.That(t, ac.deleteLocation("temple-id"), test.ShouldBeNil)
+	test.That(t, deleted, test.ShouldResemble, []string{"temple-id"})
+
+	originalIsTerminal := stdinIsTerminal
+	defer func() { stdinIsTerminal = originalIsTerminal }()
+	stdinIsTerminal = func() bool { return false }
+	cCtx, _, _, _ := setup(asc, nil, nil, &map[string]string{
+		generalFlagLocationID: "temple-id",
+		generalFlagYes:        "false",
+	}, "token")
+	err = DeleteLocationAction(cCtx)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "stdin is not a terminal")
+	test.That(t, deleted, test.ShouldHaveLength, 1)
+}
+
+func TestListRobotsActionAll(t *testing.T) {
+	asc := &inject.AppServiceClient{
+		ListOrganizationsFunc: func(ctx context.Context, in *apppb.ListOrganizationsRequest,
+			opts ...grpc.CallOption,
+		) (*apppb.ListOrganizationsResponse, error) {
+			return &apppb.ListOrganizationsResponse{Organizations: []*apppb.Organization{{Id: "org1", Name: "jedi"}}}, nil
+		},
+		ListLocationsFunc: func(ctx context.Context, in *apppb.ListLocationsRequest,
+			opts ...grpc.CallOption,
+		) (*apppb.ListLocationsResponse, error) {
+			test.That(t, in.OrganizationId, test.ShouldEqual, "org1")
+			return &apppb.ListLocationsResponse{Locations: []*apppb.Location{
+				{Id: "loc2", Name: "tatooine"},
+				{Id: "loc1", Name: "coruscant"},
+			}}, nil
+		},
+		ListRobotsFunc: func(ctx context.Context, in *apppb.ListRobotsRequest,
+			opts ...grpc.CallOption,
+		) (*apppb.ListRobotsResponse, error) {
+			robots := map[string][]*apppb.Robot{
+				"loc1": {{Id: "r2", Name: "temple"}, {Id: "r1", Name: "senate"}},
+				"loc2": {{Id: "r3", Name: "moisture farm"}},
+			}
+			return &apppb.ListRobotsResponse{Robots: robots[in.LocationId]}, nil
+		},
+	}
+
+	cCtx, ac, out, errOut := setup(asc, nil, nil, &map[string]string{listRobotsFlagAll: "true"}, "token")
+	test.That(t, ac.listRobotsAction(cCtx), test.ShouldBeNil)
+	test.That(t, errOut.messages, test.ShouldBeEmpty)
+	test.That(t, out.messages, test.ShouldResemble, []string{
+		"jedi:\n",
+		"LOCATION   NAME           ID\n",
+		"coruscant  senate         r1\n",
+		"coruscant  temple         r2\n",
+		"tatooine   moisture farm  r3\n",
+	})
+
+	cCtx, ac, out, _ = setup(asc, nil, nil, &map[string]string{listRobotsFlagAll: "true", outputFlag: outputFormatJSON}, "token")
+	test.That(t, ac.listRobotsAction(cCtx), test.ShouldBeNil)
+	test.That(t, out.messages, test.ShouldHaveLength, 1)
+	var robots []robotOutput
+	test.That(t, json.Unmarshal([]byte(out.messages[0]), &robots), test.ShouldBeNil)
+	test.That(t, robots, test.ShouldHaveLength, 3)
+	test.That(t, robots[0].ID, test.ShouldEqual, "r1")
+	test.That(t, robots[0].LocationID, test.ShouldEqual, "loc1")
+	test.That(t, robots[0].LocationName, test.ShouldEqual, "coruscant")
+	test.That(t, robots[2].LocationName, test.ShouldEqual, "tatooine")
+
+	cCtx, ac, _, _ = setup(asc, nil, nil, &map[string]string{listRobotsFlagAll: "true", locationFlag: "loc1"}, "token")
+	test.That(t, ac.listRobotsAction(cCtx), test.ShouldNotBeNil)
 }
 
 func TestTabularDataByFilterAction(t *testing.T) {
@@ -136,6 +355,8 @@ func TestTabularDataByFilterAction(t *testing.T) {
 	}
 
 	cCtx, ac, out, errOut := setup(&inject.AppServiceClient{}, dsc, nil, nil, "token")
+	dest := t.TempDir()
+	test.That(t, cCtx.Set(dataFlagDestination, dest), test.ShouldBeNil)
 
 	test.That(t, ac.dataExportAction(cCtx), test.ShouldBeNil)
 	test.That(t, len(errOut.messages), test.ShouldEqual, 0)
@@ -150,7 +371,7 @@ func TestTabularDataByFilterAction(t *testing.T) {
 	b := make([]byte, expectedDataSize)
 
 	// `data.ndjson` is the standardized name of the file data is written to in the `tabularData` call
-	filePath := utils.ResolveFile("data/data.ndjson")
+	filePath := filepath.Join(dest, "data", "data.ndjson")
 	file, err := os.Open(filePath)
 	test.That(t, err, test.ShouldBeNil)
 
@@ -166,7 +387,7 @@ func TestTabularDataByFilterAction(t *testing.T) {
 	b = make([]byte, expectedMetadataSize)
 
 	// metadata is named `0.json` based on its index in the metadata array
-	filePath = utils.ResolveFile("metadata/0.json")
+	filePath = filepath.Join(dest, "metadata", "0.json")
 	file, err = os.Open(filePath)
 	test.That(t, err, test.ShouldBeNil)
 
@@ -178,6 +399,50 @@ func TestTabularDataByFilterAction(t *testing.T) {
 	test.That(t, savedMetadata, test.ShouldEqual, "{\"locationId\":\"loc-id\"}")
 }
 
+func TestDataExportDryRun(t *testing.T) {
+	var dataRequested bool
+	binaryDataByFilterFunc := func(ctx context.Context, in *datapb.BinaryDataByFilterRequest, opts ...grpc.CallOption,
+	) (*datapb.BinaryDataByFilterResponse, error) {
+		test.That(t, in.GetIncludeBinary(), test.ShouldBeFalse)
+		if dataRequested {
+			return &datapb.BinaryDataByFilterResponse{}, nil
+		}
+		dataRequested = true
+		newData := func(name string, sec int64) *datapb.BinaryData {
+			return &datapb.BinaryData{Metadata: &datapb.BinaryMetadata{
+				CaptureMetadata: &datapb.CaptureMetadata{ComponentName: name, ComponentType: "camera"},
+				TimeRequested:   timestamppb.New(time.Unix(sec, 0)),
+			}}
+		}
+		return &datapb.BinaryDataByFilterResponse{
+			Data:           []*datapb.BinaryData{newData("cam-1", 20), newData("cam-2", 10), newData("cam-1", 30)},
+			TotalSizeBytes: 3000,
+		}, nil
+	}
+	dsc := &inject.DataServiceClient{
+		BinaryDataByFilterFunc: binaryDataByFilterFunc,
+	}
+	_, ac, out, errOut := setup(&inject.AppServiceClient{}, dsc, nil, nil, "token")
+
+	test.That(t, ac.dataExportDryRun(dataTypeBinary, &datapb.Filter{}, nil), test.ShouldBeNil)
+	test.That(t, len(errOut.messages), test.ShouldEqual, 0)
+	test.That(t, len(out.messages), test.ShouldEqual, 5)
+	test.That(t, out.messages[0], test.ShouldContainSubstring, "3 files (approximately 3kB)")
+	test.That(t, out.messages[1], test.ShouldContainSubstring, time.Unix(10, 0).UTC().Format(time.RFC3339))
+	test.That(t, out.messages[1], test.ShouldContainSubstring, time.Unix(30, 0).UTC().Format(time.RFC3339))
+	test.That(t, out.messages[3], test.ShouldEqual, "\tcam-1 (camera): 2\n")
+	test.That(t, out.messages[4], test.ShouldEqual, "\tcam-2 (camera): 1\n")
+
+	// A dry run that matches nothing should still succeed.
+	dataRequested = false
+	out.messages = nil
+	globs, err := newComponentGlobFilter(&datapb.Filter{ComponentName: "lidar-*"})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, ac.dataExportDryRun(dataTypeBinary, &datapb.Filter{}, globs), test.ShouldBeNil)
+	test.That(t, len(out.messages), test.ShouldEqual, 1)
+	test.That(t, out.messages[0], test.ShouldContainSubstring, "0 files")
+}
+
 func TestBaseURLParsing(t *testing.T) {
 	// Test basic parsing
 	url, rpcOpts, err := parseBaseURL("https://app.viam.com:443", false)
@@ -218,3 +483,262 @@ func TestBaseURLParsing(t *testing.T) {
 	_, _, err = parseBaseURL(":5", false)
 	test.That(t, fmt.Sprint(err), test.ShouldContainSubstring, "missing protocol scheme")
 }
+
+func TestRunOutputEventHandler(t *testing.T) {
+	var out bytes.Buffer
+	now := time.Date(2023, 11, 2, 10, 30, 0, 0, time.UTC)
+	h := &runOutputEventHandler{
+		DefaultEventHandler: &grpcurl.DefaultEventHandler{Out: io.Discard, Formatter: grpcurl.NewJSONFormatter(true, nil)},
+		out:                 &out,
+		now:                 func() time.Time { return now },
+	}
+
+	h.OnReceiveResponse(&apppb.Organization{Id: "org1", Name: "org one"})
+	now = now.Add(time.Second)
+	h.OnReceiveResponse(&apppb.Organization{Id: "org2", Name: "org two"})
+	test.That(t, h.err, test.ShouldBeNil)
+	test.That(t, h.NumResponses, test.ShouldEqual, 2)
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	test.That(t, lines, test.ShouldHaveLength, 2)
+	for i, line := range lines {
+		var record struct {
+			Time     time.Time         `json:"time"`
+			Response map[string]string `json:"response"`
+		}
+		test.That(t, json.Unmarshal([]byte(line), &record), test.ShouldBeNil)
+		test.That(t, record.Time, test.ShouldEqual, time.Date(2023, 11, 2, 10, 30, i, 0, time.UTC))
+		test.That(t, record.Response["id"], test.ShouldEqual, fmt.Sprintf("org%d", i+1))
+	}
+}
+
+func TestPrintRecords(t *testing.T) {
+	lastAccess := time.Date(2023, 11, 2, 10, 30, 0, 0, time.UTC)
+	robot := &apppb.Robot{Id: "robot1", Name: "machine one", LastAccess: timestamppb.New(lastAccess)}
+	parts := []machinePartStatusOutput{
+		newMachinePartStatusOutput(robot, &apppb.RobotPart{Id: "part1", Name: "main", MainPart: true, LastAccess: timestamppb.New(lastAccess)}),
+		newMachinePartStatusOutput(robot, &apppb.RobotPart{Id: "part2", Name: "arm, gripper"}),
+	}
+	status := machineStatusOutput{ID: robot.Id, Name: robot.Name, LastAccess: &lastAccess, Parts: parts}
+
+	t.Run("csv", func(t *testing.T) {
+		var out bytes.Buffer
+		test.That(t, printRecords(&out, formatCSV, status, parts, func() { t.Fatal("table printed") }), test.ShouldBeNil)
+		test.That(t, out.String(), test.ShouldEqual,
+			"machine_id,machine_name,id,name,main_part,last_access\n"+
+				"robot1,machine one,part1,main,true,2023-11-02T10:30:00Z\n"+
+				"robot1,machine one,part2,\"arm, gripper\",false,\n")
+
+		out.Reset()
+		test.That(t, printRecords(&out, formatCSV, status, []machinePartStatusOutput{}, nil), test.ShouldBeNil)
+		test.That(t, out.String(), test.ShouldEqual, "machine_id,machine_name,id,name,main_part,last_access\n")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var out bytes.Buffer
+		test.That(t, printRecords(&out, outputFormatJSON, status, parts, func() { t.Fatal("table printed") }), test.ShouldBeNil)
+		var decoded machineStatusOutput
+		test.That(t, json.Unmarshal(out.Bytes(), &decoded), test.ShouldBeNil)
+		test.That(t, decoded.ID, test.ShouldEqual, "robot1")
+		test.That(t, decoded.LastAccess.Equal(lastAccess), test.ShouldBeTrue)
+		test.That(t, decoded.Parts, test.ShouldHaveLength, 2)
+		test.That(t, decoded.Parts[0].MachineID, test.ShouldEqual, "robot1")
+		test.That(t, decoded.Parts[0].MainPart, test.ShouldBeTrue)
+		test.That(t, decoded.Parts[1].ID, test.ShouldEqual, "part2")
+		test.That(t, decoded.Parts[1].LastAccess, test.ShouldBeNil)
+	})
+
+	t.Run("table", func(t *testing.T) {
+		var printed bool
+		test.That(t, printRecords(io.Discard, formatTable, status, parts, func() { printed = true }), test.ShouldBeNil)
+		test.That(t, printed, test.ShouldBeTrue)
+	})
+
+	t.Run("format flag", func(t *testing.T) {
+		cCtx, _, _, _ := setup(&inject.AppServiceClient{}, nil, nil, &map[string]string{formatFlag: "yaml"}, "token")
+		_, err := recordFormat(cCtx)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "format must be table, json or csv")
+
+		cCtx, _, _, _ = setup(&inject.AppServiceClient{}, nil, nil, nil, "token")
+		format, err := recordFormat(cCtx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, format, test.ShouldEqual, formatTable)
+
+		// the output flag is honoured when the format flag is not passed.
+		cCtx, _, _, _ = setup(&inject.AppServiceClient{}, nil, nil, &map[string]string{outputFlag: outputFormatJSON}, "token")
+		format, err = recordFormat(cCtx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, format, test.ShouldEqual, outputFormatJSON)
+
+		cCtx, _, _, _ = setup(&inject.AppServiceClient{}, nil, nil,
+			&map[string]string{outputFlag: outputFormatJSON, formatFlag: formatCSV}, "token")
+		format, err = recordFormat(cCtx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, format, test.ShouldEqual, formatCSV)
+	})
+}
+
+func TestRobotPartLogFilter(t *testing.T) {
+	newFilter := func(flags map[string]string) (robotPartLogFilter, error) {
+		cCtx, _, _, _ := setup(&inject.AppServiceClient{}, nil, nil, &flags, "token")
+		return newRobotPartLogFilter(cCtx)
+	}
+	entry := func(level, message string, age time.Duration) *commonpb.LogEntry {
+		return &commonpb.LogEntry{Level: level, Message: message, Time: timestamppb.New(time.Now().Add(-age))}
+	}
+
+	filter, err := newFilter(nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, filter.levels(), test.ShouldBeNil)
+	test.That(t, filter.matches(entry("debug", "anything", time.Hour)), test.ShouldBeTrue)
+
+	filter, err = newFilter(map[string]string{logsFlagLevel: "WARN", logsFlagSince: "10m", logsFlagGrep: "motor [0-9]+"})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, filter.levels(), test.ShouldResemble, []string{"warn", "error", "dpanic", "panic", "fatal"})
+	test.That(t, filter.matches(entry("warn", "motor 2 stalled", time.Minute)), test.ShouldBeTrue)
+	test.That(t, filter.matches(entry("fatal", "motor 2 stalled", time.Minute)), test.ShouldBeTrue)
+	test.That(t, filter.matches(entry("custom", "motor 2 stalled", time.Minute)), test.ShouldBeTrue)
+	test.That(t, filter.matches(entry("info", "motor 2 stalled", time.Minute)), test.ShouldBeFalse)
+	test.That(t, filter.matches(entry("error", "motor 2 stalled", time.Hour)), test.ShouldBeFalse)
+	test.That(t, filter.matches(entry("error", "arm stalled", time.Minute)), test.ShouldBeFalse)
+
+	_, err = newFilter(map[string]string{logsFlagLevel: "verbose"})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "level must be debug, info, warn or error")
+
+	_, err = newFilter(map[string]string{logsFlagGrep: "("})
+	test.That(t, err, test.ShouldNotBeNil)
+
+	_, err = newFilter(map[string]string{logsFlagSince: "-1h"})
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+type fakeTailLogsStream struct {
+	grpc.ClientStream
+	logs []*apppb.TailRobotPartLogsResponse
+	// onDone is called once all of logs have been received and its error is returned.
+	onDone func() error
+}
+
+func (s *fakeTailLogsStream) Recv() (*apppb.TailRobotPartLogsResponse, error) {
+	if len(s.logs) == 0 {
+		return nil, s.onDone()
+	}
+	resp := s.logs[0]
+	s.logs = s.logs[1:]
+	return resp, nil
+}
+
+func TestTailRobotLogs(t *testing.T) {
+	originalDelay := minLogStreamReconnectDelay
+	minLogStreamReconnectDelay = time.Millisecond
+	defer func() { minLogStreamReconnectDelay = originalDelay }()
+
+	var mu sync.Mutex
+	tailCalls := map[string]int{}
+	asc := &inject.AppServiceClient{
+		TailRobotPartLogsFunc: func(ctx context.Context, in *apppb.TailRobotPartLogsRequest,
+			opts ...grpc.CallOption,
+		) (apppb.AppService_TailRobotPartLogsClient, error) {
+			mu.Lock()
+			tailCalls[in.Id]++
+			call := tailCalls[in.Id]
+			mu.Unlock()
+			if in.Id == "bad" {
+				return nil, errors.New("permission denied")
+			}
+			entry := &commonpb.LogEntry{Level: "info", Message: fmt.Sprintf("%s message %d", in.Id, call), Time: timestamppb.Now()}
+			return &fakeTailLogsStream{
+				logs: []*apppb.TailRobotPartLogsResponse{{Logs: []*commonpb.LogEntry{entry}}},
+				onDone: func() error {
+					// the first stream drops, the second one lasts until the command is stopped.
+					if call == 1 {
+						return errors.New("stream reset")
+					}
+					<-ctx.Done()
+					return ctx.Err()
+				},
+			}, nil
+		},
+	}
+
+	cCtx, ac, out, errOut := setup(asc, nil, nil, nil, "token")
+	ctx, cancel := context.WithCancel(context.Background())
+	cCtx.Context = ctx
+	ac.c = cCtx
+	parts := []*apppb.RobotPart{{Id: "p1", Name: "main"}, {Id: "p2", Name: "arm"}}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- ac.tailRobotLogs(parts, robotPartLogFilter{}, "header")
+	}()
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		mu.Lock()
+		defer mu.Unlock()
+		test.That(tb, tailCalls["p1"], test.ShouldEqual, 2)
+		test.That(tb, tailCalls["p2"], test.ShouldEqual, 2)
+	})
+	cancel()
+	test.That(t, <-done, test.ShouldBeNil)
+
+	test.That(t, out.messages[0], test.ShouldEqual, "header\n")
+	lines := strings.Join(out.messages[1:], "")
+	for _, want := range []string{"main\t", "p1 message 1", "p1 message 2", "arm\t", "p2 message 1", "p2 message 2"} {
+		test.That(t, lines, test.ShouldContainSubstring, want)
+	}
+	test.That(t, strings.Join(errOut.messages, ""), test.ShouldContainSubstring, "stream reset")
+
+	// a part whose logs cannot be followed at all stops the command.
+	cCtx, ac, _, _ = setup(asc, nil, nil, nil, "token")
+	err := ac.tailRobotLogs([]*apppb.RobotPart{{Id: "bad", Name: "bad"}, {Id: "p3", Name: "camera"}}, robotPartLogFilter{}, "")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "permission denied")
+}
+
+func TestCheckVersion(t *testing.T) {
+	origViamDotDir, origReleaseURL, origVersion := viamDotDir, rdkReleaseURL, rconfig.Version
+	defer func() {
+		viamDotDir, rdkReleaseURL, rconfig.Version = origViamDotDir, origReleaseURL, origVersion
+	}()
+	viamDotDir = t.TempDir()
+
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, `{"tag_name": "v0.20.0", "html_url": "https://github.com/viamrobotics/rdk/releases/tag/v0.20.0"}`)
+	}))
+	defer server.Close()
+	rdkReleaseURL = server.URL
+
+	rconfig.Version = "v0.19.1"
+	cCtx, _, out, _ := setup(nil, nil, nil, nil, "")
+	err := checkVersion(cCtx)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "out of date")
+	test.That(t, out.messages, test.ShouldHaveLength, 1)
+	test.That(t, out.messages[0], test.ShouldContainSubstring, "Update available: v0.20.0 (current v0.19.1)")
+	test.That(t, out.messages[0], test.ShouldContainSubstring, "https://github.com/viamrobotics/rdk/releases/tag/v0.20.0")
+
+	// the result is cached, so checking again does not query the latest release.
+	rconfig.Version = "v0.20.0"
+	cCtx, _, out, _ = setup(nil, nil, nil, nil, "")
+	test.That(t, checkVersion(cCtx), test.ShouldBeNil)
+	test.That(t, out.messages, test.ShouldResemble, []string{"Up to date: v0.20.0 is the latest release\n"})
+	test.That(t, requests, test.ShouldEqual, 1)
+
+	// --quiet suppresses the nudge but still fails.
+	rconfig.Version = "v0.19.1"
+	cCtx, _, out, _ = setup(nil, nil, nil, &map[string]string{quietFlag: "true"}, "")
+	test.That(t, checkVersion(cCtx), test.ShouldNotBeNil)
+	test.That(t, out.messages, test.ShouldBeEmpty)
+
+	// a stale cache is refreshed.
+	conf, err := configFromCache("")
+	test.That(t, err, test.ShouldBeNil)
+	conf.LatestVersionCheckedAt = time.Now().Add(-2 * latestVersionCacheDuration).Format(time.RFC3339)
+	test.That(t, storeConfigToCache(conf), test.ShouldBeNil)
+	cCtx, _, _, _ = setup(nil, nil, nil, nil, "")
+	test.That(t, checkVersion(cCtx), test.ShouldNotBeNil)
+	test.That(t, requests, test.ShouldEqual, 2)
+}
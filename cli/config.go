@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/pkg/errors"
 	"go.uber.org/multierr"
@@ -11,16 +12,107 @@ import (
 
 var viamDotDir = filepath.Join(os.Getenv("HOME"), ".viam")
 
-func getCLICachePath() string {
-	return filepath.Join(viamDotDir, "cached_cli_config.json")
+const cliCacheFileName = "cached_cli_config.json"
+
+// getCLICachePath returns the path of the cached config for profile. The default profile, "", is
+// stored at ~/.viam/cached_cli_config.json and every other profile at
+// ~/.viam/profiles/<profile>/cached_cli_config.json.
+func getCLICachePath(profile string) string {
+	return filepath.Join(profileDir(profile), cliCacheFileName)
+}
+
+// getCLIBaseURLCachePath returns the path of the cached config of profile for a base URL other than the
+// one it is logged in to, ~/.viam/[profiles/<profile>/]base_urls/<base URL>/cached_cli_config.json.
+func getCLIBaseURLCachePath(profile, baseURL string) string {
+	return filepath.Join(profileDir(profile), "base_urls", baseURLCacheDirName(baseURL), cliCacheFileName)
+}
+
+func profileDir(profile string) string {
+	if profile == "" {
+		return viamDotDir
+	}
+	return filepath.Join(viamDotDir, "profiles", profile)
+}
+
+// baseURLCacheDirName returns a directory name unique to baseURL, such as https_app.viam.com_443.
+func baseURLCacheDirName(baseURL string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '.' || r == '-' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, strings.Replace(normalizeBaseURL(baseURL), "://", "_", 1))
+}
+
+// normalizeBaseURL returns baseURL with its scheme and port filled in, so that different ways of writing
+// the same base URL compare equal. baseURL is returned unchanged if it cannot be parsed.
+func normalizeBaseURL(baseURL string) string {
+	parsed, _, err := parseBaseURL(baseURL, false)
+	if err != nil {
+		return baseURL
+	}
+	return parsed.Scheme + "://" + parsed.Host
 }
 
-func configFromCache() (*config, error) {
-	rd, err := os.ReadFile(getCLICachePath())
+// configForBaseURL returns the cached config of profile to use for the base URL passed with the base URL
+// flag, or for the cached base URL if baseURLArg is empty. In order of precedence, the base URL used is:
+//  1. the base URL flag, with the credentials cached for that base URL. A profile logged in to one base URL
+//     never reuses its credentials for another one, which are instead cached separately when logging in
+//     with the flag.
+//  2. the base URL the profile logged in to without the flag.
+//  3. the default base URL.
+func configForBaseURL(profile, baseURLArg string) (*config, error) {
+	conf, err := configFromCache(profile)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+		conf = &config{profile: profile}
+	}
+	if baseURLArg == "" || conf.BaseURL == "" || normalizeBaseURL(conf.BaseURL) == normalizeBaseURL(baseURLArg) {
+		return conf, nil
+	}
+
+	// the profile is logged in to a different base URL, so keep those credentials and use the ones cached
+	// for this base URL.
+	conf, err = configFromBaseURLCache(profile, baseURLArg)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+		conf = &config{profile: profile}
+	}
+	conf.BaseURL = baseURLArg
+	conf.cachedForBaseURL = true
+	return conf, nil
+}
+
+// validateProfile returns an error if profile cannot be used as a directory name under ~/.viam/profiles.
+func validateProfile(profile string) error {
+	if profile == "" {
+		return nil
+	}
+	if profile == "." || profile == ".." || strings.ContainsAny(profile, `/\`) {
+		return errors.Errorf("invalid profile name %q: must not be . or .. or contain path separators", profile)
+	}
+	return nil
+}
+
+func configFromCache(profile string) (*config, error) {
+	return readConfigFromCache(getCLICachePath(profile), profile)
+}
+
+func configFromBaseURLCache(profile, baseURL string) (*config, error) {
+	return readConfigFromCache(getCLIBaseURLCachePath(profile, baseURL), profile)
+}
+
+func readConfigFromCache(path, profile string) (*config, error) {
+	//nolint:gosec
+	rd, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
-	var conf config
+	conf := config{profile: profile}
 
 	tokenErr := conf.tryUnmarshallWithToken(rd)
 	if tokenErr == nil {
@@ -30,16 +122,28 @@ func configFromCache() (*config, error) {
 	if apiKeyErr == nil {
 		return &conf, nil
 	}
+	// a config cached without logging in, such as one only holding the result of an update check.
+	if conf.tryUnmarshallWithoutAuth(rd) == nil {
+		return &conf, nil
+	}
 
 	return nil, errors.Wrap(multierr.Combine(tokenErr, apiKeyErr), "failed to read config from cache")
 }
 
-func removeConfigFromCache() error {
-	return os.Remove(getCLICachePath())
+func removeConfigFromCache(cfg *config) error {
+	return os.Remove(cfg.cachePath())
 }
 
+// storeConfigToCache writes cfg to its cache path. Credentials from the environment or the --config file are
+// never cached; the credentials cfg was read with are cached in their place.
 func storeConfigToCache(cfg *config) error {
-	if err := os.MkdirAll(viamDotDir, 0o700); err != nil {
+	if cfg.overrideAuth != nil && cfg.Auth == cfg.overrideAuth {
+		cached := *cfg
+		cached.Auth = cfg.cachedAuth
+		cfg = &cached
+	}
+	path := cfg.cachePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
 		return err
 	}
 	md, err := json.MarshalIndent(cfg, "", "  ")
@@ -47,7 +151,7 @@ func storeConfigToCache(cfg *config) error {
 		return err
 	}
 	//nolint:gosec
-	return os.WriteFile(getCLICachePath(), md, 0o640)
+	return os.WriteFile(path, md, 0o640)
 }
 
 type config struct {
@@ -55,6 +159,27 @@ type config struct {
 	Auth            authMethod `json:"auth"`
 	LastUpdateCheck string     `json:"last_update_check"`
 	LatestVersion   string     `json:"latest_version"`
+	// LatestVersionURL and LatestVersionCheckedAt cache the result of 'version --check'.
+	LatestVersionURL       string `json:"latest_version_url,omitempty"`
+	LatestVersionCheckedAt string `json:"latest_version_checked_at,omitempty"`
+
+	// profile is the name of the profile this config is cached under; it is not itself cached.
+	profile string
+	// cachedForBaseURL is whether this config is cached for its base URL rather than being the profile's
+	// main config; it is not itself cached.
+	cachedForBaseURL bool
+	// overrideAuth is the api key from the environment or the --config file that Auth was set to for this
+	// command, and cachedAuth the credentials that it replaced; neither is itself cached.
+	overrideAuth *apiKey
+	cachedAuth   authMethod
+}
+
+// cachePath returns the path conf is cached at.
+func (conf *config) cachePath() string {
+	if conf.cachedForBaseURL {
+		return getCLIBaseURLCachePath(conf.profile, conf.BaseURL)
+	}
+	return getCLICachePath(conf.profile)
 }
 
 func (conf *config) tryUnmarshallWithToken(configBytes []byte) error {
@@ -78,3 +203,17 @@ func (conf *config) tryUnmarshallWithAPIKey(configBytes []byte) error {
 	}
 	return errors.New("config did not contain an api key")
 }
+
+func (conf *config) tryUnmarshallWithoutAuth(configBytes []byte) error {
+	var auth struct {
+		Auth json.RawMessage `json:"auth"`
+	}
+	if err := json.Unmarshal(configBytes, &auth); err != nil {
+		return err
+	}
+	if len(auth.Auth) != 0 && string(auth.Auth) != "null" {
+		return errors.New("config contained unknown auth")
+	}
+	conf.Auth = nil
+	return json.Unmarshal(configBytes, &conf)
+}
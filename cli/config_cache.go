@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/docker/go-units"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+
+	rconfig "go.viam.com/rdk/config"
+)
+
+// cachedConfigOutput is the JSON output of 'config cache list'.
+type cachedConfigOutput struct {
+	PartID     string    `json:"part_id"`
+	Path       string    `json:"path"`
+	SizeBytes  int64     `json:"size_bytes"`
+	ModifiedOn time.Time `json:"modified_on"`
+}
+
+// ConfigCacheListAction is the corresponding action for 'config cache list'.
+func ConfigCacheListAction(c *cli.Context) error {
+	format, err := outputFormat(c)
+	if err != nil {
+		return err
+	}
+	cached, err := rconfig.ListCachedCloudConfigs()
+	if err != nil {
+		return errors.Wrap(err, "could not list cached configs")
+	}
+	if format == outputFormatJSON {
+		out := make([]cachedConfigOutput, 0, len(cached))
+		for _, cfg := range cached {
+			out = append(out, cachedConfigOutput{
+				PartID:     cfg.PartID,
+				Path:       cfg.Path,
+				SizeBytes:  cfg.Size,
+				ModifiedOn: cfg.ModTime,
+			})
+		}
+		return printJSON(c.App.Writer, out)
+	}
+	if len(cached) == 0 {
+		printf(c.App.Writer, "No configs are cached in %s", rconfig.ViamDotDir)
+		return nil
+	}
+	tbl := newTable("PART ID", "SIZE", "MODIFIED", "PATH").keepWhole("PART ID", "PATH")
+	for _, cfg := range cached {
+		tbl.addRow(cfg.PartID, units.HumanSize(float64(cfg.Size)), cfg.ModTime.Format(time.RFC3339), cfg.Path)
+	}
+	tbl.render(c.App.Writer, tableOptionsFor(c))
+	return nil
+}
+
+// ConfigCacheClearAction is the corresponding action for 'config cache clear'.
+func ConfigCacheClearAction(c *cli.Context) error {
+	partID := c.String(configCacheFlagPartID)
+	all := c.Bool(configCacheFlagAll)
+	if (partID == "") == !all {
+		return errors.Errorf("pass either --%s or --%s", configCacheFlagPartID, configCacheFlagAll)
+	}
+
+	partIDs := []string{partID}
+	description := fmt.Sprintf("This will delete the cached config for part %s", partID)
+	if all {
+		cached, err := rconfig.ListCachedCloudConfigs()
+		if err != nil {
+			return errors.Wrap(err, "could not list cached configs")
+		}
+		if len(cached) == 0 {
+			printf(c.App.Writer, "No configs are cached in %s", rconfig.ViamDotDir)
+			return nil
+		}
+		partIDs = partIDs[:0]
+		for _, cfg := range cached {
+			partIDs = append(partIDs, cfg.PartID)
+		}
+		description = fmt.Sprintf("This will delete the cached configs for %d parts", len(partIDs))
+	}
+	// a part without a cached config must fetch its config from the cloud to start.
+	if err := confirmDestructiveAction(c, description+", which the parts start with when they cannot reach the cloud"); err != nil {
+		return err
+	}
+
+	for _, id := range partIDs {
+		if err := rconfig.ClearCachedCloudConfig(id); err != nil {
+			return err
+		}
+		printf(c.App.Writer, "Cleared cached config for part %s", id)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.viam.com/test"
+
+	rconfig "go.viam.com/rdk/config"
+)
+
+func TestConfigCacheActions(t *testing.T) {
+	prevViamDotDir := rconfig.ViamDotDir
+	rconfig.ViamDotDir = t.TempDir()
+	defer func() {
+		rconfig.ViamDotDir = prevViamDotDir
+	}()
+	for _, id := range []string{"part-a", "part-b", "part-c"} {
+		path := filepath.Join(rconfig.ViamDotDir, "cached_cloud_config_"+id+".json")
+		test.That(t, os.WriteFile(path, []byte("{}"), 0o600), test.ShouldBeNil)
+	}
+
+	out := &testWriter{}
+	app := NewApp(out, &testWriter{})
+	test.That(t, app.Run([]string{"viam", "config", "cache", "list"}), test.ShouldBeNil)
+	test.That(t, len(out.messages), test.ShouldEqual, 4)
+	test.That(t, out.messages[0], test.ShouldStartWith, "PART ID  SIZE  MODIFIED")
+	test.That(t, out.messages[1], test.ShouldStartWith, "part-a   2B    ")
+
+	err := app.Run([]string{"viam", "config", "cache", "clear"})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "either --part-id or --all")
+	err = app.Run([]string{"viam", "config", "cache", "clear", "--part-id", "part-a", "--all"})
+	test.That(t, err, test.ShouldNotBeNil)
+
+	// stdin is not a terminal in tests, so clearing without --yes is refused.
+	err = app.Run([]string{"viam", "config", "cache", "clear", "--part-id", "part-a"})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "Refusing to continue without confirmation")
+
+	out.messages = nil
+	test.That(t, app.Run([]string{"viam", "config", "cache", "clear", "--part-id", "part-a", "--yes"}), test.ShouldBeNil)
+	test.That(t, strings.Join(out.messages, ""), test.ShouldContainSubstring, "Cleared cached config for part part-a")
+	err = app.Run([]string{"viam", "config", "cache", "clear", "--part-id", "part-a", "--yes"})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "no config is cached")
+
+	test.That(t, app.Run([]string{"viam", "config", "cache", "clear", "--all", "--yes"}), test.ShouldBeNil)
+	cached, err := rconfig.ListCachedCloudConfigs()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cached, test.ShouldBeEmpty)
+
+	out.messages = nil
+	test.That(t, app.Run([]string{"viam", "config", "cache", "list", "--output", "json"}), test.ShouldBeNil)
+	test.That(t, strings.Join(out.messages, ""), test.ShouldContainSubstring, "[]")
+}
@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+)
+
+// cliConfigFile is the file passed with --config.
+type cliConfigFile struct {
+	Credentials *cliConfigFileCredentials `json:"credentials"`
+}
+
+// cliConfigFileCredentials let every command run without logging in, such as from a provisioning script.
+// The api key takes precedence over the VIAM_API_KEY_ID and VIAM_API_KEY environment variables and the cached
+// credentials, and is never cached. The base URL, org ID and location ID are used when the corresponding
+// flags are not passed.
+type cliConfigFileCredentials struct {
+	APIKeyID   string `json:"api_key_id"`
+	APIKey     string `json:"api_key"`
+	BaseURL    string `json:"base_url"`
+	OrgID      string `json:"org_id"`
+	LocationID string `json:"location_id"`
+}
+
+// readCLIConfigFile reads and validates the --config file at path.
+func readCLIConfigFile(path string) (*cliConfigFile, error) {
+	//nolint:gosec
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read config file")
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	var conf cliConfigFile
+	if err := dec.Decode(&conf); err != nil {
+		return nil, errors.Wrapf(err, "could not parse config file %s", path)
+	}
+	if creds := conf.Credentials; creds != nil && (creds.APIKeyID == "") != (creds.APIKey == "") {
+		return nil, errors.Errorf("config file %s must set both api_key_id and api_key, or neither", path)
+	}
+	return &conf, nil
+}
+
+// apiKey returns the api key of the credentials section of conf, or nil if it has none.
+func (conf *cliConfigFile) apiKey() *apiKey {
+	if conf == nil || conf.Credentials == nil || conf.Credentials.APIKeyID == "" {
+		return nil
+	}
+	return &apiKey{KeyID: conf.Credentials.APIKeyID, KeyCrypto: conf.Credentials.APIKey}
+}
+
+// warnIfCLIConfigFileReadable warns if the --config file at path, which may hold an api key, can be read by
+// users other than its owner and group.
+func warnIfCLIConfigFileReadable(w io.Writer, path string) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return
+	}
+	if info.Mode().Perm()&0o004 != 0 {
+		warningf(w, "config file %s is readable by all users; restrict its permissions with 'chmod 600 %s'", path, path)
+	}
+}
+
+// cliConfigFileFlagDefaults are the flags that the credentials of the --config file give defaults for.
+func cliConfigFileFlagDefaults(creds *cliConfigFileCredentials) map[string]string {
+	return map[string]string{
+		generalFlagOrgID:      creds.OrgID,
+		organizationFlag:      creds.OrgID,
+		generalFlagLocationID: creds.LocationID,
+		locationFlag:          creds.LocationID,
+	}
+}
+
+// cliConfigFileKey is the context key of the --config file loaded by loadCLIConfigFile.
+type cliConfigFileKey struct{}
+
+// stringFlagOrConfigFile returns the value of the string flag with the given name or, if it was not passed,
+// the default that the credentials of the --config file give it, if any.
+func stringFlagOrConfigFile(c *cli.Context, name string) string {
+	if c.IsSet(name) {
+		return c.String(name)
+	}
+	if conf, ok := c.Context.Value(cliConfigFileKey{}).(*cliConfigFile); ok && conf.Credentials != nil {
+		if value := cliConfigFileFlagDefaults(conf.Credentials)[name]; value != "" {
+			return value
+		}
+	}
+	return c.String(name)
+}
+
+// requiredStringFlagOrConfigFile is stringFlagOrConfigFile for a flag that is required unless the --config file
+// gives it a default. Such flags are not marked Required, since that is checked before the file is consulted.
+func requiredStringFlagOrConfigFile(c *cli.Context, name string) (string, error) {
+	value := stringFlagOrConfigFile(c, name)
+	if value == "" {
+		return "", errors.Errorf("Required flag %q not set", name)
+	}
+	return value, nil
+}
+
+// loadCLIConfigFile reads the --config file, if one was passed, and makes it available to the commands of the
+// app through the context of c.
+func loadCLIConfigFile(c *cli.Context) error {
+	path := c.String(configFlag)
+	if path == "" {
+		return nil
+	}
+	conf, err := readCLIConfigFile(path)
+	if err != nil {
+		return err
+	}
+	warnIfCLIConfigFileReadable(c.App.ErrWriter, path)
+	c.Context = context.WithValue(c.Context, cliConfigFileKey{}, conf)
+	return nil
+}
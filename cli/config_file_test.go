@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/urfave/cli/v2"
+	"go.viam.com/test"
+)
+
+func TestReadCLIConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, contents string, perm os.FileMode) string {
+		path := filepath.Join(dir, name)
+		test.That(t, os.WriteFile(path, []byte(contents), perm), test.ShouldBeNil)
+		test.That(t, os.Chmod(path, perm), test.ShouldBeNil)
+		return path
+	}
+
+	path := write("good.json", `{"credentials": {"api_key_id": "key-id", "api_key": "key", "base_url": "https://app.viam.dev",
+		"org_id": "org-id", "location_id": "location-id"}}`, 0o600)
+	conf, err := readCLIConfigFile(path)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, conf.apiKey(), test.ShouldResemble, &apiKey{KeyID: "key-id", KeyCrypto: "key"})
+	test.That(t, conf.Credentials.BaseURL, test.ShouldEqual, "https://app.viam.dev")
+
+	conf, err = readCLIConfigFile(write("no_key.json", `{"credentials": {"org_id": "org-id"}}`, 0o600))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, conf.apiKey(), test.ShouldBeNil)
+
+	_, err = readCLIConfigFile(write("half_key.json", `{"credentials": {"api_key_id": "key-id"}}`, 0o600))
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "both api_key_id and api_key")
+
+	_, err = readCLIConfigFile(write("typo.json", `{"credentials": {"apikey": "key"}}`, 0o600))
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "unknown field")
+
+	out := &testWriter{}
+	warnIfCLIConfigFileReadable(out, path)
+	test.That(t, out.messages, test.ShouldBeEmpty)
+	warnIfCLIConfigFileReadable(out, write("readable.json", `{}`, 0o644))
+	test.That(t, strings.Join(out.messages, ""), test.ShouldContainSubstring, "readable by all users")
+}
+
+func TestStringFlagOrConfigFile(t *testing.T) {
+	newContext := func(conf *cliConfigFile, passed map[string]string) *cli.Context {
+		flags := &flag.FlagSet{}
+		for _, name := range []string{generalFlagOrgID, generalFlagLocationID, dataFlagDestination} {
+			flags.String(name, "", "")
+		}
+		for name, value := range passed {
+			test.That(t, flags.Set(name, value), test.ShouldBeNil)
+		}
+		c := cli.NewContext(&cli.App{}, flags, nil)
+		if conf != nil {
+			c.Context = context.WithValue(c.Context, cliConfigFileKey{}, conf)
+		}
+		return c
+	}
+	conf := &cliConfigFile{Credentials: &cliConfigFileCredentials{OrgID: "org-id"}}
+
+	c := newContext(conf, nil)
+	test.That(t, stringFlagOrConfigFile(c, generalFlagOrgID), test.ShouldEqual, "org-id")
+	test.That(t, stringFlagOrConfigFile(c, dataFlagDestination), test.ShouldEqual, "")
+	orgID, err := requiredStringFlagOrConfigFile(c, generalFlagOrgID)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, orgID, test.ShouldEqual, "org-id")
+	_, err = requiredStringFlagOrConfigFile(c, generalFlagLocationID)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, generalFlagLocationID)
+
+	// a passed flag overrides the config file.
+	c = newContext(conf, map[string]string{generalFlagOrgID: "other-org-id"})
+	test.That(t, stringFlagOrConfigFile(c, generalFlagOrgID), test.ShouldEqual, "other-org-id")
+
+	c = newContext(nil, nil)
+	test.That(t, stringFlagOrConfigFile(c, generalFlagOrgID), test.ShouldEqual, "")
+	_, err = requiredStringFlagOrConfigFile(c, generalFlagOrgID)
+	test.That(t, err, test.ShouldNotBeNil)
+}
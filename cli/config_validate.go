@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+
+	rconfig "go.viam.com/rdk/config"
+	"go.viam.com/rdk/logging"
+)
+
+// configValidateStdin is the file argument to 'config validate' that reads the config from stdin.
+const configValidateStdin = "-"
+
+// ConfigValidateAction is the corresponding action for 'config validate'.
+func ConfigValidateAction(c *cli.Context) error {
+	if c.Args().Len() != 1 {
+		return errors.New("expected exactly one argument: the path of the config to validate, or - to read it from stdin")
+	}
+	path := c.Args().First()
+
+	var r io.Reader = c.App.Reader
+	// config includes are resolved relative to the config file, so for stdin they are relative to the working directory.
+	originalPath := ""
+	if path != configValidateStdin {
+		//nolint:gosec
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		//nolint:errcheck
+		defer f.Close()
+		r = f
+		originalPath = path
+	}
+
+	logger := logging.FromZapCompatible(zap.NewNop().Sugar())
+	if c.Bool(debugFlag) {
+		logger = logging.NewDebugLogger("cli")
+	}
+	if err := validateConfig(originalPath, r, logger); err != nil {
+		return errors.Errorf("%s is invalid:\n%s", configValidateName(path), err)
+	}
+	printf(c.App.Writer, "%s is valid", configValidateName(path))
+	return nil
+}
+
+// validateConfig runs the config in r through the same parsing, processing, and validation that
+// viam-server does when it starts, without fetching anything from the cloud. Each error is returned on
+// its own line.
+func validateConfig(originalPath string, r io.Reader, logger logging.Logger) error {
+	if err := rconfig.ValidateFromReader(originalPath, r, logger); err != nil {
+		// a config that fails validation in several places reports every failure, each with its location.
+		errs := multierr.Errors(errors.Cause(err))
+		if len(errs) <= 1 {
+			errs = []error{err}
+		}
+		lines := make([]string, 0, len(errs))
+		for _, err := range errs {
+			lines = append(lines, "  "+err.Error())
+		}
+		return errors.New(strings.Join(lines, "\n"))
+	}
+	return nil
+}
+
+func configValidateName(path string) string {
+	if path == configValidateStdin {
+		return "config from stdin"
+	}
+	return path
+}
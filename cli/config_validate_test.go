@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestValidateConfig(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+
+	valid := `{"components": [{"name": "arm1", "api": "rdk:component:arm", "model": "fake"}]}`
+	test.That(t, validateConfig("", strings.NewReader(valid), logger), test.ShouldBeNil)
+
+	err := validateConfig("", strings.NewReader(`{"components": [`), logger)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "failed to decode Config from json")
+
+	invalid := `{
+  "components": [
+    {"api": "rdk:component:arm", "model": "fake"},
+    {"name": "base1", "api": "rdk:component:base"}
+  ]
+}`
+	err = validateConfig("", strings.NewReader(invalid), logger)
+	test.That(t, err, test.ShouldNotBeNil)
+	lines := strings.Split(err.Error(), "\n")
+	test.That(t, len(lines), test.ShouldEqual, 2)
+	test.That(t, lines[0], test.ShouldContainSubstring, "components.0 (line 3, column 5)")
+	test.That(t, lines[1], test.ShouldContainSubstring, "base1")
+}
+
+func TestConfigValidateAction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	test.That(t, os.WriteFile(path, []byte(`{"components": [{"name": "arm1", "api": "rdk:component:arm", "model": "fake"}]}`), 0o600),
+		test.ShouldBeNil)
+
+	out := &testWriter{}
+	app := NewApp(out, &testWriter{})
+	test.That(t, app.Run([]string{"viam", "config", "validate", path}), test.ShouldBeNil)
+	test.That(t, strings.Join(out.messages, ""), test.ShouldContainSubstring, path+" is valid")
+
+	app.Reader = strings.NewReader(`{"components": [{"api": "rdk:component:arm", "model": "fake"}]}`)
+	err := app.Run([]string{"viam", "config", "validate", "-"})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldStartWith, "config from stdin is invalid:")
+
+	test.That(t, app.Run([]string{"viam", "config", "validate"}), test.ShouldNotBeNil)
+}
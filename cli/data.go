@@ -9,16 +9,22 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/docker/go-units"
 	"github.com/pkg/errors"
 	"github.com/urfave/cli/v2"
 	datapb "go.viam.com/api/app/data/v1"
+	"golang.org/x/exp/slices"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
@@ -32,12 +38,22 @@ const (
 	logEveryN     = 100
 	maxLimit      = 100
 
+	defaultDataRetries = 3
+
 	dataTypeBinary  = "binary"
 	dataTypeTabular = "tabular"
 
 	gzFileExt = ".gz"
+
+	// dataFilterDateLayout is the date-only form accepted by the --start and --end filters.
+	dataFilterDateLayout = "2006-01-02"
+	// dataFilterTimeNow is the value of --start or --end meaning the current time.
+	dataFilterTimeNow = "now"
 )
 
+// dataRetryInitialBackoff is how long to wait before the first retry of a failed data request.
+var dataRetryInitialBackoff = time.Second
+
 // DataExportAction is the corresponding action for 'data export'.
 func DataExportAction(c *cli.Context) error {
 	client, err := newViamClient(c)
@@ -49,18 +65,40 @@ func DataExportAction(c *cli.Context) error {
 }
 
 func (c *viamClient) dataExportAction(cCtx *cli.Context) error {
-	filter, err := createDataFilter(cCtx)
+	filter, err := parseDataFilter(cCtx)
+	if err != nil {
+		return err
+	}
+	globs, err := newComponentGlobFilter(filter)
 	if err != nil {
 		return err
 	}
 
+	if cCtx.Bool(dataFlagDryRun) {
+		return c.dataExportDryRun(cCtx.String(dataFlagDataType), filter, globs)
+	}
+
 	switch cCtx.String(dataFlagDataType) {
 	case dataTypeBinary:
-		if err := c.binaryData(cCtx.Path(dataFlagDestination), filter, cCtx.Uint(dataFlagParallelDownloads)); err != nil {
+		parallel, err := clampParallelDownloads(cCtx.App.ErrWriter, cCtx.Uint(dataFlagParallelDownloads))
+		if err != nil {
+			return err
+		}
+		layout, err := parseExportLayout(cCtx.String(dataFlagLayout))
+		if err != nil {
+			return err
+		}
+		opts := binaryDownloadOptions{
+			layout:          layout,
+			convert:         cCtx.Bool(dataFlagConvert),
+			includeMetadata: cCtx.Bool(dataFlagIncludeMetadata),
+		}
+		if err := c.binaryData(cCtx.Path(dataFlagDestination), filter, globs, parallel,
+			cCtx.Bool(dataFlagResume), opts); err != nil {
 			return err
 		}
 	case dataTypeTabular:
-		if err := c.tabularData(cCtx.Path(dataFlagDestination), filter); err != nil {
+		if err := c.tabularData(cCtx.Path(dataFlagDestination), filter, globs); err != nil {
 			return err
 		}
 	default:
@@ -69,6 +107,142 @@ func (c *viamClient) dataExportAction(cCtx *cli.Context) error {
 	return nil
 }
 
+// exportSummary describes the data that matches an export filter.
+type exportSummary struct {
+	count       uint64
+	sizeBytes   uint64
+	first, last time.Time
+	byComponent map[string]uint64
+}
+
+func (s *exportSummary) add(md *datapb.CaptureMetadata, timeRequested time.Time) {
+	s.count++
+	if s.first.IsZero() || timeRequested.Before(s.first) {
+		s.first = timeRequested
+	}
+	if timeRequested.After(s.last) {
+		s.last = timeRequested
+	}
+	s.byComponent[fmt.Sprintf("%s (%s)", md.GetComponentName(), md.GetComponentType())]++
+}
+
+// dataExportDryRun prints a summary of the data that matches filter and globs without downloading anything.
+func (c *viamClient) dataExportDryRun(dataType string, filter *datapb.Filter, globs *componentGlobFilter) error {
+	if err := c.ensureLoggedIn(); err != nil {
+		return err
+	}
+
+	var summary *exportSummary
+	var err error
+	var unit string
+	switch dataType {
+	case dataTypeBinary:
+		summary, err = c.binaryDataSummary(filter, globs)
+		unit = "files"
+	case dataTypeTabular:
+		summary, err = c.tabularDataSummary(filter, globs)
+		unit = "datapoints"
+	default:
+		return errors.Errorf("%s must be binary or tabular, got %q", dataFlagDataType, dataType)
+	}
+	if err != nil {
+		return err
+	}
+
+	printf(c.c.App.Writer, "Dry run: %d %s (approximately %s) match the filter", summary.count, unit,
+		units.HumanSize(float64(summary.sizeBytes)))
+	if summary.count == 0 {
+		return nil
+	}
+	printf(c.c.App.Writer, "Captured between %s and %s", summary.first.Format(time.RFC3339), summary.last.Format(time.RFC3339))
+	printf(c.c.App.Writer, "By component:")
+	components := make([]string, 0, len(summary.byComponent))
+	for component := range summary.byComponent {
+		components = append(components, component)
+	}
+	sort.Strings(components)
+	for _, component := range components {
+		printf(c.c.App.Writer, "\t%s: %d", component, summary.byComponent[component])
+	}
+	return nil
+}
+
+// binaryDataSummary pages through the metadata of all binary data matching filter and globs.
+func (c *viamClient) binaryDataSummary(filter *datapb.Filter, globs *componentGlobFilter) (*exportSummary, error) {
+	summary := &exportSummary{byComponent: make(map[string]uint64)}
+	var last string
+	for {
+		resp, err := c.dataClient.BinaryDataByFilter(c.c.Context, &datapb.BinaryDataByFilterRequest{
+			DataRequest: &datapb.DataRequest{
+				Filter: filter,
+				Limit:  maxLimit,
+				Last:   last,
+			},
+			CountOnly:     false,
+			IncludeBinary: false,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "received error from server")
+		}
+		if len(resp.GetData()) == 0 {
+			return summary, nil
+		}
+		last = resp.GetLast()
+
+		var matched uint64
+		for _, bd := range resp.GetData() {
+			md := bd.GetMetadata()
+			if !globs.matches(md.GetCaptureMetadata()) {
+				continue
+			}
+			matched++
+			summary.add(md.GetCaptureMetadata(), md.GetTimeRequested().AsTime())
+		}
+		// Sizes are only reported per page, so attribute the page's size evenly across the matched data.
+		summary.sizeBytes += resp.GetTotalSizeBytes() * matched / uint64(len(resp.GetData()))
+	}
+}
+
+// tabularDataSummary pages through all tabular data matching filter and globs.
+func (c *viamClient) tabularDataSummary(filter *datapb.Filter, globs *componentGlobFilter) (*exportSummary, error) {
+	summary := &exportSummary{byComponent: make(map[string]uint64)}
+	var last string
+	for {
+		resp, err := c.dataClient.TabularDataByFilter(c.c.Context, &datapb.TabularDataByFilterRequest{
+			DataRequest: &datapb.DataRequest{
+				Filter: filter,
+				Limit:  maxLimit,
+				Last:   last,
+			},
+			CountOnly: false,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "received error from server")
+		}
+		if len(resp.GetMetadata()) == 0 {
+			return summary, nil
+		}
+		last = resp.GetLast()
+
+		var matched uint64
+		mds := resp.GetMetadata()
+		for _, datum := range resp.GetData() {
+			if int(datum.GetMetadataIndex()) >= len(mds) {
+				continue
+			}
+			md := mds[datum.GetMetadataIndex()]
+			if !globs.matches(md) {
+				continue
+			}
+			matched++
+			summary.add(md, datum.GetTimeRequested().AsTime())
+		}
+		if len(resp.GetData()) != 0 {
+			summary.sizeBytes += resp.GetTotalSizeBytes() * matched / uint64(len(resp.GetData()))
+		}
+	}
+}
+
 // DataDeleteBinaryAction is the corresponding action for 'data delete'.
 func DataDeleteBinaryAction(c *cli.Context) error {
 	client, err := newViamClient(c)
@@ -76,11 +250,14 @@ func DataDeleteBinaryAction(c *cli.Context) error {
 		return err
 	}
 
-	filter, err := createDataFilter(c)
+	filter, err := parseDataFilter(c)
 	if err != nil {
 		return err
 	}
-	if err := client.deleteBinaryData(filter); err != nil {
+	if err := client.confirmDeleteBinaryData(filter); err != nil {
+		return err
+	}
+	if err := client.deleteBinaryData(filter, c.Int(dataFlagRetries)); err != nil {
 		return err
 	}
 	return nil
@@ -88,18 +265,116 @@ func DataDeleteBinaryAction(c *cli.Context) error {
 
 // DataDeleteTabularAction is the corresponding action for 'data delete-tabular'.
 func DataDeleteTabularAction(c *cli.Context) error {
+	orgID, err := requiredStringFlagOrConfigFile(c, generalFlagOrgID)
+	if err != nil {
+		return err
+	}
 	client, err := newViamClient(c)
 	if err != nil {
 		return err
 	}
 
-	if err := client.deleteTabularData(c.String(generalFlagOrgID), c.Int(dataFlagDeleteTabularDataOlderThanDays)); err != nil {
+	description := fmt.Sprintf("This will permanently delete all tabular data in organization %s", orgID)
+	if days := c.Int(dataFlagDeleteTabularDataOlderThanDays); days > 0 {
+		description += fmt.Sprintf(" that is older than %d days", days)
+	}
+	if err := confirmDestructiveAction(c, description); err != nil {
+		return err
+	}
+	if err := client.deleteTabularData(orgID, c.Int(dataFlagDeleteTabularDataOlderThanDays),
+		c.Int(dataFlagRetries)); err != nil {
 		return err
 	}
 	return nil
 }
 
-func createDataFilter(c *cli.Context) (*datapb.Filter, error) {
+// sharedDataFilterFlags returns the flags read by parseDataFilter. Every command that filters data uses them so
+// that the same filters are available everywhere.
+func sharedDataFilterFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:  dataFlagOrgIDs,
+			Usage: "orgs filter",
+		},
+		&cli.StringSliceFlag{
+			Name:  dataFlagLocationIDs,
+			Usage: "locations filter",
+		},
+		&AliasStringFlag{
+			cli.StringFlag{
+				Name:    generalFlagMachineID,
+				Aliases: []string{generalFlagAliasRobotID},
+				Usage:   "machine id filter",
+			},
+		},
+		&cli.StringFlag{
+			Name:  dataFlagPartID,
+			Usage: "part id filter",
+		},
+		&AliasStringFlag{
+			cli.StringFlag{
+				Name:    dataFlagMachineName,
+				Aliases: []string{dataFlagAliasRobotName},
+				Usage:   "machine name filter",
+			},
+		},
+		&cli.StringFlag{
+			Name:  dataFlagPartName,
+			Usage: "part name filter",
+		},
+		&cli.StringFlag{
+			Name:  dataFlagComponentType,
+			Usage: "component type filter",
+		},
+		&cli.StringFlag{
+			Name:  dataFlagComponentName,
+			Usage: "component name filter",
+		},
+		&cli.StringFlag{
+			Name:  dataFlagMethod,
+			Usage: "method filter",
+		},
+		&cli.StringSliceFlag{
+			Name:  dataFlagMimeTypes,
+			Usage: "mime types filter",
+		},
+		&cli.StringFlag{
+			Name: dataFlagStart,
+			Usage: "start of the interval filter. accepts an ISO-8601 timestamp, a date (midnight UTC), now, " +
+				"or a time before now like -30m, -24h, -7d or -2w",
+		},
+		&cli.StringFlag{
+			Name: dataFlagEnd,
+			Usage: "end of the interval filter. accepts an ISO-8601 timestamp, a date (midnight UTC), now, " +
+				"or a time before now like -30m, -24h, -7d or -2w",
+		},
+		&cli.StringSliceFlag{
+			Name: dataFlagTags,
+			Usage: "tags filter. " +
+				"accepts tagged for all tagged data, untagged for all untagged data, or a list of tags for all data matching any of the tags",
+		},
+		&cli.StringSliceFlag{
+			Name: dataFlagBboxLabels,
+			Usage: "bbox labels filter. " +
+				"accepts string labels corresponding to bounding boxes within images",
+		},
+	}
+}
+
+// dataFilterFlagsWithout returns the shared data filter flags except the one named name, for commands that give
+// that flag another meaning.
+func dataFilterFlagsWithout(name string) []cli.Flag {
+	var flags []cli.Flag
+	for _, flag := range sharedDataFilterFlags() {
+		if !slices.Contains(flag.Names(), name) {
+			flags = append(flags, flag)
+		}
+	}
+	return flags
+}
+
+// parseDataFilter builds a filter from the flags returned by sharedDataFilterFlags.
+func parseDataFilter(c *cli.Context) (*datapb.Filter, error) {
 	filter := &datapb.Filter{}
 
 	if c.StringSlice(dataFlagOrgIDs) != nil {
@@ -152,22 +427,19 @@ func createDataFilter(c *cli.Context) (*datapb.Filter, error) {
 	if len(c.StringSlice(dataFlagBboxLabels)) != 0 {
 		filter.BboxLabels = c.StringSlice(dataFlagBboxLabels)
 	}
-	var start *timestamppb.Timestamp
-	var end *timestamppb.Timestamp
-	timeLayout := time.RFC3339
-	if c.String(dataFlagStart) != "" {
-		t, err := time.Parse(timeLayout, c.String(dataFlagStart))
-		if err != nil {
-			return nil, errors.Wrap(err, "could not parse start flag")
-		}
-		start = timestamppb.New(t)
+	// relative times are resolved against the same instant so that --start=-1d --end=now spans exactly a day.
+	now := time.Now()
+	start, err := parseDataFilterTime(dataFlagStart, c.String(dataFlagStart), now)
+	if err != nil {
+		return nil, err
 	}
-	if c.String(dataFlagEnd) != "" {
-		t, err := time.Parse(timeLayout, c.String(dataFlagEnd))
-		if err != nil {
-			return nil, errors.Wrap(err, "could not parse end flag")
-		}
-		end = timestamppb.New(t)
+	end, err := parseDataFilterTime(dataFlagEnd, c.String(dataFlagEnd), now)
+	if err != nil {
+		return nil, err
+	}
+	if start != nil && end != nil && start.AsTime().After(end.AsTime()) {
+		return nil, errors.Errorf("invalid --%s: %s is after --%s %s", dataFlagStart,
+			c.String(dataFlagStart), dataFlagEnd, c.String(dataFlagEnd))
 	}
 	if start != nil || end != nil {
 		filter.Interval = &datapb.CaptureInterval{
@@ -178,29 +450,223 @@ func createDataFilter(c *cli.Context) (*datapb.Filter, error) {
 	return filter, nil
 }
 
-// BinaryData downloads binary data matching filter to dst.
-func (c *viamClient) binaryData(dst string, filter *datapb.Filter, parallelDownloads uint) error {
+// parseDataFilterTime parses the value of the time filter flag named name. It accepts an ISO-8601 timestamp, a
+// date, which is taken as midnight UTC, "now", or a time before now such as -7d or -1h30m. Relative times must
+// start with "-" since the data filtered on is never in the future. It returns nil if value is empty.
+func parseDataFilterTime(name, value string, now time.Time) (*timestamppb.Timestamp, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if value == dataFilterTimeNow {
+		return timestamppb.New(now), nil
+	}
+	for _, layout := range []string{time.RFC3339, dataFilterDateLayout} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return timestamppb.New(t), nil
+		}
+	}
+	if ago, ok := strings.CutPrefix(value, "-"); ok {
+		d, err := parseDataFilterDuration(ago)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid --%s", name)
+		}
+		return timestamppb.New(now.Add(-d)), nil
+	}
+	if _, err := parseDataFilterDuration(strings.TrimPrefix(value, "+")); err == nil {
+		return nil, errors.Errorf("invalid --%s: %q is ambiguous; use -%s for a time before now",
+			name, value, strings.TrimPrefix(value, "+"))
+	}
+	return nil, errors.Errorf("invalid --%s: %q is not a date like %s, a timestamp like %s, %s, or a time before now like -7d",
+		name, value, dataFilterDateLayout, time.RFC3339, dataFilterTimeNow)
+}
+
+// parseDataFilterDuration parses a duration like time.ParseDuration does, and also a whole number of days or
+// weeks like 7d or 2w.
+func parseDataFilterDuration(value string) (time.Duration, error) {
+	for unit, size := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if count, ok := strings.CutSuffix(value, unit); ok {
+			n, err := strconv.ParseUint(count, 10, 16)
+			if err != nil {
+				return 0, errors.Errorf("%q is not a whole number of %s", value, unit)
+			}
+			return time.Duration(n) * size, nil
+		}
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if d < 0 {
+		return 0, errors.Errorf("%q is negative", value)
+	}
+	return d, nil
+}
+
+// componentGlobFilter matches capture metadata against glob patterns for the component name and type.
+// The data service only supports exact matches, so when a pattern is used its server-side filter field
+// is cleared and matching is done client-side. Patterns combine with each other and with every other
+// filter using a logical AND, while a single pattern matches any component it expands to.
+type componentGlobFilter struct {
+	componentName string
+	componentType string
+}
+
+// newComponentGlobFilter moves any glob patterns in filter's component name and type into the returned
+// componentGlobFilter. It returns nil if filter contains no patterns and an error if a pattern is invalid.
+func newComponentGlobFilter(filter *datapb.Filter) (*componentGlobFilter, error) {
+	var globs componentGlobFilter
+	if isGlobPattern(filter.GetComponentName()) {
+		if _, err := path.Match(filter.GetComponentName(), ""); err != nil {
+			return nil, errors.Wrapf(err, "invalid %s pattern %q", dataFlagComponentName, filter.GetComponentName())
+		}
+		globs.componentName = filter.GetComponentName()
+		filter.ComponentName = ""
+	}
+	if isGlobPattern(filter.GetComponentType()) {
+		if _, err := path.Match(filter.GetComponentType(), ""); err != nil {
+			return nil, errors.Wrapf(err, "invalid %s pattern %q", dataFlagComponentType, filter.GetComponentType())
+		}
+		globs.componentType = filter.GetComponentType()
+		filter.ComponentType = ""
+	}
+	if globs.componentName == "" && globs.componentType == "" {
+		return nil, nil
+	}
+	return &globs, nil
+}
+
+func isGlobPattern(s string) bool {
+	return strings.ContainsAny(s, "*?[")
+}
+
+// matches returns true if md matches all patterns. A nil componentGlobFilter matches everything.
+func (g *componentGlobFilter) matches(md *datapb.CaptureMetadata) bool {
+	if g == nil {
+		return true
+	}
+	// Patterns have already been validated, so errors can be ignored.
+	if g.componentName != "" {
+		if ok, _ := path.Match(g.componentName, md.GetComponentName()); !ok {
+			return false
+		}
+	}
+	if g.componentType != "" {
+		if ok, _ := path.Match(g.componentType, md.GetComponentType()); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// BinaryData downloads binary data matching filter to dst as described by opts. If resume is true, files
+// recorded in the export manifest of a previous run are verified and skipped rather than downloaded again.
+func (c *viamClient) binaryData(dst string, filter *datapb.Filter, globs *componentGlobFilter,
+	parallelDownloads uint, resume bool, opts binaryDownloadOptions,
+) error {
 	if err := c.ensureLoggedIn(); err != nil {
 		return err
 	}
 
-	return c.performActionOnBinaryDataFromFilter(
+	var manifest *exportManifest
+	if resume {
+		var err error
+		if manifest, err = loadExportManifest(dst, opts.layout); err != nil {
+			return err
+		}
+	}
+
+	var total uint64
+	// globs are matched client-side, so the server can only count the data when there are none.
+	if globs == nil {
+		resp, err := c.dataClient.BinaryDataByFilter(c.c.Context, &datapb.BinaryDataByFilterRequest{
+			DataRequest: &datapb.DataRequest{Filter: filter},
+			CountOnly:   true,
+		})
+		if err == nil {
+			total = resp.GetCount()
+		}
+	}
+	progress := newExportProgress(c.c.App.Writer, total, stdoutIsTerminal(), time.Now)
+
+	format, err := outputFormat(c.c)
+	if err != nil {
+		return err
+	}
+
+	// each file's result is sent to a single collector so that one failure neither stops the export nor is lost.
+	results := make(chan binaryExportResult, parallelDownloads)
+	summaryCh := make(chan *binaryExportSummary, 1)
+	go func() {
+		summaryCh <- collectBinaryExportResults(results)
+	}()
+	err = c.performActionOnBinaryDataFromFilter(
 		func(id *datapb.BinaryID) error {
-			return downloadBinary(c.c.Context, c.dataClient, dst, id)
-		},
-		filter, parallelDownloads,
-		func(i int32) {
-			printf(c.c.App.Writer, "Downloaded %d files", i)
+			if manifest != nil && manifest.isComplete(id.GetFileId()) {
+				progress.add(0)
+				results <- binaryExportResult{fileID: id.GetFileId(), skipped: true}
+				return nil
+			}
+			var dataPath string
+			var err error
+			var verificationErr *downloadVerificationError
+			for attempt := 0; attempt < maxDownloadVerifyAttempts; attempt++ {
+				dataPath, _, err = downloadBinary(c.c.Context, c.dataClient, dst, id, opts)
+				if !errors.As(err, &verificationErr) {
+					break
+				}
+				warningf(c.c.App.ErrWriter, "%s", verificationErr)
+			}
+			if err != nil {
+				// the export is being canceled, so stop instead of failing every remaining file.
+				if ctxErr := c.c.Context.Err(); ctxErr != nil {
+					return ctxErr
+				}
+				progress.add(0)
+				results <- binaryExportResult{fileID: id.GetFileId(), err: err}
+				return nil
+			}
+			var size uint64
+			if info, err := os.Stat(dataPath); err == nil {
+				size = uint64(info.Size())
+			}
+			progress.add(size)
+			if manifest != nil {
+				if err := manifest.record(id.GetFileId(), dataPath); err != nil {
+					return err
+				}
+			}
+			results <- binaryExportResult{fileID: id.GetFileId(), bytes: size}
+			return nil
 		},
+		filter, globs, parallelDownloads,
+		// progress is reported as files are downloaded instead.
+		func(int32) {},
 	)
+	close(results)
+	summary := <-summaryCh
+	progress.finish()
+	if manifest != nil {
+		// Always flush the manifest, even on error, so that the next run can resume where this one stopped.
+		if flushErr := manifest.flush(); flushErr != nil && err == nil {
+			err = flushErr
+		}
+	}
+	if printErr := summary.print(c.c.App.Writer, format); printErr != nil && err == nil {
+		err = printErr
+	}
+	if err != nil {
+		return err
+	}
+	return summary.err()
 }
 
 // performActionOnBinaryDataFromFilter is a helper action that retrieves all BinaryIDs associated with
-// a filter in batches and then performs actionOnBinaryData on each binary data in parallel.
+// a filter in batches and then performs actionOnBinaryData on each binary data in parallel. If globs is
+// non-nil, only binary data whose metadata matches it is acted on.
 // Each time `logEveryN` actions have been performed, the printStatement logs a statement that takes in as
 // input how much binary data has been processed thus far.
 func (c *viamClient) performActionOnBinaryDataFromFilter(actionOnBinaryData func(*datapb.BinaryID) error,
-	filter *datapb.Filter, parallelActions uint, printStatement func(int32),
+	filter *datapb.Filter, globs *componentGlobFilter, parallelActions uint, printStatement func(int32),
 ) error {
 	ids := make(chan *datapb.BinaryID, parallelActions)
 	// Give channel buffer of 1+parallelActions because that is the number of goroutines that may be passing an
@@ -221,7 +687,7 @@ func (c *viamClient) performActionOnBinaryDataFromFilter(actionOnBinaryData func
 		} else {
 			limit = parallelActions
 		}
-		if err := getMatchingBinaryIDs(ctx, c.dataClient, filter, ids, limit); err != nil {
+		if err := getMatchingBinaryIDs(ctx, c.dataClient, filter, globs, ids, limit); err != nil {
 			errs <- err
 			cancel()
 		}
@@ -287,9 +753,9 @@ func (c *viamClient) performActionOnBinaryDataFromFilter(actionOnBinaryData func
 	return nil
 }
 
-// getMatchingIDs queries client for all BinaryData matching filter, and passes each of their ids into ids.
+// getMatchingIDs queries client for all BinaryData matching filter and globs, and passes each of their ids into ids.
 func getMatchingBinaryIDs(ctx context.Context, client datapb.DataServiceClient, filter *datapb.Filter,
-	ids chan *datapb.BinaryID, limit uint,
+	globs *componentGlobFilter, ids chan *datapb.BinaryID, limit uint,
 ) error {
 	var last string
 	defer close(ids)
@@ -318,6 +784,9 @@ func getMatchingBinaryIDs(ctx context.Context, client datapb.DataServiceClient,
 
 		for _, bd := range resp.GetData() {
 			md := bd.GetMetadata()
+			if !globs.matches(md.GetCaptureMetadata()) {
+				continue
+			}
 			ids <- &datapb.BinaryID{
 				FileId:         md.GetId(),
 				OrganizationId: md.GetCaptureMetadata().GetOrganizationId(),
@@ -327,7 +796,22 @@ func getMatchingBinaryIDs(ctx context.Context, client datapb.DataServiceClient,
 	}
 }
 
-func downloadBinary(ctx context.Context, client datapb.DataServiceClient, dst string, id *datapb.BinaryID) error {
+// binaryDownloadOptions control how downloadBinary saves binary data.
+type binaryDownloadOptions struct {
+	// layout organizes the data and metadata directories into subdirectories.
+	layout exportLayout
+	// convert saves data whose mime type has an exportConversion in the converted format.
+	convert bool
+	// includeMetadata additionally writes the metadata of each file to a <file>.json sidecar next to it.
+	includeMetadata bool
+}
+
+// downloadBinary downloads the binary data with the given id to dst as described by opts and returns the path
+// of the data file and its metadata. If the file on disk is not the whole payload, a *downloadVerificationError
+// is returned.
+func downloadBinary(ctx context.Context, client datapb.DataServiceClient, dst string, id *datapb.BinaryID,
+	opts binaryDownloadOptions,
+) (string, *datapb.BinaryMetadata, error) {
 	var resp *datapb.BinaryDataByIDsResponse
 	var err error
 	for count := 0; count < maxRetryCount; count++ {
@@ -340,36 +824,39 @@ func downloadBinary(ctx context.Context, client datapb.DataServiceClient, dst st
 		}
 	}
 	if err != nil {
-		return errors.Wrapf(err, "received error from server")
+		return "", nil, errors.Wrapf(err, "received error from server")
 	}
 	data := resp.GetData()
 
 	if len(data) != 1 {
-		return errors.Errorf("expected a single response, received %d", len(data))
+		return "", nil, errors.Errorf("expected a single response, received %d", len(data))
 	}
 
 	datum := data[0]
 
-	fileName := filenameForDownload(datum.GetMetadata())
+	fileName := filepath.Join(opts.layout.dir(datum.GetMetadata()), filenameForDownload(datum.GetMetadata()))
 	// Modify the file name in the metadata to reflect what it will be saved as.
 	metadata := datum.GetMetadata()
 	metadata.FileName = fileName
 
 	jsonPath := filepath.Join(dst, metadataDir, fileName+".json")
 	if err := os.MkdirAll(filepath.Dir(jsonPath), 0o700); err != nil {
-		return errors.Wrapf(err, "could not create metadata directory %s", filepath.Dir(jsonPath))
+		return "", nil, errors.Wrapf(err, "could not create metadata directory %s", filepath.Dir(jsonPath))
 	}
 	//nolint:gosec
 	jsonFile, err := os.Create(jsonPath)
 	if err != nil {
-		return err
+		return "", nil, err
 	}
 	mdJSONBytes, err := protojson.Marshal(metadata)
 	if err != nil {
-		return err
+		return "", nil, err
 	}
 	if _, err := jsonFile.Write(mdJSONBytes); err != nil {
-		return err
+		return "", nil, err
+	}
+	if err := jsonFile.Close(); err != nil {
+		return "", nil, err
 	}
 
 	bin := datum.GetBinary()
@@ -383,7 +870,7 @@ func downloadBinary(ctx context.Context, client datapb.DataServiceClient, dst st
 	if ext == gzFileExt {
 		r, err = gzip.NewReader(r)
 		if err != nil {
-			return err
+			return "", nil, err
 		}
 	} else if filepath.Ext(dataPath) != ext {
 		// If the file name did not already include the extension (e.g. for data capture files), add it.
@@ -391,22 +878,56 @@ func downloadBinary(ctx context.Context, client datapb.DataServiceClient, dst st
 		dataPath += ext
 	}
 
+	var conversion *exportConversion
+	if opts.convert {
+		if conversion = exportConversionFor(metadata); conversion != nil {
+			dataPath = conversion.convertedPath(dataPath)
+		}
+	}
+
 	if err := os.MkdirAll(filepath.Dir(dataPath), 0o700); err != nil {
-		return errors.Wrapf(err, "could not create data directory %s", filepath.Dir(dataPath))
+		return "", nil, errors.Wrapf(err, "could not create data directory %s", filepath.Dir(dataPath))
 	}
 	//nolint:gosec
 	dataFile, err := os.Create(dataPath)
 	if err != nil {
-		return errors.Wrapf(err, fmt.Sprintf("could not create file for datum %s", datum.GetMetadata().GetId()))
-	}
-	//nolint:gosec
-	if _, err := io.Copy(dataFile, r); err != nil {
-		return err
+		return "", nil, errors.Wrapf(err, fmt.Sprintf("could not create file for datum %s", datum.GetMetadata().GetId()))
+	}
+	verifier := newDownloadVerifier(dataFile)
+	// the size of the payload is only known if it is saved as is.
+	payloadSize := int64(-1)
+	if conversion != nil {
+		if err := conversion.convert(ctx, r, verifier); err != nil {
+			return "", nil, errors.Wrapf(err, "could not convert datum %s", datum.GetMetadata().GetId())
+		}
+	} else {
+		if ext != gzFileExt {
+			payloadSize = int64(len(bin))
+		}
+		//nolint:gosec
+		if _, err := io.Copy(verifier, r); err != nil {
+			if errors.Is(err, gzip.ErrChecksum) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return "", nil, &downloadVerificationError{fileID: datum.GetMetadata().GetId(), reason: err.Error()}
+			}
+			return "", nil, err
+		}
 	}
 	if err := r.Close(); err != nil {
-		return err
+		return "", nil, err
 	}
-	return nil
+	if err := dataFile.Close(); err != nil {
+		return "", nil, err
+	}
+	// Check what actually landed on disk so that a truncated file is never passed off as complete.
+	if err := verifier.verify(datum.GetMetadata().GetId(), dataPath, payloadSize); err != nil {
+		return "", nil, err
+	}
+	if opts.includeMetadata {
+		if err := os.WriteFile(dataPath+".json", mdJSONBytes, 0o600); err != nil {
+			return "", nil, errors.Wrapf(err, "could not write metadata for datum %s", datum.GetMetadata().GetId())
+		}
+	}
+	return dataPath, metadata, nil
 }
 
 // transform datum's filename to a destination path on this computer.
@@ -435,8 +956,8 @@ func filenameForDownload(meta *datapb.BinaryMetadata) string {
 	return fileName
 }
 
-// tabularData downloads binary data matching filter to dst.
-func (c *viamClient) tabularData(dst string, filter *datapb.Filter) error {
+// tabularData downloads binary data matching filter and globs to dst.
+func (c *viamClient) tabularData(dst string, filter *datapb.Filter, globs *componentGlobFilter) error {
 	if err := c.ensureLoggedIn(); err != nil {
 		return err
 	}
@@ -486,6 +1007,9 @@ func (c *viamClient) tabularData(dst string, filter *datapb.Filter) error {
 		// Map the current response's metadata indexes to those combined across all responses.
 		localToGlobalMDIndex := make(map[int]int)
 		for i, md := range mds {
+			if !globs.matches(md) {
+				continue // Data with this metadata is excluded, so don't create a metadata file for it.
+			}
 			currMDIndex, ok := mdIndexes[md.String()]
 			if ok {
 				localToGlobalMDIndex[i] = currMDIndex
@@ -519,10 +1043,14 @@ func (c *viamClient) tabularData(dst string, filter *datapb.Filter) error {
 			if d == nil {
 				continue
 			}
+			globalMDIndex, ok := localToGlobalMDIndex[int(datum.GetMetadataIndex())]
+			if !ok {
+				continue
+			}
 			m := d.AsMap()
 			m["TimeRequested"] = datum.GetTimeRequested()
 			m["TimeReceived"] = datum.GetTimeReceived()
-			m["MetadataIndex"] = localToGlobalMDIndex[int(datum.GetMetadataIndex())]
+			m["MetadataIndex"] = globalMDIndex
 			j, err := json.Marshal(m)
 			if err != nil {
 				return errors.Wrap(err, "could not marshal JSON response")
@@ -552,12 +1080,16 @@ func makeDestinationDirs(dst string) error {
 	return nil
 }
 
-func (c *viamClient) deleteBinaryData(filter *datapb.Filter) error {
+func (c *viamClient) deleteBinaryData(filter *datapb.Filter, retries int) error {
 	if err := c.ensureLoggedIn(); err != nil {
 		return err
 	}
-	resp, err := c.dataClient.DeleteBinaryDataByFilter(context.Background(),
-		&datapb.DeleteBinaryDataByFilterRequest{Filter: filter})
+	var resp *datapb.DeleteBinaryDataByFilterResponse
+	err := c.retryDataRequest(retries, "delete", func(ctx context.Context) error {
+		var err error
+		resp, err = c.dataClient.DeleteBinaryDataByFilter(ctx, &datapb.DeleteBinaryDataByFilterRequest{Filter: filter})
+		return err
+	})
 	if err != nil {
 		return errors.Wrapf(err, "received error from server")
 	}
@@ -566,12 +1098,17 @@ func (c *viamClient) deleteBinaryData(filter *datapb.Filter) error {
 }
 
 // deleteTabularData delete tabular data matching filter.
-func (c *viamClient) deleteTabularData(orgID string, deleteOlderThanDays int) error {
+func (c *viamClient) deleteTabularData(orgID string, deleteOlderThanDays, retries int) error {
 	if err := c.ensureLoggedIn(); err != nil {
 		return err
 	}
-	resp, err := c.dataClient.DeleteTabularData(context.Background(),
-		&datapb.DeleteTabularDataRequest{OrganizationId: orgID, DeleteOlderThanDays: uint32(deleteOlderThanDays)})
+	var resp *datapb.DeleteTabularDataResponse
+	err := c.retryDataRequest(retries, "delete", func(ctx context.Context) error {
+		var err error
+		resp, err = c.dataClient.DeleteTabularData(ctx,
+			&datapb.DeleteTabularDataRequest{OrganizationId: orgID, DeleteOlderThanDays: uint32(deleteOlderThanDays)})
+		return err
+	})
 	if err != nil {
 		return errors.Wrapf(err, "received error from server")
 	}
@@ -579,13 +1116,154 @@ func (c *viamClient) deleteTabularData(orgID string, deleteOlderThanDays int) er
 	return nil
 }
 
+// confirmDeleteBinaryData asks the user to confirm deleting the binary data matching filter.
+func (c *viamClient) confirmDeleteBinaryData(filter *datapb.Filter) error {
+	if c.c.Bool(generalFlagYes) {
+		return nil
+	}
+	if err := c.ensureLoggedIn(); err != nil {
+		return err
+	}
+	resp, err := c.dataClient.BinaryDataByFilter(c.c.Context, &datapb.BinaryDataByFilterRequest{
+		DataRequest: &datapb.DataRequest{Filter: filter},
+		CountOnly:   true,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "received error from server")
+	}
+	filterJSON, err := protojson.Marshal(filter)
+	if err != nil {
+		return err
+	}
+	return confirmDestructiveAction(c.c, fmt.Sprintf("This will permanently delete %d files matching the filter %s",
+		resp.GetCount(), filterJSON))
+}
+
+// retryDataRequest calls requestFn, retrying up to retries times with exponential backoff while it fails with
+// a transient error. action names the request in warnings. requestFn must be safe to repeat: deletes are by
+// filter, so a retry of a delete that actually succeeded just deletes nothing, and adding files to a dataset
+// that already holds them is a no-op.
+func (c *viamClient) retryDataRequest(retries int, action string, requestFn func(ctx context.Context) error) error {
+	ctx := c.c.Context
+	backoff := dataRetryInitialBackoff
+	for attempt := 0; ; attempt++ {
+		err := requestFn(ctx)
+		if err == nil || attempt >= retries || !isRetryableDataError(err) {
+			return err
+		}
+		warningf(c.c.App.ErrWriter, "%s failed, retrying in %s (%d/%d): %v", action, backoff, attempt+1, retries, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func isRetryableDataError(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
+
+// DataTagAction is the corresponding action for 'data tag'.
+func DataTagAction(c *cli.Context) error {
+	client, err := newViamClient(c)
+	if err != nil {
+		return err
+	}
+	filter, err := parseDataFilter(c)
+	if err != nil {
+		return err
+	}
+	// --tags names the tags to add rather than filtering, so every file matching the other filters is tagged.
+	filter.TagsFilter = nil
+	return client.dataTag(filter, c.StringSlice(dataFlagTags), c.Bool(dataFlagDryRun))
+}
+
+// dataTag adds tags to the binary data matching filter. A dry run only prints how many files match.
+func (c *viamClient) dataTag(filter *datapb.Filter, tags []string, dryRun bool) error {
+	if err := c.ensureLoggedIn(); err != nil {
+		return err
+	}
+	// AddTagsToBinaryDataByFilter does not report how many files it tagged, so count them first.
+	resp, err := c.dataClient.BinaryDataByFilter(c.c.Context, &datapb.BinaryDataByFilterRequest{
+		DataRequest: &datapb.DataRequest{Filter: filter},
+		CountOnly:   true,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "received error from server")
+	}
+	if dryRun {
+		printf(c.c.App.Writer, "%d files match the filters and would be tagged with %s", resp.GetCount(), strings.Join(tags, ", "))
+		return nil
+	}
+	if _, err := c.dataClient.AddTagsToBinaryDataByFilter(c.c.Context,
+		&datapb.AddTagsToBinaryDataByFilterRequest{Filter: filter, Tags: tags}); err != nil {
+		return errors.Wrapf(err, "received error from server")
+	}
+	printf(c.c.App.Writer, "Tagged %d files with %s", resp.GetCount(), strings.Join(tags, ", "))
+	return nil
+}
+
+// DataUntagAction is the corresponding action for 'data untag'.
+func DataUntagAction(c *cli.Context) error {
+	client, err := newViamClient(c)
+	if err != nil {
+		return err
+	}
+	filter, err := parseDataFilter(c)
+	if err != nil {
+		return err
+	}
+	// only files with any of the tags are affected. The tags are matched as given, even tagged or untagged.
+	filter.TagsFilter = &datapb.TagsFilter{
+		Type: datapb.TagsFilterType_TAGS_FILTER_TYPE_MATCH_BY_OR,
+		Tags: c.StringSlice(dataFlagTags),
+	}
+	return client.dataUntag(filter, c.StringSlice(dataFlagTags), c.Bool(dataFlagDryRun))
+}
+
+// dataUntag removes tags from the binary data matching filter. A dry run only prints how many files match.
+func (c *viamClient) dataUntag(filter *datapb.Filter, tags []string, dryRun bool) error {
+	if err := c.ensureLoggedIn(); err != nil {
+		return err
+	}
+	if dryRun {
+		resp, err := c.dataClient.BinaryDataByFilter(c.c.Context, &datapb.BinaryDataByFilterRequest{
+			DataRequest: &datapb.DataRequest{Filter: filter},
+			CountOnly:   true,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "received error from server")
+		}
+		printf(c.c.App.Writer, "%d files match the filters and would have %s removed", resp.GetCount(), strings.Join(tags, ", "))
+		return nil
+	}
+	resp, err := c.dataClient.RemoveTagsFromBinaryDataByFilter(c.c.Context,
+		&datapb.RemoveTagsFromBinaryDataByFilterRequest{Filter: filter, Tags: tags})
+	if err != nil {
+		return errors.Wrapf(err, "received error from server")
+	}
+	printf(c.c.App.Writer, "Removed %s from %d files", strings.Join(tags, ", "), resp.GetDeletedCount())
+	return nil
+}
+
 // DataAddToDatasetByIDs is the corresponding action for 'data dataset add ids'.
 func DataAddToDatasetByIDs(c *cli.Context) error {
+	orgID, err := requiredStringFlagOrConfigFile(c, generalFlagOrgID)
+	if err != nil {
+		return err
+	}
 	client, err := newViamClient(c)
 	if err != nil {
 		return err
 	}
-	if err := client.dataAddToDatasetByIDs(c.String(datasetFlagDatasetID), c.String(generalFlagOrgID),
+	if err := client.dataAddToDatasetByIDs(c.String(datasetFlagDatasetID), orgID,
 		c.String(dataFlagLocationID), c.StringSlice(dataFlagFileIDs)); err != nil {
 		return err
 	}
@@ -620,42 +1298,96 @@ func DataAddToDatasetByFilter(c *cli.Context) error {
 	if err != nil {
 		return err
 	}
-	filter, err := createDataFilter(c)
+	filter, err := parseDataFilter(c)
 	if err != nil {
 		return err
 	}
-	if err := client.dataAddToDatasetByFilter(filter, c.String(datasetFlagDatasetID)); err != nil {
-		return err
-	}
-	return nil
+	return client.dataAddToDatasetByFilter(filter, c.String(datasetFlagDatasetID), c.Int(dataFlagRetries),
+		c.Bool(dataFlagDryRun))
 }
 
-// dataAddToDatasetByFilter adds data, with the specified filter to the dataset corresponding to the dataset ID.
-func (c *viamClient) dataAddToDatasetByFilter(filter *datapb.Filter, datasetID string) error {
+// dataAddToDatasetByFilter adds the binary data matching filter to the dataset corresponding to the dataset ID,
+// one page of matches at a time. A batch that still fails after retries is reported and skipped, and every
+// failure is returned once the other batches have been added. A dry run only prints how many files match.
+func (c *viamClient) dataAddToDatasetByFilter(filter *datapb.Filter, datasetID string, retries int, dryRun bool) error {
 	if err := c.ensureLoggedIn(); err != nil {
 		return err
 	}
-	parallelActions := uint(100)
+	if dryRun {
+		resp, err := c.dataClient.BinaryDataByFilter(c.c.Context, &datapb.BinaryDataByFilterRequest{
+			DataRequest: &datapb.DataRequest{Filter: filter},
+			CountOnly:   true,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "received error from server")
+		}
+		printf(c.c.App.Writer, "%d files match the filters and would be added to dataset ID %s", resp.GetCount(), datasetID)
+		return nil
+	}
 
-	return c.performActionOnBinaryDataFromFilter(
-		func(id *datapb.BinaryID) error {
-			_, err := c.dataClient.AddBinaryDataToDatasetByIDs(c.c.Context,
-				&datapb.AddBinaryDataToDatasetByIDsRequest{DatasetId: datasetID, BinaryIds: []*datapb.BinaryID{id}})
-			return err
-		},
-		filter, parallelActions,
-		func(i int32) {
-			printf(c.c.App.Writer, "Added %d files to dataset ID %s", i, datasetID)
+	var last string
+	var added, failed int
+	var failures []string
+	for batch := 1; ; batch++ {
+		resp, err := c.dataClient.BinaryDataByFilter(c.c.Context, &datapb.BinaryDataByFilterRequest{
+			DataRequest: &datapb.DataRequest{Filter: filter, Limit: maxLimit, Last: last},
 		})
+		if err != nil {
+			// without this page there is no way to find the rest of the matches.
+			return errors.Wrapf(err, "could not list matching data after adding %d files to dataset ID %s", added, datasetID)
+		}
+		if len(resp.GetData()) == 0 {
+			break
+		}
+		last = resp.GetLast()
+
+		ids := make([]*datapb.BinaryID, 0, len(resp.GetData()))
+		fileIDs := make([]string, 0, len(resp.GetData()))
+		for _, bd := range resp.GetData() {
+			md := bd.GetMetadata()
+			ids = append(ids, &datapb.BinaryID{
+				FileId:         md.GetId(),
+				OrganizationId: md.GetCaptureMetadata().GetOrganizationId(),
+				LocationId:     md.GetCaptureMetadata().GetLocationId(),
+			})
+			fileIDs = append(fileIDs, md.GetId())
+		}
+		if err := c.retryDataRequest(retries, "add to dataset", func(ctx context.Context) error {
+			_, err := c.dataClient.AddBinaryDataToDatasetByIDs(ctx,
+				&datapb.AddBinaryDataToDatasetByIDsRequest{DatasetId: datasetID, BinaryIds: ids})
+			return err
+		}); err != nil {
+			if ctxErr := c.c.Context.Err(); ctxErr != nil {
+				return errors.Wrapf(ctxErr, "stopped after adding %d files to dataset ID %s", added, datasetID)
+			}
+			failed += len(ids)
+			failures = append(failures, fmt.Sprintf("  batch %d (file IDs %s): %v", batch, strings.Join(fileIDs, ", "), err))
+			warningf(c.c.App.ErrWriter, "could not add batch %d of %d files to dataset ID %s, continuing: %v",
+				batch, len(ids), datasetID, err)
+			continue
+		}
+		added += len(ids)
+		printf(c.c.App.Writer, "Batch %d: added %d files to dataset ID %s (%d total)", batch, len(ids), datasetID, added)
+	}
+
+	printf(c.c.App.Writer, "Added %d files to dataset ID %s", added, datasetID)
+	if failed > 0 {
+		return errors.Errorf("failed to add %d files to dataset ID %s:\n%s", failed, datasetID, strings.Join(failures, "\n"))
+	}
+	return nil
 }
 
 // DataRemoveFromDataset is the corresponding action for 'data dataset remove'.
 func DataRemoveFromDataset(c *cli.Context) error {
+	orgID, err := requiredStringFlagOrConfigFile(c, generalFlagOrgID)
+	if err != nil {
+		return err
+	}
 	client, err := newViamClient(c)
 	if err != nil {
 		return err
 	}
-	if err := client.dataRemoveFromDataset(c.String(datasetFlagDatasetID), c.String(generalFlagOrgID),
+	if err := client.dataRemoveFromDataset(c.String(datasetFlagDatasetID), orgID,
 		c.String(dataFlagLocationID), c.StringSlice(dataFlagFileIDs)); err != nil {
 		return err
 	}
@@ -687,11 +1419,15 @@ func (c *viamClient) dataRemoveFromDataset(datasetID, orgID, locationID string,
 
 // DataConfigureDatabaseUser is the corresponding action for 'data database configure'.
 func DataConfigureDatabaseUser(c *cli.Context) error {
+	orgID, err := requiredStringFlagOrConfigFile(c, generalFlagOrgID)
+	if err != nil {
+		return err
+	}
 	client, err := newViamClient(c)
 	if err != nil {
 		return err
 	}
-	if err := client.dataConfigureDatabaseUser(c.String(generalFlagOrgID), c.String(dataFlagDatabasePassword)); err != nil {
+	if err := client.dataConfigureDatabaseUser(orgID, c.String(dataFlagDatabasePassword)); err != nil {
 		return err
 	}
 	return nil
@@ -715,11 +1451,15 @@ func (c *viamClient) dataConfigureDatabaseUser(orgID, password string) error {
 
 // DataGetDatabaseConnection is the corresponding action for 'data database hostname'.
 func DataGetDatabaseConnection(c *cli.Context) error {
+	orgID, err := requiredStringFlagOrConfigFile(c, generalFlagOrgID)
+	if err != nil {
+		return err
+	}
 	client, err := newViamClient(c)
 	if err != nil {
 		return err
 	}
-	if err := client.dataGetDatabaseConnection(c.String(generalFlagOrgID)); err != nil {
+	if err := client.dataGetDatabaseConnection(orgID); err != nil {
 		return err
 	}
 	return nil
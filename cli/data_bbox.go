@@ -0,0 +1,183 @@
+package cli
+
+import (
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+	datapb "go.viam.com/api/app/data/v1"
+)
+
+const (
+	dataFlagFileID    = "file-id"
+	dataFlagBboxID    = "bbox-id"
+	dataFlagBboxLabel = "label"
+	dataFlagXMin      = "x-min"
+	dataFlagYMin      = "y-min"
+	dataFlagXMax      = "x-max"
+	dataFlagYMax      = "y-max"
+)
+
+// DataAddBboxAction is the corresponding action for 'data bbox add'.
+func DataAddBboxAction(c *cli.Context) error {
+	orgID, err := requiredStringFlagOrConfigFile(c, generalFlagOrgID)
+	if err != nil {
+		return err
+	}
+	client, err := newViamClient(c)
+	if err != nil {
+		return err
+	}
+	id := &datapb.BinaryID{
+		OrganizationId: orgID,
+		LocationId:     c.String(dataFlagLocationID),
+		FileId:         c.String(dataFlagFileID),
+	}
+	bbox := &datapb.BoundingBox{
+		Label:          c.String(dataFlagBboxLabel),
+		XMinNormalized: c.Float64(dataFlagXMin),
+		YMinNormalized: c.Float64(dataFlagYMin),
+		XMaxNormalized: c.Float64(dataFlagXMax),
+		YMaxNormalized: c.Float64(dataFlagYMax),
+	}
+	return client.dataAddBbox(id, bbox)
+}
+
+// validateBbox returns an error if bbox has no label or its normalized coordinates do not describe a box
+// within the image.
+func validateBbox(bbox *datapb.BoundingBox) error {
+	if bbox.GetLabel() == "" {
+		return errors.New("bounding box label must not be empty")
+	}
+	for _, coord := range []struct {
+		name  string
+		value float64
+	}{
+		{dataFlagXMin, bbox.GetXMinNormalized()},
+		{dataFlagYMin, bbox.GetYMinNormalized()},
+		{dataFlagXMax, bbox.GetXMaxNormalized()},
+		{dataFlagYMax, bbox.GetYMaxNormalized()},
+	} {
+		if coord.value < 0 || coord.value > 1 {
+			return errors.Errorf("--%s must be between 0 and 1, got %v", coord.name, coord.value)
+		}
+	}
+	if bbox.GetXMinNormalized() >= bbox.GetXMaxNormalized() {
+		return errors.Errorf("--%s must be less than --%s", dataFlagXMin, dataFlagXMax)
+	}
+	if bbox.GetYMinNormalized() >= bbox.GetYMaxNormalized() {
+		return errors.Errorf("--%s must be less than --%s", dataFlagYMin, dataFlagYMax)
+	}
+	return nil
+}
+
+// dataAddBbox adds bbox to the image with the given ID and prints the image's bounding boxes.
+func (c *viamClient) dataAddBbox(id *datapb.BinaryID, bbox *datapb.BoundingBox) error {
+	if err := validateBbox(bbox); err != nil {
+		return err
+	}
+	if err := c.ensureLoggedIn(); err != nil {
+		return err
+	}
+	if _, err := c.binaryAnnotations(id); err != nil {
+		return err
+	}
+	resp, err := c.dataClient.AddBoundingBoxToImageByID(c.c.Context, &datapb.AddBoundingBoxToImageByIDRequest{
+		BinaryId:       id,
+		Label:          bbox.GetLabel(),
+		XMinNormalized: bbox.GetXMinNormalized(),
+		YMinNormalized: bbox.GetYMinNormalized(),
+		XMaxNormalized: bbox.GetXMaxNormalized(),
+		YMaxNormalized: bbox.GetYMaxNormalized(),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "received error from server")
+	}
+	printf(c.c.App.Writer, "Added bounding box %s to file %s", resp.GetBboxId(), id.GetFileId())
+	return c.printBboxes(id)
+}
+
+// DataRemoveBboxAction is the corresponding action for 'data bbox remove'.
+func DataRemoveBboxAction(c *cli.Context) error {
+	orgID, err := requiredStringFlagOrConfigFile(c, generalFlagOrgID)
+	if err != nil {
+		return err
+	}
+	client, err := newViamClient(c)
+	if err != nil {
+		return err
+	}
+	id := &datapb.BinaryID{
+		OrganizationId: orgID,
+		LocationId:     c.String(dataFlagLocationID),
+		FileId:         c.String(dataFlagFileID),
+	}
+	return client.dataRemoveBbox(id, c.String(dataFlagBboxID), c.String(dataFlagBboxLabel))
+}
+
+// dataRemoveBbox removes the bounding box with bboxID, or every bounding box with label, from the image with
+// the given ID and prints the image's remaining bounding boxes.
+func (c *viamClient) dataRemoveBbox(id *datapb.BinaryID, bboxID, label string) error {
+	if (bboxID == "") == (label == "") {
+		return errors.Errorf("exactly one of --%s or --%s is required", dataFlagBboxID, dataFlagBboxLabel)
+	}
+	if err := c.ensureLoggedIn(); err != nil {
+		return err
+	}
+	bboxes, err := c.binaryAnnotations(id)
+	if err != nil {
+		return err
+	}
+	var toRemove []string
+	for _, bbox := range bboxes {
+		if (bboxID != "" && bbox.GetId() == bboxID) || (label != "" && bbox.GetLabel() == label) {
+			toRemove = append(toRemove, bbox.GetId())
+		}
+	}
+	if len(toRemove) == 0 {
+		if bboxID != "" {
+			return errors.Errorf("file %s has no bounding box with ID %s", id.GetFileId(), bboxID)
+		}
+		return errors.Errorf("file %s has no bounding boxes labeled %s", id.GetFileId(), label)
+	}
+	for _, removeID := range toRemove {
+		if _, err := c.dataClient.RemoveBoundingBoxFromImageByID(c.c.Context,
+			&datapb.RemoveBoundingBoxFromImageByIDRequest{BinaryId: id, BboxId: removeID}); err != nil {
+			return errors.Wrapf(err, "received error from server")
+		}
+		printf(c.c.App.Writer, "Removed bounding box %s from file %s", removeID, id.GetFileId())
+	}
+	return c.printBboxes(id)
+}
+
+// binaryAnnotations returns the bounding boxes of the file with the given ID, or an error if there is no such file.
+func (c *viamClient) binaryAnnotations(id *datapb.BinaryID) ([]*datapb.BoundingBox, error) {
+	resp, err := c.dataClient.BinaryDataByIDs(c.c.Context, &datapb.BinaryDataByIDsRequest{
+		BinaryIds:     []*datapb.BinaryID{id},
+		IncludeBinary: false,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "received error from server")
+	}
+	if len(resp.GetData()) == 0 {
+		return nil, errors.Errorf("file %s not found in org %s and location %s",
+			id.GetFileId(), id.GetOrganizationId(), id.GetLocationId())
+	}
+	return resp.GetData()[0].GetMetadata().GetAnnotations().GetBboxes(), nil
+}
+
+// printBboxes prints the bounding boxes of the file with the given ID.
+func (c *viamClient) printBboxes(id *datapb.BinaryID) error {
+	bboxes, err := c.binaryAnnotations(id)
+	if err != nil {
+		return err
+	}
+	if len(bboxes) == 0 {
+		printf(c.c.App.Writer, "File %s has no bounding boxes", id.GetFileId())
+		return nil
+	}
+	printf(c.c.App.Writer, "File %s has %d bounding boxes:", id.GetFileId(), len(bboxes))
+	for _, bbox := range bboxes {
+		printf(c.c.App.Writer, "\t%s: %s (x %.4f-%.4f, y %.4f-%.4f)", bbox.GetId(), bbox.GetLabel(),
+			bbox.GetXMinNormalized(), bbox.GetXMaxNormalized(), bbox.GetYMinNormalized(), bbox.GetYMaxNormalized())
+	}
+	return nil
+}
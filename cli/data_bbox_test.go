@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	datapb "go.viam.com/api/app/data/v1"
+	"go.viam.com/test"
+	"google.golang.org/grpc"
+
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestDataBbox(t *testing.T) {
+	id := &datapb.BinaryID{OrganizationId: "org", LocationId: "loc", FileId: "file"}
+	var bboxes []*datapb.BoundingBox
+	dataClient := &inject.DataServiceClient{
+		BinaryDataByIDsFunc: func(ctx context.Context, in *datapb.BinaryDataByIDsRequest, opts ...grpc.CallOption,
+		) (*datapb.BinaryDataByIDsResponse, error) {
+			if in.GetBinaryIds()[0].GetFileId() != id.GetFileId() {
+				return &datapb.BinaryDataByIDsResponse{}, nil
+			}
+			return &datapb.BinaryDataByIDsResponse{Data: []*datapb.BinaryData{
+				{Metadata: &datapb.BinaryMetadata{Annotations: &datapb.Annotations{Bboxes: bboxes}}},
+			}}, nil
+		},
+		AddBoundingBoxToImageByIDFunc: func(ctx context.Context, in *datapb.AddBoundingBoxToImageByIDRequest,
+			opts ...grpc.CallOption,
+		) (*datapb.AddBoundingBoxToImageByIDResponse, error) {
+			bboxID := fmt.Sprintf("bbox-%d", len(bboxes))
+			bboxes = append(bboxes, &datapb.BoundingBox{
+				Id: bboxID, Label: in.GetLabel(),
+				XMinNormalized: in.GetXMinNormalized(), YMinNormalized: in.GetYMinNormalized(),
+				XMaxNormalized: in.GetXMaxNormalized(), YMaxNormalized: in.GetYMaxNormalized(),
+			})
+			return &datapb.AddBoundingBoxToImageByIDResponse{BboxId: bboxID}, nil
+		},
+		RemoveBoundingBoxFromImageByIDFunc: func(ctx context.Context, in *datapb.RemoveBoundingBoxFromImageByIDRequest,
+			opts ...grpc.CallOption,
+		) (*datapb.RemoveBoundingBoxFromImageByIDResponse, error) {
+			for i, bbox := range bboxes {
+				if bbox.GetId() == in.GetBboxId() {
+					bboxes = append(bboxes[:i], bboxes[i+1:]...)
+					break
+				}
+			}
+			return &datapb.RemoveBoundingBoxFromImageByIDResponse{}, nil
+		},
+	}
+	_, ac, out, _ := setup(&inject.AppServiceClient{}, dataClient, nil, nil, "token")
+
+	newBbox := func(label string, xMin, yMin, xMax, yMax float64) *datapb.BoundingBox {
+		return &datapb.BoundingBox{Label: label, XMinNormalized: xMin, YMinNormalized: yMin, XMaxNormalized: xMax, YMaxNormalized: yMax}
+	}
+	for _, invalid := range []*datapb.BoundingBox{
+		newBbox("", 0, 0, 1, 1),
+		newBbox("cat", -0.1, 0, 1, 1),
+		newBbox("cat", 0, 0, 1.5, 1),
+		newBbox("cat", 0.5, 0, 0.5, 1),
+		newBbox("cat", 0, 0.6, 1, 0.4),
+	} {
+		test.That(t, ac.dataAddBbox(id, invalid), test.ShouldNotBeNil)
+	}
+	test.That(t, bboxes, test.ShouldBeEmpty)
+
+	err := ac.dataAddBbox(&datapb.BinaryID{FileId: "missing"}, newBbox("cat", 0, 0, 1, 1))
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "not found")
+
+	test.That(t, ac.dataAddBbox(id, newBbox("cat", 0.1, 0.2, 0.3, 0.4)), test.ShouldBeNil)
+	test.That(t, ac.dataAddBbox(id, newBbox("dog", 0, 0, 0.5, 0.5)), test.ShouldBeNil)
+	test.That(t, ac.dataAddBbox(id, newBbox("cat", 0.5, 0.5, 1, 1)), test.ShouldBeNil)
+	test.That(t, out.messages[len(out.messages)-4], test.ShouldContainSubstring, "File file has 3 bounding boxes:")
+	test.That(t, out.messages[len(out.messages)-3], test.ShouldContainSubstring, "bbox-0: cat (x 0.1000-0.3000, y 0.2000-0.4000)")
+
+	test.That(t, ac.dataRemoveBbox(id, "", ""), test.ShouldNotBeNil)
+	test.That(t, ac.dataRemoveBbox(id, "bbox-9", ""), test.ShouldNotBeNil)
+
+	test.That(t, ac.dataRemoveBbox(id, "", "cat"), test.ShouldBeNil)
+	test.That(t, bboxes, test.ShouldHaveLength, 1)
+	test.That(t, out.messages[len(out.messages)-2], test.ShouldContainSubstring, "File file has 1 bounding boxes:")
+
+	test.That(t, ac.dataRemoveBbox(id, "bbox-1", ""), test.ShouldBeNil)
+	test.That(t, bboxes, test.ShouldBeEmpty)
+	test.That(t, out.messages[len(out.messages)-1], test.ShouldContainSubstring, "File file has no bounding boxes")
+}
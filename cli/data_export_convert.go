@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"context"
+	"image/png"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	datapb "go.viam.com/api/app/data/v1"
+
+	"go.viam.com/rdk/rimage"
+	"go.viam.com/rdk/utils"
+)
+
+// exportConversion transcodes binary data stored in a Viam-specific encoding into a common format.
+type exportConversion struct {
+	// ext is the file extension of the converted file.
+	ext     string
+	convert func(ctx context.Context, r io.Reader, w io.Writer) error
+}
+
+// exportConversions are the conversions applied by data export with --convert, keyed by source mime type.
+// Point clouds are captured as PCD already, and JPEG and PNG images are already viewable, so they and all
+// other data are downloaded as-is.
+var exportConversions = map[string]exportConversion{
+	// Raw depth maps become 16-bit grayscale PNGs with the depth in millimeters as each pixel's value.
+	utils.MimeTypeRawDepth: {ext: ".png", convert: convertRawDepthToPNG},
+	// Raw RGBA images become PNGs.
+	utils.MimeTypeRawRGBA: {ext: ".png", convert: convertRawRGBAToPNG},
+}
+
+// exportConversionMimeTypesByExt identifies the source mime type of data with no mime type in its capture
+// metadata by its file extension.
+var exportConversionMimeTypesByExt = map[string]string{
+	".dep":  utils.MimeTypeRawDepth,
+	".rgba": utils.MimeTypeRawRGBA,
+}
+
+// exportConversionFor returns the conversion for the binary data described by md, or nil if its mime type is
+// not one that is converted.
+func exportConversionFor(md *datapb.BinaryMetadata) *exportConversion {
+	mimeType, _ := utils.CheckLazyMIMEType(md.GetCaptureMetadata().GetMimeType())
+	if mimeType == "" {
+		mimeType = exportConversionMimeTypesByExt[strings.ToLower(md.GetFileExt())]
+	}
+	conversion, ok := exportConversions[mimeType]
+	if !ok {
+		return nil
+	}
+	return &conversion
+}
+
+// convertedPath returns dataPath with its extension replaced by that of the converted file.
+func (conv *exportConversion) convertedPath(dataPath string) string {
+	if _, ok := exportConversionMimeTypesByExt[strings.ToLower(filepath.Ext(dataPath))]; ok {
+		dataPath = strings.TrimSuffix(dataPath, filepath.Ext(dataPath))
+	}
+	return dataPath + conv.ext
+}
+
+func convertRawDepthToPNG(ctx context.Context, r io.Reader, w io.Writer) error {
+	dm, err := rimage.ReadDepthMap(r)
+	if err != nil {
+		return errors.Wrap(err, "could not read raw depth map")
+	}
+	return dm.WriteToBuf(w)
+}
+
+func convertRawRGBAToPNG(ctx context.Context, r io.Reader, w io.Writer) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	img, err := rimage.DecodeImage(ctx, b, utils.MimeTypeRawRGBA)
+	if err != nil {
+		return errors.Wrap(err, "could not decode raw RGBA image")
+	}
+	return png.Encode(w, img)
+}
@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	datapb "go.viam.com/api/app/data/v1"
+
+	"go.viam.com/rdk/services/datamanager/datacapture"
+)
+
+// exportLayout is how downloaded binary files are organized into subdirectories of the export destination.
+type exportLayout string
+
+const (
+	// exportLayoutFlat puts every file directly in the data directory.
+	exportLayoutFlat exportLayout = "flat"
+	// exportLayoutByComponent groups files by the component type and name that captured them.
+	exportLayoutByComponent exportLayout = "by-component"
+	// exportLayoutByDate groups files by the UTC date they were requested.
+	exportLayoutByDate exportLayout = "by-date"
+	// exportLayoutByPart groups files by the machine and part that captured them.
+	exportLayoutByPart exportLayout = "by-part"
+
+	// exportLayoutUnknownDir is used in place of a missing component, machine, or part name.
+	exportLayoutUnknownDir = "unknown"
+)
+
+var exportLayouts = []exportLayout{exportLayoutFlat, exportLayoutByComponent, exportLayoutByDate, exportLayoutByPart}
+
+// parseExportLayout returns the layout named by value.
+func parseExportLayout(value string) (exportLayout, error) {
+	names := make([]string, 0, len(exportLayouts))
+	for _, layout := range exportLayouts {
+		if value == string(layout) {
+			return layout, nil
+		}
+		names = append(names, string(layout))
+	}
+	return "", errors.Errorf("--%s must be one of %s, got %q", dataFlagLayout, strings.Join(names, ", "), value)
+}
+
+// dir returns the subdirectory, relative to the data and metadata directories, that the file described by md is
+// downloaded to. It is empty for the flat layout.
+func (l exportLayout) dir(md *datapb.BinaryMetadata) string {
+	captureMD := md.GetCaptureMetadata()
+	switch l {
+	case exportLayoutByComponent:
+		return filepath.Join(exportLayoutDirName(captureMD.GetComponentType()),
+			exportLayoutDirName(captureMD.GetComponentName()))
+	case exportLayoutByDate:
+		return md.GetTimeRequested().AsTime().UTC().Format(dataFilterDateLayout)
+	case exportLayoutByPart:
+		return filepath.Join(exportLayoutDirName(captureMD.GetRobotName()), exportLayoutDirName(captureMD.GetPartName()))
+	case exportLayoutFlat:
+		return ""
+	default:
+		return ""
+	}
+}
+
+// exportLayoutDirName turns a name from capture metadata into a single, safe directory name.
+func exportLayoutDirName(name string) string {
+	name = strings.NewReplacer("/", "_", `\`, "_").Replace(name)
+	if name == "" || name == "." || name == ".." {
+		return exportLayoutUnknownDir
+	}
+	return datacapture.FilePathWithReplacedReservedChars(name)
+}
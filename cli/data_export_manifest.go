@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// exportManifestFile is the name of the sidecar file, stored at the root of the export destination,
+// that records which files have been completely downloaded so that an interrupted export can resume.
+const exportManifestFile = ".viam-export-manifest.json"
+
+// exportManifestEntry describes a single completely downloaded file.
+type exportManifestEntry struct {
+	// Path is relative to the export destination.
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// exportManifestContents is the on-disk form of an exportManifest.
+type exportManifestContents struct {
+	Layout exportLayout                   `json:"layout"`
+	Files  map[string]exportManifestEntry `json:"files"`
+}
+
+// exportManifest tracks completely downloaded files by file ID. It is safe for concurrent use.
+type exportManifest struct {
+	mu        sync.Mutex
+	dst       string
+	layout    exportLayout
+	entries   map[string]exportManifestEntry
+	unflushed int
+}
+
+// loadExportManifest reads the manifest in dst, returning an empty manifest if none exists yet. It is an
+// error for the manifest to record a different layout, since resuming would scatter the export across both.
+func loadExportManifest(dst string, layout exportLayout) (*exportManifest, error) {
+	m := &exportManifest{dst: dst, layout: layout, entries: make(map[string]exportManifestEntry)}
+	//nolint:gosec
+	b, err := os.ReadFile(filepath.Join(dst, exportManifestFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return m, nil
+		}
+		return nil, errors.Wrap(err, "could not read export manifest")
+	}
+	var contents exportManifestContents
+	if err := json.Unmarshal(b, &contents); err != nil {
+		return nil, errors.Wrapf(err, "could not parse export manifest %s", filepath.Join(dst, exportManifestFile))
+	}
+	if contents.Layout != "" && contents.Layout != layout {
+		return nil, errors.Errorf("%s was exported with --%s=%s; resume with the same layout or use a new destination",
+			dst, dataFlagLayout, contents.Layout)
+	}
+	if contents.Files != nil {
+		m.entries = contents.Files
+	}
+	return m, nil
+}
+
+// isComplete returns true if fileID is in the manifest and the file on disk still matches the
+// recorded size and checksum. Files that are missing, partially written, or modified are not complete.
+func (m *exportManifest) isComplete(fileID string) bool {
+	m.mu.Lock()
+	entry, ok := m.entries[fileID]
+	m.mu.Unlock()
+	if !ok {
+		return false
+	}
+	size, sum, err := fileSizeAndChecksum(filepath.Join(m.dst, entry.Path))
+	if err != nil {
+		return false
+	}
+	return size == entry.Size && sum == entry.SHA256
+}
+
+// record adds the file at path to the manifest under fileID. The manifest is written to disk every
+// logEveryN records so that progress survives an interruption without rewriting it for every file.
+func (m *exportManifest) record(fileID, path string) error {
+	size, sum, err := fileSizeAndChecksum(path)
+	if err != nil {
+		return err
+	}
+	relPath, err := filepath.Rel(m.dst, path)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[fileID] = exportManifestEntry{Path: relPath, Size: size, SHA256: sum}
+	m.unflushed++
+	if m.unflushed < logEveryN {
+		return nil
+	}
+	return m.flushLocked()
+}
+
+// flush writes the manifest to disk.
+func (m *exportManifest) flush() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.flushLocked()
+}
+
+func (m *exportManifest) flushLocked() error {
+	b, err := json.Marshal(exportManifestContents{Layout: m.layout, Files: m.entries})
+	if err != nil {
+		return errors.Wrap(err, "could not marshal export manifest")
+	}
+	if err := os.MkdirAll(m.dst, 0o700); err != nil {
+		return err
+	}
+	// Write to a temporary file and rename so an interruption never leaves a truncated manifest behind.
+	tmpPath := filepath.Join(m.dst, exportManifestFile+".tmp")
+	if err := os.WriteFile(tmpPath, b, 0o600); err != nil {
+		return errors.Wrap(err, "could not write export manifest")
+	}
+	if err := os.Rename(tmpPath, filepath.Join(m.dst, exportManifestFile)); err != nil {
+		return errors.Wrap(err, "could not write export manifest")
+	}
+	m.unflushed = 0
+	return nil
+}
+
+func fileSizeAndChecksum(path string) (int64, string, error) {
+	//nolint:gosec
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, "", err
+	}
+	//nolint:errcheck
+	defer f.Close()
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return 0, "", err
+	}
+	return size, hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/go-units"
+	"github.com/pkg/errors"
+)
+
+const (
+	// maxParallelDownloads is the most download requests 'data export' makes in parallel, since more can
+	// overwhelm a small machine or get rate limited by the server.
+	maxParallelDownloads = 250
+	// exportProgressRedrawInterval is how often the progress bar is redrawn when writing to a terminal.
+	exportProgressRedrawInterval = 100 * time.Millisecond
+	// exportProgressLogInterval is how often a progress line is printed when not writing to a terminal.
+	exportProgressLogInterval = 10 * time.Second
+	exportProgressBarWidth    = 30
+)
+
+// clampParallelDownloads returns the number of downloads to make in parallel for the requested --parallel,
+// warning on w if it had to be lowered.
+func clampParallelDownloads(w io.Writer, parallel uint) (uint, error) {
+	if parallel == 0 {
+		return 0, errors.Errorf("--%s must be at least 1", dataFlagParallelDownloads)
+	}
+	if parallel > maxParallelDownloads {
+		warningf(w, "--%s=%d is too high, downloading %d files in parallel instead",
+			dataFlagParallelDownloads, parallel, maxParallelDownloads)
+		return maxParallelDownloads, nil
+	}
+	return parallel, nil
+}
+
+// exportProgress reports how many files of an export have been downloaded. On a terminal it redraws a
+// progress bar in place; otherwise it prints a line every exportProgressLogInterval.
+type exportProgress struct {
+	w        io.Writer
+	terminal bool
+	// total is the number of files being exported, or 0 if unknown.
+	total uint64
+	now   func() time.Time
+
+	mu        sync.Mutex
+	start     time.Time
+	lastPrint time.Time
+	files     uint64
+	bytes     uint64
+}
+
+func newExportProgress(w io.Writer, total uint64, terminal bool, now func() time.Time) *exportProgress {
+	start := now()
+	return &exportProgress{w: w, terminal: terminal, total: total, now: now, start: start, lastPrint: start}
+}
+
+// add records that a file of size bytes has been downloaded.
+func (p *exportProgress) add(size uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.files++
+	p.bytes += size
+
+	interval := exportProgressLogInterval
+	if p.terminal {
+		interval = exportProgressRedrawInterval
+	}
+	if now := p.now(); now.Sub(p.lastPrint) >= interval {
+		p.print(now)
+		p.lastPrint = now
+	}
+}
+
+// finish prints the final progress.
+func (p *exportProgress) finish() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.print(p.now())
+	if p.terminal {
+		// Close the line with the progress bar
+		printf(p.w, "")
+	}
+}
+
+func (p *exportProgress) print(now time.Time) {
+	if !p.terminal {
+		printf(p.w, "Downloaded %s", p.status(now))
+		return
+	}
+	bar := ""
+	if p.total > 0 {
+		filled := exportProgressBarWidth * p.completed() / 100
+		bar = "[" + strings.Repeat("=", int(filled)) + strings.Repeat(" ", exportProgressBarWidth-int(filled)) + "] "
+	}
+	fmt.Fprintf(p.w, "\r%s%s  ", bar, p.status(now)) // no newline
+}
+
+// completed returns the percentage of files downloaded, if the total is known.
+func (p *exportProgress) completed() uint64 {
+	if p.files >= p.total {
+		return 100
+	}
+	return 100 * p.files / p.total
+}
+
+func (p *exportProgress) status(now time.Time) string {
+	files := fmt.Sprintf("%d files", p.files)
+	if p.total > 0 {
+		files = fmt.Sprintf("%d/%d files (%d%%)", p.files, p.total, p.completed())
+	}
+	var rate float64
+	if elapsed := now.Sub(p.start).Seconds(); elapsed > 0 {
+		rate = float64(p.bytes) / elapsed
+	}
+	return fmt.Sprintf("%s, %s at %s/s", files, units.HumanSize(float64(p.bytes)), units.HumanSize(rate))
+}
@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/docker/go-units"
+	"github.com/pkg/errors"
+)
+
+// binaryExportResult is the outcome of exporting a single binary file.
+type binaryExportResult struct {
+	fileID  string
+	bytes   uint64
+	skipped bool
+	err     error
+}
+
+// binaryExportFailure is a file that could not be exported and why.
+type binaryExportFailure struct {
+	FileID string `json:"file_id"`
+	Error  string `json:"error"`
+}
+
+// binaryExportSummary describes the outcome of a binary data export. It is printed once the export is done
+// and is also its JSON output.
+type binaryExportSummary struct {
+	Succeeded  int                   `json:"succeeded"`
+	Skipped    int                   `json:"skipped"`
+	Failed     []binaryExportFailure `json:"failed"`
+	TotalBytes uint64                `json:"total_bytes"`
+}
+
+// collectBinaryExportResults summarizes the results sent on results until it is closed.
+func collectBinaryExportResults(results <-chan binaryExportResult) *binaryExportSummary {
+	summary := &binaryExportSummary{Failed: []binaryExportFailure{}}
+	for result := range results {
+		switch {
+		case result.err != nil:
+			summary.Failed = append(summary.Failed, binaryExportFailure{FileID: result.fileID, Error: result.err.Error()})
+		case result.skipped:
+			summary.Skipped++
+		default:
+			summary.Succeeded++
+			summary.TotalBytes += result.bytes
+		}
+	}
+	sort.Slice(summary.Failed, func(i, j int) bool {
+		return summary.Failed[i].FileID < summary.Failed[j].FileID
+	})
+	return summary
+}
+
+// print prints the summary in format, either text or JSON.
+func (s *binaryExportSummary) print(w io.Writer, format string) error {
+	if format == outputFormatJSON {
+		return printJSON(w, s)
+	}
+	printf(w, "Exported %d files (%s)", s.Succeeded, units.HumanSize(float64(s.TotalBytes)))
+	if s.Skipped > 0 {
+		printf(w, "Skipped %d files that were already downloaded", s.Skipped)
+	}
+	if len(s.Failed) > 0 {
+		printf(w, "Failed to export %d files:", len(s.Failed))
+		for _, failure := range s.Failed {
+			printf(w, "\t%s: %s", failure.FileID, failure.Error)
+		}
+	}
+	return nil
+}
+
+// err returns an error listing the IDs of the files that failed, or nil if none did.
+func (s *binaryExportSummary) err() error {
+	if len(s.Failed) == 0 {
+		return nil
+	}
+	fileIDs := make([]string, 0, len(s.Failed))
+	for _, failure := range s.Failed {
+		fileIDs = append(fileIDs, failure.FileID)
+	}
+	return errors.Errorf("failed to export %d files: %s", len(s.Failed), strings.Join(fileIDs, ", "))
+}
@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// maxDownloadVerifyAttempts is how many times a binary file that fails verification is downloaded before it is
+// reported as failed.
+const maxDownloadVerifyAttempts = 3
+
+// downloadVerificationError is returned by downloadBinary when the file written to disk is not the whole
+// payload sent by the server, e.g. because it was truncated, or a gzipped payload fails its checksum.
+type downloadVerificationError struct {
+	fileID string
+	reason string
+}
+
+func (e *downloadVerificationError) Error() string {
+	return fmt.Sprintf("downloaded file for datum %s is incomplete or corrupt: %s", e.fileID, e.reason)
+}
+
+// downloadVerifier counts the bytes written through it so that the file they were written to can be checked
+// once it is closed.
+type downloadVerifier struct {
+	w       io.Writer
+	written int64
+}
+
+func newDownloadVerifier(w io.Writer) *downloadVerifier {
+	return &downloadVerifier{w: w}
+}
+
+func (v *downloadVerifier) Write(p []byte) (int, error) {
+	n, err := v.w.Write(p)
+	v.written += int64(n)
+	return n, err
+}
+
+// verify checks that the file at path has the size of the payload, payloadSize, when the payload was saved as
+// is. Payloads that were decompressed or converted have no known size, so payloadSize is negative and the file
+// is checked against the bytes written through v instead; gzip checks decompressed payloads itself.
+func (v *downloadVerifier) verify(fileID, path string, payloadSize int64) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return &downloadVerificationError{fileID: fileID, reason: err.Error()}
+	}
+	if payloadSize >= 0 && v.written != payloadSize {
+		return &downloadVerificationError{
+			fileID: fileID,
+			reason: fmt.Sprintf("received %d bytes but only %d were written to %s", payloadSize, v.written, path),
+		}
+	}
+	if info.Size() != v.written {
+		return &downloadVerificationError{
+			fileID: fileID,
+			reason: fmt.Sprintf("expected %d bytes in %s, found %d", v.written, path, info.Size()),
+		}
+	}
+	return nil
+}
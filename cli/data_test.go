@@ -1,10 +1,34 @@
 package cli
 
 import (
+	"bytes"
+	"context"
+	"flag"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
 	datapb "go.viam.com/api/app/data/v1"
 	"go.viam.com/test"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"go.viam.com/rdk/rimage"
+	"go.viam.com/rdk/testutils/inject"
+	"go.viam.com/rdk/utils"
 )
 
 func TestFilenameForDownload(t *testing.T) {
@@ -24,3 +48,584 @@ func TestFilenameForDownload(t *testing.T) {
 	gzInFolder := filenameForDownload(&datapb.BinaryMetadata{FileName: "dir/whatever.gz"})
 	test.That(t, gzInFolder, test.ShouldEqual, "dir/whatever")
 }
+
+func TestExportManifest(t *testing.T) {
+	dst := t.TempDir()
+	dataPath := filepath.Join(dst, dataDir, "file.txt")
+	test.That(t, os.MkdirAll(filepath.Dir(dataPath), 0o700), test.ShouldBeNil)
+	test.That(t, os.WriteFile(dataPath, []byte("hello"), 0o600), test.ShouldBeNil)
+
+	m, err := loadExportManifest(dst, exportLayoutFlat)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, m.isComplete("id"), test.ShouldBeFalse)
+	test.That(t, m.record("id", dataPath), test.ShouldBeNil)
+	test.That(t, m.isComplete("id"), test.ShouldBeTrue)
+	test.That(t, m.flush(), test.ShouldBeNil)
+
+	// A new run should see the file as already downloaded.
+	m, err = loadExportManifest(dst, exportLayoutFlat)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, m.isComplete("id"), test.ShouldBeTrue)
+	test.That(t, m.isComplete("other-id"), test.ShouldBeFalse)
+
+	// Resuming with a different layout would put the rest of the export somewhere else.
+	_, err = loadExportManifest(dst, exportLayoutByDate)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "--layout=flat")
+
+	// A partially written or modified file should be downloaded again.
+	test.That(t, os.WriteFile(dataPath, []byte("hel"), 0o600), test.ShouldBeNil)
+	test.That(t, m.isComplete("id"), test.ShouldBeFalse)
+	test.That(t, os.WriteFile(dataPath, []byte("jello"), 0o600), test.ShouldBeNil)
+	test.That(t, m.isComplete("id"), test.ShouldBeFalse)
+	test.That(t, os.Remove(dataPath), test.ShouldBeNil)
+	test.That(t, m.isComplete("id"), test.ShouldBeFalse)
+}
+
+func TestDownloadVerifier(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file.txt")
+	//nolint:gosec
+	f, err := os.Create(path)
+	test.That(t, err, test.ShouldBeNil)
+	verifier := newDownloadVerifier(f)
+	_, err = io.Copy(verifier, strings.NewReader("hello"))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, f.Close(), test.ShouldBeNil)
+	test.That(t, verifier.verify("id", path, 5), test.ShouldBeNil)
+	test.That(t, verifier.verify("id", path, -1), test.ShouldBeNil)
+
+	// fewer bytes were written than the server sent.
+	var verificationErr *downloadVerificationError
+	err = verifier.verify("id", path, 8)
+	test.That(t, errors.As(err, &verificationErr), test.ShouldBeTrue)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "received 8 bytes but only 5 were written")
+
+	// the file was truncated on disk.
+	test.That(t, os.WriteFile(path, []byte("hel"), 0o600), test.ShouldBeNil)
+	err = verifier.verify("id", path, -1)
+	test.That(t, errors.As(err, &verificationErr), test.ShouldBeTrue)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "expected 5 bytes")
+}
+
+func TestBinaryExportSummary(t *testing.T) {
+	results := make(chan binaryExportResult, 5)
+	results <- binaryExportResult{fileID: "ok1", bytes: 1000}
+	results <- binaryExportResult{fileID: "skipped", skipped: true}
+	results <- binaryExportResult{fileID: "b", err: errors.New("server unavailable")}
+	results <- binaryExportResult{fileID: "ok2", bytes: 500}
+	results <- binaryExportResult{fileID: "a", err: &downloadVerificationError{fileID: "a", reason: "truncated"}}
+	close(results)
+	summary := collectBinaryExportResults(results)
+	test.That(t, summary.Succeeded, test.ShouldEqual, 2)
+	test.That(t, summary.Skipped, test.ShouldEqual, 1)
+	test.That(t, summary.TotalBytes, test.ShouldEqual, 1500)
+	test.That(t, summary.Failed, test.ShouldHaveLength, 2)
+	test.That(t, summary.Failed[0].FileID, test.ShouldEqual, "a")
+	test.That(t, summary.Failed[1], test.ShouldResemble, binaryExportFailure{FileID: "b", Error: "server unavailable"})
+	test.That(t, summary.err(), test.ShouldNotBeNil)
+	test.That(t, summary.err().Error(), test.ShouldEqual, "failed to export 2 files: a, b")
+
+	out := &testWriter{}
+	test.That(t, summary.print(out, outputFormatText), test.ShouldBeNil)
+	text := strings.Join(out.messages, "")
+	test.That(t, text, test.ShouldContainSubstring, "Exported 2 files (1.5kB)")
+	test.That(t, text, test.ShouldContainSubstring, "Skipped 1 files")
+	test.That(t, text, test.ShouldContainSubstring, "\tb: server unavailable")
+
+	out = &testWriter{}
+	test.That(t, summary.print(out, outputFormatJSON), test.ShouldBeNil)
+	test.That(t, strings.Join(out.messages, ""), test.ShouldContainSubstring, `"file_id": "b"`)
+
+	empty := make(chan binaryExportResult)
+	close(empty)
+	summary = collectBinaryExportResults(empty)
+	test.That(t, summary.err(), test.ShouldBeNil)
+	test.That(t, summary.Failed, test.ShouldNotBeNil)
+}
+
+func TestExportLayout(t *testing.T) {
+	layout, err := parseExportLayout("by-part")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, layout, test.ShouldEqual, exportLayoutByPart)
+	_, err = parseExportLayout("by-robot")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "flat, by-component, by-date, by-part")
+
+	md := &datapb.BinaryMetadata{
+		TimeRequested: timestamppb.New(time.Date(2023, 1, 2, 23, 0, 0, 0, time.FixedZone("", -2*60*60))),
+		CaptureMetadata: &datapb.CaptureMetadata{
+			ComponentType: "rdk:component:camera",
+			ComponentName: "left/cam",
+			RobotName:     "rover",
+		},
+	}
+	test.That(t, exportLayoutFlat.dir(md), test.ShouldEqual, "")
+	test.That(t, exportLayoutByComponent.dir(md), test.ShouldEqual, filepath.Join("rdk_component_camera", "left_cam"))
+	test.That(t, exportLayoutByDate.dir(md), test.ShouldEqual, "2023-01-03")
+	test.That(t, exportLayoutByPart.dir(md), test.ShouldEqual, filepath.Join("rover", exportLayoutUnknownDir))
+}
+
+func TestExportConversion(t *testing.T) {
+	test.That(t, exportConversionFor(&datapb.BinaryMetadata{FileExt: ".jpeg"}), test.ShouldBeNil)
+	test.That(t, exportConversionFor(&datapb.BinaryMetadata{
+		CaptureMetadata: &datapb.CaptureMetadata{MimeType: utils.MimeTypePCD},
+		FileExt:         ".pcd",
+	}), test.ShouldBeNil)
+
+	conversion := exportConversionFor(&datapb.BinaryMetadata{FileExt: ".dep"})
+	test.That(t, conversion, test.ShouldNotBeNil)
+	test.That(t, conversion.convertedPath(filepath.Join("data", "a_b.dep")), test.ShouldEqual, filepath.Join("data", "a_b.png"))
+	test.That(t, conversion.convertedPath(filepath.Join("data", "a_b")), test.ShouldEqual, filepath.Join("data", "a_b.png"))
+
+	dm := rimage.NewEmptyDepthMap(2, 1)
+	dm.Set(1, 0, 1234)
+	var raw bytes.Buffer
+	_, err := rimage.WriteViamDepthMapTo(dm, &raw)
+	test.That(t, err, test.ShouldBeNil)
+	var converted bytes.Buffer
+	test.That(t, conversion.convert(context.Background(), &raw, &converted), test.ShouldBeNil)
+	img, err := png.Decode(&converted)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, img.At(1, 0), test.ShouldResemble, color.Gray16{Y: 1234})
+
+	conversion = exportConversionFor(&datapb.BinaryMetadata{
+		CaptureMetadata: &datapb.CaptureMetadata{MimeType: utils.MimeTypeRawRGBA + "+" + utils.MimeTypeSuffixLazy},
+	})
+	test.That(t, conversion, test.ShouldNotBeNil)
+	rgba := image.NewNRGBA(image.Rect(0, 0, 1, 1))
+	rgba.Set(0, 0, color.NRGBA{R: 10, G: 20, B: 30, A: 255})
+	rawRGBA, err := rimage.EncodeImage(context.Background(), rgba, utils.MimeTypeRawRGBA)
+	test.That(t, err, test.ShouldBeNil)
+	converted.Reset()
+	test.That(t, conversion.convert(context.Background(), bytes.NewReader(rawRGBA), &converted), test.ShouldBeNil)
+	img, err = png.Decode(&converted)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, color.NRGBAModel.Convert(img.At(0, 0)), test.ShouldResemble, color.NRGBA{R: 10, G: 20, B: 30, A: 255})
+
+	test.That(t, conversion.convert(context.Background(), strings.NewReader("junk"), &converted), test.ShouldNotBeNil)
+}
+
+func TestDownloadBinaryIncludeMetadata(t *testing.T) {
+	md := &datapb.BinaryMetadata{
+		Id:              "file-id",
+		CaptureMetadata: &datapb.CaptureMetadata{ComponentName: "cam", Tags: []string{"tag"}},
+		FileName:        "image.jpeg",
+		FileExt:         ".jpeg",
+	}
+	client := &inject.DataServiceClient{
+		BinaryDataByIDsFunc: func(ctx context.Context, in *datapb.BinaryDataByIDsRequest,
+			opts ...grpc.CallOption,
+		) (*datapb.BinaryDataByIDsResponse, error) {
+			return &datapb.BinaryDataByIDsResponse{
+				Data: []*datapb.BinaryData{{Metadata: proto.Clone(md).(*datapb.BinaryMetadata), Binary: []byte("image")}},
+			}, nil
+		},
+	}
+
+	dst := t.TempDir()
+	dataPath, _, err := downloadBinary(context.Background(), client, dst, &datapb.BinaryID{FileId: "file-id"},
+		binaryDownloadOptions{layout: exportLayoutFlat})
+	test.That(t, err, test.ShouldBeNil)
+	_, err = os.Stat(dataPath + ".json")
+	test.That(t, os.IsNotExist(err), test.ShouldBeTrue)
+
+	dataPath, _, err = downloadBinary(context.Background(), client, dst, &datapb.BinaryID{FileId: "file-id"},
+		binaryDownloadOptions{layout: exportLayoutFlat, includeMetadata: true})
+	test.That(t, err, test.ShouldBeNil)
+	b, err := os.ReadFile(dataPath + ".json")
+	test.That(t, err, test.ShouldBeNil)
+	var sidecar datapb.BinaryMetadata
+	test.That(t, protojson.Unmarshal(b, &sidecar), test.ShouldBeNil)
+	test.That(t, sidecar.GetId(), test.ShouldEqual, "file-id")
+	test.That(t, sidecar.GetCaptureMetadata().GetComponentName(), test.ShouldEqual, "cam")
+	test.That(t, sidecar.GetCaptureMetadata().GetTags(), test.ShouldResemble, []string{"tag"})
+}
+
+// newDataFilterContext returns a context with the shared data filter flags parsed from args.
+func newDataFilterContext(t *testing.T, args ...string) *cli.Context {
+	t.Helper()
+	set := flag.NewFlagSet("filter", flag.ContinueOnError)
+	for _, f := range sharedDataFilterFlags() {
+		test.That(t, f.Apply(set), test.ShouldBeNil)
+	}
+	test.That(t, set.Parse(args), test.ShouldBeNil)
+	return cli.NewContext(NewApp(&testWriter{}, &testWriter{}), set, nil)
+}
+
+func TestParseDataFilter(t *testing.T) {
+	filter, err := parseDataFilter(newDataFilterContext(t))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, filter, test.ShouldResemble, &datapb.Filter{})
+
+	filter, err = parseDataFilter(newDataFilterContext(t,
+		"--"+dataFlagOrgIDs, "org1,org2",
+		"--"+dataFlagLocationIDs, "loc1",
+		"--"+generalFlagMachineID, "machine-id",
+		"--"+dataFlagPartID, "part-id",
+		"--"+dataFlagMachineName, "machine",
+		"--"+dataFlagPartName, "part",
+		"--"+dataFlagComponentType, "camera",
+		"--"+dataFlagComponentName, "cam",
+		"--"+dataFlagMethod, "ReadImage",
+		"--"+dataFlagMimeTypes, "image/jpeg,image/png",
+		"--"+dataFlagStart, "2023-01-01T00:00:00Z",
+		"--"+dataFlagEnd, "2023-02-01T00:00:00Z",
+		"--"+dataFlagTags, "a,b",
+		"--"+dataFlagBboxLabels, "cat",
+	))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, filter.OrganizationIds, test.ShouldResemble, []string{"org1", "org2"})
+	test.That(t, filter.LocationIds, test.ShouldResemble, []string{"loc1"})
+	test.That(t, filter.RobotId, test.ShouldEqual, "machine-id")
+	test.That(t, filter.PartId, test.ShouldEqual, "part-id")
+	test.That(t, filter.RobotName, test.ShouldEqual, "machine")
+	test.That(t, filter.PartName, test.ShouldEqual, "part")
+	test.That(t, filter.ComponentType, test.ShouldEqual, "camera")
+	test.That(t, filter.ComponentName, test.ShouldEqual, "cam")
+	test.That(t, filter.Method, test.ShouldEqual, "ReadImage")
+	test.That(t, filter.MimeType, test.ShouldResemble, []string{"image/jpeg", "image/png"})
+	test.That(t, filter.Interval.Start.AsTime(), test.ShouldEqual, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	test.That(t, filter.Interval.End.AsTime(), test.ShouldEqual, time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC))
+	test.That(t, filter.TagsFilter.Type, test.ShouldEqual, datapb.TagsFilterType_TAGS_FILTER_TYPE_MATCH_BY_OR)
+	test.That(t, filter.TagsFilter.Tags, test.ShouldResemble, []string{"a", "b"})
+	test.That(t, filter.BboxLabels, test.ShouldResemble, []string{"cat"})
+
+	filter, err = parseDataFilter(newDataFilterContext(t, "--"+dataFlagEnd, "2023-02-01T00:00:00Z"))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, filter.Interval.Start, test.ShouldBeNil)
+	test.That(t, filter.Interval.End, test.ShouldNotBeNil)
+
+	filter, err = parseDataFilter(newDataFilterContext(t, "--"+dataFlagTags, "tagged"))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, filter.TagsFilter.Type, test.ShouldEqual, datapb.TagsFilterType_TAGS_FILTER_TYPE_TAGGED)
+	filter, err = parseDataFilter(newDataFilterContext(t, "--"+dataFlagTags, "untagged"))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, filter.TagsFilter.Type, test.ShouldEqual, datapb.TagsFilterType_TAGS_FILTER_TYPE_UNTAGGED)
+
+	filter, err = parseDataFilter(newDataFilterContext(t,
+		"--"+dataFlagStart, "2023-01-01", "--"+dataFlagEnd, "2023-01-01T12:30:00.5+02:00"))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, filter.Interval.Start.AsTime(), test.ShouldEqual, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	test.That(t, filter.Interval.End.AsTime(), test.ShouldEqual, time.Date(2023, 1, 1, 10, 30, 0, 5e8, time.UTC))
+
+	_, err = parseDataFilter(newDataFilterContext(t, "--"+dataFlagStart, "yesterday"))
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldStartWith, "invalid --start:")
+	_, err = parseDataFilter(newDataFilterContext(t, "--"+dataFlagEnd, "2023-13-01"))
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldStartWith, "invalid --end:")
+	_, err = parseDataFilter(newDataFilterContext(t, "--"+dataFlagStart, "2023-02-01", "--"+dataFlagEnd, "2023-01-01"))
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "is after --end")
+	_, err = parseDataFilter(newDataFilterContext(t, "--"+dataFlagStart, "2023-01-01", "--"+dataFlagEnd, "2023-01-01"))
+	test.That(t, err, test.ShouldBeNil)
+}
+
+func TestParseDataFilterTime(t *testing.T) {
+	now := time.Date(2023, 3, 15, 12, 0, 0, 0, time.UTC)
+	for value, expected := range map[string]time.Time{
+		"now":        now,
+		"-30m":       now.Add(-30 * time.Minute),
+		"-1h30m":     now.Add(-90 * time.Minute),
+		"-7d":        now.AddDate(0, 0, -7),
+		"-2w":        now.AddDate(0, 0, -14),
+		"2023-01-01": time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+	} {
+		ts, err := parseDataFilterTime(dataFlagStart, value, now)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, ts.AsTime(), test.ShouldEqual, expected)
+	}
+
+	for _, value := range []string{"7d", "+2h", "-1.5d", "-d", "--1h", "-soon"} {
+		_, err := parseDataFilterTime(dataFlagStart, value, now)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldStartWith, "invalid --start")
+	}
+	_, err := parseDataFilterTime(dataFlagEnd, "2h", now)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "use -2h")
+
+	filter, err := parseDataFilter(newDataFilterContext(t, "--"+dataFlagStart, "-7d", "--"+dataFlagEnd, "now"))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, filter.Interval.End.AsTime().Sub(filter.Interval.Start.AsTime()), test.ShouldEqual, 7*24*time.Hour)
+}
+
+func TestDataFilterFlagsWithout(t *testing.T) {
+	var names []string
+	for _, f := range dataFilterFlagsWithout(dataFlagTags) {
+		names = append(names, f.Names()...)
+	}
+	test.That(t, names, test.ShouldNotContain, dataFlagTags)
+	test.That(t, names, test.ShouldContain, dataFlagBboxLabels)
+	test.That(t, len(dataFilterFlagsWithout(dataFlagTags)), test.ShouldEqual, len(sharedDataFilterFlags())-1)
+}
+
+func TestComponentGlobFilter(t *testing.T) {
+	filter := &datapb.Filter{ComponentName: "camera-1", ComponentType: "camera"}
+	globs, err := newComponentGlobFilter(filter)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, globs, test.ShouldBeNil)
+	test.That(t, filter.ComponentName, test.ShouldEqual, "camera-1")
+	test.That(t, globs.matches(&datapb.CaptureMetadata{ComponentName: "anything"}), test.ShouldBeTrue)
+
+	filter = &datapb.Filter{ComponentName: "camera-*", ComponentType: "camera"}
+	globs, err = newComponentGlobFilter(filter)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, filter.ComponentName, test.ShouldBeEmpty)
+	test.That(t, filter.ComponentType, test.ShouldEqual, "camera")
+	test.That(t, globs.matches(&datapb.CaptureMetadata{ComponentName: "camera-1"}), test.ShouldBeTrue)
+	test.That(t, globs.matches(&datapb.CaptureMetadata{ComponentName: "camera-22"}), test.ShouldBeTrue)
+	test.That(t, globs.matches(&datapb.CaptureMetadata{ComponentName: "lidar-1"}), test.ShouldBeFalse)
+
+	filter = &datapb.Filter{ComponentName: "camera-?", ComponentType: "*sensor"}
+	globs, err = newComponentGlobFilter(filter)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, globs.matches(&datapb.CaptureMetadata{ComponentName: "camera-1", ComponentType: "rdk:component:sensor"}),
+		test.ShouldBeTrue)
+	test.That(t, globs.matches(&datapb.CaptureMetadata{ComponentName: "camera-1", ComponentType: "rdk:component:camera"}),
+		test.ShouldBeFalse)
+
+	_, err = newComponentGlobFilter(&datapb.Filter{ComponentName: "camera-[1"})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, dataFlagComponentName)
+}
+
+func TestDeleteTabularDataRetries(t *testing.T) {
+	originalBackoff := dataRetryInitialBackoff
+	dataRetryInitialBackoff = time.Millisecond
+	defer func() { dataRetryInitialBackoff = originalBackoff }()
+
+	var calls int
+	var errs []error
+	dataClient := &inject.DataServiceClient{
+		DeleteTabularDataFunc: func(ctx context.Context, in *datapb.DeleteTabularDataRequest,
+			opts ...grpc.CallOption,
+		) (*datapb.DeleteTabularDataResponse, error) {
+			calls++
+			if len(errs) > 0 {
+				err := errs[0]
+				errs = errs[1:]
+				return nil, err
+			}
+			return &datapb.DeleteTabularDataResponse{DeletedCount: 42}, nil
+		},
+	}
+
+	t.Run("retries transient errors", func(t *testing.T) {
+		calls = 0
+		errs = []error{status.Error(codes.Unavailable, "blip"), status.Error(codes.DeadlineExceeded, "slow")}
+		_, ac, out, errOut := setup(&inject.AppServiceClient{}, dataClient, nil, nil, "token")
+		test.That(t, ac.deleteTabularData("org", 0, 2), test.ShouldBeNil)
+		test.That(t, calls, test.ShouldEqual, 3)
+		test.That(t, strings.Count(strings.Join(errOut.messages, ""), "delete failed, retrying"), test.ShouldEqual, 2)
+		test.That(t, out.messages[0], test.ShouldContainSubstring, "Deleted 42 datapoints")
+	})
+
+	t.Run("gives up after retries", func(t *testing.T) {
+		calls = 0
+		errs = []error{status.Error(codes.Unavailable, "blip"), status.Error(codes.Unavailable, "blip")}
+		_, ac, _, _ := setup(&inject.AppServiceClient{}, dataClient, nil, nil, "token")
+		err := ac.deleteTabularData("org", 0, 1)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, status.Code(errors.Cause(err)), test.ShouldEqual, codes.Unavailable)
+		test.That(t, calls, test.ShouldEqual, 2)
+	})
+
+	t.Run("fails fast on other errors", func(t *testing.T) {
+		calls = 0
+		errs = []error{status.Error(codes.PermissionDenied, "nope")}
+		_, ac, _, _ := setup(&inject.AppServiceClient{}, dataClient, nil, nil, "token")
+		err := ac.deleteTabularData("org", 0, 3)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "nope")
+		test.That(t, calls, test.ShouldEqual, 1)
+	})
+}
+
+func TestDataAddToDatasetByFilter(t *testing.T) {
+	originalBackoff := dataRetryInitialBackoff
+	dataRetryInitialBackoff = time.Millisecond
+	defer func() { dataRetryInitialBackoff = originalBackoff }()
+
+	pages := [][]string{{"a", "b"}, {"c"}, {"d", "e"}}
+	var added []string
+	dataClient := &inject.DataServiceClient{
+		BinaryDataByFilterFunc: func(ctx context.Context, in *datapb.BinaryDataByFilterRequest,
+			opts ...grpc.CallOption,
+		) (*datapb.BinaryDataByFilterResponse, error) {
+			if in.GetCountOnly() {
+				return &datapb.BinaryDataByFilterResponse{Count: 5}, nil
+			}
+			// Last is the index of the next page, or empty for the first.
+			page, _ := strconv.Atoi(in.GetDataRequest().GetLast())
+			if page >= len(pages) {
+				return &datapb.BinaryDataByFilterResponse{}, nil
+			}
+			var data []*datapb.BinaryData
+			for _, id := range pages[page] {
+				data = append(data, &datapb.BinaryData{Metadata: &datapb.BinaryMetadata{Id: id}})
+			}
+			return &datapb.BinaryDataByFilterResponse{Data: data, Last: strconv.Itoa(page + 1)}, nil
+		},
+		AddBinaryDataToDatasetByIDsFunc: func(ctx context.Context, in *datapb.AddBinaryDataToDatasetByIDsRequest,
+			opts ...grpc.CallOption,
+		) (*datapb.AddBinaryDataToDatasetByIDsResponse, error) {
+			if in.GetBinaryIds()[0].GetFileId() == "c" {
+				return nil, status.Error(codes.PermissionDenied, "nope")
+			}
+			for _, id := range in.GetBinaryIds() {
+				added = append(added, id.GetFileId())
+			}
+			return &datapb.AddBinaryDataToDatasetByIDsResponse{}, nil
+		},
+	}
+
+	t.Run("dry run", func(t *testing.T) {
+		added = nil
+		_, ac, out, _ := setup(&inject.AppServiceClient{}, dataClient, nil, nil, "token")
+		test.That(t, ac.dataAddToDatasetByFilter(&datapb.Filter{}, "dataset-id", 0, true), test.ShouldBeNil)
+		test.That(t, added, test.ShouldBeEmpty)
+		test.That(t, out.messages[0], test.ShouldContainSubstring, "5 files match the filters and would be added")
+	})
+
+	t.Run("continues past failed batches", func(t *testing.T) {
+		added = nil
+		_, ac, out, errOut := setup(&inject.AppServiceClient{}, dataClient, nil, nil, "token")
+		err := ac.dataAddToDatasetByFilter(&datapb.Filter{}, "dataset-id", 0, false)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "failed to add 1 files to dataset ID dataset-id")
+		test.That(t, err.Error(), test.ShouldContainSubstring, "batch 2 (file IDs c): ")
+		test.That(t, added, test.ShouldResemble, []string{"a", "b", "d", "e"})
+		test.That(t, strings.Join(errOut.messages, ""), test.ShouldContainSubstring, "could not add batch 2")
+		output := strings.Join(out.messages, "")
+		test.That(t, output, test.ShouldContainSubstring, "Batch 3: added 2 files to dataset ID dataset-id (4 total)")
+		test.That(t, output, test.ShouldContainSubstring, "Added 4 files to dataset ID dataset-id")
+	})
+}
+
+func TestDataTag(t *testing.T) {
+	var added, removed []string
+	dataClient := &inject.DataServiceClient{
+		BinaryDataByFilterFunc: func(ctx context.Context, in *datapb.BinaryDataByFilterRequest, opts ...grpc.CallOption,
+		) (*datapb.BinaryDataByFilterResponse, error) {
+			test.That(t, in.GetCountOnly(), test.ShouldBeTrue)
+			return &datapb.BinaryDataByFilterResponse{Count: 7}, nil
+		},
+		AddTagsToBinaryDataByFilterFunc: func(ctx context.Context, in *datapb.AddTagsToBinaryDataByFilterRequest,
+			opts ...grpc.CallOption,
+		) (*datapb.AddTagsToBinaryDataByFilterResponse, error) {
+			added = in.GetTags()
+			return &datapb.AddTagsToBinaryDataByFilterResponse{}, nil
+		},
+		RemoveTagsFromBinaryDataByFilterFunc: func(ctx context.Context, in *datapb.RemoveTagsFromBinaryDataByFilterRequest,
+			opts ...grpc.CallOption,
+		) (*datapb.RemoveTagsFromBinaryDataByFilterResponse, error) {
+			removed = in.GetTags()
+			return &datapb.RemoveTagsFromBinaryDataByFilterResponse{DeletedCount: 3}, nil
+		},
+	}
+	tags := []string{"wifi", "outdoor"}
+
+	_, ac, out, _ := setup(&inject.AppServiceClient{}, dataClient, nil, nil, "token")
+	test.That(t, ac.dataTag(&datapb.Filter{}, tags, true), test.ShouldBeNil)
+	test.That(t, added, test.ShouldBeNil)
+	test.That(t, out.messages[0], test.ShouldContainSubstring, "7 files match the filters and would be tagged with wifi, outdoor")
+
+	test.That(t, ac.dataTag(&datapb.Filter{}, tags, false), test.ShouldBeNil)
+	test.That(t, added, test.ShouldResemble, tags)
+	test.That(t, out.messages[1], test.ShouldContainSubstring, "Tagged 7 files with wifi, outdoor")
+
+	test.That(t, ac.dataUntag(&datapb.Filter{}, tags, true), test.ShouldBeNil)
+	test.That(t, removed, test.ShouldBeNil)
+	test.That(t, out.messages[2], test.ShouldContainSubstring, "7 files match the filters and would have wifi, outdoor removed")
+
+	test.That(t, ac.dataUntag(&datapb.Filter{}, tags, false), test.ShouldBeNil)
+	test.That(t, removed, test.ShouldResemble, tags)
+	test.That(t, out.messages[3], test.ShouldContainSubstring, "Removed wifi, outdoor from 3 files")
+}
+
+func TestConfirmDestructiveAction(t *testing.T) {
+	originalInput, originalIsTerminal := confirmationInput, stdinIsTerminal
+	defer func() {
+		confirmationInput, stdinIsTerminal = originalInput, originalIsTerminal
+	}()
+	isTerminal := true
+	stdinIsTerminal = func() bool { return isTerminal }
+
+	cCtx, _, out, _ := setup(nil, nil, nil, &map[string]string{generalFlagYes: "false"}, "token")
+
+	confirmationInput = strings.NewReader("yes\n")
+	test.That(t, confirmDestructiveAction(cCtx, "This will delete things"), test.ShouldBeNil)
+	test.That(t, strings.Join(out.messages, ""), test.ShouldContainSubstring, "This will delete things")
+
+	confirmationInput = strings.NewReader("y\n")
+	err := confirmDestructiveAction(cCtx, "This will delete things")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldEqual, "aborted")
+
+	isTerminal = false
+	err = confirmDestructiveAction(cCtx, "This will delete things")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "stdin is not a terminal")
+
+	test.That(t, cCtx.Set(generalFlagYes, "true"), test.ShouldBeNil)
+	test.That(t, confirmDestructiveAction(cCtx, "This will delete things"), test.ShouldBeNil)
+}
+
+func TestClampParallelDownloads(t *testing.T) {
+	errOut := &testWriter{}
+	parallel, err := clampParallelDownloads(errOut, 100)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, parallel, test.ShouldEqual, 100)
+	test.That(t, errOut.messages, test.ShouldBeEmpty)
+
+	parallel, err = clampParallelDownloads(errOut, 10000)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, parallel, test.ShouldEqual, maxParallelDownloads)
+	test.That(t, strings.Join(errOut.messages, ""), test.ShouldContainSubstring, "--parallel=10000 is too high")
+
+	_, err = clampParallelDownloads(errOut, 0)
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestExportProgress(t *testing.T) {
+	now := time.Unix(0, 0)
+	clock := func() time.Time { return now }
+
+	t.Run("terminal", func(t *testing.T) {
+		out := &testWriter{}
+		progress := newExportProgress(out, 4, true, clock)
+		now = now.Add(time.Second)
+		progress.add(1000)
+		test.That(t, out.messages, test.ShouldResemble, []string{
+			"\r[=======                       ] 1/4 files (25%), 1kB at 1kB/s  ",
+		})
+
+		// redraws are rate limited.
+		progress.add(1000)
+		test.That(t, out.messages, test.ShouldHaveLength, 1)
+
+		now = now.Add(time.Second)
+		progress.add(2000)
+		progress.add(0)
+		progress.finish()
+		test.That(t, out.messages[len(out.messages)-2], test.ShouldEqual,
+			"\r[==============================] 4/4 files (100%), 4kB at 2kB/s  ")
+		test.That(t, out.messages[len(out.messages)-1], test.ShouldEqual, "\n")
+	})
+
+	t.Run("not a terminal", func(t *testing.T) {
+		out := &testWriter{}
+		progress := newExportProgress(out, 0, false, clock)
+		now = now.Add(time.Second)
+		progress.add(1000)
+		test.That(t, out.messages, test.ShouldBeEmpty)
+
+		now = now.Add(exportProgressLogInterval)
+		progress.add(1000)
+		test.That(t, out.messages, test.ShouldResemble, []string{"Downloaded 2 files, 2kB at 181.8B/s\n"})
+
+		progress.finish()
+		test.That(t, out.messages, test.ShouldHaveLength, 2)
+	})
+}
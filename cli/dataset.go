@@ -2,9 +2,17 @@ package cli
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/urfave/cli/v2"
+	datapb "go.viam.com/api/app/data/v1"
 	datasetpb "go.viam.com/api/app/dataset/v1"
 )
 
@@ -18,28 +26,32 @@ const (
 
 // DatasetCreateAction is the corresponding action for 'dataset create'.
 func DatasetCreateAction(c *cli.Context) error {
+	orgID, err := requiredStringFlagOrConfigFile(c, generalFlagOrgID)
+	if err != nil {
+		return err
+	}
 	client, err := newViamClient(c)
 	if err != nil {
 		return err
 	}
-	if err := client.createDataset(c.String(generalFlagOrgID), c.String(datasetFlagName)); err != nil {
+	if _, err := client.createDataset(orgID, c.String(datasetFlagName)); err != nil {
 		return err
 	}
 	return nil
 }
 
-// createDataset creates a dataset with the a dataset ID.
-func (c *viamClient) createDataset(orgID, datasetName string) error {
+// createDataset creates a dataset and returns its ID.
+func (c *viamClient) createDataset(orgID, datasetName string) (string, error) {
 	if err := c.ensureLoggedIn(); err != nil {
-		return err
+		return "", err
 	}
 	resp, err := c.datasetClient.CreateDataset(context.Background(),
 		&datasetpb.CreateDatasetRequest{OrganizationId: orgID, Name: datasetName})
 	if err != nil {
-		return errors.Wrapf(err, "received error from server")
+		return "", errors.Wrapf(err, "received error from server")
 	}
 	printf(c.c.App.Writer, "Created dataset %s with dataset ID: %s", datasetName, resp.GetId())
-	return nil
+	return resp.GetId(), nil
 }
 
 // DatasetRenameAction is the corresponding action for 'dataset rename'.
@@ -80,53 +92,149 @@ func DatasetListAction(c *cli.Context) error {
 	if orgID != "" && datasetIDs != nil {
 		return errors.New("must specify either dataset IDs or organization ID, got both")
 	}
+	if datasetIDs == nil {
+		orgID = stringFlagOrConfigFile(c, generalFlagOrgID)
+	}
+	format, err := outputFormat(c)
+	if err != nil {
+		return err
+	}
+	var datasets []*datasetpb.Dataset
 	if datasetIDs != nil {
-		if err := client.listDatasetByIDs(datasetIDs); err != nil {
-			return err
-		}
+		datasets, err = client.listDatasetByIDs(datasetIDs)
 	} else {
-		if err := client.listDatasetByOrg(orgID); err != nil {
-			return err
-		}
+		datasets, err = client.listDatasetByOrg(orgID)
 	}
+	if err != nil {
+		return err
+	}
+	return client.printDatasets(datasets, format, tableOptionsFor(c))
+}
 
-	return nil
+// datasetOutput is the JSON output of 'dataset list'.
+type datasetOutput struct {
+	ID             string     `json:"id"`
+	Name           string     `json:"name"`
+	OrganizationID string     `json:"organization_id"`
+	CreatedOn      *time.Time `json:"created_on,omitempty"`
+	// ItemCount is null if the items of the dataset could not be counted.
+	ItemCount *uint64 `json:"item_count"`
 }
 
-// listDatasetByIDs list all datasets by ID.
-func (c *viamClient) listDatasetByIDs(datasetIDs []string) error {
+// datasetCountParallelism is how many datasets 'dataset list' counts the items of at once.
+const datasetCountParallelism = 10
+
+// listDatasetByIDs returns the datasets with the given IDs. IDs of datasets that do not exist or that the user
+// cannot access are warned about rather than failing the whole list.
+func (c *viamClient) listDatasetByIDs(datasetIDs []string) ([]*datasetpb.Dataset, error) {
 	if err := c.ensureLoggedIn(); err != nil {
-		return err
+		return nil, err
 	}
-	resp, err := c.datasetClient.ListDatasetsByIDs(context.Background(),
+	resp, err := c.datasetClient.ListDatasetsByIDs(c.c.Context,
 		&datasetpb.ListDatasetsByIDsRequest{Ids: datasetIDs})
 	if err != nil {
-		return errors.Wrapf(err, "received error from server")
+		return nil, errors.Wrapf(err, "received error from server")
 	}
+	found := make(map[string]bool, len(resp.GetDatasets()))
 	for _, dataset := range resp.GetDatasets() {
-		printf(c.c.App.Writer, "\t%s (ID: %s, Organization ID: %s)", dataset.Name, dataset.Id, dataset.OrganizationId)
+		found[dataset.GetId()] = true
 	}
-	return nil
+	for _, id := range datasetIDs {
+		if !found[id] {
+			warningf(c.c.App.ErrWriter, "dataset ID %s does not exist or you do not have access to it", id)
+		}
+	}
+	return resp.GetDatasets(), nil
 }
 
-// listDatasetByOrg list all datasets for the specified org ID.
-func (c *viamClient) listDatasetByOrg(orgID string) error {
+// listDatasetByOrg returns all datasets for the specified org ID.
+func (c *viamClient) listDatasetByOrg(orgID string) ([]*datasetpb.Dataset, error) {
 	if err := c.ensureLoggedIn(); err != nil {
-		return err
+		return nil, err
 	}
-	resp, err := c.datasetClient.ListDatasetsByOrganizationID(context.Background(),
+	resp, err := c.datasetClient.ListDatasetsByOrganizationID(c.c.Context,
 		&datasetpb.ListDatasetsByOrganizationIDRequest{OrganizationId: orgID})
 	if err != nil {
-		return errors.Wrapf(err, "received error from server")
+		return nil, errors.Wrapf(err, "received error from server")
 	}
-	for _, dataset := range resp.GetDatasets() {
-		printf(c.c.App.Writer, "\t%s (ID: %s, Organization ID: %s)", dataset.Name, dataset.Id, dataset.OrganizationId)
+	return resp.GetDatasets(), nil
+}
+
+// printDatasets prints datasets with how many items each holds, and the total in text output.
+func (c *viamClient) printDatasets(datasets []*datasetpb.Dataset, format string, opts tableOptions) error {
+	counts := c.countDatasetItems(datasets)
+	if format == outputFormatJSON {
+		out := make([]datasetOutput, 0, len(datasets))
+		for i, dataset := range datasets {
+			out = append(out, datasetOutput{
+				ID:             dataset.GetId(),
+				Name:           dataset.GetName(),
+				OrganizationID: dataset.GetOrganizationId(),
+				CreatedOn:      timestampOrNil(dataset.GetTimeCreated()),
+				ItemCount:      counts[i],
+			})
+		}
+		return printJSON(c.c.App.Writer, out)
+	}
+
+	var total uint64
+	tbl := newTable("NAME", "ID", "ORGANIZATION ID", "CREATED", "ITEMS").keepWhole("ID", "ORGANIZATION ID")
+	for i, dataset := range datasets {
+		items := "unknown"
+		if counts[i] != nil {
+			items = fmt.Sprint(*counts[i])
+			total += *counts[i]
+		}
+		created := "-"
+		if dataset.GetTimeCreated() != nil {
+			created = dataset.GetTimeCreated().AsTime().Format(time.RFC3339)
+		}
+		tbl.addRow(dataset.GetName(), dataset.GetId(), dataset.GetOrganizationId(), created, items)
+	}
+	tbl.render(c.c.App.Writer, opts)
+	if !opts.quiet {
+		printf(c.c.App.Writer, "%d datasets, %d items", len(datasets), total)
 	}
 	return nil
 }
 
-// DatasetDeleteAction is the corresponding action for 'dataset rename'.
+// countDatasetItems returns how many items each of datasets holds, counting several datasets at once. The count
+// of a dataset that cannot be counted, such as one the user cannot read the data of, is nil.
+func (c *viamClient) countDatasetItems(datasets []*datasetpb.Dataset) []*uint64 {
+	counts := make([]*uint64, len(datasets))
+	sem := make(chan struct{}, datasetCountParallelism)
+	var wg sync.WaitGroup
+	var warnMu sync.Mutex
+	for i, dataset := range datasets {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, dataset *datasetpb.Dataset) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			resp, err := c.dataClient.BinaryDataByFilter(c.c.Context, &datapb.BinaryDataByFilterRequest{
+				DataRequest: &datapb.DataRequest{Filter: &datapb.Filter{DatasetId: dataset.GetId()}},
+				CountOnly:   true,
+			})
+			if err != nil {
+				warnMu.Lock()
+				warningf(c.c.App.ErrWriter, "could not count the items in dataset ID %s: %v", dataset.GetId(), err)
+				warnMu.Unlock()
+				return
+			}
+			count := resp.GetCount()
+			counts[i] = &count
+		}(i, dataset)
+	}
+	wg.Wait()
+	return counts
+}
+
+// DatasetDeleteAction is the corresponding action for 'dataset delete'.
 func DatasetDeleteAction(c *cli.Context) error {
+	if err := confirmDestructiveAction(c, fmt.Sprintf("This will permanently delete the dataset with ID %s",
+		c.String(datasetFlagDatasetID))); err != nil {
+		return err
+	}
 	client, err := newViamClient(c)
 	if err != nil {
 		return err
@@ -150,3 +258,93 @@ func (c *viamClient) deleteDataset(datasetID string) error {
 	printf(c.c.App.Writer, "Dataset with ID %s deleted", datasetID)
 	return nil
 }
+
+// datasetManifestFile is the name of the file, stored at the root of a dataset export's destination,
+// that maps each file in the dataset to where it was downloaded and its labels.
+const datasetManifestFile = "dataset.json"
+
+// datasetManifest describes an exported dataset.
+type datasetManifest struct {
+	DatasetID string                 `json:"dataset_id"`
+	Files     []datasetManifestEntry `json:"files"`
+}
+
+// datasetManifestEntry describes a single exported file of a dataset.
+type datasetManifestEntry struct {
+	FileID string `json:"file_id"`
+	// Path is relative to the export destination.
+	Path       string   `json:"path"`
+	Tags       []string `json:"tags,omitempty"`
+	BboxLabels []string `json:"bbox_labels,omitempty"`
+}
+
+// DatasetExportAction is the corresponding action for 'dataset export'.
+func DatasetExportAction(c *cli.Context) error {
+	client, err := newViamClient(c)
+	if err != nil {
+		return err
+	}
+	if err := client.exportDataset(c.Path(dataFlagDestination), c.String(datasetFlagDatasetID),
+		c.Uint(dataFlagParallelDownloads)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// exportDataset downloads all binary data in the dataset with the specified ID to dst, along with a manifest
+// mapping each file to where it was downloaded and its labels.
+func (c *viamClient) exportDataset(dst, datasetID string, parallelDownloads uint) error {
+	if err := c.ensureLoggedIn(); err != nil {
+		return err
+	}
+
+	manifest := datasetManifest{DatasetID: datasetID, Files: []datasetManifestEntry{}}
+	var mu sync.Mutex
+	err := c.performActionOnBinaryDataFromFilter(
+		func(id *datapb.BinaryID) error {
+			dataPath, md, err := downloadBinary(c.c.Context, c.dataClient, dst, id, binaryDownloadOptions{layout: exportLayoutFlat})
+			if err != nil {
+				return err
+			}
+			relPath, err := filepath.Rel(dst, dataPath)
+			if err != nil {
+				return err
+			}
+			entry := datasetManifestEntry{
+				FileID: id.GetFileId(),
+				Path:   relPath,
+				Tags:   md.GetCaptureMetadata().GetTags(),
+			}
+			for _, bbox := range md.GetAnnotations().GetBboxes() {
+				entry.BboxLabels = append(entry.BboxLabels, bbox.GetLabel())
+			}
+			mu.Lock()
+			manifest.Files = append(manifest.Files, entry)
+			mu.Unlock()
+			return nil
+		},
+		&datapb.Filter{DatasetId: datasetID}, nil, parallelDownloads,
+		func(i int32) {
+			printf(c.c.App.Writer, "Downloaded %d files", i)
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(manifest.Files, func(i, j int) bool {
+		return manifest.Files[i].FileID < manifest.Files[j].FileID
+	})
+	b, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "could not marshal dataset manifest")
+	}
+	if err := os.MkdirAll(dst, 0o700); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dst, datasetManifestFile), b, 0o600); err != nil {
+		return errors.Wrap(err, "could not write dataset manifest")
+	}
+	printf(c.c.App.Writer, "Exported %d files from dataset %s to %s", len(manifest.Files), datasetID, dst)
+	return nil
+}
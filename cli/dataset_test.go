@@ -0,0 +1,193 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/urfave/cli/v2"
+	datapb "go.viam.com/api/app/data/v1"
+	datasetpb "go.viam.com/api/app/dataset/v1"
+	"go.viam.com/test"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestDatasetDeleteAction(t *testing.T) {
+	var deletedID string
+	cCtx, ac, out, errOut := setup(&inject.AppServiceClient{}, nil, nil,
+		&map[string]string{datasetFlagDatasetID: "dataset-id", generalFlagYes: "false"}, "token")
+	ac.datasetClient = &inject.DatasetServiceClient{
+		DeleteDatasetFunc: func(ctx context.Context, in *datasetpb.DeleteDatasetRequest,
+			opts ...grpc.CallOption,
+		) (*datasetpb.DeleteDatasetResponse, error) {
+			deletedID = in.GetId()
+			return &datasetpb.DeleteDatasetResponse{}, nil
+		},
+	}
+	test.That(t, ac.deleteDataset("dataset-id"), test.ShouldBeNil)
+	test.That(t, deletedID, test.ShouldEqual, "dataset-id")
+	test.That(t, errOut.messages, test.ShouldHaveLength, 0)
+	test.That(t, out.messages[0], test.ShouldContainSubstring, "Dataset with ID dataset-id deleted")
+
+	t.Run("dataset delete command deletes", func(t *testing.T) {
+		originalIsTerminal := stdinIsTerminal
+		defer func() { stdinIsTerminal = originalIsTerminal }()
+		stdinIsTerminal = func() bool { return false }
+
+		var cmd *cli.Command
+		for _, subcommand := range cCtx.App.Command("dataset").Subcommands {
+			if subcommand.Name == "delete" {
+				cmd = subcommand
+			}
+		}
+		test.That(t, cmd, test.ShouldNotBeNil)
+		// Without --yes and a terminal, the delete path refuses to run before making any requests.
+		err := cmd.Action(cCtx)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "permanently delete the dataset with ID dataset-id")
+	})
+}
+
+func TestDatasetExport(t *testing.T) {
+	md := &datapb.BinaryMetadata{
+		Id: "file-id",
+		CaptureMetadata: &datapb.CaptureMetadata{
+			OrganizationId: "org-id",
+			LocationId:     "location-id",
+			Tags:           []string{"tag"},
+		},
+		FileName: "image.jpeg",
+		FileExt:  ".jpeg",
+		Annotations: &datapb.Annotations{Bboxes: []*datapb.BoundingBox{
+			{Label: "cat"},
+			{Label: "dog"},
+		}},
+	}
+	var filterDatasetID string
+	dataClient := &inject.DataServiceClient{
+		BinaryDataByFilterFunc: func(ctx context.Context, in *datapb.BinaryDataByFilterRequest,
+			opts ...grpc.CallOption,
+		) (*datapb.BinaryDataByFilterResponse, error) {
+			filterDatasetID = in.GetDataRequest().GetFilter().GetDatasetId()
+			if in.GetDataRequest().GetLast() != "" {
+				return &datapb.BinaryDataByFilterResponse{}, nil
+			}
+			return &datapb.BinaryDataByFilterResponse{
+				Data: []*datapb.BinaryData{{Metadata: md}},
+				Last: "last",
+			}, nil
+		},
+		BinaryDataByIDsFunc: func(ctx context.Context, in *datapb.BinaryDataByIDsRequest,
+			opts ...grpc.CallOption,
+		) (*datapb.BinaryDataByIDsResponse, error) {
+			return &datapb.BinaryDataByIDsResponse{
+				Data: []*datapb.BinaryData{{Metadata: md, Binary: []byte("image")}},
+			}, nil
+		},
+	}
+	_, ac, out, _ := setup(&inject.AppServiceClient{}, dataClient, nil, nil, "token")
+
+	dst := t.TempDir()
+	test.That(t, ac.exportDataset(dst, "dataset-id", 10), test.ShouldBeNil)
+	test.That(t, filterDatasetID, test.ShouldEqual, "dataset-id")
+	test.That(t, strings.Join(out.messages, ""), test.ShouldContainSubstring, "Exported 1 files from dataset dataset-id")
+
+	b, err := os.ReadFile(filepath.Join(dst, datasetManifestFile))
+	test.That(t, err, test.ShouldBeNil)
+	var manifest datasetManifest
+	test.That(t, json.Unmarshal(b, &manifest), test.ShouldBeNil)
+	test.That(t, manifest.DatasetID, test.ShouldEqual, "dataset-id")
+	test.That(t, manifest.Files, test.ShouldHaveLength, 1)
+	entry := manifest.Files[0]
+	test.That(t, entry.FileID, test.ShouldEqual, "file-id")
+	test.That(t, entry.Tags, test.ShouldResemble, []string{"tag"})
+	test.That(t, entry.BboxLabels, test.ShouldResemble, []string{"cat", "dog"})
+	data, err := os.ReadFile(filepath.Join(dst, entry.Path))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, string(data), test.ShouldEqual, "image")
+}
+
+func TestDatasetList(t *testing.T) {
+	created := time.Date(2024, 3, 4, 5, 6, 7, 0, time.UTC)
+	datasets := []*datasetpb.Dataset{
+		{Id: "a", Name: "cats", OrganizationId: "org-id", TimeCreated: timestamppb.New(created)},
+		{Id: "b", Name: "dogs", OrganizationId: "org-id"},
+		{Id: "c", Name: "secret", OrganizationId: "org-id"},
+	}
+	dataClient := &inject.DataServiceClient{
+		BinaryDataByFilterFunc: func(ctx context.Context, in *datapb.BinaryDataByFilterRequest,
+			opts ...grpc.CallOption,
+		) (*datapb.BinaryDataByFilterResponse, error) {
+			test.That(t, in.GetCountOnly(), test.ShouldBeTrue)
+			switch in.GetDataRequest().GetFilter().GetDatasetId() {
+			case "a":
+				return &datapb.BinaryDataByFilterResponse{Count: 3}, nil
+			case "b":
+				return &datapb.BinaryDataByFilterResponse{Count: 0}, nil
+			default:
+				return nil, status.Error(codes.PermissionDenied, "no access")
+			}
+		},
+	}
+	datasetClient := &inject.DatasetServiceClient{
+		ListDatasetsByOrganizationIDFunc: func(ctx context.Context, in *datasetpb.ListDatasetsByOrganizationIDRequest,
+			opts ...grpc.CallOption,
+		) (*datasetpb.ListDatasetsByOrganizationIDResponse, error) {
+			return &datasetpb.ListDatasetsByOrganizationIDResponse{Datasets: datasets}, nil
+		},
+		ListDatasetsByIDsFunc: func(ctx context.Context, in *datasetpb.ListDatasetsByIDsRequest,
+			opts ...grpc.CallOption,
+		) (*datasetpb.ListDatasetsByIDsResponse, error) {
+			return &datasetpb.ListDatasetsByIDsResponse{Datasets: datasets[:1]}, nil
+		},
+	}
+
+	t.Run("text", func(t *testing.T) {
+		_, ac, out, errOut := setup(&inject.AppServiceClient{}, dataClient, nil, nil, "token")
+		ac.datasetClient = datasetClient
+		ds, err := ac.listDatasetByOrg("org-id")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, ac.printDatasets(ds, outputFormatText, tableOptions{}), test.ShouldBeNil)
+		test.That(t, out.messages, test.ShouldResemble, []string{
+			"NAME    ID  ORGANIZATION ID  CREATED               ITEMS\n",
+			"cats    a   org-id           2024-03-04T05:06:07Z  3\n",
+			"dogs    b   org-id           -                     0\n",
+			"secret  c   org-id           -                     unknown\n",
+			"3 datasets, 3 items\n",
+		})
+		test.That(t, strings.Join(errOut.messages, ""), test.ShouldContainSubstring, "could not count the items in dataset ID c")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		_, ac, out, _ := setup(&inject.AppServiceClient{}, dataClient, nil, nil, "token")
+		ac.datasetClient = datasetClient
+		ds, err := ac.listDatasetByOrg("org-id")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, ac.printDatasets(ds, outputFormatJSON, tableOptions{}), test.ShouldBeNil)
+		var got []datasetOutput
+		test.That(t, json.Unmarshal([]byte(strings.Join(out.messages, "")), &got), test.ShouldBeNil)
+		test.That(t, got, test.ShouldHaveLength, 3)
+		test.That(t, *got[0].ItemCount, test.ShouldEqual, 3)
+		test.That(t, got[0].CreatedOn.Equal(created), test.ShouldBeTrue)
+		test.That(t, *got[1].ItemCount, test.ShouldEqual, 0)
+		test.That(t, got[2].ItemCount, test.ShouldBeNil)
+	})
+
+	t.Run("missing IDs", func(t *testing.T) {
+		_, ac, _, errOut := setup(&inject.AppServiceClient{}, dataClient, nil, nil, "token")
+		ac.datasetClient = datasetClient
+		ds, err := ac.listDatasetByIDs([]string{"a", "z"})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, ds, test.ShouldHaveLength, 1)
+		test.That(t, strings.Join(errOut.messages, ""), test.ShouldContainSubstring, "dataset ID z does not exist")
+	})
+}
@@ -3,14 +3,19 @@ package cli
 import (
 	"fmt"
 	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
 	"strings"
-	"text/tabwriter"
 	"time"
 
+	"github.com/docker/go-units"
 	"github.com/pkg/errors"
 	"github.com/urfave/cli/v2"
 	"go.uber.org/multierr"
 	buildpb "go.viam.com/api/app/build/v1"
+	vutils "go.viam.com/utils"
 	"go.viam.com/utils/pexec"
 	"golang.org/x/exp/maps"
 	"golang.org/x/exp/slices"
@@ -49,6 +54,9 @@ func (c *viamClient) moduleBuildStartAction(cCtx *cli.Context) error {
 	if err != nil {
 		return err
 	}
+	if err := checkManifestRegistered(manifest); err != nil {
+		return err
+	}
 	version := cCtx.String(moduleBuildFlagVersion)
 	if manifest.Build == nil || manifest.Build.Build == "" {
 		return errors.New("your meta.json cannot have an empty build step. See 'viam module build --help' for more information")
@@ -108,6 +116,12 @@ func (c *viamClient) moduleBuildLocalAction(cCtx *cli.Context) error {
 			return err
 		}
 	}
+	artifactPath := manifest.Build.Path
+	if artifactPath == "" {
+		artifactPath = defaultBuildInfo.Path
+	}
+	// an artifact left over from an earlier build would hide a build step that silently produced nothing.
+	previousArtifact, _ := os.Stat(artifactPath)
 	infof(cCtx.App.Writer, "Starting build step: %q", manifest.Build.Build)
 	processConfig.Args = []string{"-c", manifest.Build.Build}
 	proc := pexec.NewManagedProcess(processConfig, logger.AsZap())
@@ -115,9 +129,77 @@ func (c *viamClient) moduleBuildLocalAction(cCtx *cli.Context) error {
 		return err
 	}
 	infof(cCtx.App.Writer, "Completed build")
+
+	artifact, err := os.Stat(artifactPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return errors.Errorf("the build step did not produce %q. Make sure your build command writes the module "+
+				"to the \"path\" in the \"build\" section of %s", artifactPath, manifestPath)
+		}
+		return err
+	}
+	if previousArtifact != nil && !artifact.ModTime().After(previousArtifact.ModTime()) {
+		warningf(cCtx.App.ErrWriter, "%s was not modified by the build step, so it may be left over from an earlier build",
+			artifactPath)
+	}
+	absArtifactPath, err := filepath.Abs(artifactPath)
+	if err != nil {
+		return err
+	}
+	if artifact.IsDir() {
+		infof(cCtx.App.Writer, "Built module: %s", absArtifactPath)
+	} else {
+		infof(cCtx.App.Writer, "Built module: %s (%s)", absArtifactPath, units.HumanSize(float64(artifact.Size())))
+	}
+
+	if output := cCtx.String(moduleBuildFlagCopyTo); output != "" {
+		if artifact.IsDir() {
+			return errors.Errorf("cannot copy %s to --%s because it is a directory", artifactPath, moduleBuildFlagCopyTo)
+		}
+		outputPath, err := copyBuildArtifact(artifactPath, output)
+		if err != nil {
+			return err
+		}
+		infof(cCtx.App.Writer, "Copied module to %s", outputPath)
+	}
 	return nil
 }
 
+// copyBuildArtifact copies the file at artifactPath to output, or into output if it is a directory, and returns
+// the absolute path of the copy.
+func copyBuildArtifact(artifactPath, output string) (string, error) {
+	if info, err := os.Stat(output); err == nil && info.IsDir() {
+		output = filepath.Join(output, filepath.Base(artifactPath))
+	}
+	output, err := filepath.Abs(output)
+	if err != nil {
+		return "", err
+	}
+	//nolint:gosec
+	src, err := os.Open(artifactPath)
+	if err != nil {
+		return "", err
+	}
+	defer vutils.UncheckedErrorFunc(src.Close)
+	info, err := src.Stat()
+	if err != nil {
+		return "", err
+	}
+	//nolint:gosec
+	dst, err := os.OpenFile(output, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, info.Mode().Perm())
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to create %s", output)
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		vutils.UncheckedError(dst.Close())
+		return "", errors.Wrapf(err, "failed to copy %s to %s", artifactPath, output)
+	}
+	if err := dst.Close(); err != nil {
+		return "", errors.Wrapf(err, "failed to copy %s to %s", artifactPath, output)
+	}
+	return output, nil
+}
+
 // ModuleBuildListAction lists the module's build jobs.
 func ModuleBuildListAction(cCtx *cli.Context) error {
 	c, err := newViamClient(cCtx)
@@ -128,6 +210,10 @@ func ModuleBuildListAction(cCtx *cli.Context) error {
 }
 
 func (c *viamClient) moduleBuildListAction(cCtx *cli.Context) error {
+	format, err := outputFormat(cCtx)
+	if err != nil {
+		return err
+	}
 	var buildIDFilter *string
 	var moduleIDFilter string
 	// This will use the build id if present and fall back on the module manifest if not
@@ -146,35 +232,104 @@ func (c *viamClient) moduleBuildListAction(cCtx *cli.Context) error {
 		}
 		moduleIDFilter = moduleID.String()
 	}
-	var numberOfJobsToReturn *int32
-	if cCtx.IsSet(moduleBuildFlagCount) {
-		count := int32(cCtx.Int(moduleBuildFlagCount))
-		numberOfJobsToReturn = &count
-	}
-	jobs, err := c.listModuleBuildJobs(moduleIDFilter, numberOfJobsToReturn, buildIDFilter)
+	// The server limits the number of jobs rather than builds, and a build has a job per platform, so all jobs
+	// are fetched and the builds are limited once grouped and filtered by platform.
+	jobs, err := c.listModuleBuildJobs(moduleIDFilter, nil, buildIDFilter)
 	if err != nil {
 		return err
 	}
-	// table format rules:
-	// minwidth, tabwidth, padding int, padchar byte, flags uint
-	w := tabwriter.NewWriter(cCtx.App.Writer, 5, 4, 1, ' ', 0)
-	tableFormat := "%s\t%s\t%s\t%s\t%s\n"
-	fmt.Fprintf(w, tableFormat, "ID", "PLATFORM", "STATUS", "VERSION", "TIME")
-	for _, job := range jobs.Jobs {
-		fmt.Fprintf(w,
-			tableFormat,
-			job.BuildId,
-			job.Platform,
-			jobStatusFromProto(job.Status),
-			job.Version,
-			job.StartTime.AsTime().Format(time.RFC3339))
-	}
-	// the table is not printed to stdout until the tabwriter is flushed
-	//nolint: errcheck,gosec
-	w.Flush()
+	builds := groupModuleBuildJobs(jobs.Jobs, cCtx.String(moduleBuildFlagPlatform))
+	if cCtx.IsSet(moduleBuildFlagCount) {
+		if count := cCtx.Int(moduleBuildFlagCount); count >= 0 && count < len(builds) {
+			builds = builds[:count]
+		}
+	}
+	if format == outputFormatJSON {
+		return printJSON(cCtx.App.Writer, builds)
+	}
+	tbl := newTable("ID", "STATUS", "PLATFORMS", "VERSION", "TIME").keepWhole("ID")
+	for _, build := range builds {
+		platforms := make([]string, 0, len(build.Platforms))
+		for _, platform := range build.Platforms {
+			platforms = append(platforms, fmt.Sprintf("%s: %s", platform.Platform, platform.Status))
+		}
+		var startTime string
+		if build.StartTime != nil {
+			startTime = build.StartTime.Format(time.RFC3339)
+		}
+		tbl.addRow(build.ID, string(build.Status), strings.Join(platforms, ", "), build.Version, startTime)
+	}
+	tbl.render(cCtx.App.Writer, tableOptionsFor(cCtx))
 	return nil
 }
 
+// moduleBuildOutput is a build in the output of 'module build list'. A build runs a job for each of its
+// platforms.
+type moduleBuildOutput struct {
+	ID      string `json:"id"`
+	Version string `json:"version"`
+	// Status is jobStatusDone once every platform is done. Otherwise it is jobStatusFailed if any platform
+	// failed, then jobStatusInProgress if any is building, then jobStatusUnspecified.
+	Status    jobStatus                   `json:"status"`
+	StartTime *time.Time                  `json:"start_time,omitempty"`
+	Platforms []moduleBuildPlatformOutput `json:"platforms"`
+}
+
+// moduleBuildPlatformOutput is the job of a build for one platform in the output of 'module build list'.
+type moduleBuildPlatformOutput struct {
+	Platform  string     `json:"platform"`
+	Status    jobStatus  `json:"status"`
+	StartTime *time.Time `json:"start_time,omitempty"`
+	EndTime   *time.Time `json:"end_time,omitempty"`
+}
+
+// groupModuleBuildJobs groups jobs into builds, in the order the builds first appear in jobs. If platform is
+// set, only jobs for that platform are kept, and builds without one are left out.
+func groupModuleBuildJobs(jobs []*buildpb.JobInfo, platform string) []*moduleBuildOutput {
+	var builds []*moduleBuildOutput
+	buildsByID := map[string]*moduleBuildOutput{}
+	for _, job := range jobs {
+		if platform != "" && job.Platform != platform {
+			continue
+		}
+		build, ok := buildsByID[job.BuildId]
+		if !ok {
+			build = &moduleBuildOutput{ID: job.BuildId, Version: job.Version}
+			buildsByID[job.BuildId] = build
+			builds = append(builds, build)
+		}
+		startTime := timestampOrNil(job.StartTime)
+		if startTime != nil && (build.StartTime == nil || startTime.Before(*build.StartTime)) {
+			build.StartTime = startTime
+		}
+		build.Platforms = append(build.Platforms, moduleBuildPlatformOutput{
+			Platform:  job.Platform,
+			Status:    jobStatusFromProto(job.Status),
+			StartTime: startTime,
+			EndTime:   timestampOrNil(job.EndTime),
+		})
+	}
+	for _, build := range builds {
+		sort.Slice(build.Platforms, func(i, j int) bool { return build.Platforms[i].Platform < build.Platforms[j].Platform })
+		build.Status = moduleBuildStatus(build.Platforms)
+	}
+	return builds
+}
+
+// moduleBuildStatus combines the statuses of the platforms of a build as described by moduleBuildOutput.Status.
+func moduleBuildStatus(platforms []moduleBuildPlatformOutput) jobStatus {
+	statuses := map[jobStatus]bool{}
+	for _, platform := range platforms {
+		statuses[platform.Status] = true
+	}
+	for _, status := range []jobStatus{jobStatusFailed, jobStatusInProgress, jobStatusUnspecified} {
+		if statuses[status] {
+			return status
+		}
+	}
+	return jobStatusDone
+}
+
 // anyFailed returns a useful error based on which platforms failed, or nil if all good.
 func buildError(statuses map[string]jobStatus) error {
 	failedPlatforms := utils.FilterMap(
@@ -192,12 +347,17 @@ func ModuleBuildLogsAction(c *cli.Context) error {
 	buildID := c.String(moduleBuildFlagBuildID)
 	platform := c.String(moduleBuildFlagPlatform)
 	shouldWait := c.Bool(moduleBuildFlagWait)
+	shouldFollow := c.Bool(moduleBuildFlagFollow)
 
 	client, err := newViamClient(c)
 	if err != nil {
 		return err
 	}
 
+	if shouldFollow {
+		return client.followModuleBuildLogsForPlatforms(buildID, platform)
+	}
+
 	var statuses map[string]jobStatus
 	if shouldWait {
 		statuses, err = client.waitForBuildToFinish(buildID, platform)
@@ -248,8 +408,17 @@ func (c *viamClient) startBuild(repo, ref, moduleID string, platforms []string,
 }
 
 func (c *viamClient) printModuleBuildLogs(buildID, platform string) error {
+	lastBuildStep := ""
+	_, err := c.streamModuleBuildLogs(buildID, platform, 0, &lastBuildStep)
+	return err
+}
+
+// streamModuleBuildLogs prints the logs of the build for platform as they are received, skipping the
+// first skip log entries. It returns the number of log entries received, including the skipped ones.
+// lastBuildStep is the build step of the last printed entry and is updated as entries are printed.
+func (c *viamClient) streamModuleBuildLogs(buildID, platform string, skip int, lastBuildStep *string) (int, error) {
 	if err := c.ensureLoggedIn(); err != nil {
-		return err
+		return 0, err
 	}
 
 	logsReq := &buildpb.GetLogsRequest{
@@ -259,28 +428,106 @@ func (c *viamClient) printModuleBuildLogs(buildID, platform string) error {
 
 	stream, err := c.buildClient.GetLogs(c.c.Context, logsReq)
 	if err != nil {
-		return err
+		return 0, err
 	}
-	lastBuildStep := ""
+	received := 0
 	for {
 		if c.c.Context.Err() != nil {
-			return c.c.Context.Err()
+			return received, c.c.Context.Err()
 		}
 		log, err := stream.Recv()
 		if errors.Is(err, io.EOF) {
 			break
 		}
 		if err != nil {
-			return err
+			return received, err
+		}
+		received++
+		if received <= skip {
+			continue
 		}
-		if lastBuildStep != log.BuildStep {
+		if *lastBuildStep != log.BuildStep {
 			infof(c.c.App.Writer, log.BuildStep)
-			lastBuildStep = log.BuildStep
+			*lastBuildStep = log.BuildStep
 		}
 		fmt.Fprint(c.c.App.Writer, log.Data) // data is already formatted with newlines
 	}
 
-	return nil
+	return received, nil
+}
+
+// followModuleBuildLogsForPlatforms follows the logs of the build for platform, or for each of the build's
+// platforms in turn if platform is empty, and returns an error if any of them failed to build.
+func (c *viamClient) followModuleBuildLogsForPlatforms(buildID, platform string) error {
+	platforms, err := c.getPlatformsForModuleBuild(buildID)
+	if err != nil {
+		return err
+	}
+	if platform != "" {
+		if !slices.Contains(platforms, platform) {
+			return fmt.Errorf("platform %q is not present on build %q", platform, buildID)
+		}
+		platforms = []string{platform}
+	}
+	statuses := make(map[string]jobStatus)
+	for _, platform := range platforms {
+		if len(platforms) > 1 {
+			infof(c.c.App.Writer, "Logs for %q", platform)
+		}
+		status, err := c.followModuleBuildLogs(buildID, platform)
+		if err != nil {
+			return err
+		}
+		statuses[platform] = status
+	}
+	return buildError(statuses)
+}
+
+// followModuleBuildLogs streams the logs of the build for platform until the build reaches a terminal state,
+// reconnecting every moduleBuildPollingInterval if the log stream ends or drops before then. It returns the
+// final status of the build.
+func (c *viamClient) followModuleBuildLogs(buildID, platform string) (jobStatus, error) {
+	received := 0
+	lastBuildStep := ""
+	for {
+		n, streamErr := c.streamModuleBuildLogs(buildID, platform, received, &lastBuildStep)
+		if n > received {
+			received = n
+		}
+		if err := c.c.Context.Err(); err != nil {
+			return jobStatusUnspecified, err
+		}
+		status, err := c.getModuleBuildStatus(buildID, platform)
+		if err != nil {
+			return jobStatusUnspecified, err
+		}
+		// Only stop once the stream ended cleanly after the build finished so that no trailing logs are missed.
+		if streamErr == nil && (status == jobStatusDone || status == jobStatusFailed) {
+			return status, nil
+		}
+		if streamErr != nil {
+			warningf(c.c.App.ErrWriter, "log stream for %q disconnected, reconnecting: %v", platform, streamErr)
+		}
+		select {
+		case <-c.c.Context.Done():
+			return jobStatusUnspecified, c.c.Context.Err()
+		case <-time.After(moduleBuildPollingInterval):
+		}
+	}
+}
+
+// getModuleBuildStatus returns the status of the build for platform.
+func (c *viamClient) getModuleBuildStatus(buildID, platform string) (jobStatus, error) {
+	jobsResponse, err := c.listModuleBuildJobs("", nil, &buildID)
+	if err != nil {
+		return jobStatusUnspecified, errors.Wrap(err, "failed to list module build jobs")
+	}
+	for _, job := range jobsResponse.Jobs {
+		if job.Platform == platform {
+			return jobStatusFromProto(job.Status), nil
+		}
+	}
+	return jobStatusUnspecified, fmt.Errorf("platform %q is not present on build %q", platform, buildID)
 }
 
 func (c *viamClient) listModuleBuildJobs(moduleIDFilter string, count *int32, buildIDFilter *string) (*buildpb.ListJobsResponse, error) {
@@ -2,6 +2,9 @@ package cli
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -79,12 +82,73 @@ func TestListBuild(t *testing.T) {
 	err := ac.moduleBuildListAction(cCtx)
 	test.That(t, err, test.ShouldBeNil)
 	joinedOutput := strings.Join(out.messages, "")
-	test.That(t, joinedOutput, test.ShouldEqual, `ID     PLATFORM    STATUS VERSION TIME
-xyz123 linux/amd64 Done   1.2.3   1970-01-01T00:00:00Z
+	test.That(t, joinedOutput, test.ShouldEqual, `ID      STATUS  PLATFORMS          VERSION  TIME
+xyz123  Done    linux/amd64: Done  1.2.3
 `)
 	test.That(t, errOut.messages, test.ShouldHaveLength, 0)
 }
 
+func TestListMultiPlatformBuilds(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	buildClient := &inject.BuildServiceClient{
+		ListJobsFunc: func(ctx context.Context, in *v1.ListJobsRequest, opts ...grpc.CallOption) (*v1.ListJobsResponse, error) {
+			return &v1.ListJobsResponse{Jobs: []*v1.JobInfo{
+				{
+					BuildId: "new", Platform: "linux/arm64", Version: "1.3.0",
+					Status: v1.JobStatus_JOB_STATUS_IN_PROGRESS, StartTime: timestamppb.New(start.Add(time.Hour)),
+				},
+				{
+					BuildId: "new", Platform: "linux/amd64", Version: "1.3.0",
+					Status: v1.JobStatus_JOB_STATUS_DONE, StartTime: timestamppb.New(start.Add(time.Hour)),
+				},
+				{
+					BuildId: "old", Platform: "linux/amd64", Version: "1.2.3",
+					Status: v1.JobStatus_JOB_STATUS_FAILED, StartTime: timestamppb.New(start.Add(time.Minute)),
+				},
+				{
+					BuildId: "old", Platform: "darwin/arm64", Version: "1.2.3",
+					Status: v1.JobStatus_JOB_STATUS_DONE, StartTime: timestamppb.New(start),
+					EndTime: timestamppb.New(start.Add(time.Minute)),
+				},
+			}}, nil
+		},
+	}
+
+	cCtx, ac, out, _ := setup(&inject.AppServiceClient{}, nil, buildClient, &map[string]string{moduleBuildFlagBuildID: ""}, "token")
+	test.That(t, cCtx.Set(moduleBuildFlagBuildID, "any"), test.ShouldBeNil)
+	test.That(t, ac.moduleBuildListAction(cCtx), test.ShouldBeNil)
+	test.That(t, strings.Join(out.messages, ""), test.ShouldEqual,
+		`ID   STATUS    PLATFORMS                                 VERSION  TIME
+new  Building  linux/amd64: Done, linux/arm64: Building  1.3.0    2024-01-01T01:00:00Z
+old  Failed    darwin/arm64: Done, linux/amd64: Failed   1.2.3    2024-01-01T00:00:00Z
+`)
+
+	cCtx, ac, out, _ = setup(&inject.AppServiceClient{}, nil, buildClient,
+		&map[string]string{moduleBuildFlagBuildID: "", moduleBuildFlagPlatform: "linux/amd64", outputFlag: outputFormatJSON}, "token")
+	test.That(t, cCtx.Set(moduleBuildFlagBuildID, "any"), test.ShouldBeNil)
+	test.That(t, ac.moduleBuildListAction(cCtx), test.ShouldBeNil)
+	var builds []moduleBuildOutput
+	test.That(t, json.Unmarshal([]byte(strings.Join(out.messages, "")), &builds), test.ShouldBeNil)
+	test.That(t, builds, test.ShouldHaveLength, 2)
+	test.That(t, builds[0].ID, test.ShouldEqual, "new")
+	test.That(t, builds[0].Status, test.ShouldEqual, jobStatusDone)
+	test.That(t, builds[0].Platforms, test.ShouldHaveLength, 1)
+	test.That(t, builds[1].Status, test.ShouldEqual, jobStatusFailed)
+	test.That(t, builds[1].Platforms[0].Platform, test.ShouldEqual, "linux/amd64")
+
+	// the count limits builds rather than jobs.
+	cCtx, ac, out, _ = setup(&inject.AppServiceClient{}, nil, buildClient,
+		&map[string]string{moduleBuildFlagBuildID: "", moduleBuildFlagCount: "", outputFlag: outputFormatJSON}, "token")
+	test.That(t, cCtx.Set(moduleBuildFlagBuildID, "any"), test.ShouldBeNil)
+	test.That(t, cCtx.Set(moduleBuildFlagCount, "1"), test.ShouldBeNil)
+	test.That(t, ac.moduleBuildListAction(cCtx), test.ShouldBeNil)
+	builds = nil
+	test.That(t, json.Unmarshal([]byte(strings.Join(out.messages, "")), &builds), test.ShouldBeNil)
+	test.That(t, builds, test.ShouldHaveLength, 1)
+	test.That(t, builds[0].ID, test.ShouldEqual, "new")
+	test.That(t, builds[0].Platforms, test.ShouldHaveLength, 2)
+}
+
 func TestBuildError(t *testing.T) {
 	err := buildError(map[string]jobStatus{"ok": jobStatusDone})
 	test.That(t, err, test.ShouldBeNil)
@@ -156,6 +220,65 @@ func TestModuleGetPlatformsForModule(t *testing.T) {
 	test.That(t, platforms, test.ShouldResemble, []string{"linux/amd64", "linux/arm64"})
 }
 
+type fakeBuildLogsStream struct {
+	grpc.ClientStream
+	logs []*v1.GetLogsResponse
+	err  error
+}
+
+func (s *fakeBuildLogsStream) Recv() (*v1.GetLogsResponse, error) {
+	if len(s.logs) == 0 {
+		return nil, s.err
+	}
+	log := s.logs[0]
+	s.logs = s.logs[1:]
+	return log, nil
+}
+
+func TestModuleBuildFollowLogs(t *testing.T) {
+	originalPollingInterval := moduleBuildPollingInterval
+	moduleBuildPollingInterval = 10 * time.Millisecond
+	defer func() { moduleBuildPollingInterval = originalPollingInterval }()
+
+	allLogs := []*v1.GetLogsResponse{
+		{BuildStep: "setup", Data: "line 1\n"},
+		{BuildStep: "build", Data: "line 2\n"},
+		{BuildStep: "build", Data: "line 3\n"},
+	}
+	getLogsCalls := 0
+	_, ac, out, errOut := setup(&inject.AppServiceClient{}, nil, &inject.BuildServiceClient{
+		ListJobsFunc: func(ctx context.Context, in *v1.ListJobsRequest, opts ...grpc.CallOption) (*v1.ListJobsResponse, error) {
+			status := v1.JobStatus_JOB_STATUS_IN_PROGRESS
+			if getLogsCalls > 1 {
+				status = v1.JobStatus_JOB_STATUS_DONE
+			}
+			return &v1.ListJobsResponse{Jobs: []*v1.JobInfo{
+				{BuildId: "xyz123", Platform: "linux/amd64", Status: status},
+			}}, nil
+		},
+		GetLogsFunc: func(ctx context.Context, in *v1.GetLogsRequest, opts ...grpc.CallOption) (v1.BuildService_GetLogsClient, error) {
+			getLogsCalls++
+			// the first stream drops partway through the build, the second one replays all of the logs
+			if getLogsCalls == 1 {
+				return &fakeBuildLogsStream{logs: allLogs[:2], err: errors.New("stream dropped")}, nil
+			}
+			return &fakeBuildLogsStream{logs: allLogs, err: io.EOF}, nil
+		},
+	}, &map[string]string{moduleBuildFlagBuildID: "xyz123"}, "token")
+	err := ac.followModuleBuildLogsForPlatforms("xyz123", "")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, getLogsCalls, test.ShouldEqual, 2)
+	joinedOutput := strings.Join(out.messages, "")
+	test.That(t, strings.Count(joinedOutput, "line 1"), test.ShouldEqual, 1)
+	test.That(t, strings.Count(joinedOutput, "line 2"), test.ShouldEqual, 1)
+	test.That(t, strings.Count(joinedOutput, "line 3"), test.ShouldEqual, 1)
+	test.That(t, strings.Join(errOut.messages, ""), test.ShouldContainSubstring, "reconnecting")
+
+	err = ac.followModuleBuildLogsForPlatforms("xyz123", "linux/arm64")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "not present on build")
+}
+
 func TestLocalBuild(t *testing.T) {
 	testDir := t.TempDir()
 	err := os.Chdir(testDir)
@@ -176,7 +299,7 @@ func TestLocalBuild(t *testing.T) {
 
 	err = os.WriteFile(
 		filepath.Join(testDir, "Makefile"),
-		[]byte("make build:\n\techo build step msg"),
+		[]byte("make build:\n\techo build step msg\n\tprintf module > module"),
 		0o700,
 	)
 	test.That(t, err, test.ShouldBeNil)
@@ -192,4 +315,25 @@ func TestLocalBuild(t *testing.T) {
 	outMsg := strings.Join(out.messages, "")
 	test.That(t, outMsg, test.ShouldContainSubstring, "setup step msg")
 	test.That(t, outMsg, test.ShouldContainSubstring, "build step msg")
+	test.That(t, outMsg, test.ShouldContainSubstring, "Built module: "+filepath.Join(testDir, "module")+" (6B)")
+
+	// the built module is copied into the --copy-to directory
+	outputDir := t.TempDir()
+	cCtx, ac, out, _ = setup(&inject.AppServiceClient{}, nil, &inject.BuildServiceClient{},
+		&map[string]string{moduleBuildFlagPath: manifest, moduleBuildFlagCopyTo: outputDir}, "token")
+	test.That(t, ac.moduleBuildLocalAction(cCtx), test.ShouldBeNil)
+	test.That(t, strings.Join(out.messages, ""), test.ShouldContainSubstring, "Copied module to "+filepath.Join(outputDir, "module"))
+	copied, err := os.ReadFile(filepath.Join(outputDir, "module"))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, string(copied), test.ShouldEqual, "module")
+
+	// a build step that does not produce the module fails
+	test.That(t, os.Remove(filepath.Join(testDir, "module")), test.ShouldBeNil)
+	err = os.WriteFile(filepath.Join(testDir, "Makefile"), []byte("make build:\n\techo build step msg"), 0o700)
+	test.That(t, err, test.ShouldBeNil)
+	cCtx, ac, _, _ = setup(&inject.AppServiceClient{}, nil, &inject.BuildServiceClient{},
+		&map[string]string{moduleBuildFlagPath: manifest}, "token")
+	err = ac.moduleBuildLocalAction(cCtx)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `the build step did not produce "module"`)
 }
@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/resource"
+)
+
+// validateManifest checks the fields of a meta.json before 'module update' or 'module upload' sends it to
+// app.viam.com, which would otherwise reject it with a less precise error. It returns an error for each
+// invalid field, naming the field.
+func validateManifest(manifest moduleManifest) []error {
+	var errs []error
+	fieldErrorf := func(field, format string, args ...interface{}) {
+		errs = append(errs, errors.Errorf("%s: %s", field, fmt.Sprintf(format, args...)))
+	}
+
+	if manifest.ModuleID == "" {
+		fieldErrorf("module_id", "required")
+	} else if id, err := parseModuleID(manifest.ModuleID); err != nil || id.prefix == "" || id.name == "" {
+		fieldErrorf("module_id", "%q must be in the form 'public-namespace:module-name' or 'org-id:module-name'",
+			manifest.ModuleID)
+	}
+
+	switch manifest.Visibility {
+	case moduleVisibilityPrivate, moduleVisibilityPublic:
+	case "":
+		fieldErrorf("visibility", "required, must be %q or %q", moduleVisibilityPrivate, moduleVisibilityPublic)
+	default:
+		fieldErrorf("visibility", "must be %q or %q, got %q", moduleVisibilityPrivate, moduleVisibilityPublic,
+			manifest.Visibility)
+	}
+
+	if manifest.URL != "" {
+		if u, err := url.Parse(manifest.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			fieldErrorf("url", "%q is not an http or https URL", manifest.URL)
+		}
+	}
+
+	for i, model := range manifest.Models {
+		field := fmt.Sprintf("models[%d]", i)
+		if model.API == "" && model.Model == "" {
+			fieldErrorf(field, "api and model are empty. Fill in or remove this example entry")
+			continue
+		}
+		if _, err := resource.NewAPIFromString(model.API); err != nil {
+			fieldErrorf(field+".api", "%q must be in the form 'namespace:type:subtype', ex: rdk:component:motor", model.API)
+		}
+		// a lone model name is a valid model of rdk:builtin, but not of a module.
+		if m, err := resource.NewModelFromString(model.Model); err != nil || m.String() != model.Model {
+			fieldErrorf(field+".model", "%q must be in the form 'namespace:family:name', ex: acme:demo:my-motor", model.Model)
+		}
+	}
+
+	if manifest.Entrypoint == "" {
+		fieldErrorf("entrypoint", "required, the path of the executable that starts the module within its archive")
+	} else if entrypoint := filepath.Clean(manifest.Entrypoint); filepath.IsAbs(entrypoint) || !isWithinDir(entrypoint) || entrypoint == "." {
+		fieldErrorf("entrypoint", "%q must be a path within the module archive", manifest.Entrypoint)
+	}
+
+	if build := manifest.Build; build != nil {
+		if build.Build == "" {
+			fieldErrorf("build.build", "required, the command that builds the module")
+		}
+		for i, platform := range build.Arch {
+			if parts := strings.Split(platform, "/"); len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+				fieldErrorf(fmt.Sprintf("build.arch[%d]", i), "%q must be in the form 'os/arch', ex: linux/arm64", platform)
+			}
+		}
+	}
+	return errs
+}
+
+// checkUploadEntrypoint returns an error if the archive 'module upload' creates from path, a file or directory
+// that is not a tarball, would not contain an executable file at entrypoint. Files are archived at their path
+// as given, so an entrypoint of ./bin/my-module requires uploading ./bin/my-module or a directory containing it.
+func checkUploadEntrypoint(path, entrypoint string) error {
+	root := filepath.Clean(path)
+	info, err := os.Stat(root)
+	if err != nil {
+		return err
+	}
+	target := filepath.Clean(entrypoint)
+	if !info.IsDir() {
+		if target != root {
+			return errors.Errorf("entrypoint %q is not the uploaded file %q. "+
+				"Upload the entrypoint or a directory containing it", entrypoint, path)
+		}
+	} else {
+		rel, err := filepath.Rel(root, target)
+		if err != nil || !isWithinDir(rel) {
+			return errors.Errorf("entrypoint %q is not inside the uploaded directory %q", entrypoint, path)
+		}
+		ignore, err := loadViamIgnore(root)
+		if err != nil {
+			return err
+		}
+		if ignore != nil && ignore.ignored(filepath.ToSlash(rel), false) {
+			return errors.Errorf("entrypoint %q is excluded from the upload by %s", entrypoint,
+				filepath.Join(path, viamIgnoreFilename))
+		}
+	}
+
+	info, err = os.Stat(target)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return errors.Errorf("entrypoint %q does not exist in %q", entrypoint, path)
+	case err != nil:
+		return err
+	case info.IsDir():
+		return errors.Errorf("entrypoint %q is a directory instead of an executable file", entrypoint)
+	case info.Mode().Perm()&0o111 == 0:
+		return errors.Errorf("entrypoint %q is not marked as executable", entrypoint)
+	}
+	return nil
+}
+
+// manifestValidationError reports the errors found validating the meta.json at manifestPath, each on its
+// own line, or returns nil if there are none.
+func manifestValidationError(manifestPath string, errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	lines := make([]string, 0, len(errs))
+	for _, err := range errs {
+		lines = append(lines, "  "+err.Error())
+	}
+	return errors.Errorf("%s is invalid:\n%s", manifestPath, strings.Join(lines, "\n"))
+}
+
+// isWithinDir returns whether the relative path rel stays within the directory it is relative to.
+func isWithinDir(rel string) bool {
+	rel = filepath.Clean(rel)
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// jsonTypeName describes the JSON values that decode into t.
+func jsonTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "true or false"
+	case reflect.Slice, reflect.Array:
+		return "a list"
+	case reflect.Struct, reflect.Map, reflect.Ptr:
+		return "an object"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return "a number"
+	default:
+		return t.String()
+	}
+}
@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.viam.com/test"
+)
+
+const validTestManifest = `{
+  "module_id": "acme:my-module",
+  "visibility": "private",
+  "url": "https://github.com/acme/my-module",
+  "description": "a module",
+  "models": [{"api": "rdk:component:motor", "model": "acme:demo:my-motor"}],
+  "build": {"build": "make module.tar.gz", "path": "module.tar.gz", "arch": ["linux/amd64", "linux/arm64"]},
+  "entrypoint": "bin/my-module"
+}`
+
+func TestValidateManifest(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		// replace is applied to validTestManifest to make the fixture.
+		replace     []string
+		errContains []string
+	}{
+		{name: "valid"},
+		{
+			name:        "missing required fields",
+			replace:     []string{`"acme:my-module"`, `""`, `"private"`, `""`, `"bin/my-module"`, `""`},
+			errContains: []string{"module_id: required", "visibility: required", "entrypoint: required"},
+		},
+		{
+			name:        "malformed module id and visibility",
+			replace:     []string{`"acme:my-module"`, `"my-module"`, `"private"`, `"secret"`},
+			errContains: []string{`module_id: "my-module" must be in the form`, `visibility: must be "private" or "public", got "secret"`},
+		},
+		{
+			name:    "invalid model triples",
+			replace: []string{`"rdk:component:motor"`, `"motor"`, `"acme:demo:my-motor"`, `"my-motor"`},
+			errContains: []string{
+				`models[0].api: "motor" must be in the form 'namespace:type:subtype'`,
+				`models[0].model: "my-motor" must be in the form 'namespace:family:name'`,
+			},
+		},
+		{
+			name:        "example model left in",
+			replace:     []string{`{"api": "rdk:component:motor", "model": "acme:demo:my-motor"}`, `{}`},
+			errContains: []string{"models[0]: api and model are empty"},
+		},
+		{
+			name:        "entrypoint outside the archive",
+			replace:     []string{`"bin/my-module"`, `"../my-module"`},
+			errContains: []string{`entrypoint: "../my-module" must be a path within the module archive`},
+		},
+		{
+			name:        "invalid build section",
+			replace:     []string{`"make module.tar.gz"`, `""`, `"linux/arm64"`, `"arm64"`},
+			errContains: []string{"build.build: required", `build.arch[1]: "arm64" must be in the form 'os/arch'`},
+		},
+		{
+			name:        "invalid url",
+			replace:     []string{`"https://github.com/acme/my-module"`, `"github.com/acme/my-module"`},
+			errContains: []string{`url: "github.com/acme/my-module" is not an http or https URL`},
+		},
+		{
+			name:        "wrong type",
+			replace:     []string{`"arch": ["linux/amd64", "linux/arm64"]`, `"arch": "linux/amd64"`},
+			errContains: []string{"build.arch: must be a list, got string"},
+		},
+		{
+			name:        "not json",
+			replace:     []string{`"private",`, `"private"`},
+			errContains: []string{"is not valid JSON"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), defaultManifestFilename)
+			fixture := strings.NewReplacer(tc.replace...).Replace(validTestManifest)
+			test.That(t, os.WriteFile(path, []byte(fixture), 0o600), test.ShouldBeNil)
+
+			manifest, err := loadManifest(path)
+			if err == nil {
+				err = manifestValidationError(path, validateManifest(manifest))
+			}
+			if len(tc.errContains) == 0 {
+				test.That(t, err, test.ShouldBeNil)
+				return
+			}
+			test.That(t, err, test.ShouldNotBeNil)
+			for _, errContains := range tc.errContains {
+				test.That(t, err.Error(), test.ShouldContainSubstring, errContains)
+			}
+		})
+	}
+}
+
+func TestCheckUploadEntrypoint(t *testing.T) {
+	dir := t.TempDir()
+	bin := filepath.Join(dir, "bin")
+	test.That(t, os.MkdirAll(bin, 0o700), test.ShouldBeNil)
+	entrypoint := filepath.Join(bin, "my-module")
+	test.That(t, os.WriteFile(entrypoint, []byte("#!/bin/sh"), 0o700), test.ShouldBeNil)
+	test.That(t, os.WriteFile(filepath.Join(bin, "README.md"), nil, 0o600), test.ShouldBeNil)
+
+	test.That(t, checkUploadEntrypoint(dir, entrypoint), test.ShouldBeNil)
+	test.That(t, checkUploadEntrypoint(bin, entrypoint), test.ShouldBeNil)
+	test.That(t, checkUploadEntrypoint(entrypoint, entrypoint), test.ShouldBeNil)
+
+	for _, tc := range []struct{ path, entrypoint, errContains string }{
+		{bin, filepath.Join(bin, "missing"), "does not exist"},
+		{bin, filepath.Join(dir, "my-module"), "is not inside the uploaded directory"},
+		{entrypoint, filepath.Join(bin, "README.md"), "is not the uploaded file"},
+		{dir, bin, "is a directory"},
+		{dir, filepath.Join(bin, "README.md"), "is not marked as executable"},
+	} {
+		err := checkUploadEntrypoint(tc.path, tc.entrypoint)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, tc.errContains)
+	}
+
+	test.That(t, os.WriteFile(filepath.Join(dir, viamIgnoreFilename), []byte("bin/my-module\n"), 0o600), test.ShouldBeNil)
+	err := checkUploadEntrypoint(dir, entrypoint)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "is excluded from the upload")
+}
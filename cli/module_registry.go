@@ -10,14 +10,18 @@ import (
 	"io"
 	"io/fs"
 	"math"
+	"net/http"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 
+	"github.com/Masterminds/semver/v3"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"github.com/urfave/cli/v2"
 	"go.uber.org/multierr"
+	packagepb "go.viam.com/api/app/packages/v1"
 	apppb "go.viam.com/api/app/v1"
 	vutils "go.viam.com/utils"
 
@@ -31,6 +35,13 @@ import (
 // moduleUploadChunkSize sets the number of bytes included in each chunk of the upload stream.
 var moduleUploadChunkSize = 32 * 1024
 
+var (
+	// moduleNamePattern and publicNamespacePattern are the formats of module names and public namespaces that
+	// 'module create --local' accepts without contacting app.viam.com.
+	moduleNamePattern      = regexp.MustCompile(`^[a-z0-9](?:[a-z0-9_-]*[a-z0-9])?$`)
+	publicNamespacePattern = regexp.MustCompile(`^[a-z0-9](?:[a-z0-9-]*[a-z0-9])?$`)
+)
+
 // moduleVisibility determines whether modules are public or private.
 type moduleVisibility string
 
@@ -76,6 +87,8 @@ type moduleManifest struct {
 	Models      []ModuleComponent  `json:"models"`
 	Entrypoint  string             `json:"entrypoint"`
 	Build       *manifestBuildInfo `json:"build,omitempty"`
+	// Unregistered is set by 'module create --local' until the module is registered on app.viam.com.
+	Unregistered bool `json:"unregistered,omitempty"`
 }
 
 const (
@@ -84,11 +97,19 @@ const (
 
 // CreateModuleAction is the corresponding Action for 'module create'. It runs
 // the command to create a module. This includes both a gRPC call to register
-// the module on app.viam.com and creating the manifest file.
+// the module on app.viam.com and creating the manifest file. With --local, only
+// the manifest file is created, without any network calls.
 func CreateModuleAction(c *cli.Context) error {
 	moduleNameArg := c.String(moduleFlagName)
 	publicNamespaceArg := c.String(moduleFlagPublicNamespace)
 	orgIDArg := c.String(generalFlagOrgID)
+	if publicNamespaceArg == "" {
+		orgIDArg = stringFlagOrConfigFile(c, generalFlagOrgID)
+	}
+
+	if c.Bool(moduleFlagLocal) {
+		return createLocalModule(c, moduleNameArg, publicNamespaceArg, orgIDArg)
+	}
 
 	client, err := newViamClient(c)
 	if err != nil {
@@ -99,25 +120,12 @@ func CreateModuleAction(c *cli.Context) error {
 		return err
 	}
 
-	shouldWriteNewEmptyManifest := true
-
 	// If a meta.json exists in the current directory, we have a slightly different creation flow
 	// in order to minimize user frustration. We will continue the creation if the args passed to create
 	// match the values in the meta.json
-	if _, err := os.Stat(defaultManifestFilename); err == nil {
-		modManifest, err := loadManifest(defaultManifestFilename)
-		if err != nil {
-			return errors.New("another meta.json already exists in the current directory. Delete it and try again")
-		}
-		manifestModuleID, err := parseModuleID(modManifest.ModuleID)
-		if err != nil ||
-			manifestModuleID.name != moduleNameArg ||
-			(manifestModuleID.prefix != org.GetId() && manifestModuleID.prefix != org.GetPublicNamespace()) {
-			return errors.Errorf("a different module's meta.json already exists in the current directory. "+
-				"Either delete that meta.json, or edit its module_id (%q) to match the args passed to this command",
-				modManifest.ModuleID)
-		}
-		shouldWriteNewEmptyManifest = false
+	existingManifest, err := loadExistingManifestForCreate(moduleNameArg, org.GetId(), org.GetPublicNamespace())
+	if err != nil {
+		return err
 	}
 
 	response, err := client.createModule(moduleNameArg, org.GetId())
@@ -135,36 +143,148 @@ func CreateModuleAction(c *cli.Context) error {
 		printf(c.App.Writer, "You can view it here: %s", response.GetUrl())
 	}
 
-	if shouldWriteNewEmptyManifest {
-		emptyManifest := moduleManifest{
-			ModuleID:   returnedModuleID.String(),
-			Visibility: moduleVisibilityPrivate,
-			// This is done so that the json has an empty example
-			Models: []ModuleComponent{
-				{},
-			},
-		}
-		if err := writeManifest(defaultManifestFilename, emptyManifest); err != nil {
+	if existingManifest == nil {
+		if err := writeManifest(defaultManifestFilename, newEmptyManifest(returnedModuleID)); err != nil {
 			return err
 		}
 
 		printf(c.App.Writer, "Configuration for the module has been written to meta.json")
+	} else if existingManifest.Unregistered {
+		// The meta.json was written by 'module create --local' and the module is now registered.
+		existingManifest.Unregistered = false
+		existingManifest.ModuleID = returnedModuleID.String()
+		if err := writeManifest(defaultManifestFilename, *existingManifest); err != nil {
+			return err
+		}
+		printf(c.App.Writer, "meta.json has been marked as registered")
+	}
+	return nil
+}
+
+// createLocalModule writes the meta.json of a module that is not yet registered on app.viam.com, so that
+// a module can be started offline. The name and namespace or org id are only checked for their format here;
+// whether they are available is checked when the module is registered.
+func createLocalModule(c *cli.Context, name, publicNamespace, orgID string) error {
+	prefix, err := validateLocalModuleID(name, publicNamespace, orgID)
+	if err != nil {
+		return err
+	}
+	existingManifest, err := loadExistingManifestForCreate(name, prefix)
+	if err != nil {
+		return err
+	}
+	if existingManifest != nil {
+		printf(c.App.Writer, "meta.json for '%s' already exists in the current directory", existingManifest.ModuleID)
+		return nil
+	}
+
+	manifest := newEmptyManifest(moduleID{prefix: prefix, name: name})
+	manifest.Unregistered = true
+	if err := writeManifest(defaultManifestFilename, manifest); err != nil {
+		return err
 	}
+
+	prefixFlag := moduleFlagPublicNamespace
+	if orgID != "" {
+		prefixFlag = generalFlagOrgID
+	}
+	printf(c.App.Writer, "Configuration for the module has been written to meta.json")
+	printf(c.App.Writer, "The module is not registered on app.viam.com yet. Once you are online, register it with "+
+		"'viam module create --%s %s --%s %s' from this directory", moduleFlagName, name, prefixFlag, prefix)
 	return nil
 }
 
+// validateLocalModuleID checks the format of a module name and its public namespace or org id without
+// contacting app.viam.com, and returns the prefix of the module id.
+func validateLocalModuleID(name, publicNamespace, orgID string) (string, error) {
+	if !moduleNamePattern.MatchString(name) {
+		return "", errors.Errorf("module name %q must be lowercase letters, numbers, hyphens and underscores, "+
+			"starting and ending with a letter or number", name)
+	}
+	switch {
+	case orgID != "" && publicNamespace != "":
+		return "", errors.New("cannot specify both org-id and public-namespace")
+	case orgID != "":
+		if !isValidOrgID(orgID) {
+			return "", errors.Errorf("provided org-id %q is not a valid org-id", orgID)
+		}
+		return orgID, nil
+	case publicNamespace != "":
+		if !publicNamespacePattern.MatchString(publicNamespace) {
+			return "", errors.Errorf("public namespace %q must be lowercase letters, numbers and hyphens, "+
+				"starting and ending with a letter or number", publicNamespace)
+		}
+		return publicNamespace, nil
+	default:
+		return "", errors.New("must provide either org-id or public-namespace")
+	}
+}
+
+// loadExistingManifestForCreate returns the meta.json in the current directory, or nil if there is none. It
+// returns an error if the meta.json is for a module other than the one with the given name and any of the
+// given prefixes.
+func loadExistingManifestForCreate(name string, prefixes ...string) (*moduleManifest, error) {
+	if _, err := os.Stat(defaultManifestFilename); err != nil {
+		//nolint:nilerr
+		return nil, nil
+	}
+	modManifest, err := loadManifest(defaultManifestFilename)
+	if err != nil {
+		return nil, errors.New("another meta.json already exists in the current directory. Delete it and try again")
+	}
+	manifestModuleID, err := parseModuleID(modManifest.ModuleID)
+	if err == nil && manifestModuleID.name == name {
+		for _, prefix := range prefixes {
+			if prefix != "" && manifestModuleID.prefix == prefix {
+				return &modManifest, nil
+			}
+		}
+	}
+	return nil, errors.Errorf("a different module's meta.json already exists in the current directory. "+
+		"Either delete that meta.json, or edit its module_id (%q) to match the args passed to this command",
+		modManifest.ModuleID)
+}
+
+// newEmptyManifest returns the manifest 'module create' writes for a new module.
+func newEmptyManifest(id moduleID) moduleManifest {
+	return moduleManifest{
+		ModuleID:   id.String(),
+		Visibility: moduleVisibilityPrivate,
+		// This is done so that the json has an empty example
+		Models: []ModuleComponent{
+			{},
+		},
+	}
+}
+
+// checkManifestRegistered returns an error if the manifest was written by 'module create --local' for a
+// module that has not been registered on app.viam.com since.
+func checkManifestRegistered(manifest moduleManifest) error {
+	if !manifest.Unregistered {
+		return nil
+	}
+	return errors.Errorf("module %q has not been registered on app.viam.com yet. "+
+		"Run 'viam module create' without --local from the directory of its meta.json to register it", manifest.ModuleID)
+}
+
 // UpdateModuleAction is the corresponding Action for 'module update'. It runs
 // the command to update a module. This includes updating the meta.json to
 // include the public namespace (if set on the org).
 func UpdateModuleAction(c *cli.Context) error {
 	manifestPath := c.String(moduleFlagPath)
 
-	client, err := newViamClient(c)
+	manifest, err := loadManifest(manifestPath)
 	if err != nil {
 		return err
 	}
+	if err := checkManifestRegistered(manifest); err != nil {
+		return err
+	}
+	if err := manifestValidationError(manifestPath, validateManifest(manifest)); err != nil {
+		return err
+	}
 
-	manifest, err := loadManifest(manifestPath)
+	client, err := newViamClient(c)
 	if err != nil {
 		return err
 	}
@@ -202,22 +322,48 @@ func UploadModuleAction(c *cli.Context) error {
 	manifestPath := c.String(moduleFlagPath)
 	publicNamespaceArg := c.String(moduleFlagPublicNamespace)
 	orgIDArg := c.String(generalFlagOrgID)
+	if publicNamespaceArg == "" {
+		orgIDArg = stringFlagOrConfigFile(c, generalFlagOrgID)
+	}
 	nameArg := c.String(moduleFlagName)
 	versionArg := c.String(moduleFlagVersion)
 	platformArg := c.String(moduleFlagPlatform)
 	forceUploadArg := c.Bool(moduleFlagForce)
-	moduleUploadPath := c.Args().First()
-	if c.Args().Len() > 1 {
-		return errors.New("too many arguments passed to upload command. " +
-			"Make sure to specify flag and optional arguments before the required positional package argument")
-	}
-	if moduleUploadPath == "" {
-		return errors.New("nothing to upload -- please provide a path to your module. Use --help for more information")
+	uploads, err := resolveModuleUploads(c.Args().Slice(), platformArg)
+	if err != nil {
+		return err
 	}
 
 	// Clean the version argument to ensure compatibility with github tag standards
 	versionArg = strings.TrimPrefix(versionArg, "v")
 
+	// if we can find a manifest, use it, and validate it before making any requests
+	var manifest *moduleManifest
+	if _, err := os.Stat(manifestPath); err == nil {
+		loaded, err := loadManifest(manifestPath)
+		if err != nil {
+			return err
+		}
+		if err := checkManifestRegistered(loaded); err != nil {
+			return err
+		}
+		errs := validateManifest(loaded)
+		if !forceUploadArg && loaded.Entrypoint != "" {
+			for _, upload := range uploads {
+				if isTarball(upload.path) {
+					continue
+				}
+				if err := checkUploadEntrypoint(upload.path, loaded.Entrypoint); err != nil {
+					errs = append(errs, err)
+				}
+			}
+		}
+		if err := manifestValidationError(manifestPath, errs); err != nil {
+			return err
+		}
+		manifest = &loaded
+	}
+
 	client, err := newViamClient(c)
 	if err != nil {
 		return err
@@ -225,7 +371,7 @@ func UploadModuleAction(c *cli.Context) error {
 
 	var moduleID moduleID
 	// if the manifest cant be found, use passed in arguments to determine the module id
-	if _, err := os.Stat(manifestPath); err != nil {
+	if manifest == nil {
 		if nameArg == "" || (publicNamespaceArg == "" && orgIDArg == "") {
 			return errors.New("unable to find the meta.json. " +
 				"If you want to upload a version without a meta.json, you must supply a module name and namespace (or module name and org-id)",
@@ -238,11 +384,6 @@ func UploadModuleAction(c *cli.Context) error {
 			moduleID.prefix = orgIDArg
 		}
 	} else {
-		// if we can find a manifest, use that
-		manifest, err := loadManifest(manifestPath)
-		if err != nil {
-			return err
-		}
 		moduleID, err = parseModuleID(manifest.ModuleID)
 		if err != nil {
 			return err
@@ -258,39 +399,333 @@ func UploadModuleAction(c *cli.Context) error {
 			return err
 		}
 
-		_, err = client.updateModule(moduleID, manifest)
+		_, err = client.updateModule(moduleID, *manifest)
 		if err != nil {
 			return errors.Wrap(err, "Module update failed. Please correct the following issues in your meta.json")
 		}
 	}
 
-	tarballPath := moduleUploadPath
-	if !isTarball(tarballPath) {
-		tarballPath, err = createTarballForUpload(moduleUploadPath, c.App.Writer)
+	if len(uploads) == 1 {
+		response, err := client.uploadModuleVersion(moduleID, versionArg, uploads[0], forceUploadArg)
 		if err != nil {
 			return err
 		}
+		printf(c.App.Writer, "Version successfully uploaded! you can view your changes online here: %s", response.GetUrl())
+		return nil
+	}
+
+	// Upload every platform even if some fail, so that a failure can be retried on its own.
+	var failed []string
+	var url string
+	for _, upload := range uploads {
+		response, err := client.uploadModuleVersion(moduleID, versionArg, upload, forceUploadArg)
+		if err != nil {
+			failed = append(failed, upload.platform)
+			printf(c.App.Writer, "%s: failed to upload %s: %v", upload.platform, upload.path, err)
+			continue
+		}
+		url = response.GetUrl()
+		printf(c.App.Writer, "%s: uploaded %s", upload.platform, upload.path)
+	}
+	if len(failed) > 0 {
+		return errors.Errorf("failed to upload %d of %d platforms: %s", len(failed), len(uploads), strings.Join(failed, ", "))
+	}
+	printf(c.App.Writer, "Version successfully uploaded for %d platforms! you can view your changes online here: %s", len(uploads), url)
+	return nil
+}
+
+// moduleUpload is a file or directory to upload as the module version for a platform.
+type moduleUpload struct {
+	platform string
+	path     string
+}
+
+// moduleUploadPlatformPattern matches the platform at the end of the name of a tarball, without its extension,
+// such as my-module-linux-arm64 or my_module_darwin_amd64.
+var moduleUploadPlatformPattern = regexp.MustCompile(
+	`(?:^|[-_.])(?:(linux|darwin|any)[-_](amd64|arm64|arm32v7|arm32v6|any)|(any))$`)
+
+// resolveModuleUploads returns what to upload for the paths passed to 'module upload'. With --platform, a single
+// path, which may be a file, a directory or a tarball, is uploaded for that platform. Without it, every path must be
+// a tarball, or a directory of tarballs, whose platform is inferred from its file name.
+func resolveModuleUploads(paths []string, platform string) ([]moduleUpload, error) {
+	if len(paths) == 0 {
+		return nil, errors.New("nothing to upload -- please provide a path to your module. Use --help for more information")
+	}
+	if platform != "" {
+		if len(paths) > 1 {
+			return nil, errors.Errorf("too many arguments passed to upload command. To upload tarballs for several platforms, "+
+				"leave out --%s so that the platform of each is inferred from its file name. "+
+				"Otherwise, make sure to specify flag and optional arguments before the required positional package argument",
+				moduleFlagPlatform)
+		}
+		return []moduleUpload{{platform: platform, path: paths[0]}}, nil
+	}
+
+	var tarballs []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			tarballs = append(tarballs, path)
+			continue
+		}
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		var found bool
+		for _, entry := range entries {
+			if !entry.IsDir() && isTarball(entry.Name()) {
+				tarballs = append(tarballs, filepath.Join(path, entry.Name()))
+				found = true
+			}
+		}
+		if !found {
+			return nil, errors.Errorf("%s contains no tarballs. To upload the directory itself, pass --%s", path, moduleFlagPlatform)
+		}
+	}
+
+	uploads := make([]moduleUpload, 0, len(tarballs))
+	pathsByPlatform := map[string]string{}
+	for _, tarball := range tarballs {
+		platform, err := inferModuleUploadPlatform(tarball)
+		if err != nil {
+			return nil, err
+		}
+		if other, ok := pathsByPlatform[platform]; ok {
+			return nil, errors.Errorf("both %s and %s are for platform %s", other, tarball, platform)
+		}
+		pathsByPlatform[platform] = tarball
+		uploads = append(uploads, moduleUpload{platform: platform, path: tarball})
+	}
+	return uploads, nil
+}
+
+// inferModuleUploadPlatform returns the platform at the end of the file name of the tarball at path, such as
+// linux/arm64 for my-module-linux-arm64.tar.gz.
+func inferModuleUploadPlatform(path string) (string, error) {
+	if !isTarball(path) {
+		return "", errors.Errorf("%s is not a tarball, so pass --%s to upload it", path, moduleFlagPlatform)
+	}
+	name := strings.ToLower(filepath.Base(path))
+	name = strings.TrimSuffix(strings.TrimSuffix(name, ".tar.gz"), ".tgz")
+	match := moduleUploadPlatformPattern.FindStringSubmatch(name)
+	switch {
+	case match == nil:
+		return "", errors.Errorf("cannot infer the platform of %s from its file name, which should end in a platform "+
+			"such as linux-arm64. Rename it, or upload it on its own with --%s", path, moduleFlagPlatform)
+	case match[3] != "", match[1] == "any" && match[2] == "any":
+		return "any", nil
+	default:
+		return match[1] + "/" + match[2], nil
+	}
+}
+
+// uploadModuleVersion validates, unless force is set, and uploads upload as the given version of the module,
+// archiving it first if it is not a tarball.
+func (c *viamClient) uploadModuleVersion(
+	moduleID moduleID, version string, upload moduleUpload, force bool,
+) (*apppb.UploadModuleFileResponse, error) {
+	tarballPath := upload.path
+	if !isTarball(tarballPath) {
+		var err error
+		tarballPath, err = createTarballForUpload(upload.path, c.c.App.Writer)
+		if err != nil {
+			return nil, err
+		}
 		defer utils.RemoveFileNoError(tarballPath)
 	}
 
-	if !forceUploadArg {
-		if err := validateModuleFile(client, moduleID, tarballPath, versionArg); err != nil {
-			return fmt.Errorf(
+	if !force {
+		if err := validateModuleFile(c, moduleID, tarballPath, version); err != nil {
+			return nil, fmt.Errorf(
 				"error validating module: %w. For more details, please visit: https://docs.viam.com/fleet/cli/#module ",
 				err)
 		}
 	}
+	return c.uploadModuleFile(moduleID, version, upload.platform, tarballPath)
+}
+
+// DownloadModuleAction is the corresponding action for 'module download'.
+func DownloadModuleAction(c *cli.Context) error {
+	manifestPath := c.String(moduleFlagPath)
+	idArg := c.String(moduleFlagID)
+	publicNamespaceArg := c.String(moduleFlagPublicNamespace)
+	orgIDArg := c.String(generalFlagOrgID)
+	if publicNamespaceArg == "" {
+		orgIDArg = stringFlagOrConfigFile(c, generalFlagOrgID)
+	}
+	nameArg := c.String(moduleFlagName)
+	versionArg := strings.TrimPrefix(c.String(moduleFlagVersion), "v")
+	platformArg := c.String(moduleFlagPlatform)
+	destinationArg := c.String(moduleFlagDestination)
+
+	var moduleID moduleID
+	var err error
+	switch {
+	case idArg != "":
+		moduleID, err = parseModuleID(idArg)
+		if err != nil {
+			return err
+		}
+	case nameArg != "":
+		if publicNamespaceArg == "" && orgIDArg == "" {
+			return errors.New("a module name was supplied without a namespace. " +
+				"You must supply the module name and namespace (or module name and org-id)")
+		}
+		moduleID.name = nameArg
+		if publicNamespaceArg != "" {
+			moduleID.prefix = publicNamespaceArg
+		} else {
+			moduleID.prefix = orgIDArg
+		}
+	default:
+		if _, err := os.Stat(manifestPath); err != nil {
+			return errors.New("unable to find the meta.json. " +
+				"If you want to download a module without a meta.json, you must supply a module id " +
+				"or a module name and namespace (or module name and org-id)",
+			)
+		}
+		manifest, err := loadManifest(manifestPath)
+		if err != nil {
+			return err
+		}
+		moduleID, err = parseModuleID(manifest.ModuleID)
+		if err != nil {
+			return err
+		}
+	}
 
-	response, err := client.uploadModuleFile(moduleID, versionArg, platformArg, tarballPath)
+	client, err := newViamClient(c)
 	if err != nil {
 		return err
 	}
+	res, err := client.getModule(moduleID)
+	if err != nil {
+		return err
+	}
+	version, err := getModuleVersion(res.GetModule(), versionArg)
+	if err != nil {
+		return err
+	}
+	if err := checkModuleVersionPlatform(version, platformArg); err != nil {
+		return err
+	}
 
-	printf(c.App.Writer, "Version successfully uploaded! you can view your changes online here: %s", response.GetUrl())
-
+	destPath := filepath.Join(destinationArg, fmt.Sprintf("%s-%s-%s-%s.tar.gz",
+		moduleID.prefix, moduleID.name, version.GetVersion(), strings.ReplaceAll(platformArg, "/", "-")))
+	if err := client.downloadModuleFile(res.GetModule(), version.GetVersion(), platformArg, destPath); err != nil {
+		return err
+	}
+	printf(c.App.Writer, "Version %s of %s for %s downloaded to %s", version.GetVersion(), moduleID.String(), platformArg, destPath)
 	return nil
 }
 
+// getModuleVersion returns the version of mod matching version, or the latest version if version is empty.
+func getModuleVersion(mod *apppb.Module, version string) (*apppb.VersionHistory, error) {
+	if len(mod.GetVersions()) == 0 {
+		return nil, errors.Errorf("module %q has no uploaded versions", mod.GetModuleId())
+	}
+	if version != "" {
+		for _, ver := range mod.GetVersions() {
+			if ver.GetVersion() == version {
+				return ver, nil
+			}
+		}
+		return nil, errors.Errorf("module %q has no version %q", mod.GetModuleId(), version)
+	}
+
+	var latest *apppb.VersionHistory
+	var latestSemver *semver.Version
+	for _, ver := range mod.GetVersions() {
+		parsed, err := semver.NewVersion(ver.GetVersion())
+		if err != nil {
+			continue
+		}
+		if latestSemver == nil || parsed.GreaterThan(latestSemver) {
+			latest, latestSemver = ver, parsed
+		}
+	}
+	if latest == nil {
+		return nil, errors.Errorf("module %q has no valid semver versions", mod.GetModuleId())
+	}
+	return latest, nil
+}
+
+// checkModuleVersionPlatform returns an error listing the available platforms if version was not uploaded for platform.
+func checkModuleVersionPlatform(version *apppb.VersionHistory, platform string) error {
+	platforms := make([]string, 0, len(version.GetFiles()))
+	for _, file := range version.GetFiles() {
+		if file.GetPlatform() == platform {
+			return nil
+		}
+		platforms = append(platforms, file.GetPlatform())
+	}
+	return errors.Errorf("version %q has no upload for platform %q. Available platforms: %s",
+		version.GetVersion(), platform, strings.Join(platforms, ", "))
+}
+
+// downloadModuleFile downloads the tarball uploaded for version and platform of mod to destPath.
+func (c *viamClient) downloadModuleFile(mod *apppb.Module, version, platform, destPath string) error {
+	if err := c.ensureLoggedIn(); err != nil {
+		return err
+	}
+	includeURL := true
+	packageType := packagepb.PackageType_PACKAGE_TYPE_MODULE
+	resp, err := c.packageClient.GetPackage(c.c.Context, &packagepb.GetPackageRequest{
+		Id:         fmt.Sprintf("%s/%s", mod.GetOrganizationId(), mod.GetName()),
+		Version:    version,
+		Type:       &packageType,
+		Platform:   &platform,
+		IncludeUrl: &includeURL,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to get module package")
+	}
+
+	req, err := http.NewRequestWithContext(c.c.Context, http.MethodGet, resp.GetPackage().GetUrl(), nil)
+	if err != nil {
+		return err
+	}
+	switch auth := c.conf.Auth.(type) {
+	case *token:
+		req.Header.Add("Authorization", "Bearer "+auth.AccessToken)
+	case *apiKey:
+		req.Header.Add("key_id", auth.KeyID)
+		req.Header.Add("key", auth.KeyCrypto)
+	}
+	//nolint:bodyclose
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer vutils.UncheckedErrorFunc(res.Body.Close)
+	if res.StatusCode != http.StatusOK {
+		return errors.Errorf("failed to download module: %s", res.Status)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(destPath), 0o700); err != nil {
+		return err
+	}
+	// Download to a temporary file first so a failed download doesn't leave a partial tarball behind.
+	tmpFile, err := os.CreateTemp(filepath.Dir(destPath), filepath.Base(destPath)+".download-*")
+	if err != nil {
+		return err
+	}
+	defer utils.RemoveFileNoError(tmpFile.Name())
+	if _, err := io.Copy(tmpFile, res.Body); err != nil {
+		vutils.UncheckedError(tmpFile.Close())
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile.Name(), destPath)
+}
+
 // UpdateModelsAction figures out the models that a module supports and updates it's metadata file.
 func UpdateModelsAction(c *cli.Context) error {
 	logger := logging.NewLogger("x")
@@ -612,7 +1047,13 @@ func loadManifest(manifestPath string) (moduleManifest, error) {
 	}
 	var manifest moduleManifest
 	if err := json.Unmarshal(manifestBytes, &manifest); err != nil {
-		return moduleManifest{}, err
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return moduleManifest{}, manifestValidationError(manifestPath, []error{
+				errors.Errorf("%s: must be %s, got %s", typeErr.Field, jsonTypeName(typeErr.Type), typeErr.Value),
+			})
+		}
+		return moduleManifest{}, errors.Wrapf(err, "%s is not valid JSON", manifestPath)
 	}
 	return manifest, nil
 }
@@ -664,6 +1105,20 @@ func createTarballForUpload(moduleUploadPath string, stdout io.Writer) (string,
 	if err != nil {
 		return "", errors.Wrapf(err, "failed to find files to compress in %q", moduleUploadPath)
 	}
+	if info, err := os.Stat(moduleUploadPath); err == nil && info.IsDir() {
+		ignore, err := loadViamIgnore(moduleUploadPath)
+		if err != nil {
+			return "", err
+		}
+		if ignore != nil {
+			var numExcluded int
+			archiveFiles, numExcluded, err = ignore.filter(filepath.Clean(moduleUploadPath), archiveFiles)
+			if err != nil {
+				return "", err
+			}
+			printf(stdout, "Using %s: including %d files, excluding %d files", viamIgnoreFilename, len(archiveFiles), numExcluded)
+		}
+	}
 	if len(archiveFiles) == 0 {
 		return "", errors.Errorf("failed to find any files in %q", moduleUploadPath)
 	}
@@ -0,0 +1,171 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	apppb "go.viam.com/api/app/v1"
+	"go.viam.com/test"
+)
+
+func TestGetModuleVersion(t *testing.T) {
+	mod := &apppb.Module{
+		ModuleId: "my-namespace:my-module",
+		Versions: []*apppb.VersionHistory{
+			{Version: "0.2.0"},
+			{Version: "0.10.0"},
+			{Version: "0.9.1"},
+		},
+	}
+
+	version, err := getModuleVersion(mod, "")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, version.GetVersion(), test.ShouldEqual, "0.10.0")
+
+	version, err = getModuleVersion(mod, "0.2.0")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, version.GetVersion(), test.ShouldEqual, "0.2.0")
+
+	_, err = getModuleVersion(mod, "1.0.0")
+	test.That(t, err, test.ShouldBeError)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "has no version \"1.0.0\"")
+
+	_, err = getModuleVersion(&apppb.Module{ModuleId: "my-namespace:my-module"}, "")
+	test.That(t, err, test.ShouldBeError)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "has no uploaded versions")
+}
+
+func TestCheckModuleVersionPlatform(t *testing.T) {
+	version := &apppb.VersionHistory{
+		Version: "0.1.0",
+		Files: []*apppb.Uploads{
+			{Platform: "linux/amd64"},
+			{Platform: "linux/arm64"},
+		},
+	}
+	test.That(t, checkModuleVersionPlatform(version, "linux/arm64"), test.ShouldBeNil)
+
+	err := checkModuleVersionPlatform(version, "darwin/arm64")
+	test.That(t, err, test.ShouldBeError)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "Available platforms: linux/amd64, linux/arm64")
+}
+
+func TestResolveModuleUploads(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{
+		"module-linux-amd64.tar.gz", "module_linux_arm64.tgz", "module-darwin-arm64.tar.gz", "module-any.tar.gz", "README.md",
+	} {
+		test.That(t, os.WriteFile(filepath.Join(dir, name), nil, 0o600), test.ShouldBeNil)
+	}
+
+	// With --platform, a single path of any kind is uploaded for it.
+	uploads, err := resolveModuleUploads([]string{dir}, "linux/amd64")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, uploads, test.ShouldResemble, []moduleUpload{{platform: "linux/amd64", path: dir}})
+	_, err = resolveModuleUploads([]string{dir, dir}, "linux/amd64")
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = resolveModuleUploads(nil, "")
+	test.That(t, err, test.ShouldNotBeNil)
+
+	// Without it, the platform of each tarball is inferred from its file name.
+	uploads, err = resolveModuleUploads([]string{dir}, "")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, uploads, test.ShouldResemble, []moduleUpload{
+		{platform: "any", path: filepath.Join(dir, "module-any.tar.gz")},
+		{platform: "darwin/arm64", path: filepath.Join(dir, "module-darwin-arm64.tar.gz")},
+		{platform: "linux/amd64", path: filepath.Join(dir, "module-linux-amd64.tar.gz")},
+		{platform: "linux/arm64", path: filepath.Join(dir, "module_linux_arm64.tgz")},
+	})
+	uploads, err = resolveModuleUploads([]string{filepath.Join(dir, "module-linux-amd64.tar.gz")}, "")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, uploads, test.ShouldResemble, []moduleUpload{
+		{platform: "linux/amd64", path: filepath.Join(dir, "module-linux-amd64.tar.gz")},
+	})
+
+	_, err = resolveModuleUploads([]string{filepath.Join(dir, "README.md")}, "")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "not a tarball")
+	_, err = resolveModuleUploads([]string{dir, filepath.Join(dir, "module-any.tar.gz")}, "")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "are for platform any")
+	_, err = resolveModuleUploads([]string{t.TempDir()}, "")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "contains no tarballs")
+}
+
+func TestInferModuleUploadPlatform(t *testing.T) {
+	for name, expected := range map[string]string{
+		"module-linux-arm32v7.tar.gz": "linux/arm32v7",
+		"Module_Darwin_AMD64.TGZ":     "darwin/amd64",
+		"module-linux-any.tar.gz":     "linux/any",
+		"module-any-arm64.tar.gz":     "any/arm64",
+		"module-any-any.tar.gz":       "any",
+		"any.tar.gz":                  "any",
+	} {
+		platform, err := inferModuleUploadPlatform(name)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, platform, test.ShouldEqual, expected)
+	}
+	for _, name := range []string{"module.tar.gz", "module-linux.tar.gz", "company-module-windows-amd64.tar.gz"} {
+		_, err := inferModuleUploadPlatform(name)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "cannot infer the platform")
+	}
+}
+
+func TestCreateLocalModule(t *testing.T) {
+	// other tests may have left the working directory in a removed temporary directory.
+	if wd, err := os.Getwd(); err == nil {
+		defer func() {
+			test.That(t, os.Chdir(wd), test.ShouldBeNil)
+		}()
+	}
+	test.That(t, os.Chdir(t.TempDir()), test.ShouldBeNil)
+
+	flags := map[string]string{moduleFlagName: "my-module", moduleFlagPublicNamespace: "my-org", moduleFlagLocal: "true"}
+	cCtx, _, out, _ := setup(nil, nil, nil, &flags, "")
+	test.That(t, CreateModuleAction(cCtx), test.ShouldBeNil)
+	test.That(t, out.messages[len(out.messages)-1], test.ShouldContainSubstring,
+		"viam module create --name my-module --public-namespace my-org")
+
+	manifest, err := loadManifest(defaultManifestFilename)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, manifest.ModuleID, test.ShouldEqual, "my-org:my-module")
+	test.That(t, manifest.Unregistered, test.ShouldBeTrue)
+	err = checkManifestRegistered(manifest)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "has not been registered")
+
+	// creating the same module again leaves its meta.json alone, but another module cannot overwrite it.
+	test.That(t, CreateModuleAction(cCtx), test.ShouldBeNil)
+	flags[moduleFlagName] = "other-module"
+	cCtx, _, _, _ = setup(nil, nil, nil, &flags, "")
+	err = CreateModuleAction(cCtx)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "a different module's meta.json already exists")
+}
+
+func TestValidateLocalModuleID(t *testing.T) {
+	orgID := "c7a3b2a5-0e43-4b8a-9a6f-2f4b3f0e8b1d"
+	prefix, err := validateLocalModuleID("my_module-2", "", orgID)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, prefix, test.ShouldEqual, orgID)
+	prefix, err = validateLocalModuleID("module", "my-org", "")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, prefix, test.ShouldEqual, "my-org")
+
+	for _, tc := range []struct{ name, namespace, orgID, errContains string }{
+		{"My-Module", "my-org", "", "module name"},
+		{"my-module-", "my-org", "", "module name"},
+		{"my:module", "my-org", "", "module name"},
+		{"my-module", "my_org", "", "public namespace"},
+		{"my-module", "", "not-an-org-id", "not a valid org-id"},
+		{"my-module", "my-org", orgID, "cannot specify both"},
+		{"my-module", "", "", "must provide either"},
+	} {
+		_, err := validateLocalModuleID(tc.name, tc.namespace, tc.orgID)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, tc.errContains)
+	}
+}
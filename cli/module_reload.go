@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+	apppb "go.viam.com/api/app/v1"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	reloadModuleFlagName = "name"
+
+	// moduleReloadEnvVar is the environment variable of a module that 'machines part reload-module' sets to the
+	// time of the reload. Changing the config of a module makes the part restart it, so this reloads the module
+	// without restarting the rest of the part.
+	moduleReloadEnvVar = "VIAM_MODULE_RELOADED_AT"
+)
+
+// RobotsPartReloadModuleAction is the corresponding Action for 'machines part reload-module'.
+func RobotsPartReloadModuleAction(c *cli.Context) error {
+	client, err := newViamClient(c)
+	if err != nil {
+		return err
+	}
+	return client.reloadModule(
+		stringFlagOrConfigFile(c, organizationFlag),
+		stringFlagOrConfigFile(c, locationFlag),
+		c.String(machineFlag),
+		c.String(partFlag),
+		c.String(reloadModuleFlagName),
+	)
+}
+
+// reloadModule makes the part restart the module with the given name, which the part does when it next polls for
+// config, and prints the version of the module. It does so by setting moduleReloadEnvVar in the config of the
+// module, which stays in the part config. The whole part config is written back, so edits made to it since it
+// was read are lost.
+func (c *viamClient) reloadModule(orgStr, locStr, robotStr, partStr, moduleName string) error {
+	part, err := c.robotPart(orgStr, locStr, robotStr, partStr)
+	if err != nil {
+		return errors.Wrap(err, "could not get machine part")
+	}
+	config := part.GetRobotConfig()
+	token := time.Now().UTC().Format(time.RFC3339Nano)
+	version, err := markModuleForReload(config, moduleName, token)
+	if err != nil {
+		return errors.Wrapf(err, "cannot reload module on machine part %q", part.Name)
+	}
+	if _, err := c.client.UpdateRobotPart(c.c.Context, &apppb.UpdateRobotPartRequest{
+		Id:          part.Id,
+		Name:        part.Name,
+		RobotConfig: config,
+	}); err != nil {
+		return errors.Wrap(err, "could not update machine part config")
+	}
+	printf(c.c.App.Writer, "Requested reload of module %q (version %s) on machine part %q", moduleName, version, part.Name)
+	infof(c.c.App.Writer, "Set %s=%s in the config of the module on machine part %q", moduleReloadEnvVar, token, part.Name)
+	if lastAccess := part.LastAccess.AsTime(); time.Since(lastAccess) > partOfflineThreshold {
+		warningf(c.c.App.ErrWriter, "machine part %q is offline (last access %s ago); the module will reload once it is back online",
+			part.Name, time.Since(lastAccess).Round(time.Second))
+	}
+	return nil
+}
+
+// markModuleForReload sets moduleReloadEnvVar of the module with the given name in the part config to token,
+// so that the part restarts the module when it picks up the config. It returns the version of the module.
+func markModuleForReload(config *structpb.Struct, moduleName, token string) (string, error) {
+	module, names := findConfiguredModule(config, moduleName)
+	if module == nil {
+		if len(names) == 0 {
+			return "", errors.Errorf("module %q is not configured; the machine part has no modules", moduleName)
+		}
+		return "", errors.Errorf("module %q is not configured; configured modules are %s",
+			moduleName, strings.Join(names, ", "))
+	}
+	env := module.Fields["env"].GetStructValue()
+	if env == nil {
+		env = &structpb.Struct{Fields: map[string]*structpb.Value{}}
+		module.Fields["env"] = structpb.NewStructValue(env)
+	}
+	env.Fields[moduleReloadEnvVar] = structpb.NewStringValue(token)
+	return configuredModuleVersion(module), nil
+}
+
+// findConfiguredModule returns the module with the given name in the part config, or nil and the sorted names of
+// the configured modules if there is none.
+func findConfiguredModule(config *structpb.Struct, moduleName string) (*structpb.Struct, []string) {
+	var names []string
+	for _, value := range config.GetFields()["modules"].GetListValue().GetValues() {
+		module := value.GetStructValue()
+		name := module.GetFields()["name"].GetStringValue()
+		if name == moduleName {
+			return module, nil
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return nil, names
+}
+
+// configuredModuleVersion describes the version of a module in the part config: the version of a registry
+// module, or the executable of a local one.
+func configuredModuleVersion(module *structpb.Struct) string {
+	fields := module.GetFields()
+	if version := fields["version"].GetStringValue(); version != "" {
+		return version
+	}
+	if exe := fields["executable_path"].GetStringValue(); exe != "" {
+		return "local (" + exe + ")"
+	}
+	return "unknown"
+}
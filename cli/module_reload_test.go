@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"testing"
+
+	"go.viam.com/test"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestMarkModuleForReload(t *testing.T) {
+	config, err := structpb.NewStruct(map[string]interface{}{
+		"modules": []interface{}{
+			map[string]interface{}{"name": "registry-module", "type": "registry", "version": "1.2.3"},
+			map[string]interface{}{
+				"name": "local-module", "type": "local", "executable_path": "/bin/module",
+				"env": map[string]interface{}{"FOO": "bar"},
+			},
+		},
+	})
+	test.That(t, err, test.ShouldBeNil)
+
+	version, err := markModuleForReload(config, "registry-module", "first")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, version, test.ShouldEqual, "1.2.3")
+
+	version, err = markModuleForReload(config, "local-module", "second")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, version, test.ShouldEqual, "local (/bin/module)")
+
+	modules := config.AsMap()["modules"].([]interface{})
+	test.That(t, modules[0].(map[string]interface{})["env"], test.ShouldResemble,
+		map[string]interface{}{moduleReloadEnvVar: "first"})
+	test.That(t, modules[1].(map[string]interface{})["env"], test.ShouldResemble,
+		map[string]interface{}{"FOO": "bar", moduleReloadEnvVar: "second"})
+
+	_, err = markModuleForReload(config, "missing", "third")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `module "missing" is not configured`)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "local-module, registry-module")
+
+	_, err = markModuleForReload(&structpb.Struct{}, "missing", "third")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "has no modules")
+}
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"fmt"
+	"sync"
+
+	apppb "go.viam.com/api/app/v1"
+)
+
+// maxParallelOrganizationRequests is how many organizations extended information is requested for at once.
+const maxParallelOrganizationRequests = 8
+
+// organizationExtendedInfo is the extended information shown by 'organizations list --extended'. Fields that
+// could not be fetched are nil.
+type organizationExtendedInfo struct {
+	MachineCount      *int     `json:"machine_count"`
+	BillingTier       *string  `json:"billing_tier"`
+	CurrentMonthUsage *float64 `json:"current_month_usage"`
+}
+
+// extendedOrganizationOutput is the JSON output of 'organizations list --extended'.
+type extendedOrganizationOutput struct {
+	organizationOutput
+	organizationExtendedInfo
+}
+
+func (info organizationExtendedInfo) available() bool {
+	return info.MachineCount != nil || info.BillingTier != nil || info.CurrentMonthUsage != nil
+}
+
+// organizationExtendedInfoHeader is the header of the table columns of tableCells.
+var organizationExtendedInfoHeader = []string{"MACHINES", "BILLING TIER", "USAGE THIS MONTH"}
+
+// tableCells returns the fields of info for text output, with "-" for those that are unavailable.
+func (info organizationExtendedInfo) tableCells() []string {
+	cells := []string{"-", "-", "-"}
+	if info.MachineCount != nil {
+		cells[0] = fmt.Sprint(*info.MachineCount)
+	}
+	if info.BillingTier != nil {
+		cells[1] = *info.BillingTier
+	}
+	if info.CurrentMonthUsage != nil {
+		cells[2] = fmt.Sprintf("$%.2f", *info.CurrentMonthUsage)
+	}
+	return cells
+}
+
+// organizationsExtendedInfo returns the extended information of each of orgs, in the same order. Organizations
+// are requested in parallel. Information that cannot be fetched, for example because the user may not see an
+// organization's billing, is left out rather than failing, and a single warning says for how many organizations.
+func (c *viamClient) organizationsExtendedInfo(orgs []*apppb.Organization) []organizationExtendedInfo {
+	infos := make([]organizationExtendedInfo, len(orgs))
+	sem := make(chan struct{}, maxParallelOrganizationRequests)
+	var wg sync.WaitGroup
+	for i, org := range orgs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, orgID string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			infos[i] = c.organizationExtendedInfo(orgID)
+		}(i, org.Id)
+	}
+	wg.Wait()
+
+	var unavailable int
+	for _, info := range infos {
+		if !info.available() {
+			unavailable++
+		}
+	}
+	if unavailable > 0 {
+		warningf(c.c.App.ErrWriter, "extended information is unavailable for %d of %d organizations", unavailable, len(orgs))
+	}
+	return infos
+}
+
+// organizationExtendedInfo returns the extended information of the organization with the given ID.
+func (c *viamClient) organizationExtendedInfo(orgID string) organizationExtendedInfo {
+	var info organizationExtendedInfo
+	if count, err := c.machineCount(orgID); err == nil {
+		info.MachineCount = &count
+	}
+	if billing, err := c.billingClient.GetOrgBillingInformation(c.c.Context,
+		&apppb.GetOrgBillingInformationRequest{OrgId: orgID}); err == nil && billing.BillingTier != nil {
+		info.BillingTier = billing.BillingTier
+	}
+	if usage, err := c.billingClient.GetCurrentMonthUsage(c.c.Context,
+		&apppb.GetCurrentMonthUsageRequest{OrgId: orgID}); err == nil {
+		total := usage.GetTotalUsageWithDiscount()
+		info.CurrentMonthUsage = &total
+	}
+	return info
+}
+
+// machineCount returns the number of machines in all of the locations of the organization with the given ID.
+func (c *viamClient) machineCount(orgID string) (int, error) {
+	locs, err := c.client.ListLocations(c.c.Context, &apppb.ListLocationsRequest{OrganizationId: orgID})
+	if err != nil {
+		return 0, err
+	}
+	var count int
+	for _, loc := range locs.Locations {
+		robots, err := c.client.ListRobots(c.c.Context, &apppb.ListRobotsRequest{LocationId: loc.Id})
+		if err != nil {
+			return 0, err
+		}
+		count += len(robots.Robots)
+	}
+	return count, nil
+}
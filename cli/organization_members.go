@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+	apppb "go.viam.com/api/app/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// organizationRolePrefix is the prefix of the authorization IDs of organization wide roles, such as
+// organization_owner.
+const organizationRolePrefix = "organization_"
+
+// organizationMemberOutput is the JSON output of 'organizations members list'.
+type organizationMemberOutput struct {
+	UserID string `json:"user_id"`
+	Email  string `json:"email"`
+	// Role is the member's organization wide role, or empty if they only have access to some of the
+	// organization's locations or machines.
+	Role      string     `json:"role"`
+	DateAdded *time.Time `json:"date_added,omitempty"`
+	LastLogin *time.Time `json:"last_login,omitempty"`
+}
+
+// OrganizationRenameAction is the corresponding Action for 'organizations rename'.
+func OrganizationRenameAction(c *cli.Context) error {
+	orgID, err := requiredStringFlagOrConfigFile(c, generalFlagOrgID)
+	if err != nil {
+		return err
+	}
+	client, err := newViamClient(c)
+	if err != nil {
+		return err
+	}
+	org, err := client.renameOrganization(orgID, c.String(organizationFlagName))
+	if err != nil {
+		return err
+	}
+	printf(c.App.Writer, "Renamed organization %s to %s", org.GetId(), org.GetName())
+	return nil
+}
+
+// renameOrganization renames the organization with the given ID to name.
+func (c *viamClient) renameOrganization(orgID, name string) (*apppb.Organization, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, errors.Errorf("--%s must not be empty", organizationFlagName)
+	}
+	if err := c.ensureLoggedIn(); err != nil {
+		return nil, err
+	}
+	resp, err := c.client.UpdateOrganization(c.c.Context, &apppb.UpdateOrganizationRequest{
+		OrganizationId: orgID,
+		Name:           &name,
+	})
+	if err != nil {
+		return nil, organizationRequestError(err, "rename", orgID)
+	}
+	return resp.GetOrganization(), nil
+}
+
+// OrganizationMembersListAction is the corresponding Action for 'organizations members list'.
+func OrganizationMembersListAction(c *cli.Context) error {
+	if _, err := requiredStringFlagOrConfigFile(c, generalFlagOrgID); err != nil {
+		return err
+	}
+	client, err := newViamClient(c)
+	if err != nil {
+		return err
+	}
+	return client.organizationMembersListAction(c)
+}
+
+func (c *viamClient) organizationMembersListAction(cCtx *cli.Context) error {
+	format, err := outputFormat(cCtx)
+	if err != nil {
+		return err
+	}
+	orgID := stringFlagOrConfigFile(cCtx, generalFlagOrgID)
+	members, err := c.listOrganizationMembers(orgID)
+	if err != nil {
+		return err
+	}
+	if format == outputFormatJSON {
+		return printJSON(cCtx.App.Writer, members)
+	}
+	opts := tableOptionsFor(cCtx)
+	if !opts.quiet {
+		printf(cCtx.App.Writer, "Members of organization %s:", orgID)
+	}
+	tbl := newTable("EMAIL", "ROLE").keepWhole("EMAIL")
+	for _, member := range members {
+		role := member.Role
+		if role == "" {
+			role = "no organization role"
+		}
+		tbl.addRow(member.Email, role)
+	}
+	tbl.render(cCtx.App.Writer, opts)
+	return nil
+}
+
+// listOrganizationMembers returns the members of the organization with the given ID along with their
+// organization wide roles.
+func (c *viamClient) listOrganizationMembers(orgID string) ([]organizationMemberOutput, error) {
+	if err := c.ensureLoggedIn(); err != nil {
+		return nil, err
+	}
+	resp, err := c.client.ListOrganizationMembers(c.c.Context, &apppb.ListOrganizationMembersRequest{OrganizationId: orgID})
+	if err != nil {
+		return nil, organizationRequestError(err, "list the members of", orgID)
+	}
+	auths, err := c.client.ListAuthorizations(c.c.Context, &apppb.ListAuthorizationsRequest{OrganizationId: orgID})
+	if err != nil {
+		return nil, organizationRequestError(err, "list the members of", orgID)
+	}
+	roles := map[string][]string{}
+	for _, auth := range auths.GetAuthorizations() {
+		if auth.GetResourceType() != "organization" || auth.GetResourceId() != orgID {
+			continue
+		}
+		roles[auth.GetIdentityId()] = append(roles[auth.GetIdentityId()],
+			strings.TrimPrefix(auth.GetAuthorizationId(), organizationRolePrefix))
+	}
+
+	members := make([]organizationMemberOutput, 0, len(resp.GetMembers()))
+	for _, member := range resp.GetMembers() {
+		members = append(members, organizationMemberOutput{
+			UserID:    member.GetUserId(),
+			Email:     strings.Join(member.GetEmails(), ", "),
+			Role:      strings.Join(roles[member.GetUserId()], ", "),
+			DateAdded: timestampOrNil(member.GetDateAdded()),
+			LastLogin: timestampOrNil(member.GetLastLogin()),
+		})
+	}
+	return members, nil
+}
+
+// organizationRequestError describes err, returned by a request to do action to the organization with the
+// given ID. Permission errors are explained rather than returned as is.
+func organizationRequestError(err error, action, orgID string) error {
+	if status.Code(err) == codes.PermissionDenied {
+		return errors.Errorf("you do not have permission to %s organization %s; only its owners can", action, orgID)
+	}
+	return errors.Wrapf(err, "could not %s organization %s", action, orgID)
+}
@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	apppb "go.viam.com/api/app/v1"
+	"go.viam.com/test"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestRenameOrganization(t *testing.T) {
+	asc := &inject.AppServiceClient{
+		UpdateOrganizationFunc: func(ctx context.Context, in *apppb.UpdateOrganizationRequest,
+			opts ...grpc.CallOption,
+		) (*apppb.UpdateOrganizationResponse, error) {
+			if in.OrganizationId != "jedi" {
+				return nil, status.Error(codes.PermissionDenied, "not an owner")
+			}
+			return &apppb.UpdateOrganizationResponse{Organization: &apppb.Organization{Id: in.OrganizationId, Name: in.GetName()}}, nil
+		},
+	}
+	_, ac, _, _ := setup(asc, nil, nil, nil, "token")
+
+	org, err := ac.renameOrganization("jedi", "jedi order")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, org.Name, test.ShouldEqual, "jedi order")
+
+	_, err = ac.renameOrganization("jedi", "")
+	test.That(t, err, test.ShouldNotBeNil)
+
+	_, err = ac.renameOrganization("sith", "empire")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldEqual, "you do not have permission to rename organization sith; only its owners can")
+}
+
+func TestOrganizationMembersListAction(t *testing.T) {
+	asc := &inject.AppServiceClient{
+		ListOrganizationMembersFunc: func(ctx context.Context, in *apppb.ListOrganizationMembersRequest,
+			opts ...grpc.CallOption,
+		) (*apppb.ListOrganizationMembersResponse, error) {
+			if in.OrganizationId != "jedi" {
+				return nil, status.Error(codes.PermissionDenied, "not an owner")
+			}
+			return &apppb.ListOrganizationMembersResponse{Members: []*apppb.OrganizationMember{
+				{UserId: "yoda", Emails: []string{"yoda@jedi.org"}},
+				{UserId: "luke", Emails: []string{"luke@jedi.org"}},
+				{UserId: "r2", Emails: []string{"r2@jedi.org"}},
+			}}, nil
+		},
+		ListAuthorizationsFunc: func(ctx context.Context, in *apppb.ListAuthorizationsRequest,
+			opts ...grpc.CallOption,
+		) (*apppb.ListAuthorizationsResponse, error) {
+			return &apppb.ListAuthorizationsResponse{Authorizations: []*apppb.Authorization{
+				{AuthorizationId: "organization_owner", ResourceType: "organization", ResourceId: "jedi", IdentityId: "yoda"},
+				{AuthorizationId: "organization_operator", ResourceType: "organization", ResourceId: "jedi", IdentityId: "luke"},
+				{AuthorizationId: "location_owner", ResourceType: "location", ResourceId: "dagobah", IdentityId: "r2"},
+			}}, nil
+		},
+	}
+	cCtx, ac, out, _ := setup(asc, nil, nil, &map[string]string{generalFlagOrgID: "jedi"}, "token")
+	test.That(t, ac.organizationMembersListAction(cCtx), test.ShouldBeNil)
+	test.That(t, out.messages, test.ShouldResemble, []string{
+		"Members of organization jedi:\n",
+		"EMAIL          ROLE\n",
+		"yoda@jedi.org  owner\n",
+		"luke@jedi.org  operator\n",
+		"r2@jedi.org    no organization role\n",
+	})
+
+	cCtx, ac, out, _ = setup(asc, nil, nil, &map[string]string{generalFlagOrgID: "jedi", outputFlag: outputFormatJSON}, "token")
+	test.That(t, ac.organizationMembersListAction(cCtx), test.ShouldBeNil)
+	var members []organizationMemberOutput
+	test.That(t, json.Unmarshal([]byte(out.messages[0]), &members), test.ShouldBeNil)
+	test.That(t, members, test.ShouldHaveLength, 3)
+	test.That(t, members[0].Email, test.ShouldEqual, "yoda@jedi.org")
+	test.That(t, members[0].Role, test.ShouldEqual, "owner")
+	test.That(t, members[2].Role, test.ShouldBeEmpty)
+
+	cCtx, ac, _, _ = setup(asc, nil, nil, &map[string]string{generalFlagOrgID: "sith"}, "token")
+	err := ac.organizationMembersListAction(cCtx)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "you do not have permission to list the members of organization sith")
+}
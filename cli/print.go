@@ -1,14 +1,35 @@
 package cli
 
 import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"os"
+	"reflect"
+	"strings"
+	"time"
 	"unicode"
 	"unicode/utf8"
 
 	"github.com/fatih/color"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/term"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	outputFormatText = "text"
+	outputFormatJSON = "json"
+)
+
+// formats accepted by the format flag. JSON is outputFormatJSON.
+const (
+	formatTable = "table"
+	formatCSV   = "csv"
 )
 
 const asciiViam = `
@@ -26,6 +47,160 @@ func printf(w io.Writer, format string, a ...interface{}) {
 	fmt.Fprintf(w, format+"\n", a...)
 }
 
+// printJSON prints v as indented JSON.
+func printJSON(w io.Writer, v interface{}) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "could not marshal output to JSON")
+	}
+	printf(w, "%s", b)
+	return nil
+}
+
+// outputFormat returns the format requested with the output flag, which may be passed either
+// globally or to the command itself. It defaults to text.
+func outputFormat(c *cli.Context) (string, error) {
+	format := outputFormatText
+	for _, ctx := range c.Lineage() {
+		if f := ctx.String(outputFlag); f != "" {
+			format = f
+			break
+		}
+	}
+	switch format {
+	case outputFormatText, outputFormatJSON:
+		return format, nil
+	default:
+		return "", errors.Errorf("%s must be %s or %s, got %q", outputFlag, outputFormatText, outputFormatJSON, format)
+	}
+}
+
+// recordFormat returns the format requested with the format flag. If it is not passed, the output flag is
+// honoured, so that text is printed as a table and json as JSON.
+func recordFormat(c *cli.Context) (string, error) {
+	format := c.String(formatFlag)
+	switch format {
+	case "":
+		output, err := outputFormat(c)
+		if err != nil {
+			return "", err
+		}
+		if output == outputFormatJSON {
+			return outputFormatJSON, nil
+		}
+		return formatTable, nil
+	case formatTable, outputFormatJSON, formatCSV:
+		return format, nil
+	default:
+		return "", errors.Errorf("%s must be %s, %s or %s, got %q", formatFlag, formatTable, outputFormatJSON, formatCSV, format)
+	}
+}
+
+// printRecords prints the output of a command that supports the format flag. value is printed as JSON, rows,
+// a slice of structs with only scalar or time fields, is printed as CSV with a header row named after the
+// JSON names of the fields, and printTable prints the human readable table format.
+func printRecords(w io.Writer, format string, value, rows interface{}, printTable func()) error {
+	switch format {
+	case outputFormatJSON:
+		return printJSON(w, value)
+	case formatCSV:
+		return printCSV(w, rows)
+	default:
+		printTable()
+		return nil
+	}
+}
+
+// printCSV prints rows, a slice of structs, as CSV. The header row holds the JSON names of the fields in the
+// order they are declared, so it does not change with the rows being printed.
+func printCSV(w io.Writer, rows interface{}) error {
+	rowsV := reflect.ValueOf(rows)
+	if rowsV.Kind() != reflect.Slice || rowsV.Type().Elem().Kind() != reflect.Struct {
+		return errors.Errorf("cannot print %T as CSV", rows)
+	}
+	rowType := rowsV.Type().Elem()
+	header := make([]string, 0, rowType.NumField())
+	for i := 0; i < rowType.NumField(); i++ {
+		name, _, _ := strings.Cut(rowType.Field(i).Tag.Get("json"), ",")
+		if name == "" {
+			name = rowType.Field(i).Name
+		}
+		header = append(header, name)
+	}
+
+	csvW := csv.NewWriter(w)
+	if err := csvW.Write(header); err != nil {
+		return errors.Wrap(err, "could not write CSV output")
+	}
+	for i := 0; i < rowsV.Len(); i++ {
+		row := rowsV.Index(i)
+		record := make([]string, 0, len(header))
+		for j := 0; j < row.NumField(); j++ {
+			record = append(record, csvField(row.Field(j)))
+		}
+		if err := csvW.Write(record); err != nil {
+			return errors.Wrap(err, "could not write CSV output")
+		}
+	}
+	csvW.Flush()
+	return errors.Wrap(csvW.Error(), "could not write CSV output")
+}
+
+// csvField formats a single struct field for CSV output. Unset pointers are empty and times use RFC 3339.
+func csvField(v reflect.Value) string {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if t, ok := v.Interface().(time.Time); ok {
+		return t.Format(time.RFC3339Nano)
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// confirmationInput is where confirmation prompts read their answer from, and stdinIsTerminal reports
+// whether it is an interactive terminal. stdoutIsTerminal reports whether output is written to one. They are
+// variables so that tests can replace them.
+var (
+	confirmationInput io.Reader = os.Stdin
+	stdinIsTerminal             = func() bool { return term.IsTerminal(int(os.Stdin.Fd())) }
+	stdoutIsTerminal            = func() bool { return term.IsTerminal(int(os.Stdout.Fd())) }
+)
+
+// confirmDestructiveAction describes what is about to be destroyed and returns nil only if the user
+// confirms by typing "yes", or passed the yes flag. When stdin is not a terminal there is nobody to ask,
+// so it refuses to continue unless the yes flag was passed.
+func confirmDestructiveAction(c *cli.Context, description string) error {
+	if c.Bool(generalFlagYes) {
+		return nil
+	}
+	if !stdinIsTerminal() {
+		return errors.Errorf("%s. Refusing to continue without confirmation because stdin is not a terminal; "+
+			"pass --%s to skip confirmation", description, generalFlagYes)
+	}
+	printf(c.App.Writer, "%s. This cannot be undone.", description)
+	fmt.Fprint(c.App.Writer, "Type \"yes\" to continue: ") // no newline
+	answer, err := bufio.NewReader(confirmationInput).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return errors.Wrap(err, "could not read confirmation")
+	}
+	if strings.TrimSpace(answer) != "yes" {
+		return errors.New("aborted")
+	}
+	return nil
+}
+
+// timestampOrNil converts ts to a time that is omitted from JSON output when ts is unset.
+func timestampOrNil(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
+
 // infof prints a message prefixed with a bold cyan "Info: ".
 func infof(w io.Writer, format string, a ...interface{}) {
 	// NOTE(benjirewis): for some reason, both errcheck and gosec complain about
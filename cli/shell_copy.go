@@ -0,0 +1,487 @@
+package cli
+
+import (
+	"archive/tar"
+	"bufio"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+	"go.viam.com/utils"
+
+	"go.viam.com/rdk/services/shell"
+)
+
+const (
+	// shellCopyPartPrefix marks the argument of 'machines part cp' that is a path on the machine part.
+	shellCopyPartPrefix = "part:"
+	// shellCopyProgressMinSize is the size in bytes from which 'machines part cp' shows its progress.
+	shellCopyProgressMinSize = 1 << 20
+	shellCopyMarkerPrefix    = "VIAM_CP_"
+	// shellCopyChunkSize is how many bytes are sent to the shell at a time. It is a multiple of the 57 bytes
+	// encoded on each 76 character line of base64.
+	shellCopyChunkSize = 57 * 48
+)
+
+var errShellClosed = errors.New("shell closed unexpectedly")
+
+// shellSession runs commands in the interactive shell of a shell service. Files are transferred as base64
+// encoded tar archives since the shell is a terminal.
+type shellSession struct {
+	input  chan<- string
+	output *io.PipeReader
+	lines  *bufio.Reader
+	// marker starts the lines printed around the output of each command. It is split in two in the commands
+	// sent to the shell so that echoing them never prints it.
+	marker string
+}
+
+func newShellSession(ctx context.Context, svc shell.Service) (*shellSession, error) {
+	input, output, err := svc.Shell(ctx, map[string]interface{}{})
+	if err != nil {
+		return nil, err
+	}
+	pr, pw := io.Pipe()
+	utils.PanicCapturingGo(func() {
+		// keep draining output after the session is closed so the shell is never blocked.
+		for out := range output {
+			if out.EOF {
+				pw.CloseWithError(errShellClosed)
+				continue
+			}
+			//nolint:errcheck
+			pw.Write([]byte(out.Output + out.Error))
+		}
+		pw.CloseWithError(errShellClosed)
+	})
+
+	s := &shellSession{
+		input:  input,
+		output: pr,
+		lines:  bufio.NewReader(pr),
+		marker: shellCopyMarkerPrefix + utils.RandomAlphaString(8),
+	}
+	// stop the terminal from echoing the data sent to commands.
+	if status, err := s.run(ctx, "stty -echo", nil, io.Discard); err != nil {
+		utils.UncheckedError(s.Close())
+		return nil, err
+	} else if status != 0 {
+		utils.UncheckedError(s.Close())
+		return nil, errors.Errorf("failed to set up shell (exit status %d)", status)
+	}
+	return s, nil
+}
+
+// Close exits the shell.
+func (s *shellSession) Close() error {
+	close(s.input)
+	return s.output.Close()
+}
+
+func (s *shellSession) send(ctx context.Context, data string) error {
+	select {
+	case s.input <- data:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run runs command in the shell, copies its output to stdout and returns its exit status. If stdin is not nil,
+// it is sent base64 encoded to the command, followed by a line holding only s.marker+"D".
+func (s *shellSession) run(ctx context.Context, command string, stdin io.Reader, stdout io.Writer) (int, error) {
+	nonce := strings.TrimPrefix(s.marker, shellCopyMarkerPrefix)
+	begin, end := s.marker+"B", s.marker+"E "
+	if err := s.send(ctx, fmt.Sprintf(
+		"printf '%%s%%s\\n' %s %sB; %s; printf '%%s%%s %%s\\n' %s %sE \"$?\"\n",
+		shellCopyMarkerPrefix, nonce, command, shellCopyMarkerPrefix, nonce,
+	)); err != nil {
+		return 0, err
+	}
+
+	for {
+		line, err := s.lines.ReadString('\n')
+		if err != nil {
+			return 0, err
+		}
+		if strings.HasSuffix(strings.TrimRight(line, "\r\n"), begin) {
+			break
+		}
+	}
+
+	sendErr := make(chan error, 1)
+	if stdin != nil {
+		sendCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		utils.PanicCapturingGo(func() {
+			sendErr <- s.sendBase64(sendCtx, stdin)
+		})
+	} else {
+		sendErr <- nil
+	}
+
+	for {
+		line, err := s.lines.ReadString('\n')
+		if err != nil {
+			return 0, err
+		}
+		idx := strings.Index(line, end)
+		if idx == -1 {
+			if _, err := io.WriteString(stdout, line); err != nil {
+				return 0, err
+			}
+			continue
+		}
+		if _, err := io.WriteString(stdout, line[:idx]); err != nil {
+			return 0, err
+		}
+		status, err := strconv.Atoi(strings.TrimSpace(line[idx+len(end):]))
+		if err != nil {
+			return 0, errors.Wrap(err, "failed to read exit status of command")
+		}
+		return status, <-sendErr
+	}
+}
+
+// sendBase64 sends the contents of r to the shell as lines of base64.
+func (s *shellSession) sendBase64(ctx context.Context, r io.Reader) error {
+	buf := make([]byte, shellCopyChunkSize)
+	for {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			encoded := base64.StdEncoding.EncodeToString(buf[:n])
+			var chunk strings.Builder
+			for len(encoded) > 76 {
+				chunk.WriteString(encoded[:76] + "\n")
+				encoded = encoded[76:]
+			}
+			chunk.WriteString(encoded + "\n")
+			if err := s.send(ctx, chunk.String()); err != nil {
+				return err
+			}
+		}
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return s.send(ctx, s.marker+"D\n")
+		}
+		if err != nil {
+			// still end the data so the command does not wait for more.
+			utils.UncheckedError(s.send(ctx, s.marker+"D\n"))
+			return err
+		}
+	}
+}
+
+// shellPathInfo describes a path on the machine part.
+type shellPathInfo struct {
+	exists bool
+	isDir  bool
+	size   int64
+}
+
+func (s *shellSession) stat(ctx context.Context, p string) (shellPathInfo, error) {
+	var out strings.Builder
+	status, err := s.run(ctx, fmt.Sprintf(
+		"if [ -d %[1]s ]; then echo dir; elif [ -e %[1]s ]; then echo file $(wc -c < %[1]s 2>/dev/null); else echo missing; fi",
+		shellQuote(p),
+	), nil, &out)
+	if err != nil {
+		return shellPathInfo{}, err
+	}
+	if status != 0 {
+		return shellPathInfo{}, errors.Errorf("failed to inspect %s on machine part (exit status %d)", p, status)
+	}
+	fields := strings.Fields(out.String())
+	switch {
+	case len(fields) == 1 && fields[0] == "missing":
+		return shellPathInfo{}, nil
+	case len(fields) == 1 && fields[0] == "dir":
+		return shellPathInfo{exists: true, isDir: true}, nil
+	case len(fields) >= 1 && fields[0] == "file":
+		info := shellPathInfo{exists: true}
+		if len(fields) == 2 {
+			info.size, _ = strconv.ParseInt(fields[1], 10, 64)
+		}
+		return info, nil
+	default:
+		return shellPathInfo{}, errors.Errorf("failed to inspect %s on machine part: unexpected output %q", p, out.String())
+	}
+}
+
+// copyToPart copies the local file or directory src to dst on the machine part. If dst is a directory, src is
+// copied into it.
+func (s *shellSession) copyToPart(ctx context.Context, src, dst string, recursive bool, progress io.Writer) error {
+	srcInfo, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if srcInfo.IsDir() && !recursive {
+		return errors.Errorf("%s is a directory; use -r to copy directories", src)
+	}
+
+	dstInfo, err := s.stat(ctx, dst)
+	if err != nil {
+		return err
+	}
+	destDir, name := path.Dir(path.Clean(dst)), path.Base(path.Clean(dst))
+	if dstInfo.isDir {
+		destDir, name = path.Clean(dst), filepath.Base(src)
+	} else if dstInfo.exists && srcInfo.IsDir() {
+		return errors.Errorf("cannot overwrite non-directory %s on machine part with directory %s", dst, src)
+	}
+
+	var total int64
+	if err := filepath.WalkDir(src, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			total += info.Size()
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	counter := newCopyProgress(progress, total)
+	defer counter.done()
+
+	pr, pw := io.Pipe()
+	utils.PanicCapturingGo(func() {
+		pw.CloseWithError(writeShellCopyArchive(pw, src, name, counter))
+	})
+	defer utils.UncheckedErrorFunc(pr.Close)
+
+	// the data is read completely before extracting so that a failing tar never leaves the rest of it to be
+	// run by the shell.
+	status, err := s.run(ctx, fmt.Sprintf(
+		"sed -n '/^%sD$/q;p' | { base64 -d | tar xf - -C %s; s=$?; cat > /dev/null; exit $s; }",
+		s.marker, shellQuote(destDir),
+	), pr, io.Discard)
+	if err != nil {
+		return err
+	}
+	if status != 0 {
+		return shellCopyStatusError(status)
+	}
+	return nil
+}
+
+// copyFromPart copies the file or directory src on the machine part to the local path dst. If dst is a
+// directory, src is copied into it.
+func (s *shellSession) copyFromPart(ctx context.Context, src, dst string, recursive bool, progress io.Writer) error {
+	srcInfo, err := s.stat(ctx, src)
+	if err != nil {
+		return err
+	}
+	if !srcInfo.exists {
+		return errors.Errorf("%s does not exist on machine part", src)
+	}
+	if srcInfo.isDir && !recursive {
+		return errors.Errorf("%s is a directory; use -r to copy directories", src)
+	}
+
+	srcDir, root := path.Dir(path.Clean(src)), path.Base(path.Clean(src))
+	if root == "/" {
+		srcDir, root = "/", "."
+	}
+	target := dst
+	if info, err := os.Stat(dst); err == nil && info.IsDir() {
+		target = filepath.Join(dst, filepath.FromSlash(path.Base(path.Clean(src))))
+	}
+
+	counter := newCopyProgress(progress, srcInfo.size)
+	defer counter.done()
+
+	pr, pw := io.Pipe()
+	extractErr := make(chan error, 1)
+	utils.PanicCapturingGo(func() {
+		err := extractShellCopyArchive(base64.NewDecoder(base64.StdEncoding, pr), root, target, counter)
+		if err != nil {
+			pr.CloseWithError(err)
+		} else {
+			// drain any padding after the end of the archive.
+			_, err = io.Copy(io.Discard, pr)
+		}
+		extractErr <- err
+	})
+
+	status, err := s.run(ctx, fmt.Sprintf(
+		"tar cf - -C %s %s 2>/dev/null | base64",
+		shellQuote(srcDir), shellQuote(root),
+	), nil, pw)
+	pw.CloseWithError(err)
+	if err := <-extractErr; err != nil {
+		return errors.Wrap(err, "failed to extract copied files")
+	}
+	if err != nil {
+		return err
+	}
+	if status != 0 {
+		return shellCopyStatusError(status)
+	}
+	return nil
+}
+
+func shellCopyStatusError(status int) error {
+	if status == 127 {
+		return errors.New("failed to copy: the machine part needs tar, base64 and sed installed")
+	}
+	return errors.Errorf("failed to copy (exit status %d)", status)
+}
+
+// writeShellCopyArchive writes the file or directory src to w as a tar archive, naming it name.
+func writeShellCopyArchive(w io.Writer, src, name string, progress io.Writer) error {
+	tw := tar.NewWriter(w)
+	err := filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && !d.Type().IsRegular() {
+			// symlinks and special files are not copied.
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		header.Name = path.Join(name, filepath.ToSlash(rel))
+		if d.IsDir() {
+			header.Name += "/"
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		//nolint:gosec
+		file, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer utils.UncheckedErrorFunc(file.Close)
+		_, err = io.Copy(tw, io.TeeReader(file, progress))
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// extractShellCopyArchive extracts the entry named root of the tar archive read from r, and everything
+// under it, to target. Other entries, and anything but regular files and directories, are skipped.
+func extractShellCopyArchive(r io.Reader, root, target string, progress io.Writer) error {
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		rel, ok := shellCopyArchivePath(header.Name, root)
+		if !ok {
+			continue
+		}
+		p := filepath.Join(target, filepath.FromSlash(rel))
+		mode := fs.FileMode(header.Mode).Perm()
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(p, mode|0o700); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(p), 0o750); err != nil {
+				return err
+			}
+			//nolint:gosec
+			file, err := os.OpenFile(p, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+			if err != nil {
+				return err
+			}
+			//nolint:gosec
+			_, err = io.Copy(io.MultiWriter(file, progress), tr)
+			if err := multierr.Combine(err, file.Close()); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// shellCopyArchivePath returns the path of the archive entry name relative to root, if it is root or under it.
+func shellCopyArchivePath(name, root string) (string, bool) {
+	name = path.Clean(name)
+	var rel string
+	switch {
+	case root == ".":
+		rel = name
+	case name == root:
+		rel = "."
+	case strings.HasPrefix(name, root+"/"):
+		rel = strings.TrimPrefix(name, root+"/")
+	default:
+		return "", false
+	}
+	if path.IsAbs(rel) || rel == ".." || strings.HasPrefix(rel, "../") {
+		return "", false
+	}
+	return rel, true
+}
+
+// copyProgress counts the bytes written to it and, if the total is large enough, prints the progress to w.
+type copyProgress struct {
+	w      io.Writer
+	total  int64
+	copied int64
+}
+
+func newCopyProgress(w io.Writer, total int64) *copyProgress {
+	if total < shellCopyProgressMinSize {
+		w = nil
+	}
+	return &copyProgress{w: w, total: total}
+}
+
+func (p *copyProgress) Write(b []byte) (int, error) {
+	p.copied += int64(len(b))
+	if p.w != nil {
+		percent := int(math.Min(100, math.Ceil(100*float64(p.copied)/float64(p.total))))
+		fmt.Fprintf(p.w, "\rCopying... %d%% (%d/%d bytes)", percent, p.copied, p.total) // no newline
+	}
+	return len(b), nil
+}
+
+// done closes the line with the progress reading.
+func (p *copyProgress) done() {
+	if p.w != nil && p.copied > 0 {
+		printf(p.w, "")
+	}
+}
+
+// shellQuote quotes s as a single word for a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
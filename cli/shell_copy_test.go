@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/services/shell"
+	"go.viam.com/rdk/services/shell/builtin"
+)
+
+func TestShellSessionCopy(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell service is not supported on windows")
+	}
+	t.Setenv("SHELL", "/bin/sh")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	svc, err := builtin.NewBuiltIn(shell.Named("shell"), logging.NewTestLogger(t))
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		cancel()
+		test.That(t, svc.Close(context.Background()), test.ShouldBeNil)
+	}()
+	session, err := newShellSession(ctx, svc)
+	test.That(t, err, test.ShouldBeNil)
+	defer session.Close()
+
+	// the "part" is this machine, so both sides of the copy are temporary directories.
+	local, part := t.TempDir(), t.TempDir()
+	large := bytes.Repeat([]byte("0123456789abcdef"), shellCopyProgressMinSize/16+100)
+	test.That(t, os.MkdirAll(filepath.Join(local, "src", "nested"), 0o750), test.ShouldBeNil)
+	test.That(t, os.WriteFile(filepath.Join(local, "src", "small.txt"), []byte("it's small\n"), 0o600), test.ShouldBeNil)
+	test.That(t, os.WriteFile(filepath.Join(local, "src", "nested", "large.bin"), large, 0o600), test.ShouldBeNil)
+
+	t.Run("file to part", func(t *testing.T) {
+		var progress bytes.Buffer
+		err := session.copyToPart(ctx, filepath.Join(local, "src", "small.txt"), filepath.Join(part, "renamed.txt"), false, &progress)
+		test.That(t, err, test.ShouldBeNil)
+		content, err := os.ReadFile(filepath.Join(part, "renamed.txt"))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, string(content), test.ShouldEqual, "it's small\n")
+		// small files do not show progress.
+		test.That(t, progress.String(), test.ShouldBeEmpty)
+	})
+
+	t.Run("directory to part", func(t *testing.T) {
+		err := session.copyToPart(ctx, filepath.Join(local, "src"), part, false, nil)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "use -r")
+
+		var progress bytes.Buffer
+		test.That(t, session.copyToPart(ctx, filepath.Join(local, "src"), part, true, &progress), test.ShouldBeNil)
+		content, err := os.ReadFile(filepath.Join(part, "src", "nested", "large.bin"))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, content, test.ShouldResemble, large)
+		test.That(t, progress.String(), test.ShouldContainSubstring, "Copying... 100%")
+	})
+
+	t.Run("file from part", func(t *testing.T) {
+		var progress bytes.Buffer
+		dst := filepath.Join(local, "large.bin")
+		test.That(t, session.copyFromPart(ctx, filepath.Join(part, "src", "nested", "large.bin"), dst, false, &progress), test.ShouldBeNil)
+		content, err := os.ReadFile(dst)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, content, test.ShouldResemble, large)
+		test.That(t, progress.String(), test.ShouldContainSubstring, "Copying... 100%")
+	})
+
+	t.Run("directory from part", func(t *testing.T) {
+		dst := t.TempDir()
+		err := session.copyFromPart(ctx, filepath.Join(part, "src"), dst, false, nil)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "use -r")
+
+		test.That(t, session.copyFromPart(ctx, filepath.Join(part, "src"), dst, true, nil), test.ShouldBeNil)
+		content, err := os.ReadFile(filepath.Join(dst, "src", "small.txt"))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, string(content), test.ShouldEqual, "it's small\n")
+		content, err = os.ReadFile(filepath.Join(dst, "src", "nested", "large.bin"))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, content, test.ShouldResemble, large)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		err := session.copyFromPart(ctx, filepath.Join(part, "missing"), local, false, nil)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "does not exist")
+	})
+}
+
+func TestShellCopyArchivePath(t *testing.T) {
+	for _, tc := range []struct {
+		name, root, rel string
+		ok              bool
+	}{
+		{"src", "src", ".", true},
+		{"src/", "src", ".", true},
+		{"src/a/b.txt", "src", "a/b.txt", true},
+		{"srcs/a.txt", "src", "", false},
+		{"other", "src", "", false},
+		{"./a.txt", ".", "a.txt", true},
+		{"../a.txt", ".", "", false},
+		{"/etc/passwd", ".", "", false},
+	} {
+		rel, ok := shellCopyArchivePath(tc.name, tc.root)
+		test.That(t, ok, test.ShouldEqual, tc.ok)
+		test.That(t, rel, test.ShouldEqual, tc.rel)
+	}
+}
@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/term"
+)
+
+const (
+	// tableColumnGap separates the columns of a table.
+	tableColumnGap = "  "
+	// tableMinColumnWidth is the narrowest a column is truncated to when a table does not fit the terminal.
+	tableMinColumnWidth = 8
+)
+
+// table is the human readable output of list commands: a header row followed by rows of cells, rendered as
+// aligned columns.
+type table struct {
+	header []string
+	rows   [][]string
+	// whole holds the indices of columns that are never truncated, such as IDs that are meant to be copied.
+	whole map[int]bool
+}
+
+// newTable returns a table with the given column headers.
+func newTable(header ...string) *table {
+	return &table{header: header, whole: map[int]bool{}}
+}
+
+// keepWhole marks the columns with the given headers as never truncated and returns t.
+func (t *table) keepWhole(headers ...string) *table {
+	for _, h := range headers {
+		for i, header := range t.header {
+			if header == h {
+				t.whole[i] = true
+			}
+		}
+	}
+	return t
+}
+
+// addRow adds a row with a cell for each column. Missing cells are empty.
+func (t *table) addRow(cells ...string) {
+	row := make([]string, len(t.header))
+	copy(row, cells)
+	t.rows = append(t.rows, row)
+}
+
+// tableOptions control how a table is rendered.
+type tableOptions struct {
+	// width is the width the table is truncated to fit, or 0 to never truncate it.
+	width int
+	// color makes the header bold.
+	color bool
+	// quiet leaves out the header, and tells list commands to leave out titles and totals, so only rows are
+	// printed.
+	quiet bool
+}
+
+// tableOptionsFor returns the options for tables printed by the command of c. Tables are only fit to the
+// terminal width and colorized when output is to a terminal, and are never colorized when NO_COLOR is set.
+func tableOptionsFor(c *cli.Context) tableOptions {
+	opts := tableOptions{quiet: c.Bool(quietFlag)}
+	if !stdoutIsTerminal() {
+		return opts
+	}
+	if width, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil {
+		opts.width = width
+	}
+	_, noColor := os.LookupEnv("NO_COLOR")
+	opts.color = !noColor
+	return opts
+}
+
+// render prints t to w.
+func (t *table) render(w io.Writer, opts tableOptions) {
+	widths := t.columnWidths(opts.width)
+	if !opts.quiet {
+		header := color.New(color.Bold)
+		if opts.color {
+			header.EnableColor()
+		} else {
+			header.DisableColor()
+		}
+		t.renderRow(w, t.header, widths, header)
+	}
+	for _, row := range t.rows {
+		t.renderRow(w, row, widths, nil)
+	}
+}
+
+func (t *table) renderRow(w io.Writer, row []string, widths []int, style *color.Color) {
+	var line strings.Builder
+	for i, cell := range row {
+		cell = truncateCell(cell, widths[i])
+		if style != nil {
+			line.WriteString(style.Sprint(cell))
+		} else {
+			line.WriteString(cell)
+		}
+		if i < len(row)-1 {
+			line.WriteString(strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)))
+			line.WriteString(tableColumnGap)
+		}
+	}
+	// rows with empty trailing cells would otherwise end in padding.
+	printf(w, "%s", strings.TrimRight(line.String(), " "))
+}
+
+// columnWidths returns the width of each column: the width of its widest cell, with the widest columns that
+// may be truncated narrowed until the table fits in maxWidth, if maxWidth is positive.
+func (t *table) columnWidths(maxWidth int) []int {
+	widths := make([]int, len(t.header))
+	for i, header := range t.header {
+		widths[i] = utf8.RuneCountInString(header)
+	}
+	for _, row := range t.rows {
+		for i, cell := range row {
+			if n := utf8.RuneCountInString(cell); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+	if maxWidth <= 0 {
+		return widths
+	}
+	total := len(tableColumnGap) * (len(widths) - 1)
+	for _, width := range widths {
+		total += width
+	}
+	for ; total > maxWidth; total-- {
+		widest := -1
+		for i, width := range widths {
+			if !t.whole[i] && width > tableMinColumnWidth && (widest < 0 || width > widths[widest]) {
+				widest = i
+			}
+		}
+		if widest < 0 {
+			break
+		}
+		widths[widest]--
+	}
+	return widths
+}
+
+// truncateCell shortens cell to width, ending it with an ellipsis if it was cut.
+func truncateCell(cell string, width int) string {
+	if utf8.RuneCountInString(cell) <= width {
+		return cell
+	}
+	runes := []rune(cell)
+	return string(runes[:width-1]) + "…"
+}
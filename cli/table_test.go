@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"go.viam.com/test"
+)
+
+func TestTable(t *testing.T) {
+	tbl := newTable("NAME", "ID", "NOTE").keepWhole("ID")
+	tbl.addRow("a rather long machine name", "0123456789abcdef", "ok")
+	tbl.addRow("short", "42")
+
+	out := &testWriter{}
+	tbl.render(out, tableOptions{})
+	test.That(t, out.messages, test.ShouldResemble, []string{
+		"NAME                        ID                NOTE\n",
+		"a rather long machine name  0123456789abcdef  ok\n",
+		"short                       42\n",
+	})
+
+	// only columns that may be truncated are narrowed to fit the width.
+	out = &testWriter{}
+	tbl.render(out, tableOptions{width: 40})
+	test.That(t, out.messages, test.ShouldResemble, []string{
+		"NAME              ID                NOTE\n",
+		"a rather long m…  0123456789abcdef  ok\n",
+		"short             42\n",
+	})
+	for _, line := range out.messages {
+		test.That(t, len([]rune(strings.TrimSuffix(line, "\n"))), test.ShouldBeLessThanOrEqualTo, 40)
+	}
+
+	out = &testWriter{}
+	tbl.render(out, tableOptions{quiet: true})
+	test.That(t, out.messages, test.ShouldHaveLength, 2)
+	test.That(t, out.messages[0], test.ShouldStartWith, "a rather long machine name")
+
+	test.That(t, truncateCell("abcdef", 4), test.ShouldEqual, "abc…")
+	test.That(t, truncateCell("abc", 4), test.ShouldEqual, "abc")
+}
@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+	"google.golang.org/grpc"
+)
+
+// perRequestTimeoutCommands are the long-running commands, marked with perRequestTimeout where they are
+// defined, that apply --timeout to each of their unary requests rather than to the command as a whole.
+var perRequestTimeoutCommands = map[*cli.Command]bool{}
+
+// perRequestTimeout marks cmd as applying --timeout to each of its unary requests and returns it.
+func perRequestTimeout(cmd *cli.Command) *cli.Command {
+	perRequestTimeoutCommands[cmd] = true
+	return cmd
+}
+
+// wrapActionsWithTimeout wraps the action of every command in cmds, and of their subcommands, so that it is
+// aborted once --timeout elapses unless the command is marked with perRequestTimeout. It returns cmds.
+func wrapActionsWithTimeout(cmds []*cli.Command) []*cli.Command {
+	for _, cmd := range cmds {
+		if cmd.Action != nil && !perRequestTimeoutCommands[cmd] {
+			cmd.Action = withTimeout(cmd.Action)
+		}
+		wrapActionsWithTimeout(cmd.Subcommands)
+	}
+	return cmds
+}
+
+// withTimeout returns an action that runs action with a context that is cancelled once --timeout elapses.
+func withTimeout(action cli.ActionFunc) cli.ActionFunc {
+	return func(c *cli.Context) error {
+		timeout := c.Duration(timeoutFlag)
+		if timeout <= 0 {
+			return action(c)
+		}
+		ctx, cancel := context.WithTimeout(c.Context, timeout)
+		defer cancel()
+		c.Context = ctx
+		err := action(c)
+		if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return errors.Wrapf(err, "command timed out after %s", timeout)
+		}
+		return err
+	}
+}
+
+// perRequestTimeoutInterceptor returns a grpc.UnaryClientInterceptor that aborts each request once timeout elapses.
+func perRequestTimeoutInterceptor(timeout time.Duration) grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply interface{},
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
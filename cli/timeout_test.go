@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"testing"
+	"time"
+
+	"github.com/urfave/cli/v2"
+	"go.viam.com/test"
+	"google.golang.org/grpc"
+)
+
+func TestWrapActionsWithTimeout(t *testing.T) {
+	var hadDeadline map[string]bool
+	action := func(name string) cli.ActionFunc {
+		return func(c *cli.Context) error {
+			_, ok := c.Context.Deadline()
+			hadDeadline[name] = ok
+			return nil
+		}
+	}
+	cmds := []*cli.Command{
+		{
+			Name: "data",
+			Subcommands: []*cli.Command{
+				perRequestTimeout(&cli.Command{Name: "export", Action: action("export")}),
+				{Name: "delete", Action: action("delete")},
+			},
+		},
+	}
+	wrapActionsWithTimeout(cmds)
+
+	for _, timeout := range []string{"0s", "1m"} {
+		hadDeadline = map[string]bool{}
+		flags := &flag.FlagSet{}
+		flags.Duration(timeoutFlag, 0, "")
+		test.That(t, flags.Set(timeoutFlag, timeout), test.ShouldBeNil)
+		cCtx := cli.NewContext(&cli.App{}, flags, nil)
+		for _, cmd := range cmds[0].Subcommands {
+			test.That(t, cmd.Action(cCtx), test.ShouldBeNil)
+		}
+		test.That(t, hadDeadline["export"], test.ShouldBeFalse)
+		test.That(t, hadDeadline["delete"], test.ShouldEqual, timeout != "0s")
+	}
+}
+
+func TestPerRequestTimeoutCommands(t *testing.T) {
+	var marked []string
+	var walk func(cmds []*cli.Command, path string)
+	walk = func(cmds []*cli.Command, path string) {
+		for _, cmd := range cmds {
+			if perRequestTimeoutCommands[cmd] {
+				marked = append(marked, path+cmd.Name)
+			}
+			walk(cmd.Subcommands, path+cmd.Name+" ")
+		}
+	}
+	walk(NewApp(nil, nil).Commands, "")
+	test.That(t, marked, test.ShouldResemble, []string{
+		"data export",
+		"dataset export",
+		"machines logs",
+		"machines part logs",
+		"machines part run",
+		"machines part shell",
+		"machines part cp",
+		"module build logs",
+	})
+}
+
+func TestWithTimeout(t *testing.T) {
+	flags := &flag.FlagSet{}
+	flags.Duration(timeoutFlag, 10*time.Millisecond, "")
+	cCtx := cli.NewContext(&cli.App{}, flags, nil)
+
+	err := withTimeout(func(c *cli.Context) error {
+		<-c.Context.Done()
+		return c.Context.Err()
+	})(cCtx)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "command timed out after 10ms")
+
+	interceptor := perRequestTimeoutInterceptor(10 * time.Millisecond)
+	err = interceptor(context.Background(), "method", nil, nil, nil,
+		func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+	test.That(t, errors.Is(err, context.DeadlineExceeded), test.ShouldBeTrue)
+}
@@ -2,13 +2,16 @@ package cli
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"time"
 
 	"github.com/pkg/errors"
 	"github.com/urfave/cli/v2"
+	datapb "go.viam.com/api/app/data/v1"
 	mltrainingpb "go.viam.com/api/app/mltraining/v1"
 	"golang.org/x/exp/slices"
+	"google.golang.org/protobuf/proto"
 )
 
 const (
@@ -19,27 +22,101 @@ const (
 	trainFlagModelVersion = "model-version"
 	trainFlagModelType    = "model-type"
 	trainFlagModelLabels  = "model-labels"
+	trainFlagWait         = "wait"
 
 	trainingStatusPrefix = "TRAINING_STATUS_"
 )
 
+var (
+	// trainingJobPollingInterval is how long 'train wait' waits before first checking the status of a training
+	// job again. The wait doubles after each check that finds the status unchanged, up to
+	// trainingJobMaxPollingInterval.
+	trainingJobPollingInterval    = 5 * time.Second
+	trainingJobMaxPollingInterval = time.Minute
+)
+
 // DataSubmitTrainingJob is the corresponding action for 'data train submit'.
 func DataSubmitTrainingJob(c *cli.Context) error {
 	client, err := newViamClient(c)
 	if err != nil {
 		return err
 	}
+	filter, err := parseDataFilter(c)
+	if err != nil {
+		return err
+	}
+	datasetID := c.String(datasetFlagDatasetID)
+	fromFilter := !proto.Equal(filter, &datapb.Filter{})
+	switch {
+	case datasetID != "" && fromFilter:
+		return errors.Errorf("pass either --%s or data filter flags, not both", datasetFlagDatasetID)
+	case datasetID == "" && !fromFilter:
+		return errors.Errorf("pass --%s, or data filter flags to train on the data matching them", datasetFlagDatasetID)
+	case fromFilter:
+		datasetID, err = client.createTrainingDataset(filter, c.String(trainFlagModelOrgID), c.String(trainFlagModelName))
+		if err != nil {
+			return err
+		}
+	}
+
 	trainingJobID, err := client.dataSubmitTrainingJob(
-		c.String(datasetFlagDatasetID), c.String(trainFlagModelOrgID),
+		datasetID, c.String(trainFlagModelOrgID),
 		c.String(trainFlagModelName), c.String(trainFlagModelVersion),
 		c.String(trainFlagModelType), c.StringSlice(trainFlagModelLabels))
 	if err != nil {
+		if fromFilter {
+			client.deleteTrainingDataset(datasetID)
+		}
 		return err
 	}
 	printf(c.App.Writer, "Submitted training job with ID %s", trainingJobID)
+	if fromFilter {
+		printf(c.App.Writer, "The job trains on dataset ID %s, which was created for it. Once the job has finished, "+
+			"delete the dataset with 'viam dataset delete --%s=%s'", datasetID, datasetFlagDatasetID, datasetID)
+	}
+	if c.Bool(trainFlagWait) {
+		return client.waitForTrainingJob(trainingJobID)
+	}
 	return nil
 }
 
+// createTrainingDataset creates a dataset in the org with the given ID holding the binary data matching filter,
+// for a training job submitted with data filter flags instead of a dataset ID. The dataset is deleted again if
+// it cannot be filled with every matching file.
+func (c *viamClient) createTrainingDataset(filter *datapb.Filter, orgID, modelName string) (string, error) {
+	if err := c.ensureLoggedIn(); err != nil {
+		return "", err
+	}
+	resp, err := c.dataClient.BinaryDataByFilter(c.c.Context, &datapb.BinaryDataByFilterRequest{
+		DataRequest: &datapb.DataRequest{Filter: filter},
+		CountOnly:   true,
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "received error from server")
+	}
+	if resp.GetCount() == 0 {
+		return "", errors.New("no data matches the filters, so there is nothing to train on")
+	}
+
+	datasetID, err := c.createDataset(orgID, fmt.Sprintf("%s-training-%s", modelName, time.Now().Format("2006-01-02T15-04-05")))
+	if err != nil {
+		return "", err
+	}
+	if err := c.dataAddToDatasetByFilter(filter, datasetID, defaultDataRetries, false); err != nil {
+		c.deleteTrainingDataset(datasetID)
+		return "", errors.Wrap(err, "could not add the matching data to a dataset to train on")
+	}
+	return datasetID, nil
+}
+
+// deleteTrainingDataset deletes a dataset created by createTrainingDataset that no training job uses, warning
+// if it cannot be deleted so that it can be cleaned up by hand.
+func (c *viamClient) deleteTrainingDataset(datasetID string) {
+	if err := c.deleteDataset(datasetID); err != nil {
+		warningf(c.c.App.ErrWriter, "could not delete dataset ID %s created for the training job: %v", datasetID, err)
+	}
+}
+
 // dataSubmitTrainingJob trains on data with the specified filter.
 func (c *viamClient) dataSubmitTrainingJob(datasetID, orgID, modelName, modelVersion, modelType string,
 	labels []string,
@@ -87,13 +164,69 @@ func (c *viamClient) dataGetTrainingJob(trainingJobID string) (*mltrainingpb.Tra
 	if err := c.ensureLoggedIn(); err != nil {
 		return nil, err
 	}
-	resp, err := c.mlTrainingClient.GetTrainingJob(context.Background(), &mltrainingpb.GetTrainingJobRequest{Id: trainingJobID})
+	resp, err := c.mlTrainingClient.GetTrainingJob(c.c.Context, &mltrainingpb.GetTrainingJobRequest{Id: trainingJobID})
 	if err != nil {
 		return nil, err
 	}
 	return resp.Metadata, nil
 }
 
+// DataWaitForTrainingJob is the corresponding action for 'train wait'.
+func DataWaitForTrainingJob(c *cli.Context) error {
+	client, err := newViamClient(c)
+	if err != nil {
+		return err
+	}
+	return client.waitForTrainingJob(c.String(trainFlagJobID))
+}
+
+// waitForTrainingJob polls the training job with the given ID until it completes, fails or is canceled,
+// printing each status it moves to. It returns an error unless the job completed, or once --timeout elapses.
+func (c *viamClient) waitForTrainingJob(trainingJobID string) error {
+	var lastStatus mltrainingpb.TrainingStatus
+	interval := trainingJobPollingInterval
+	for {
+		job, err := c.dataGetTrainingJob(trainingJobID)
+		if err != nil {
+			return errors.Wrapf(err, "could not get status of training job %s", trainingJobID)
+		}
+		status := job.GetStatus()
+		if status != lastStatus {
+			printf(c.c.App.Writer, "%s training job %s is %s", time.Now().Format("15:04:05"), trainingJobID,
+				formatTrainingStatus(status))
+			lastStatus = status
+			interval = trainingJobPollingInterval
+		} else if interval *= 2; interval > trainingJobMaxPollingInterval {
+			interval = trainingJobMaxPollingInterval
+		}
+
+		switch status {
+		case mltrainingpb.TrainingStatus_TRAINING_STATUS_COMPLETED:
+			return nil
+		case mltrainingpb.TrainingStatus_TRAINING_STATUS_FAILED:
+			if msg := job.GetErrorStatus().GetMessage(); msg != "" {
+				return errors.Errorf("training job %s failed: %s", trainingJobID, msg)
+			}
+			return errors.Errorf("training job %s failed", trainingJobID)
+		case mltrainingpb.TrainingStatus_TRAINING_STATUS_CANCELED:
+			return errors.Errorf("training job %s was canceled", trainingJobID)
+		default:
+		}
+
+		select {
+		case <-c.c.Context.Done():
+			return errors.Wrapf(c.c.Context.Err(), "stopped waiting for training job %s, which is %s",
+				trainingJobID, formatTrainingStatus(status))
+		case <-time.After(interval):
+		}
+	}
+}
+
+// formatTrainingStatus returns status the way it is passed to --job-status, e.g. in_progress.
+func formatTrainingStatus(status mltrainingpb.TrainingStatus) string {
+	return strings.ToLower(strings.TrimPrefix(status.String(), trainingStatusPrefix))
+}
+
 // DataCancelTrainingJob is the corresponding action for 'data train cancel'.
 func DataCancelTrainingJob(c *cli.Context) error {
 	client, err := newViamClient(c)
@@ -122,11 +255,15 @@ func (c *viamClient) dataCancelTrainingJob(trainingJobID string) error {
 
 // DataListTrainingJobs is the corresponding action for 'data train list'.
 func DataListTrainingJobs(c *cli.Context) error {
+	orgID, err := requiredStringFlagOrConfigFile(c, generalFlagOrgID)
+	if err != nil {
+		return err
+	}
 	client, err := newViamClient(c)
 	if err != nil {
 		return err
 	}
-	jobs, err := client.dataListTrainingJobs(c.String(generalFlagOrgID), c.String(trainFlagJobStatus))
+	jobs, err := client.dataListTrainingJobs(orgID, c.String(trainFlagJobStatus))
 	if err != nil {
 		return err
 	}
@@ -164,9 +301,8 @@ func (c *viamClient) dataListTrainingJobs(orgID, status string) ([]*mltrainingpb
 // allTrainingStatusValues returns the accepted values for the trainFlagJobStatus flag.
 func allTrainingStatusValues() string {
 	var formattedStatuses []string
-	for status := range mltrainingpb.TrainingStatus_value {
-		formattedStatus := strings.ToLower(strings.TrimPrefix(status, trainingStatusPrefix))
-		formattedStatuses = append(formattedStatuses, formattedStatus)
+	for _, status := range mltrainingpb.TrainingStatus_value {
+		formattedStatuses = append(formattedStatuses, formatTrainingStatus(mltrainingpb.TrainingStatus(status)))
 	}
 
 	slices.Sort(formattedStatuses)
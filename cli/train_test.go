@@ -0,0 +1,160 @@
+package cli
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	datapb "go.viam.com/api/app/data/v1"
+	datasetpb "go.viam.com/api/app/dataset/v1"
+	mltrainingpb "go.viam.com/api/app/mltraining/v1"
+	"go.viam.com/test"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestCreateTrainingDataset(t *testing.T) {
+	var matches uint64
+	var addErr error
+	var added []string
+	dataClient := &inject.DataServiceClient{
+		BinaryDataByFilterFunc: func(ctx context.Context, in *datapb.BinaryDataByFilterRequest,
+			opts ...grpc.CallOption,
+		) (*datapb.BinaryDataByFilterResponse, error) {
+			if in.GetCountOnly() {
+				return &datapb.BinaryDataByFilterResponse{Count: matches}, nil
+			}
+			if in.GetDataRequest().GetLast() != "" {
+				return &datapb.BinaryDataByFilterResponse{}, nil
+			}
+			return &datapb.BinaryDataByFilterResponse{
+				Data: []*datapb.BinaryData{{Metadata: &datapb.BinaryMetadata{Id: "file-id"}}},
+				Last: "last",
+			}, nil
+		},
+		AddBinaryDataToDatasetByIDsFunc: func(ctx context.Context, in *datapb.AddBinaryDataToDatasetByIDsRequest,
+			opts ...grpc.CallOption,
+		) (*datapb.AddBinaryDataToDatasetByIDsResponse, error) {
+			if addErr != nil {
+				return nil, addErr
+			}
+			added = append(added, in.GetDatasetId())
+			return &datapb.AddBinaryDataToDatasetByIDsResponse{}, nil
+		},
+	}
+	var created *datasetpb.CreateDatasetRequest
+	var deleted []string
+	datasetClient := &inject.DatasetServiceClient{
+		CreateDatasetFunc: func(ctx context.Context, in *datasetpb.CreateDatasetRequest,
+			opts ...grpc.CallOption,
+		) (*datasetpb.CreateDatasetResponse, error) {
+			created = in
+			return &datasetpb.CreateDatasetResponse{Id: "dataset-id"}, nil
+		},
+		DeleteDatasetFunc: func(ctx context.Context, in *datasetpb.DeleteDatasetRequest,
+			opts ...grpc.CallOption,
+		) (*datasetpb.DeleteDatasetResponse, error) {
+			deleted = append(deleted, in.GetId())
+			return &datasetpb.DeleteDatasetResponse{}, nil
+		},
+	}
+	filter := &datapb.Filter{ComponentName: "cam"}
+
+	t.Run("no matching data", func(t *testing.T) {
+		matches, created = 0, nil
+		_, ac, _, _ := setup(&inject.AppServiceClient{}, dataClient, nil, nil, "token")
+		ac.datasetClient = datasetClient
+		_, err := ac.createTrainingDataset(filter, "org-id", "model")
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "no data matches")
+		test.That(t, created, test.ShouldBeNil)
+	})
+
+	t.Run("creates and fills a dataset", func(t *testing.T) {
+		matches, addErr, added = 1, nil, nil
+		_, ac, _, _ := setup(&inject.AppServiceClient{}, dataClient, nil, nil, "token")
+		ac.datasetClient = datasetClient
+		id, err := ac.createTrainingDataset(filter, "org-id", "model")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, id, test.ShouldEqual, "dataset-id")
+		test.That(t, created.GetOrganizationId(), test.ShouldEqual, "org-id")
+		test.That(t, created.GetName(), test.ShouldStartWith, "model-training-")
+		test.That(t, added, test.ShouldResemble, []string{"dataset-id"})
+	})
+
+	t.Run("deletes the dataset if it cannot be filled", func(t *testing.T) {
+		matches, addErr, deleted = 1, status.Error(codes.PermissionDenied, "nope"), nil
+		_, ac, _, _ := setup(&inject.AppServiceClient{}, dataClient, nil, nil, "token")
+		ac.datasetClient = datasetClient
+		_, err := ac.createTrainingDataset(filter, "org-id", "model")
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, strings.Contains(err.Error(), "could not add the matching data"), test.ShouldBeTrue)
+		test.That(t, deleted, test.ShouldResemble, []string{"dataset-id"})
+	})
+}
+
+func TestWaitForTrainingJob(t *testing.T) {
+	pollingInterval := trainingJobPollingInterval
+	trainingJobPollingInterval = time.Millisecond
+	t.Cleanup(func() { trainingJobPollingInterval = pollingInterval })
+
+	waitFor := func(t *testing.T, ctx context.Context, jobs ...*mltrainingpb.TrainingJobMetadata) ([]string, error) {
+		t.Helper()
+		cCtx, ac, out, _ := setup(&inject.AppServiceClient{}, nil, nil, nil, "token")
+		cCtx.Context = ctx
+		ac.mlTrainingClient = &inject.MLTrainingServiceClient{
+			GetTrainingJobFunc: func(ctx context.Context, in *mltrainingpb.GetTrainingJobRequest,
+				opts ...grpc.CallOption,
+			) (*mltrainingpb.GetTrainingJobResponse, error) {
+				test.That(t, in.GetId(), test.ShouldEqual, "job-id")
+				job := jobs[0]
+				if len(jobs) > 1 {
+					jobs = jobs[1:]
+				}
+				return &mltrainingpb.GetTrainingJobResponse{Metadata: job}, nil
+			},
+		}
+		err := ac.waitForTrainingJob("job-id")
+		return out.messages, err
+	}
+	pending := &mltrainingpb.TrainingJobMetadata{Status: mltrainingpb.TrainingStatus_TRAINING_STATUS_PENDING}
+	inProgress := &mltrainingpb.TrainingJobMetadata{Status: mltrainingpb.TrainingStatus_TRAINING_STATUS_IN_PROGRESS}
+
+	t.Run("completed", func(t *testing.T) {
+		messages, err := waitFor(t, context.Background(), pending, pending, inProgress, inProgress,
+			&mltrainingpb.TrainingJobMetadata{Status: mltrainingpb.TrainingStatus_TRAINING_STATUS_COMPLETED})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, messages, test.ShouldHaveLength, 3)
+		test.That(t, messages[0], test.ShouldEndWith, "training job job-id is pending\n")
+		test.That(t, messages[1], test.ShouldEndWith, "training job job-id is in_progress\n")
+		test.That(t, messages[2], test.ShouldEndWith, "training job job-id is completed\n")
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		_, err := waitFor(t, context.Background(), inProgress, &mltrainingpb.TrainingJobMetadata{
+			Status:      mltrainingpb.TrainingStatus_TRAINING_STATUS_FAILED,
+			ErrorStatus: status.New(codes.ResourceExhausted, "out of memory").Proto(),
+		})
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldEqual, "training job job-id failed: out of memory")
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		_, err := waitFor(t, context.Background(),
+			&mltrainingpb.TrainingJobMetadata{Status: mltrainingpb.TrainingStatus_TRAINING_STATUS_CANCELED})
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "was canceled")
+	})
+
+	t.Run("timed out", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		_, err := waitFor(t, ctx, inProgress)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "stopped waiting for training job job-id, which is in_progress")
+	})
+}
@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.viam.com/utils"
+)
+
+// viamIgnoreFilename is the name of the file in the root of a module directory listing the paths that
+// should be left out when the directory is packaged for upload.
+const viamIgnoreFilename = ".viamignore"
+
+// viamIgnorePattern is a single line of a .viamignore file. The syntax is the same as .gitignore:
+//   - blank lines and lines starting with # are skipped
+//   - a leading ! re-includes paths excluded by an earlier pattern
+//   - a trailing / only matches directories
+//   - a pattern containing a / other than a trailing one is relative to the module root, otherwise it
+//     matches at any depth
+//   - *, ? and [...] match within a path segment and ** matches any number of segments
+type viamIgnorePattern struct {
+	segments []string
+	negate   bool
+	dirOnly  bool
+}
+
+// viamIgnore is a parsed .viamignore file. Later patterns take precedence over earlier ones.
+type viamIgnore struct {
+	patterns []viamIgnorePattern
+}
+
+// loadViamIgnore parses the .viamignore file in dir. It returns nil if there is none.
+func loadViamIgnore(dir string) (*viamIgnore, error) {
+	//nolint:gosec
+	file, err := os.Open(filepath.Join(dir, viamIgnoreFilename))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer utils.UncheckedErrorFunc(file.Close)
+
+	var ignore viamIgnore
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if pattern, ok := parseViamIgnorePattern(scanner.Text()); ok {
+			ignore.patterns = append(ignore.patterns, pattern)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s", viamIgnoreFilename)
+	}
+	return &ignore, nil
+}
+
+func parseViamIgnorePattern(line string) (viamIgnorePattern, bool) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return viamIgnorePattern{}, false
+	}
+	var pattern viamIgnorePattern
+	if strings.HasPrefix(line, "!") {
+		pattern.negate = true
+		line = line[1:]
+	}
+	// \# and \! allow patterns for files whose names start with those characters.
+	if strings.HasPrefix(line, `\#`) || strings.HasPrefix(line, `\!`) {
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		pattern.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return viamIgnorePattern{}, false
+	}
+	anchored := strings.Contains(line, "/")
+	pattern.segments = strings.Split(strings.TrimPrefix(line, "/"), "/")
+	if !anchored {
+		pattern.segments = append([]string{"**"}, pattern.segments...)
+	}
+	return pattern, true
+}
+
+// ignored returns whether relPath, a slash separated path relative to the module root, is excluded.
+// As with git, a path inside an excluded directory is excluded even if a later pattern re-includes it.
+func (vi *viamIgnore) ignored(relPath string, isDir bool) bool {
+	segments := strings.Split(relPath, "/")
+	for i := 1; i < len(segments); i++ {
+		if vi.matches(segments[:i], true) {
+			return true
+		}
+	}
+	return vi.matches(segments, isDir)
+}
+
+// matches returns whether the last pattern matching segments excludes it.
+func (vi *viamIgnore) matches(segments []string, isDir bool) bool {
+	excluded := false
+	for _, pattern := range vi.patterns {
+		if pattern.dirOnly && !isDir {
+			continue
+		}
+		if matchViamIgnoreSegments(pattern.segments, segments) {
+			excluded = !pattern.negate
+		}
+	}
+	return excluded
+}
+
+func matchViamIgnoreSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchViamIgnoreSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	if matched, err := path.Match(pattern[0], segments[0]); err != nil || !matched {
+		return false
+	}
+	return matchViamIgnoreSegments(pattern[1:], segments[1:])
+}
+
+// filter removes the files excluded by vi from files, which must all be inside root. It also returns
+// the number of excluded files.
+func (vi *viamIgnore) filter(root string, files []string) ([]string, int, error) {
+	included := make([]string, 0, len(files))
+	for _, file := range files {
+		relPath, err := filepath.Rel(root, file)
+		if err != nil {
+			return nil, 0, err
+		}
+		if vi.ignored(filepath.ToSlash(relPath), false) {
+			continue
+		}
+		included = append(included, file)
+	}
+	return included, len(files) - len(included), nil
+}
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"go.viam.com/test"
+)
+
+func TestViamIgnore(t *testing.T) {
+	tempDir := t.TempDir()
+	files := []string{
+		"meta.json",
+		"run.sh",
+		"src/main.py",
+		"src/main.pyc",
+		"src/keep.pyc",
+		"venv/lib/site.py",
+		".git/HEAD",
+		"build/out/bin",
+		"docs/build/index.html",
+		"logs/a.log",
+	}
+	for _, file := range files {
+		fullPath := filepath.Join(tempDir, file)
+		test.That(t, os.MkdirAll(filepath.Dir(fullPath), 0o700), test.ShouldBeNil)
+		test.That(t, os.WriteFile(fullPath, []byte("content"), 0o600), test.ShouldBeNil)
+	}
+
+	ignore, err := loadViamIgnore(tempDir)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, ignore, test.ShouldBeNil)
+
+	viamIgnoreContents := `# comments and blank lines are skipped
+
+.git
+venv/
+*.pyc
+!keep.pyc
+/build
+logs/**
+`
+	err = os.WriteFile(filepath.Join(tempDir, viamIgnoreFilename), []byte(viamIgnoreContents), 0o600)
+	test.That(t, err, test.ShouldBeNil)
+	ignore, err = loadViamIgnore(tempDir)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, ignore, test.ShouldNotBeNil)
+
+	archiveFiles, err := getArchiveFilePaths([]string{tempDir})
+	test.That(t, err, test.ShouldBeNil)
+	included, numExcluded, err := ignore.filter(tempDir, archiveFiles)
+	test.That(t, err, test.ShouldBeNil)
+
+	var relIncluded []string
+	for _, file := range included {
+		relPath, err := filepath.Rel(tempDir, file)
+		test.That(t, err, test.ShouldBeNil)
+		relIncluded = append(relIncluded, filepath.ToSlash(relPath))
+	}
+	sort.Strings(relIncluded)
+	test.That(t, relIncluded, test.ShouldResemble, []string{
+		viamIgnoreFilename,
+		"docs/build/index.html",
+		"meta.json",
+		"run.sh",
+		"src/keep.pyc",
+		"src/main.py",
+	})
+	test.That(t, numExcluded, test.ShouldEqual, 5)
+
+	t.Run("files in excluded directories cannot be re-included", func(t *testing.T) {
+		pattern1, ok := parseViamIgnorePattern("venv/")
+		test.That(t, ok, test.ShouldBeTrue)
+		pattern2, ok := parseViamIgnorePattern("!venv/lib/site.py")
+		test.That(t, ok, test.ShouldBeTrue)
+		ignore := &viamIgnore{patterns: []viamIgnorePattern{pattern1, pattern2}}
+		test.That(t, ignore.ignored("venv/lib/site.py", false), test.ShouldBeTrue)
+		test.That(t, ignore.ignored("venv", false), test.ShouldBeFalse)
+	})
+}
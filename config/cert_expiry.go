@@ -0,0 +1,74 @@
+package config
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/logging"
+)
+
+// expiringCertRetryInterval is the least time between refreshes of a TLS certificate that are only due to it
+// nearing expiry, so that a cloud that keeps returning the same certificate is not asked for it on every
+// config read.
+const expiringCertRetryInterval = 5 * time.Minute
+
+// expiringCertRefreshes holds when the certificate of each cloud ID was last refreshed because it was nearing
+// expiry.
+var expiringCertRefreshes = struct {
+	mu   sync.Mutex
+	last map[string]time.Time
+}{last: map[string]time.Time{}}
+
+// certExpiresAt returns the NotAfter time of the first certificate in the PEM encoded certPEM.
+func certExpiresAt(certPEM string) (time.Time, error) {
+	block, _ := pem.Decode([]byte(certPEM))
+	if block == nil {
+		return time.Time{}, errors.New("TLS certificate is not PEM encoded")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "cannot parse TLS certificate")
+	}
+	return cert.NotAfter, nil
+}
+
+// shouldRefreshExpiringCert reports whether the TLS certificate certPEM of the cloud with the given ID
+// expires within window of now and so should be fetched again, even if nothing it was issued for has
+// changed. A certificate that cannot be parsed is left to the other refresh checks.
+func shouldRefreshExpiringCert(id, certPEM string, window time.Duration, now time.Time, logger logging.Logger) bool {
+	if certPEM == "" {
+		return false
+	}
+	if window == 0 {
+		window = defaultCertRefreshWindow
+	}
+	expiresAt, err := certExpiresAt(certPEM)
+	if err != nil {
+		logger.Warnw("cannot check when the TLS certificate expires", "error", err)
+		return false
+	}
+	refreshAt := expiresAt.Add(-window)
+	if now.Before(refreshAt) {
+		logger.Debugw("TLS certificate is not due for refresh", "expires", expiresAt, "refresh_after", refreshAt)
+		return false
+	}
+
+	expiringCertRefreshes.mu.Lock()
+	defer expiringCertRefreshes.mu.Unlock()
+	if last, ok := expiringCertRefreshes.last[id]; ok && now.Sub(last) < expiringCertRetryInterval {
+		logger.Debugw("TLS certificate expires soon but was refreshed recently; waiting to refresh it again",
+			"expires", expiresAt, "last_refresh", last)
+		return false
+	}
+	expiringCertRefreshes.last[id] = now
+	if now.After(expiresAt) {
+		logger.Warnw("TLS certificate has expired; refreshing it", "expired", expiresAt)
+	} else {
+		logger.Infow("TLS certificate expires soon; refreshing it", "expires", expiresAt, "window", window)
+	}
+	return true
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/logging"
+)
+
+func newTestCertPEM(t *testing.T, notAfter time.Time) string {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	test.That(t, err, test.ShouldBeNil)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	test.That(t, err, test.ShouldBeNil)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func TestShouldRefreshExpiringCert(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	now := time.Now()
+	window := 7 * 24 * time.Hour
+
+	expiresAt, err := certExpiresAt(newTestCertPEM(t, now.Add(30*24*time.Hour).Truncate(time.Second)))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, expiresAt.Equal(now.Add(30*24*time.Hour).Truncate(time.Second)), test.ShouldBeTrue)
+	_, err = certExpiresAt("cert")
+	test.That(t, err, test.ShouldNotBeNil)
+
+	test.That(t, shouldRefreshExpiringCert("far", newTestCertPEM(t, now.Add(30*24*time.Hour)), window, now, logger),
+		test.ShouldBeFalse)
+	test.That(t, shouldRefreshExpiringCert("empty", "", window, now, logger), test.ShouldBeFalse)
+	test.That(t, shouldRefreshExpiringCert("unparseable", "cert", window, now, logger), test.ShouldBeFalse)
+
+	soon := newTestCertPEM(t, now.Add(2*24*time.Hour))
+	test.That(t, shouldRefreshExpiringCert("soon", soon, window, now, logger), test.ShouldBeTrue)
+	// the default window is used if none is configured.
+	test.That(t, shouldRefreshExpiringCert("soon-default", soon, 0, now, logger), test.ShouldBeTrue)
+	test.That(t, shouldRefreshExpiringCert("soon-small-window", soon, 24*time.Hour, now, logger), test.ShouldBeFalse)
+
+	// a certificate still expiring after a refresh is not refreshed again right away.
+	test.That(t, shouldRefreshExpiringCert("soon", soon, window, now.Add(time.Minute), logger), test.ShouldBeFalse)
+	test.That(t, shouldRefreshExpiringCert("soon", soon, window, now.Add(expiringCertRetryInterval), logger),
+		test.ShouldBeTrue)
+
+	expired := newTestCertPEM(t, now.Add(-time.Hour))
+	test.That(t, shouldRefreshExpiringCert("expired", expired, window, now, logger), test.ShouldBeTrue)
+}
+
+func TestCloudCertRefreshWindow(t *testing.T) {
+	var cloud Cloud
+	test.That(t, json.Unmarshal([]byte(`{"id": "id", "secret": "secret", "cert_refresh_window": "72h"}`), &cloud),
+		test.ShouldBeNil)
+	test.That(t, cloud.CertRefreshWindow, test.ShouldEqual, 72*time.Hour)
+	b, err := json.Marshal(cloud)
+	test.That(t, err, test.ShouldBeNil)
+	var roundTripped Cloud
+	test.That(t, json.Unmarshal(b, &roundTripped), test.ShouldBeNil)
+	test.That(t, roundTripped.CertRefreshWindow, test.ShouldEqual, 72*time.Hour)
+
+	cloud.CertRefreshWindow = 0
+	test.That(t, cloud.Validate("cloud", false), test.ShouldBeNil)
+	test.That(t, cloud.CertRefreshWindow, test.ShouldEqual, defaultCertRefreshWindow)
+	cloud.CertRefreshWindow = -time.Hour
+	test.That(t, cloud.Validate("cloud", false), test.ShouldNotBeNil)
+}
@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"go.uber.org/multierr"
 	"go.viam.com/utils/jwks"
 	"go.viam.com/utils/pexec"
 	"go.viam.com/utils/rpc"
@@ -98,50 +99,54 @@ func (c *Config) validateUniqueResource(logger logging.Logger, seenResources map
 }
 
 // Ensure ensures all parts of the config are valid, which may include updating it. Only returns an error
-// if c.DisablePartialStart is true (default: false).
+// if c.DisablePartialStart is true (default: false), in which case it combines every validation error found
+// rather than just the first.
 func (c *Config) Ensure(fromCloud bool, logger logging.Logger) error {
 	seenResources := make(map[string]bool)
+	var errs error
 
 	if c.Cloud != nil {
 		// Adds default for RefreshInterval if not set.
 		if err := c.Cloud.Validate("cloud", fromCloud); err != nil {
-			return err
+			errs = multierr.Append(errs, err)
 		}
 	}
 
 	//  Adds default BindAddress and HeartbeatWindow if not set.
 	if err := c.Network.Validate("network"); err != nil {
-		return err
+		errs = multierr.Append(errs, err)
 	}
 
 	// Updates ValidatedKeySet once validated.
 	if err := c.Auth.Validate("auth"); err != nil {
-		return err
+		errs = multierr.Append(errs, err)
 	}
 
 	for idx := 0; idx < len(c.Modules); idx++ {
 		if err := c.Modules[idx].Validate(fmt.Sprintf("%s.%d", "modules", idx)); err != nil {
 			if c.DisablePartialStart {
-				return err
+				errs = multierr.Append(errs, err)
+				continue
 			}
 			logger.Errorw("module config error; starting robot without module", "name", c.Modules[idx].Name, "error", err)
 		}
 		if err := c.validateUniqueResource(logger, seenResources, c.Modules[idx].Name); err != nil {
-			return err
+			errs = multierr.Append(errs, err)
 		}
 	}
 
 	for idx := 0; idx < len(c.Remotes); idx++ {
 		if _, err := c.Remotes[idx].Validate(fmt.Sprintf("%s.%d", "remotes", idx)); err != nil {
 			if c.DisablePartialStart {
-				return err
+				errs = multierr.Append(errs, err)
+				continue
 			}
 			logger.Errorw("remote config error; starting robot without remote", "name", c.Remotes[idx].Name, "error", err)
 		}
 		// we need to figure out how to make it so that the remote is tied to the API
 		resourceRemoteName := resource.NewName(resource.APINamespaceRDK.WithType("remote").WithSubtype(""), c.Remotes[idx].Name)
 		if err := c.validateUniqueResource(logger, seenResources, resourceRemoteName.String()); err != nil {
-			return err
+			errs = multierr.Append(errs, err)
 		}
 	}
 
@@ -156,7 +161,8 @@ func (c *Config) Ensure(fromCloud bool, logger logging.Logger) error {
 		if err != nil {
 			fullErr := errors.Wrapf(err, "error validating component %s: %s", component.Name, err)
 			if c.DisablePartialStart {
-				return fullErr
+				errs = multierr.Append(errs, fullErr)
+				continue
 			}
 			resLogger := logger.Sublogger(component.ResourceName().String())
 			resLogger.Errorw("component config error; starting robot without component", "name", component.Name, "error", err)
@@ -164,20 +170,21 @@ func (c *Config) Ensure(fromCloud bool, logger logging.Logger) error {
 			component.ImplicitDependsOn = dependsOn
 		}
 		if err := c.validateUniqueResource(logger, seenResources, component.ResourceName().String()); err != nil {
-			return err
+			errs = multierr.Append(errs, err)
 		}
 	}
 
 	for idx := 0; idx < len(c.Processes); idx++ {
 		if err := c.Processes[idx].Validate(fmt.Sprintf("%s.%d", "processes", idx)); err != nil {
 			if c.DisablePartialStart {
-				return err
+				errs = multierr.Append(errs, err)
+				continue
 			}
 			logger.Errorw("process config error; starting robot without process", "name", c.Processes[idx].Name, "error", err)
 		}
 
 		if err := c.validateUniqueResource(logger, seenResources, c.Processes[idx].ID); err != nil {
-			return err
+			errs = multierr.Append(errs, err)
 		}
 	}
 
@@ -191,7 +198,8 @@ func (c *Config) Ensure(fromCloud bool, logger logging.Logger) error {
 		dependsOn, err := service.Validate(fmt.Sprintf("%s.%d", "services", idx), resource.APITypeServiceName)
 		if err != nil {
 			if c.DisablePartialStart {
-				return err
+				errs = multierr.Append(errs, err)
+				continue
 			}
 			resLogger := logger.Sublogger(service.ResourceName().String())
 			resLogger.Errorw("service config error; starting robot without service", "name", service.Name, "error", err)
@@ -200,20 +208,21 @@ func (c *Config) Ensure(fromCloud bool, logger logging.Logger) error {
 		}
 
 		if err := c.validateUniqueResource(logger, seenResources, service.ResourceName().String()); err != nil {
-			return err
+			errs = multierr.Append(errs, err)
 		}
 	}
 
 	for idx := 0; idx < len(c.Packages); idx++ {
 		if err := c.Packages[idx].Validate(fmt.Sprintf("%s.%d", "packages", idx)); err != nil {
-			fullErr := errors.Errorf("error validating package config %s", err)
+			fullErr := errors.Wrap(err, "error validating package config")
 			if c.DisablePartialStart {
-				return fullErr
+				errs = multierr.Append(errs, fullErr)
+				continue
 			}
 			logger.Errorw("package config error; starting robot without package", "name", c.Packages[idx].Name, "error", err)
 		}
 		if err := c.validateUniqueResource(logger, seenResources, c.Packages[idx].Package); err != nil {
-			return err
+			errs = multierr.Append(errs, err)
 		}
 	}
 
@@ -223,7 +232,7 @@ func (c *Config) Ensure(fromCloud bool, logger logging.Logger) error {
 		}
 	}
 
-	return nil
+	return errs
 }
 
 // FindComponent finds a particular component by name.
@@ -501,6 +510,9 @@ type Cloud struct {
 	LogPath           string
 	AppAddress        string
 	RefreshInterval   time.Duration
+	// CertRefreshWindow is how long before the TLS certificate expires it is fetched again, even if nothing
+	// it was issued for has changed.
+	CertRefreshWindow time.Duration
 
 	// cached by us and fetched from a non-config endpoint.
 	TLSCertificate string
@@ -526,6 +538,7 @@ type cloudData struct {
 	Path              string           `json:"path,omitempty"`
 	LogPath           string           `json:"log_path,omitempty"`
 	RefreshInterval   string           `json:"refresh_interval,omitempty"`
+	CertRefreshWindow string           `json:"cert_refresh_window,omitempty"`
 
 	// cached by us and fetched from a non-config endpoint.
 	TLSCertificate string `json:"tls_certificate"`
@@ -563,6 +576,13 @@ func (config *Cloud) UnmarshalJSON(data []byte) error {
 		}
 		config.RefreshInterval = dur
 	}
+	if temp.CertRefreshWindow != "" {
+		dur, err := time.ParseDuration(temp.CertRefreshWindow)
+		if err != nil {
+			return err
+		}
+		config.CertRefreshWindow = dur
+	}
 	return nil
 }
 
@@ -589,10 +609,14 @@ func (config Cloud) MarshalJSON() ([]byte, error) {
 	if config.RefreshInterval != 0 {
 		temp.RefreshInterval = config.RefreshInterval.String()
 	}
+	if config.CertRefreshWindow != 0 {
+		temp.CertRefreshWindow = config.CertRefreshWindow.String()
+	}
 	return json.Marshal(temp)
 }
 
-// Validate ensures all parts of the config are valid. Adds default for RefreshInterval if not set.
+// Validate ensures all parts of the config are valid. Adds defaults for RefreshInterval and CertRefreshWindow
+// if not set.
 func (config *Cloud) Validate(path string, fromCloud bool) error {
 	if config.ID == "" {
 		return resource.NewConfigValidationFieldRequiredError(path, "id")
@@ -616,9 +640,18 @@ func (config *Cloud) Validate(path string, fromCloud bool) error {
 	if config.RefreshInterval == 0 {
 		config.RefreshInterval = 10 * time.Second
 	}
+	if config.CertRefreshWindow < 0 {
+		return resource.NewConfigValidationError(path, errors.New("cert_refresh_window cannot be negative"))
+	}
+	if config.CertRefreshWindow == 0 {
+		config.CertRefreshWindow = defaultCertRefreshWindow
+	}
 	return nil
 }
 
+// defaultCertRefreshWindow is the default CertRefreshWindow.
+const defaultCertRefreshWindow = 7 * 24 * time.Hour
+
 // ValidateTLS ensures TLS fields are valid.
 func (config *Cloud) ValidateTLS(path string) error {
 	if config.TLSCertificate == "" {
@@ -3,11 +3,14 @@ package config
 import (
 	"crypto/tls"
 	"encoding/json"
+	"fmt"
 	"reflect"
 	"sort"
+	"strings"
 
 	"github.com/sergi/go-diff/diffmatchpatch"
 	"go.viam.com/utils/pexec"
+	"golang.org/x/exp/slices"
 
 	"go.viam.com/rdk/resource"
 )
@@ -494,3 +497,192 @@ func diffModule(left, right Module, diff *Diff) bool {
 	diff.Modified.Modules = append(diff.Modified.Modules, right)
 	return true
 }
+
+// A ConfigChangeType is how a part of a config changed between two configs.
+type ConfigChangeType string
+
+// The ways a part of a config can change.
+const (
+	ConfigChangeAdded    ConfigChangeType = "added"
+	ConfigChangeRemoved  ConfigChangeType = "removed"
+	ConfigChangeModified ConfigChangeType = "modified"
+)
+
+// A ConfigChange describes a single remote, component, service, process, package or module that was added,
+// removed or modified.
+type ConfigChange struct {
+	Kind   string
+	Name   string
+	Change ConfigChangeType
+	// Fields holds the sorted JSON names of the top level fields that differ when Change is ConfigChangeModified.
+	Fields []string
+}
+
+// String returns a human readable description of the change, such as "component camera-1 modified (attributes)".
+func (c ConfigChange) String() string {
+	desc := fmt.Sprintf("%s %s %s", c.Kind, c.Name, c.Change)
+	if len(c.Fields) != 0 {
+		desc += fmt.Sprintf(" (%s)", strings.Join(c.Fields, ", "))
+	}
+	return desc
+}
+
+// A DiffSummary is a structured summary of a Diff. It only holds names and never any config values, so it is
+// safe to log even when the configs contain secrets.
+type DiffSummary struct {
+	Changes        []ConfigChange
+	NetworkChanged bool
+}
+
+// String returns a human readable description of every change in the summary.
+func (s DiffSummary) String() string {
+	descs := make([]string, 0, len(s.Changes)+1)
+	for _, c := range s.Changes {
+		descs = append(descs, c.String())
+	}
+	if s.NetworkChanged {
+		descs = append(descs, "network config modified")
+	}
+	if len(descs) == 0 {
+		return "no changes"
+	}
+	return strings.Join(descs, ", ")
+}
+
+// configChangeKinds orders the changes in a DiffSummary.
+var configChangeKinds = []string{"remote", "component", "service", "process", "package", "module"}
+
+// Summary returns a summary of what was added, removed and modified from left to right. Changes are sorted
+// by kind and then by name so that the summary does not depend on the order of the configs.
+func (diff *Diff) Summary() DiffSummary {
+	summary := DiffSummary{NetworkChanged: !diff.NetworkEqual}
+	add := func(kind, name string, change ConfigChangeType, left, right interface{}) {
+		c := ConfigChange{Kind: kind, Name: name, Change: change}
+		if change == ConfigChangeModified {
+			c.Fields = changedJSONFields(left, right)
+		}
+		summary.Changes = append(summary.Changes, c)
+	}
+
+	for _, cfg := range []struct {
+		conf   *Config
+		change ConfigChangeType
+	}{{diff.Added, ConfigChangeAdded}, {diff.Removed, ConfigChangeRemoved}} {
+		if cfg.conf == nil {
+			continue
+		}
+		for _, r := range cfg.conf.Remotes {
+			add("remote", r.Name, cfg.change, nil, nil)
+		}
+		for _, c := range cfg.conf.Components {
+			add("component", c.Name, cfg.change, nil, nil)
+		}
+		for _, s := range cfg.conf.Services {
+			add("service", s.Name, cfg.change, nil, nil)
+		}
+		for _, p := range cfg.conf.Processes {
+			add("process", p.ID, cfg.change, nil, nil)
+		}
+		for _, p := range cfg.conf.Packages {
+			add("package", p.Name, cfg.change, nil, nil)
+		}
+		for _, m := range cfg.conf.Modules {
+			add("module", m.Name, cfg.change, nil, nil)
+		}
+	}
+
+	if diff.Modified != nil && diff.Left != nil {
+		for _, r := range diff.Modified.Remotes {
+			for _, l := range diff.Left.Remotes {
+				if l.Name == r.Name {
+					add("remote", r.Name, ConfigChangeModified, l, r)
+				}
+			}
+		}
+		for _, c := range diff.Modified.Components {
+			for _, l := range diff.Left.Components {
+				if l.ResourceName() == c.ResourceName() {
+					add("component", c.Name, ConfigChangeModified, l, c)
+				}
+			}
+		}
+		for _, s := range diff.Modified.Services {
+			for _, l := range diff.Left.Services {
+				if l.ResourceName() == s.ResourceName() {
+					add("service", s.Name, ConfigChangeModified, l, s)
+				}
+			}
+		}
+		for _, p := range diff.Modified.Processes {
+			for _, l := range diff.Left.Processes {
+				if l.ID == p.ID {
+					add("process", p.ID, ConfigChangeModified, l, p)
+				}
+			}
+		}
+		for _, p := range diff.Modified.Packages {
+			for _, l := range diff.Left.Packages {
+				if l.Name == p.Name {
+					add("package", p.Name, ConfigChangeModified, l, p)
+				}
+			}
+		}
+		for _, m := range diff.Modified.Modules {
+			for _, l := range diff.Left.Modules {
+				if l.Name == m.Name {
+					add("module", m.Name, ConfigChangeModified, l, m)
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(summary.Changes, func(i, j int) bool {
+		left, right := summary.Changes[i], summary.Changes[j]
+		if left.Kind != right.Kind {
+			return slices.Index(configChangeKinds, left.Kind) < slices.Index(configChangeKinds, right.Kind)
+		}
+		if left.Name != right.Name {
+			return left.Name < right.Name
+		}
+		return left.Change < right.Change
+	})
+	return summary
+}
+
+// changedJSONFields returns the sorted names of the top level fields that differ between the JSON encodings
+// of left and right. Key order does not matter. It returns nil if either cannot be encoded as a JSON object.
+func changedJSONFields(left, right interface{}) []string {
+	toMap := func(v interface{}) (map[string]interface{}, error) {
+		md, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		var m map[string]interface{}
+		if err := json.Unmarshal(md, &m); err != nil {
+			return nil, err
+		}
+		return m, nil
+	}
+	leftM, err := toMap(left)
+	if err != nil {
+		return nil
+	}
+	rightM, err := toMap(right)
+	if err != nil {
+		return nil
+	}
+
+	var fields []string
+	for k, l := range leftM {
+		if r, ok := rightM[k]; !ok || !reflect.DeepEqual(l, r) {
+			fields = append(fields, k)
+		}
+	}
+	for k := range rightM {
+		if _, ok := leftM[k]; !ok {
+			fields = append(fields, k)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
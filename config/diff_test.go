@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
+	"strings"
 	"testing"
 
 	"go.viam.com/test"
@@ -721,3 +722,47 @@ func modifiedConfigDiffValidate(c *config.ModifiedConfigDiff) error {
 
 	return nil
 }
+
+func TestDiffSummary(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	left, err := config.FromReader(context.Background(), "", strings.NewReader(`{
+		"remotes": [{"name": "bar", "address": "bar:8080"}],
+		"components": [
+			{"name": "camera-1", "type": "camera", "model": "fake", "attributes": {"width": 100}},
+			{"name": "arm-1", "type": "arm", "model": "fake"},
+			{"name": "base-1", "type": "base", "model": "fake"}
+		]
+	}`), logger)
+	test.That(t, err, test.ShouldBeNil)
+	// the same components as left in a different order, with one changed, one removed and a remote added.
+	right, err := config.FromReader(context.Background(), "", strings.NewReader(`{
+		"remotes": [
+			{"name": "foo", "address": "foo:8080"},
+			{"name": "bar", "address": "bar:8080"}
+		],
+		"components": [
+			{"name": "base-1", "type": "base", "model": "fake"},
+			{"model": "fake", "type": "camera", "attributes": {"width": 200}, "name": "camera-1"}
+		],
+		"network": {"bind_address": "localhost:9090"}
+	}`), logger)
+	test.That(t, err, test.ShouldBeNil)
+
+	diff, err := config.DiffConfigs(*left, *right, false)
+	test.That(t, err, test.ShouldBeNil)
+	summary := diff.Summary()
+	test.That(t, summary, test.ShouldResemble, config.DiffSummary{
+		Changes: []config.ConfigChange{
+			{Kind: "remote", Name: "foo", Change: config.ConfigChangeAdded},
+			{Kind: "component", Name: "arm-1", Change: config.ConfigChangeRemoved},
+			{Kind: "component", Name: "camera-1", Change: config.ConfigChangeModified, Fields: []string{"attributes"}},
+		},
+		NetworkChanged: true,
+	})
+	test.That(t, summary.String(), test.ShouldEqual,
+		"remote foo added, component arm-1 removed, component camera-1 modified (attributes), network config modified")
+
+	diff, err = config.DiffConfigs(*left, *left, false)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, diff.Summary().String(), test.ShouldEqual, "no changes")
+}
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+
+	"go.viam.com/rdk/resource"
+)
+
+// locateConfigErrors prefixes each of the validation errors combined in err with the JSON path and, if it can
+// be found in buf, the line and column of the config object the error is about.
+func locateConfigErrors(buf []byte, err error) error {
+	errs := multierr.Errors(err)
+	for i, err := range errs {
+		path, ok := configErrorPath(err)
+		if !ok {
+			continue
+		}
+		offset, ok := jsonPathOffset(buf, strings.Split(path, "."))
+		if !ok {
+			errs[i] = errors.Wrap(err, path)
+			continue
+		}
+		line, column := jsonLineColumn(buf, offset)
+		errs[i] = errors.Wrapf(err, "%s (line %d, column %d)", path, line, column)
+	}
+	return multierr.Combine(errs...)
+}
+
+// configErrorPath returns the dotted JSON path, such as components.2.attributes, of the config object that
+// err is about.
+func configErrorPath(err error) (string, bool) {
+	var fre resource.FieldRequiredError
+	if errors.As(err, &fre) {
+		return fre.Path, fre.Path != ""
+	}
+	var cve resource.ConfigValidationError
+	if errors.As(err, &cve) {
+		return cve.Path, cve.Path != ""
+	}
+	return "", false
+}
+
+// jsonPathOffset returns the byte offset in the JSON document buf of the value at path, where each element
+// of path is an object key or a list index.
+func jsonPathOffset(buf []byte, path []string) (int64, bool) {
+	decoder := json.NewDecoder(bytes.NewReader(buf))
+	offset, ok := findJSONPath(decoder, path)
+	if !ok {
+		return 0, false
+	}
+	// the decoder's offset is just past the previous token, so skip to the start of the value.
+	for offset < int64(len(buf)) && strings.ContainsRune(" \t\r\n:,", rune(buf[offset])) {
+		offset++
+	}
+	return offset, true
+}
+
+// findJSONPath reads the next value from decoder and returns the offset just before the value at path within it.
+func findJSONPath(decoder *json.Decoder, path []string) (int64, bool) {
+	if len(path) == 0 {
+		return decoder.InputOffset(), true
+	}
+	tok, err := decoder.Token()
+	if err != nil {
+		return 0, false
+	}
+	switch tok {
+	case json.Delim('{'):
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return 0, false
+			}
+			if key == path[0] {
+				return findJSONPath(decoder, path[1:])
+			}
+			if err := skipJSONValue(decoder); err != nil {
+				return 0, false
+			}
+		}
+	case json.Delim('['):
+		idx, err := strconv.Atoi(path[0])
+		if err != nil {
+			return 0, false
+		}
+		for i := 0; decoder.More(); i++ {
+			if i == idx {
+				return findJSONPath(decoder, path[1:])
+			}
+			if err := skipJSONValue(decoder); err != nil {
+				return 0, false
+			}
+		}
+	}
+	return 0, false
+}
+
+func skipJSONValue(decoder *json.Decoder) error {
+	var value json.RawMessage
+	return decoder.Decode(&value)
+}
+
+// jsonLineColumn returns the 1-based line and column of offset in buf.
+func jsonLineColumn(buf []byte, offset int64) (int, int) {
+	if offset > int64(len(buf)) {
+		offset = int64(len(buf))
+	}
+	before := buf[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := len(before) - bytes.LastIndexByte(before, '\n')
+	return line, column
+}
+
+// jsonErrorLocation returns a description of where in buf the JSON decoding error err occurred, if known.
+func jsonErrorLocation(buf []byte, err error) string {
+	var syntaxErr *json.SyntaxError
+	if buf == nil || !errors.As(err, &syntaxErr) {
+		return ""
+	}
+	// the offset is just past the invalid character.
+	offset := syntaxErr.Offset
+	if offset > 0 {
+		offset--
+	}
+	line, column := jsonLineColumn(buf, offset)
+	return fmt.Sprintf(" at line %d, column %d", line, column)
+}
@@ -0,0 +1,162 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// includesKey is the top level JSON key listing the local config files that a config file is composed from.
+const includesKey = "includes"
+
+// resolveIncludes merges the files listed under "includes" in the JSON config buf into it. Included files
+// are merged in the order they are listed and the including config is merged last, so later values override
+// earlier ones. Included files may themselves include other files. Relative paths are resolved against the
+// directory of the including file, or the working directory if configPath is empty. buf is returned unchanged
+// if it has no includes.
+func resolveIncludes(configPath string, buf []byte) ([]byte, error) {
+	var top map[string]json.RawMessage
+	if err := json.Unmarshal(buf, &top); err != nil {
+		// let the regular config decoding report the error.
+		return buf, nil
+	}
+	if _, ok := top[includesKey]; !ok {
+		return buf, nil
+	}
+
+	var stack []string
+	if configPath != "" {
+		absPath, err := filepath.Abs(configPath)
+		if err != nil {
+			return nil, err
+		}
+		stack = append(stack, absPath)
+	}
+	merged, err := mergeIncludes(filepath.Dir(configPath), buf, stack)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(merged)
+}
+
+// mergeIncludes decodes the JSON config buf and returns it merged on top of its includes. dir is the directory
+// relative includes are resolved against and stack holds the absolute paths of the files currently being
+// included, used to detect cycles.
+func mergeIncludes(dir string, buf []byte, stack []string) (map[string]interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(buf))
+	decoder.UseNumber()
+	var cfg map[string]interface{}
+	if err := decoder.Decode(&cfg); err != nil {
+		return nil, errors.Wrap(err, "failed to decode Config from json")
+	}
+
+	rawIncludes, ok := cfg[includesKey]
+	if !ok {
+		return cfg, nil
+	}
+	delete(cfg, includesKey)
+	includes, ok := rawIncludes.([]interface{})
+	if !ok {
+		return nil, errors.Errorf("%q must be a list of file paths", includesKey)
+	}
+
+	merged := map[string]interface{}{}
+	for _, rawInclude := range includes {
+		include, ok := rawInclude.(string)
+		if !ok || include == "" {
+			return nil, errors.Errorf("%q must be a list of file paths", includesKey)
+		}
+		if !filepath.IsAbs(include) {
+			include = filepath.Join(dir, include)
+		}
+		includePath, err := filepath.Abs(include)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range stack {
+			if p == includePath {
+				return nil, errors.Errorf("config include cycle: %s", strings.Join(append(stack, includePath), " -> "))
+			}
+		}
+
+		//nolint:gosec
+		includeBuf, err := os.ReadFile(includePath)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read included config %q", includePath)
+		}
+		if includeBuf, err = interpolateEnv(includeBuf); err != nil {
+			return nil, errors.Wrapf(err, "failed to include config %q", includePath)
+		}
+		included, err := mergeIncludes(filepath.Dir(includePath), includeBuf, append(stack[:len(stack):len(stack)], includePath))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to include config %q", includePath)
+		}
+		merged = mergeConfigJSON(merged, included).(map[string]interface{})
+	}
+	return mergeConfigJSON(merged, cfg).(map[string]interface{}), nil
+}
+
+// mergeConfigJSON merges override into base. Objects are merged key by key, lists whose elements are all
+// objects with a "name" (such as components, services, remotes and modules) are merged by name, keeping
+// the position of the first occurrence, and any other value in override replaces the one in base.
+func mergeConfigJSON(base, override interface{}) interface{} {
+	switch override := override.(type) {
+	case map[string]interface{}:
+		baseMap, ok := base.(map[string]interface{})
+		if !ok {
+			return override
+		}
+		merged := make(map[string]interface{}, len(baseMap)+len(override))
+		for k, v := range baseMap {
+			merged[k] = v
+		}
+		for k, v := range override {
+			if baseV, ok := merged[k]; ok {
+				merged[k] = mergeConfigJSON(baseV, v)
+			} else {
+				merged[k] = v
+			}
+		}
+		return merged
+	case []interface{}:
+		baseList, ok := base.([]interface{})
+		if !ok || !allNamed(baseList) || !allNamed(override) {
+			return override
+		}
+		merged := append([]interface{}{}, baseList...)
+		indexByName := make(map[string]int, len(baseList))
+		for i, v := range baseList {
+			indexByName[v.(map[string]interface{})["name"].(string)] = i
+		}
+		for _, v := range override {
+			name := v.(map[string]interface{})["name"].(string)
+			if i, ok := indexByName[name]; ok {
+				merged[i] = mergeConfigJSON(merged[i], v)
+				continue
+			}
+			indexByName[name] = len(merged)
+			merged = append(merged, v)
+		}
+		return merged
+	default:
+		return override
+	}
+}
+
+// allNamed returns whether every element of list is an object with a string "name".
+func allNamed(list []interface{}) bool {
+	for _, v := range list {
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if _, ok := obj["name"].(string); !ok {
+			return false
+		}
+	}
+	return true
+}
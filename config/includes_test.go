@@ -0,0 +1,75 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestFromReaderIncludes(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	dir := t.TempDir()
+	writeFile := func(name, contents string) string {
+		p := filepath.Join(dir, name)
+		test.That(t, os.MkdirAll(filepath.Dir(p), 0o700), test.ShouldBeNil)
+		test.That(t, os.WriteFile(p, []byte(contents), 0o600), test.ShouldBeNil)
+		return p
+	}
+
+	writeFile("common/base.json", `{
+		"includes": ["arm.json"],
+		"components": [
+			{"name": "base1", "api": "rdk:component:base", "model": "fake", "attributes": {"a": 1, "b": 2}}
+		],
+		"debug": true
+	}`)
+	writeFile("common/arm.json", `{
+		"components": [{"name": "arm1", "api": "rdk:component:arm", "model": "fake"}]
+	}`)
+	cfgPath := writeFile("robot.json", `{
+		"includes": ["common/base.json"],
+		"components": [
+			{"name": "base1", "api": "rdk:component:base", "model": "fake", "attributes": {"b": 3}},
+			{"name": "camera1", "api": "rdk:component:camera", "model": "fake"}
+		],
+		"debug": false
+	}`)
+
+	cfg, err := ReadLocalConfig(context.Background(), cfgPath, logger)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cfg.ConfigFilePath, test.ShouldEqual, cfgPath)
+	test.That(t, cfg.Debug, test.ShouldBeFalse)
+	test.That(t, cfg.Components, test.ShouldHaveLength, 3)
+	test.That(t, cfg.Components[0].Name, test.ShouldEqual, "arm1")
+	test.That(t, cfg.Components[1].Name, test.ShouldEqual, "base1")
+	test.That(t, cfg.Components[1].Attributes.Int("a", 0), test.ShouldEqual, 1)
+	test.That(t, cfg.Components[1].Attributes.Int("b", 0), test.ShouldEqual, 3)
+	test.That(t, cfg.Components[2].Name, test.ShouldEqual, "camera1")
+
+	t.Run("cycle", func(t *testing.T) {
+		writeFile("a.json", `{"includes": ["b.json"]}`)
+		writeFile("b.json", `{"includes": ["a.json"]}`)
+		_, err := ReadLocalConfig(context.Background(), filepath.Join(dir, "a.json"), logger)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "config include cycle")
+	})
+
+	t.Run("missing include", func(t *testing.T) {
+		p := writeFile("missing.json", `{"includes": ["nope.json"]}`)
+		_, err := ReadLocalConfig(context.Background(), p, logger)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "failed to read included config")
+	})
+
+	t.Run("invalid includes", func(t *testing.T) {
+		_, err := FromReader(context.Background(), "", strings.NewReader(`{"includes": "base.json"}`), logger)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "must be a list of file paths")
+	})
+}
@@ -3,6 +3,8 @@ package config
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -10,12 +12,14 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/a8m/envsubst"
 	"github.com/pkg/errors"
 	apppb "go.viam.com/api/app/v1"
 	"go.viam.com/utils"
-	"go.viam.com/utils/artifact"
 	"go.viam.com/utils/rpc"
 	"golang.org/x/sys/cpu"
 
@@ -81,22 +85,44 @@ func init() {
 	viamPackagesDir = filepath.Join(ViamDotDir, "packages")
 }
 
+// cloudCacheFilePrefix and cloudCacheFileSuffix surround the part ID in the name of a cached cloud config.
+const (
+	cloudCacheFilePrefix = "cached_cloud_config_"
+	cloudCacheFileSuffix = ".json"
+)
+
 func getCloudCacheFilePath(id string) string {
-	return filepath.Join(ViamDotDir, fmt.Sprintf("cached_cloud_config_%s.json", id))
+	return filepath.Join(ViamDotDir, fmt.Sprintf("%s%s%s", cloudCacheFilePrefix, id, cloudCacheFileSuffix))
+}
+
+// getCloudCacheChecksumFilePath returns the path of the file holding the hex encoded SHA-256 checksum of
+// the cached config, which is used to detect a cache left corrupt or incomplete by a partial write.
+func getCloudCacheChecksumFilePath(id string) string {
+	return getCloudCacheFilePath(id) + ".sha256"
 }
 
 func readFromCache(id string) (*Config, error) {
-	r, err := os.Open(getCloudCacheFilePath(id))
+	md, err := os.ReadFile(getCloudCacheFilePath(id))
 	if err != nil {
 		return nil, err
 	}
-	defer utils.UncheckedErrorFunc(r.Close)
+
+	// caches written before checksums were stored have no checksum file and are only checked by parsing them.
+	checksum, err := os.ReadFile(getCloudCacheChecksumFilePath(id))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil && string(bytes.TrimSpace(checksum)) != cacheChecksum(md) {
+		// clear the cache if it does not match the checksum stored with it.
+		clearCache(id)
+		return nil, errors.New("cached config does not match its checksum")
+	}
 
 	unprocessedConfig := &Config{
 		ConfigFilePath: "",
 	}
 
-	if err := json.NewDecoder(r).Decode(unprocessedConfig); err != nil {
+	if err := json.Unmarshal(md, unprocessedConfig); err != nil {
 		// clear the cache if we cannot parse the file.
 		clearCache(id)
 		return nil, errors.Wrap(err, "cannot parse the cached config as json")
@@ -117,13 +143,131 @@ func storeToCache(id string, cfg *Config) error {
 
 	path := getCloudCacheFilePath(id)
 
-	return artifact.AtomicStore(path, reader, id)
+	if err := atomicWriteFile(path, reader); err != nil {
+		return err
+	}
+	// the checksum is stored after the config so that a failure in between leaves a mismatch that is
+	// detected on the next read rather than a stale checksum that matches.
+	return atomicWriteFile(getCloudCacheChecksumFilePath(id), strings.NewReader(cacheChecksum(md)))
+}
+
+// atomicWriteFile writes the contents of r to a temporary file in the same directory as path, syncs it
+// to disk and renames it into place, so that a crash or power loss leaves either the previous file or the
+// new one but never a partially written file at path.
+func atomicWriteFile(path string, r io.Reader) error {
+	tempFile, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	var successful bool
+	defer func() {
+		if !successful {
+			utils.UncheckedError(tempFile.Close())
+			utils.UncheckedError(os.Remove(tempFile.Name()))
+		}
+	}()
+	if err := tempFile.Chmod(0o600); err != nil {
+		return err
+	}
+	if _, err := io.Copy(tempFile, r); err != nil {
+		return err
+	}
+	if err := tempFile.Sync(); err != nil {
+		return err
+	}
+	if err := tempFile.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tempFile.Name(), path); err != nil {
+		return err
+	}
+	successful = true
+	return syncDir(filepath.Dir(path))
+}
+
+// syncDir syncs the directory dir so that a rename within it survives power loss. Directories cannot be
+// synced on windows, where this does nothing.
+func syncDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	//nolint:gosec
+	dirFile, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer utils.UncheckedErrorFunc(dirFile.Close)
+	return dirFile.Sync()
+}
+
+func cacheChecksum(md []byte) string {
+	sum := sha256.Sum256(md)
+	return hex.EncodeToString(sum[:])
 }
 
 func clearCache(id string) {
 	utils.UncheckedErrorFunc(func() error {
 		return os.Remove(getCloudCacheFilePath(id))
 	})
+	utils.UncheckedErrorFunc(func() error {
+		return os.Remove(getCloudCacheChecksumFilePath(id))
+	})
+}
+
+// CachedCloudConfig describes the cloud config of a machine part cached on disk, which the part starts
+// with when it cannot reach the cloud.
+type CachedCloudConfig struct {
+	PartID  string
+	Path    string
+	Size    int64
+	ModTime time.Time
+}
+
+// ListCachedCloudConfigs returns the cloud configs cached in ViamDotDir, sorted by part ID.
+func ListCachedCloudConfigs() ([]CachedCloudConfig, error) {
+	paths, err := filepath.Glob(filepath.Join(ViamDotDir, cloudCacheFilePrefix+"*"+cloudCacheFileSuffix))
+	if err != nil {
+		return nil, err
+	}
+	cached := make([]CachedCloudConfig, 0, len(paths))
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				// removed since it was listed.
+				continue
+			}
+			return nil, err
+		}
+		if info.IsDir() {
+			continue
+		}
+		cached = append(cached, CachedCloudConfig{
+			PartID:  strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), cloudCacheFilePrefix), cloudCacheFileSuffix),
+			Path:    path,
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		})
+	}
+	sort.Slice(cached, func(i, j int) bool {
+		return cached[i].PartID < cached[j].PartID
+	})
+	return cached, nil
+}
+
+// ClearCachedCloudConfig removes the cached cloud config of the part with the given ID. It is an error if
+// no config is cached for the part.
+func ClearCachedCloudConfig(partID string) error {
+	if err := os.Remove(getCloudCacheFilePath(partID)); err != nil {
+		if os.IsNotExist(err) {
+			return errors.Errorf("no config is cached for part %q", partID)
+		}
+		return err
+	}
+	if err := os.Remove(getCloudCacheChecksumFilePath(partID)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
 }
 
 func readCertificateDataFromCloudGRPC(ctx context.Context,
@@ -160,6 +304,24 @@ func readCertificateDataFromCloudGRPC(ctx context.Context,
 	}, nil
 }
 
+// ShouldRefreshCert reports whether the TLS certificate of a machine part should be fetched again from the
+// cloud after its cloud config changes from prevCloud to cloud. This is the same decision viam-server makes
+// when it reads a new config: a refresh is warranted when any field the certificate is issued for or
+// authenticated with changes, that is the FQDNs, signaling address or its insecurity, who manages the part,
+// or its location secrets. Changes to the certificate or key themselves do not warrant a refresh.
+//
+// If prevCloud is nil there is no certificate to keep, so a refresh is warranted. If cloud is nil the part is
+// no longer cloud managed and there is nothing to refresh.
+func ShouldRefreshCert(prevCloud, cloud *Cloud) bool {
+	if cloud == nil {
+		return false
+	}
+	if prevCloud == nil {
+		return true
+	}
+	return shouldCheckForCert(prevCloud, cloud)
+}
+
 // shouldCheckForCert checks the Cloud config to see if the TLS cert should be refetched.
 func shouldCheckForCert(prevCloud, cloud *Cloud) bool {
 	// only checking the same fields as the ones that are explicitly overwritten in mergeCloudConfig
@@ -243,14 +405,19 @@ func readFromCloud(
 	if prevCfg != nil && shouldCheckForCert(prevCfg.Cloud, cfg.Cloud) {
 		checkForNewCert = true
 	}
+	// a certificate nearing expiry is refreshed even if nothing it was issued for has changed, e.g. after a long
+	// time offline. The window comes from the local config, like the rest of the cloud section.
+	onlyExpiring := !checkForNewCert &&
+		shouldRefreshExpiringCert(cloudCfg.ID, tls.certificate, cloudCfg.CertRefreshWindow, time.Now(), logger)
 
-	if checkForNewCert || tls.certificate == "" || tls.privateKey == "" {
+	if checkForNewCert || onlyExpiring || tls.certificate == "" || tls.privateKey == "" {
 		logger.Debug("reading tlsCertificate from the cloud")
 		// Use the SignalingInsecure from the Cloud config returned from the app not the initial config.
 
 		certData, err := readCertificateDataFromCloudGRPC(ctx, cfg.Cloud.SignalingInsecure, cloudCfg, logger)
 		if err != nil {
-			if !errors.Is(err, context.DeadlineExceeded) {
+			// failing to refresh a certificate only because it is expiring is not fatal; it is tried again later.
+			if !errors.Is(err, context.DeadlineExceeded) && !onlyExpiring {
 				return nil, err
 			}
 			if tls.certificate == "" || tls.privateKey == "" {
@@ -329,7 +496,8 @@ func Read(
 	filePath string,
 	logger logging.Logger,
 ) (*Config, error) {
-	buf, err := envsubst.ReadFile(filePath)
+	//nolint:gosec
+	buf, err := os.ReadFile(filePath)
 	if err != nil {
 		return nil, err
 	}
@@ -343,7 +511,8 @@ func ReadLocalConfig(
 	filePath string,
 	logger logging.Logger,
 ) (*Config, error) {
-	buf, err := envsubst.ReadFile(filePath)
+	//nolint:gosec
+	buf, err := os.ReadFile(filePath)
 	if err != nil {
 		return nil, err
 	}
@@ -371,25 +540,79 @@ func fromReader(
 	logger logging.Logger,
 	shouldReadFromCloud bool,
 ) (*Config, error) {
+	cfgFromDisk, err := processConfigFromReader(originalPath, r, logger, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if shouldReadFromCloud && cfgFromDisk.Cloud != nil {
+		cfg, err := readFromCloud(ctx, cfgFromDisk, nil, true, true, logger)
+		return cfg, err
+	}
+
+	return cfgFromDisk, nil
+}
+
+// ValidateFromReader reads the config in r like FromReader, without fetching anything from the cloud, and
+// returns every error that would keep any part of it from starting. Errors for individual resources, which
+// viam-server only logs when partial start is enabled, are returned too.
+func ValidateFromReader(originalPath string, r io.Reader, logger logging.Logger) error {
+	_, err := processConfigFromReader(originalPath, r, logger, true)
+	return err
+}
+
+// processConfigFromReader reads and processes the config in r. If disablePartialStart is true, it is
+// processed as if the config set disable_partial_start.
+func processConfigFromReader(
+	originalPath string,
+	r io.Reader,
+	logger logging.Logger,
+	disablePartialStart bool,
+) (*Config, error) {
+	buf, err := io.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read Config")
+	}
+	buf, err = interpolateEnv(buf)
+	if err != nil {
+		return nil, err
+	}
+	resolved, err := resolveIncludes(originalPath, buf)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to resolve Config includes")
+	}
+	// Lines and columns in errors can only be reported if the config was not merged from included files.
+	if !bytes.Equal(resolved, buf) {
+		buf = nil
+	}
+
 	// First read and process config from disk
 	unprocessedConfig := Config{
 		ConfigFilePath: originalPath,
 	}
-	err := json.NewDecoder(r).Decode(&unprocessedConfig)
+	err = json.NewDecoder(bytes.NewReader(resolved)).Decode(&unprocessedConfig)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to decode Config from json")
+		return nil, errors.Wrapf(err, "failed to decode Config from json%s", jsonErrorLocation(buf, err))
+	}
+	if disablePartialStart {
+		unprocessedConfig.DisablePartialStart = true
 	}
 	cfgFromDisk, err := processConfigLocalConfig(&unprocessedConfig, logger)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to process Config")
+		return nil, errors.Wrapf(locateConfigErrors(buf, err), "failed to process Config")
 	}
+	return cfgFromDisk, nil
+}
 
-	if shouldReadFromCloud && cfgFromDisk.Cloud != nil {
-		cfg, err := readFromCloud(ctx, cfgFromDisk, nil, true, true, logger)
-		return cfg, err
+// interpolateEnv substitutes environment variables into the raw JSON config buf, before it is parsed.
+// ${VAR} and $VAR are replaced with the value of VAR and ${VAR:-default} with default if VAR is unset or
+// empty. $$ escapes a literal $. It is an error to reference an unset variable without a default.
+func interpolateEnv(buf []byte) ([]byte, error) {
+	interpolated, err := envsubst.BytesRestricted(buf, true, false)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to substitute environment variables in Config")
 	}
-
-	return cfgFromDisk, err
+	return interpolated, nil
 }
 
 // processConfigFromCloud returns a copy of the current config with all attributes parsed
@@ -76,3 +76,55 @@ func TestFromReaderValidate(t *testing.T) {
 	test.That(t, expected.Ensure(false, logger), test.ShouldBeNil)
 	test.That(t, conf, test.ShouldResemble, expected)
 }
+
+func TestFromReaderInterpolatesEnv(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	t.Setenv("TEST_INTERPOLATE_NAME", "foo")
+	t.Setenv("TEST_INTERPOLATE_EMPTY", "")
+
+	conf, err := config.FromReader(context.Background(), "somepath", strings.NewReader(`{"components": [
+		{"name": "${TEST_INTERPOLATE_NAME}", "type": "arm", "model": "${TEST_INTERPOLATE_UNSET:-fake}"},
+		{"name": "$TEST_INTERPOLATE_NAME-2", "type": "arm", "model": "${TEST_INTERPOLATE_EMPTY:-fake}"},
+		{"name": "bar", "type": "arm", "model": "fake", "attributes": {"price": "$$5"}}
+	]}`), logger)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, conf.Components, test.ShouldHaveLength, 3)
+	test.That(t, conf.Components[0].Name, test.ShouldEqual, "foo")
+	test.That(t, conf.Components[0].Model, test.ShouldResemble, resource.DefaultModelFamily.WithModel("fake"))
+	test.That(t, conf.Components[1].Name, test.ShouldEqual, "foo-2")
+	test.That(t, conf.Components[1].Model, test.ShouldResemble, resource.DefaultModelFamily.WithModel("fake"))
+	test.That(t, conf.Components[2].Attributes.String("price"), test.ShouldEqual, "$5")
+
+	_, err = config.FromReader(context.Background(), "somepath", strings.NewReader(`{"components": [
+		{"name": "${TEST_INTERPOLATE_UNSET}", "type": "arm", "model": "fake"}
+	]}`), logger)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "${TEST_INTERPOLATE_UNSET} not set")
+}
+
+func TestFromReaderErrorLocations(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+
+	_, err := config.FromReader(context.Background(), "somepath", strings.NewReader("{\n  \"components\": [\n    {,}\n  ]\n}"), logger)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "failed to decode Config from json at line 3, column 6")
+
+	_, err = config.FromReader(context.Background(), "somepath", strings.NewReader(`{
+  "disable_partial_start": true,
+  "components": [
+    {"name": "foo", "type": "arm", "model": "fake"},
+    {"type": "arm", "model": "fake"},
+    {"name": "foo", "type": "arm", "model": "fake"}
+  ],
+  "remotes": [
+    {"name": "rem"}
+  ]
+}`), logger)
+	test.That(t, err, test.ShouldNotBeNil)
+	// every error is reported, rather than just the first.
+	test.That(t, err.Error(), test.ShouldContainSubstring, `remotes.0 (line 9, column 5): Error validating, missing required field. Path: "remotes.0" Field: "address"`)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `components.1 (line 5, column 5): error validating component`)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "duplicate resource rdk:component:arm/foo")
+	var fre resource.FieldRequiredError
+	test.That(t, errors.As(err, &fre), test.ShouldBeTrue)
+}
@@ -2,7 +2,9 @@ package config
 
 import (
 	"context"
+	"errors"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -80,6 +82,103 @@ func TestCacheInvalidation(t *testing.T) {
 	test.That(t, os.IsNotExist(err), test.ShouldBeTrue)
 }
 
+func TestCacheChecksum(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	cfg, err := FromReader(context.Background(), "", strings.NewReader(`{"components": [{"name": "foo", "type": "arm", "model": "fake"}]}`), logger)
+	test.That(t, err, test.ShouldBeNil)
+
+	id := uuid.New().String()
+	test.That(t, storeToCache(id, cfg), test.ShouldBeNil)
+	defer clearCache(id)
+
+	cachedCfg, err := readFromCache(id)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cachedCfg.Components, test.ShouldHaveLength, 1)
+
+	// replace the cache with valid json that does not match the checksum, as a partial write could.
+	err = os.WriteFile(getCloudCacheFilePath(id), []byte(`{}`), 0o600)
+	test.That(t, err, test.ShouldBeNil)
+	_, err = readFromCache(id)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "does not match its checksum")
+
+	// both the cache and its checksum should have been removed
+	_, err = os.Stat(getCloudCacheFilePath(id))
+	test.That(t, os.IsNotExist(err), test.ShouldBeTrue)
+	_, err = os.Stat(getCloudCacheChecksumFilePath(id))
+	test.That(t, os.IsNotExist(err), test.ShouldBeTrue)
+}
+
+type failingReader struct {
+	data []byte
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if len(r.data) == 0 {
+		return 0, errors.New("interrupted")
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func TestListAndClearCachedCloudConfigs(t *testing.T) {
+	prevViamDotDir := ViamDotDir
+	ViamDotDir = t.TempDir()
+	defer func() {
+		ViamDotDir = prevViamDotDir
+	}()
+
+	cached, err := ListCachedCloudConfigs()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cached, test.ShouldBeEmpty)
+
+	test.That(t, storeToCache("part-b", &Config{}), test.ShouldBeNil)
+	test.That(t, storeToCache("part-a", &Config{}), test.ShouldBeNil)
+	cached, err = ListCachedCloudConfigs()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cached, test.ShouldHaveLength, 2)
+	test.That(t, cached[0].PartID, test.ShouldEqual, "part-a")
+	test.That(t, cached[0].Path, test.ShouldEqual, getCloudCacheFilePath("part-a"))
+	test.That(t, cached[0].Size, test.ShouldBeGreaterThan, 0)
+	test.That(t, cached[0].ModTime.IsZero(), test.ShouldBeFalse)
+	test.That(t, cached[1].PartID, test.ShouldEqual, "part-b")
+
+	test.That(t, ClearCachedCloudConfig("part-a"), test.ShouldBeNil)
+	_, err = os.Stat(getCloudCacheChecksumFilePath("part-a"))
+	test.That(t, os.IsNotExist(err), test.ShouldBeTrue)
+	err = ClearCachedCloudConfig("part-a")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "no config is cached")
+	cached, err = ListCachedCloudConfigs()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cached, test.ShouldHaveLength, 1)
+	test.That(t, cached[0].PartID, test.ShouldEqual, "part-b")
+}
+
+func TestAtomicWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cached_cloud_config.json")
+	test.That(t, atomicWriteFile(path, strings.NewReader(`{"previous": true}`)), test.ShouldBeNil)
+
+	// a write interrupted partway through should leave the previous file intact and no temporary files behind.
+	err := atomicWriteFile(path, &failingReader{data: []byte(`{"next": `)})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "interrupted")
+
+	contents, err := os.ReadFile(path)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, string(contents), test.ShouldEqual, `{"previous": true}`)
+	entries, err := os.ReadDir(dir)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, entries, test.ShouldHaveLength, 1)
+
+	test.That(t, atomicWriteFile(path, strings.NewReader(`{"next": true}`)), test.ShouldBeNil)
+	contents, err = os.ReadFile(path)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, string(contents), test.ShouldEqual, `{"next": true}`)
+}
+
 func TestShouldCheckForCert(t *testing.T) {
 	cloud1 := Cloud{
 		ManagedBy:        "acme",
@@ -115,6 +214,62 @@ func TestShouldCheckForCert(t *testing.T) {
 	test.That(t, shouldCheckForCert(&cloud1, &cloud2), test.ShouldBeTrue)
 }
 
+func TestShouldRefreshCert(t *testing.T) {
+	cloud1 := Cloud{
+		ManagedBy:        "acme",
+		SignalingAddress: "abc",
+		ID:               "forCachingTest",
+		Secret:           "ghi",
+		FQDN:             "fqdn",
+		LocalFQDN:        "localFqdn",
+		TLSCertificate:   "cert",
+		TLSPrivateKey:    "key",
+		LocationID:       "the-location",
+		PrimaryOrgID:     "the-primary-org",
+		LocationSecrets: []LocationSecret{
+			{ID: "id1", Secret: "secret1"},
+			{ID: "id2", Secret: "secret2"},
+		},
+	}
+	cloud2 := cloud1
+	test.That(t, ShouldRefreshCert(&cloud1, &cloud2), test.ShouldBeFalse)
+
+	cloud2.TLSCertificate = "abc"
+	cloud2.TLSPrivateKey = "def"
+	test.That(t, ShouldRefreshCert(&cloud1, &cloud2), test.ShouldBeFalse)
+
+	cloud2 = cloud1
+	cloud2.LocationSecret = "something else"
+	test.That(t, ShouldRefreshCert(&cloud1, &cloud2), test.ShouldBeTrue)
+
+	cloud2 = cloud1
+	cloud2.LocationSecrets = []LocationSecret{
+		{ID: "id1", Secret: "secret1"},
+		{ID: "id2", Secret: "secret3"},
+	}
+	test.That(t, ShouldRefreshCert(&cloud1, &cloud2), test.ShouldBeTrue)
+
+	cloud2 = cloud1
+	cloud2.LocationSecrets = cloud1.LocationSecrets[:1]
+	test.That(t, ShouldRefreshCert(&cloud1, &cloud2), test.ShouldBeTrue)
+
+	for _, modify := range []func(c *Cloud){
+		func(c *Cloud) { c.FQDN = "other" },
+		func(c *Cloud) { c.LocalFQDN = "other" },
+		func(c *Cloud) { c.SignalingAddress = "other" },
+		func(c *Cloud) { c.SignalingInsecure = true },
+		func(c *Cloud) { c.ManagedBy = "other" },
+	} {
+		cloud2 = cloud1
+		modify(&cloud2)
+		test.That(t, ShouldRefreshCert(&cloud1, &cloud2), test.ShouldBeTrue)
+	}
+
+	test.That(t, ShouldRefreshCert(nil, &cloud1), test.ShouldBeTrue)
+	test.That(t, ShouldRefreshCert(&cloud1, nil), test.ShouldBeFalse)
+	test.That(t, ShouldRefreshCert(nil, nil), test.ShouldBeFalse)
+}
+
 func TestProcessConfig(t *testing.T) {
 	logger := logging.NewTestLogger(t)
 	unprocessedConfig := Config{
@@ -126,6 +281,21 @@ func TestProcessConfig(t *testing.T) {
 	test.That(t, *cfg, test.ShouldResemble, unprocessedConfig)
 }
 
+func TestValidateFromReader(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	invalid := `{"components": [{"api": "rdk:component:arm", "model": "fake"}]}`
+
+	// the invalid component is only logged when starting, but is an error when validating.
+	cfg, err := FromReader(context.Background(), "", strings.NewReader(invalid), logger)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cfg.Components, test.ShouldHaveLength, 1)
+	err = ValidateFromReader("", strings.NewReader(invalid), logger)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "components.0 (line 1, column 17)")
+
+	test.That(t, ValidateFromReader("", strings.NewReader(`{"components": []}`), logger), test.ShouldBeNil)
+}
+
 func TestReadTLSFromCache(t *testing.T) {
 	logger := logging.NewTestLogger(t)
 	ctx := context.Background()
@@ -0,0 +1,183 @@
+package data
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	v1 "go.viam.com/api/app/datasync/v1"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"go.viam.com/rdk/services/datamanager/datacapture"
+)
+
+// AggregateFn is how the tabular readings captured within an aggregation window are combined into one.
+type AggregateFn string
+
+const (
+	// AggregateMean averages each numeric field of the readings. It is the default.
+	AggregateMean AggregateFn = "mean"
+	// AggregateMax takes the largest value of each numeric field of the readings.
+	AggregateMax AggregateFn = "max"
+	// AggregateLast keeps only the last reading.
+	AggregateLast AggregateFn = "last"
+)
+
+// Validate returns an error if fn is not one of the AggregateFns. The empty AggregateFn is AggregateMean.
+func (fn AggregateFn) Validate() error {
+	switch fn {
+	case "", AggregateMean, AggregateMax, AggregateLast:
+		return nil
+	default:
+		return errors.Errorf("aggregate function must be %q, %q or %q, got %q", AggregateMean, AggregateMax, AggregateLast, fn)
+	}
+}
+
+// aggregatingWriter is a datacapture.BufferedWriter that combines the tabular readings requested within each
+// window into one reading before writing it to the wrapped writer, so that less data is written and synced.
+// Windows are aligned to multiples of their duration since the Unix epoch. Binary readings cannot be combined
+// and are written as they are.
+type aggregatingWriter struct {
+	datacapture.BufferedWriter
+	window time.Duration
+	fn     AggregateFn
+
+	mu sync.Mutex
+	// pending holds the readings of the current window, which starts at windowStart.
+	pending     []*v1.SensorData
+	windowStart time.Time
+}
+
+// newAggregatingWriter wraps w so that tabular readings are combined with fn over windows of the given
+// duration.
+func newAggregatingWriter(w datacapture.BufferedWriter, window time.Duration, fn AggregateFn) *aggregatingWriter {
+	if fn == "" {
+		fn = AggregateMean
+	}
+	return &aggregatingWriter{BufferedWriter: w, window: window, fn: fn}
+}
+
+// Write adds item to the current window. The readings of the window are combined and written once an item
+// requested after the window arrives.
+func (w *aggregatingWriter) Write(item *v1.SensorData) error {
+	if item.GetStruct() == nil {
+		return w.BufferedWriter.Write(item)
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	windowStart := item.GetMetadata().GetTimeRequested().AsTime().Truncate(w.window)
+	if len(w.pending) > 0 && !windowStart.Equal(w.windowStart) {
+		if err := w.writePending(); err != nil {
+			return err
+		}
+	}
+	w.windowStart = windowStart
+	w.pending = append(w.pending, item)
+	return nil
+}
+
+// Flush writes the combined readings of the current window, even though it may not be over, and flushes the
+// wrapped writer.
+func (w *aggregatingWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.pending) > 0 {
+		if err := w.writePending(); err != nil {
+			return err
+		}
+	}
+	return w.BufferedWriter.Flush()
+}
+
+// writePending writes the combined readings of the current window. It must be called with w.mu held.
+func (w *aggregatingWriter) writePending() error {
+	item := aggregateReadings(w.pending, w.fn)
+	w.pending = nil
+	return w.BufferedWriter.Write(item)
+}
+
+// aggregateReadings combines the tabular readings into one with fn. The combined reading was requested when the
+// first reading was requested and received when the last reading was received.
+func aggregateReadings(readings []*v1.SensorData, fn AggregateFn) *v1.SensorData {
+	first, last := readings[0], readings[len(readings)-1]
+	metadata := &v1.SensorMetadata{
+		TimeRequested: first.GetMetadata().GetTimeRequested(),
+		TimeReceived:  last.GetMetadata().GetTimeReceived(),
+	}
+	if fn == AggregateLast {
+		return &v1.SensorData{Metadata: metadata, Data: last.GetData()}
+	}
+	structs := make([]*structpb.Struct, 0, len(readings))
+	for _, reading := range readings {
+		structs = append(structs, reading.GetStruct())
+	}
+	return &v1.SensorData{Metadata: metadata, Data: &v1.SensorData_Struct{Struct: aggregateStructs(structs, fn)}}
+}
+
+// aggregateStructs combines each field of structs with fn, over the structs that have the field.
+func aggregateStructs(structs []*structpb.Struct, fn AggregateFn) *structpb.Struct {
+	fields := map[string][]*structpb.Value{}
+	for _, s := range structs {
+		for name, value := range s.GetFields() {
+			fields[name] = append(fields[name], value)
+		}
+	}
+	aggregated := &structpb.Struct{Fields: make(map[string]*structpb.Value, len(fields))}
+	for name, values := range fields {
+		aggregated.Fields[name] = aggregateValues(values, fn)
+	}
+	return aggregated
+}
+
+// aggregateValues combines numbers with fn and structs field by field. Values of any other kind, or of mixed
+// kinds, cannot be combined, so the last one is kept.
+func aggregateValues(values []*structpb.Value, fn AggregateFn) *structpb.Value {
+	last := values[len(values)-1]
+	switch last.GetKind().(type) {
+	case *structpb.Value_NumberValue:
+		numbers := make([]float64, 0, len(values))
+		for _, v := range values {
+			n, ok := v.GetKind().(*structpb.Value_NumberValue)
+			if !ok {
+				return last
+			}
+			numbers = append(numbers, n.NumberValue)
+		}
+		return structpb.NewNumberValue(aggregateNumbers(numbers, fn))
+	case *structpb.Value_StructValue:
+		structs := make([]*structpb.Struct, 0, len(values))
+		for _, v := range values {
+			s, ok := v.GetKind().(*structpb.Value_StructValue)
+			if !ok {
+				return last
+			}
+			structs = append(structs, s.StructValue)
+		}
+		return structpb.NewStructValue(aggregateStructs(structs, fn))
+	default:
+		return last
+	}
+}
+
+// aggregateNumbers combines numbers with fn.
+func aggregateNumbers(numbers []float64, fn AggregateFn) float64 {
+	switch fn {
+	case AggregateMax:
+		largest := math.Inf(-1)
+		for _, n := range numbers {
+			largest = math.Max(largest, n)
+		}
+		return largest
+	case AggregateLast:
+		return numbers[len(numbers)-1]
+	case AggregateMean:
+		fallthrough
+	default:
+		var sum float64
+		for _, n := range numbers {
+			sum += n
+		}
+		return sum / float64(len(numbers))
+	}
+}
@@ -0,0 +1,125 @@
+package data
+
+import (
+	"testing"
+	"time"
+
+	v1 "go.viam.com/api/app/datasync/v1"
+	"go.viam.com/test"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"go.viam.com/rdk/logging"
+)
+
+// recordingWriter is a datacapture.BufferedWriter that records what is written to it.
+type recordingWriter struct {
+	written []*v1.SensorData
+	flushes int
+}
+
+func (w *recordingWriter) Write(item *v1.SensorData) error {
+	w.written = append(w.written, item)
+	return nil
+}
+
+func (w *recordingWriter) Flush() error {
+	w.flushes++
+	return nil
+}
+
+func (w *recordingWriter) Path() string {
+	return ""
+}
+
+func tabularReadingAt(t *testing.T, at time.Time, fields map[string]interface{}) *v1.SensorData {
+	t.Helper()
+	s, err := structpb.NewStruct(fields)
+	test.That(t, err, test.ShouldBeNil)
+	return &v1.SensorData{
+		Metadata: &v1.SensorMetadata{
+			TimeRequested: timestamppb.New(at),
+			TimeReceived:  timestamppb.New(at.Add(time.Millisecond)),
+		},
+		Data: &v1.SensorData_Struct{Struct: s},
+	}
+}
+
+func TestAggregateReadings(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	readings := []*v1.SensorData{
+		tabularReadingAt(t, start, map[string]interface{}{
+			"temp": 1, "name": "a", "position": map[string]interface{}{"x": 2, "y": -4},
+		}),
+		tabularReadingAt(t, start.Add(10*time.Millisecond), map[string]interface{}{
+			"temp": 2, "name": "b", "position": map[string]interface{}{"x": 4, "y": -8},
+		}),
+		tabularReadingAt(t, start.Add(20*time.Millisecond), map[string]interface{}{
+			"temp": 6, "name": "c", "position": map[string]interface{}{"x": 9, "y": -12}, "humidity": 40,
+		}),
+	}
+
+	mean := aggregateReadings(readings, AggregateMean)
+	test.That(t, mean.GetStruct().AsMap(), test.ShouldResemble, map[string]interface{}{
+		"temp": 3.0, "name": "c", "position": map[string]interface{}{"x": 5.0, "y": -8.0}, "humidity": 40.0,
+	})
+	test.That(t, mean.GetMetadata().GetTimeRequested().AsTime(), test.ShouldEqual, start)
+	test.That(t, mean.GetMetadata().GetTimeReceived().AsTime(), test.ShouldEqual, start.Add(21*time.Millisecond))
+
+	largest := aggregateReadings(readings, AggregateMax)
+	test.That(t, largest.GetStruct().AsMap(), test.ShouldResemble, map[string]interface{}{
+		"temp": 6.0, "name": "c", "position": map[string]interface{}{"x": 9.0, "y": -4.0}, "humidity": 40.0,
+	})
+
+	last := aggregateReadings(readings, AggregateLast)
+	test.That(t, last.GetStruct(), test.ShouldEqual, readings[2].GetStruct())
+
+	// A field that is not a number in every reading cannot be averaged, so its last value is kept.
+	mixed := aggregateReadings([]*v1.SensorData{
+		tabularReadingAt(t, start, map[string]interface{}{"value": 1}),
+		tabularReadingAt(t, start, map[string]interface{}{"value": "unknown"}),
+	}, AggregateMean)
+	test.That(t, mixed.GetStruct().AsMap(), test.ShouldResemble, map[string]interface{}{"value": "unknown"})
+}
+
+func TestAggregatingWriter(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	target := &recordingWriter{}
+	w := newAggregatingWriter(target, 100*time.Millisecond, "")
+
+	for i, temp := range []float64{1, 2, 3, 10, 20} {
+		// The first three readings are in the first window and the last two in the second.
+		at := start.Add(time.Duration(i) * 40 * time.Millisecond)
+		test.That(t, w.Write(tabularReadingAt(t, at, map[string]interface{}{"temp": temp})), test.ShouldBeNil)
+	}
+	test.That(t, target.written, test.ShouldHaveLength, 1)
+	test.That(t, target.written[0].GetStruct().AsMap(), test.ShouldResemble, map[string]interface{}{"temp": 2.0})
+
+	binary := &v1.SensorData{Metadata: &v1.SensorMetadata{}, Data: &v1.SensorData_Binary{Binary: []byte("image")}}
+	test.That(t, w.Write(binary), test.ShouldBeNil)
+	test.That(t, target.written, test.ShouldHaveLength, 2)
+	test.That(t, target.written[1], test.ShouldEqual, binary)
+
+	// Flushing writes the readings of the current window.
+	test.That(t, w.Flush(), test.ShouldBeNil)
+	test.That(t, target.flushes, test.ShouldEqual, 1)
+	test.That(t, target.written, test.ShouldHaveLength, 3)
+	test.That(t, target.written[2].GetStruct().AsMap(), test.ShouldResemble, map[string]interface{}{"temp": 15.0})
+	test.That(t, w.Flush(), test.ShouldBeNil)
+	test.That(t, target.written, test.ShouldHaveLength, 3)
+}
+
+func TestCollectorParamsAggregation(t *testing.T) {
+	params := CollectorParams{
+		ComponentName:   "sensor",
+		Target:          &recordingWriter{},
+		Logger:          logging.NewTestLogger(t),
+		AggregateWindow: time.Second,
+		AggregateFn:     "median",
+	}
+	test.That(t, params.Validate(), test.ShouldNotBeNil)
+	params.AggregateFn = AggregateMax
+	test.That(t, params.Validate(), test.ShouldBeNil)
+	params.AggregateWindow = -time.Second
+	test.That(t, params.Validate(), test.ShouldNotBeNil)
+}
@@ -5,6 +5,7 @@ package data
 import (
 	"context"
 	"fmt"
+	"math"
 	"reflect"
 	"sync"
 	"time"
@@ -58,6 +59,7 @@ type collector struct {
 	captureResults chan *v1.SensorData
 	captureErrors  chan error
 	interval       time.Duration
+	frequencyHz    float64
 	params         map[string]*anypb.Any
 	lock           sync.Mutex
 	logger         logging.Logger
@@ -139,8 +141,9 @@ func (c *collector) capture(started chan struct{}) {
 }
 
 func (c *collector) sleepBasedCapture(started chan struct{}) {
-	next := c.clock.Now().Add(c.interval)
-	until := c.clock.Until(next)
+	start := c.clock.Now()
+	captures := int64(1)
+	until := c.clock.Until(c.captureDeadline(start, captures))
 	var captureWorkers sync.WaitGroup
 
 	close(started)
@@ -165,11 +168,20 @@ func (c *collector) sleepBasedCapture(started chan struct{}) {
 				c.getAndPushNextReading()
 			})
 		}
-		next = next.Add(c.interval)
-		until = c.clock.Until(next)
+		captures++
+		until = c.clock.Until(c.captureDeadline(start, captures))
 	}
 }
 
+// captureDeadline returns when the nth capture after start is due. Deadlines are computed from start rather than
+// from the previous deadline so that, given the exact capture frequency, rounding errors do not accumulate.
+func (c *collector) captureDeadline(start time.Time, n int64) time.Time {
+	if c.frequencyHz <= 0 {
+		return start.Add(time.Duration(n) * c.interval)
+	}
+	return start.Add(time.Duration(math.Round(float64(n) * float64(time.Second) / c.frequencyHz)))
+}
+
 func (c *collector) tickerBasedCapture(started chan struct{}) {
 	ticker := c.clock.Ticker(c.interval)
 	defer ticker.Stop()
@@ -279,16 +291,21 @@ func NewCollector(captureFunc CaptureFunc, params CollectorParams) (Collector, e
 	} else {
 		c = params.Clock
 	}
+	target := params.Target
+	if params.AggregateWindow > 0 {
+		target = newAggregatingWriter(target, params.AggregateWindow, params.AggregateFn)
+	}
 	return &collector{
 		captureResults: make(chan *v1.SensorData, params.QueueSize),
 		captureErrors:  make(chan error, params.QueueSize),
 		interval:       params.Interval,
+		frequencyHz:    params.CaptureFrequencyHz,
 		params:         params.MethodParams,
 		logger:         params.Logger,
 		cancelCtx:      cancelCtx,
 		cancel:         cancelFunc,
 		captureFunc:    captureFunc,
-		target:         params.Target,
+		target:         target,
 		clock:          c,
 		closed:         false,
 	}, nil
@@ -3,6 +3,7 @@ package data
 import (
 	"context"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
 	"sync"
@@ -325,3 +326,36 @@ func (b *signalingBuffer) Flush() error {
 func (b *signalingBuffer) Path() string {
 	return b.bw.Path()
 }
+
+func TestCaptureDeadline(t *testing.T) {
+	start := time.Unix(0, 0)
+	// 30kHz does not divide a second into a whole number of nanoseconds.
+	c := &collector{interval: 33333 * time.Nanosecond}
+	test.That(t, c.captureDeadline(start, 30000).Sub(start), test.ShouldEqual, 999990000*time.Nanosecond)
+
+	c.frequencyHz = 30000
+	test.That(t, c.captureDeadline(start, 1).Sub(start), test.ShouldEqual, 33333*time.Nanosecond)
+	test.That(t, c.captureDeadline(start, 2).Sub(start), test.ShouldEqual, 66667*time.Nanosecond)
+	test.That(t, c.captureDeadline(start, 30000).Sub(start), test.ShouldEqual, time.Second)
+}
+
+// BenchmarkCaptureDeadlineDrift reports how far the deadline of the last capture in an hour is from an hour
+// after the first, when deadlines are computed from the rounded interval and from the exact frequency.
+func BenchmarkCaptureDeadlineDrift(b *testing.B) {
+	start := time.Unix(0, 0)
+	for _, hz := range []float64{3, 7000, 10000, 30000} {
+		b.Run(fmt.Sprintf("%vHz", hz), func(b *testing.B) {
+			interval := time.Duration(float64(time.Second) / hz)
+			rounded := &collector{interval: interval}
+			exact := &collector{interval: interval, frequencyHz: hz}
+			captures := int64(hz * 3600)
+			var roundedDrift, exactDrift time.Duration
+			for i := 0; i < b.N; i++ {
+				roundedDrift = rounded.captureDeadline(start, captures).Sub(start) - time.Hour
+				exactDrift = exact.captureDeadline(start, captures).Sub(start) - time.Hour
+			}
+			b.ReportMetric(math.Abs(float64(roundedDrift)), "ns-interval-drift/hour")
+			b.ReportMetric(math.Abs(float64(exactDrift)), "ns-frequency-drift/hour")
+		})
+	}
+}
@@ -21,12 +21,20 @@ type CollectorConstructor func(resource interface{}, params CollectorParams) (Co
 type CollectorParams struct {
 	ComponentName string
 	Interval      time.Duration
-	MethodParams  map[string]*anypb.Any
-	Target        datacapture.BufferedWriter
-	QueueSize     int
-	BufferSize    int
-	Logger        logging.Logger
-	Clock         clock.Clock
+	// CaptureFrequencyHz, if set, is the exact frequency Interval was derived from. Captures below the sleep
+	// based capture cutoff are then scheduled at exact multiples of 1/CaptureFrequencyHz from the first one, so
+	// that the fraction of a nanosecond that Interval was rounded by does not accumulate into drift.
+	CaptureFrequencyHz float64
+	MethodParams       map[string]*anypb.Any
+	Target             datacapture.BufferedWriter
+	QueueSize          int
+	BufferSize         int
+	Logger             logging.Logger
+	Clock              clock.Clock
+	// AggregateWindow, if positive, is the window over which tabular readings are combined with AggregateFn
+	// into one before they are written to Target. Readings are written as they are captured if it is zero.
+	AggregateWindow time.Duration
+	AggregateFn     AggregateFn
 }
 
 // Validate validates that p contains all required parameters.
@@ -40,6 +48,12 @@ func (p CollectorParams) Validate() error {
 	if p.ComponentName == "" {
 		return errors.New("missing required parameter component name")
 	}
+	if p.AggregateWindow < 0 {
+		return errors.New("aggregate window must not be negative")
+	}
+	if err := p.AggregateFn.Validate(); err != nil {
+		return err
+	}
 	return nil
 }
 
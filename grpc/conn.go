@@ -3,16 +3,59 @@ package grpc
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
 	"sync"
+	"time"
 
+	"go.uber.org/multierr"
+	goutils "go.viam.com/utils"
 	"go.viam.com/utils/rpc"
 	googlegrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
 )
 
+const (
+	reconnectAttempts       = 3
+	reconnectInitialBackoff = 100 * time.Millisecond
+)
+
+var errNotConnected = errors.New("not connected")
+
+// CallHook is called after every unary call and stream made through a ReconfigurableClientConn with the
+// full method name, how long the call took, and the error it finished with, if any. For streams, the
+// duration is from when the stream was created until it finished receiving.
+type CallHook func(method string, dur time.Duration, err error)
+
+// NoopCallHook is a CallHook that does nothing. Calls are not timed at all until a hook is set.
+func NoopCallHook(method string, dur time.Duration, err error) {}
+
 // ReconfigurableClientConn allows for the underlying client connections to be swapped under the hood.
 type ReconfigurableClientConn struct {
-	connMu sync.RWMutex
-	conn   rpc.ClientConn
+	connMu          sync.RWMutex
+	conn            rpc.ClientConn
+	closed          bool
+	onConnChangeFns []func(connected bool)
+	callHook        CallHook
+	defaultCallOpts []googlegrpc.CallOption
+
+	// dialer, if set, is used to reconnect when a call fails because the connection is unavailable.
+	// reconnectMu ensures only one reconnect is attempted at a time.
+	dialer      func(ctx context.Context) (rpc.ClientConn, error)
+	reconnectMu sync.Mutex
+
+	// inFlight is the number of calls and streams in progress and idle is closed whenever it drops to zero.
+	inFlightMu sync.Mutex
+	inFlight   int
+	idle       chan struct{}
+}
+
+// NewReconfigurableClientConn returns a ReconfigurableClientConn that uses dialer to connect on first use and to
+// reconnect, with backoff, whenever a call fails because the underlying client connection is unavailable.
+func NewReconfigurableClientConn(dialer func(ctx context.Context) (rpc.ClientConn, error)) *ReconfigurableClientConn {
+	return &ReconfigurableClientConn{dialer: dialer}
 }
 
 // Invoke invokes using the underlying client connection. In the case of c.conn being closed in the middle of
@@ -23,11 +66,35 @@ func (c *ReconfigurableClientConn) Invoke(
 	args, reply interface{},
 	opts ...googlegrpc.CallOption,
 ) error {
-	c.connMu.RLock()
-	conn := c.conn
-	c.connMu.RUnlock()
-	if conn == nil {
-		return errors.New("not connected")
+	c.startCall()
+	defer c.endCall()
+	hook := c.getCallHook()
+	if hook == nil {
+		return c.invoke(ctx, method, args, reply, opts...)
+	}
+	start := time.Now()
+	err := c.invoke(ctx, method, args, reply, opts...)
+	hook(method, time.Since(start), err)
+	return err
+}
+
+func (c *ReconfigurableClientConn) invoke(
+	ctx context.Context,
+	method string,
+	args, reply interface{},
+	opts ...googlegrpc.CallOption,
+) error {
+	conn, err := c.getConn(ctx)
+	if err != nil {
+		return err
+	}
+	opts = c.withDefaultCallOptions(opts)
+	err = conn.Invoke(ctx, method, args, reply, opts...)
+	if !c.shouldReconnect(err) {
+		return err
+	}
+	if conn, err = c.reconnect(ctx, conn); err != nil {
+		return err
 	}
 	return conn.Invoke(ctx, method, args, reply, opts...)
 }
@@ -40,13 +107,281 @@ func (c *ReconfigurableClientConn) NewStream(
 	method string,
 	opts ...googlegrpc.CallOption,
 ) (googlegrpc.ClientStream, error) {
+	// A stream is in flight until its context is done, so one whose context can never be done is not counted.
+	tracked := ctx.Done() != nil
+	if tracked {
+		c.startCall()
+	}
+	hook := c.getCallHook()
+	var start time.Time
+	if hook != nil {
+		start = time.Now()
+	}
+	stream, err := c.newStream(ctx, desc, method, opts...)
+	if err != nil {
+		if tracked {
+			c.endCall()
+		}
+		if hook != nil {
+			hook(method, time.Since(start), err)
+		}
+		return nil, err
+	}
+	if tracked {
+		goutils.PanicCapturingGo(func() {
+			<-ctx.Done()
+			c.endCall()
+		})
+	}
+	if hook == nil {
+		return stream, nil
+	}
+	return &hookedClientStream{ClientStream: stream, done: func(err error) {
+		hook(method, time.Since(start), err)
+	}}, nil
+}
+
+func (c *ReconfigurableClientConn) newStream(
+	ctx context.Context,
+	desc *googlegrpc.StreamDesc,
+	method string,
+	opts ...googlegrpc.CallOption,
+) (googlegrpc.ClientStream, error) {
+	conn, err := c.getConn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	opts = c.withDefaultCallOptions(opts)
+	stream, err := conn.NewStream(ctx, desc, method, opts...)
+	if !c.shouldReconnect(err) {
+		return stream, err
+	}
+	if conn, err = c.reconnect(ctx, conn); err != nil {
+		return nil, err
+	}
+	return conn.NewStream(ctx, desc, method, opts...)
+}
+
+// getConn returns the underlying client connection, dialing one if there is none and c has a dialer and
+// has not been closed.
+func (c *ReconfigurableClientConn) getConn(ctx context.Context) (rpc.ClientConn, error) {
+	c.connMu.RLock()
+	conn, closed := c.conn, c.closed
+	c.connMu.RUnlock()
+	if conn != nil {
+		return conn, nil
+	}
+	if c.dialer == nil || closed {
+		return nil, errNotConnected
+	}
+	return c.reconnect(ctx, nil)
+}
+
+// shouldReconnect returns whether err indicates that the underlying client connection is unavailable and
+// c is able to replace it.
+func (c *ReconfigurableClientConn) shouldReconnect(err error) bool {
+	return c.dialer != nil && err != nil && status.Code(err) == codes.Unavailable
+}
+
+// reconnect replaces the failed client connection with a newly dialed one, retrying with exponential backoff.
+// If another caller already replaced failed, the current connection is returned without dialing, so
+// concurrent failures result in at most one reconnect.
+func (c *ReconfigurableClientConn) reconnect(ctx context.Context, failed rpc.ClientConn) (rpc.ClientConn, error) {
+	c.reconnectMu.Lock()
+	defer c.reconnectMu.Unlock()
+
+	c.connMu.RLock()
+	current, closed := c.conn, c.closed
+	c.connMu.RUnlock()
+	if closed {
+		return nil, errNotConnected
+	}
+	if current != nil && current != failed {
+		return current, nil
+	}
+
+	var conn rpc.ClientConn
+	var err error
+	backoff := reconnectInitialBackoff
+	for attempt := 0; attempt < reconnectAttempts; attempt++ {
+		if attempt > 0 {
+			timer := time.NewTimer(backoff)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, ctx.Err()
+			case <-timer.C:
+			}
+			backoff *= 2
+		}
+		if conn, err = c.dialer(ctx); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c.connMu.Lock()
+	if c.closed {
+		c.connMu.Unlock()
+		//nolint:errcheck
+		_ = conn.Close()
+		return nil, errNotConnected
+	}
+	c.conn = conn
+	fns := c.onConnChangeFns
+	c.connMu.Unlock()
+	if failed != nil {
+		//nolint:errcheck
+		_ = failed.Close()
+	}
+	notifyConnChange(fns, true)
+	return conn, nil
+}
+
+// SetCallHook sets the hook called after every call made through c, for example to record metrics. It is called
+// without any locks held. A nil hook is the same as NoopCallHook.
+func (c *ReconfigurableClientConn) SetCallHook(hook CallHook) {
+	c.connMu.Lock()
+	c.callHook = hook
+	c.connMu.Unlock()
+}
+
+// getCallHook returns the hook set with SetCallHook, or nil if there is none.
+func (c *ReconfigurableClientConn) getCallHook() CallHook {
+	c.connMu.RLock()
+	defer c.connMu.RUnlock()
+	return c.callHook
+}
+
+// SetDefaultCallOptions sets the call options used by every call and stream made through c, such as a larger
+// maximum receive size for large camera frames. The defaults come before the options passed to each call, and
+// gRPC applies call options in order, so an option passed to a call overrides a default of the same kind while
+// options that accumulate, such as per-RPC credentials, are added to the defaults. Setting no options removes
+// the defaults.
+func (c *ReconfigurableClientConn) SetDefaultCallOptions(opts ...googlegrpc.CallOption) {
+	c.connMu.Lock()
+	c.defaultCallOpts = append([]googlegrpc.CallOption(nil), opts...)
+	c.connMu.Unlock()
+}
+
+// withDefaultCallOptions returns opts preceded by the options set with SetDefaultCallOptions.
+func (c *ReconfigurableClientConn) withDefaultCallOptions(opts []googlegrpc.CallOption) []googlegrpc.CallOption {
+	c.connMu.RLock()
+	defaults := c.defaultCallOpts
+	c.connMu.RUnlock()
+	if len(defaults) == 0 {
+		return opts
+	}
+	return append(append(make([]googlegrpc.CallOption, 0, len(defaults)+len(opts)), defaults...), opts...)
+}
+
+// hookedClientStream calls done once, with a nil error if the stream ended normally, when the stream finishes.
+type hookedClientStream struct {
+	googlegrpc.ClientStream
+	once sync.Once
+	done func(err error)
+}
+
+func (s *hookedClientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		s.once.Do(func() {
+			if errors.Is(err, io.EOF) {
+				s.done(nil)
+				return
+			}
+			s.done(err)
+		})
+	}
+	return err
+}
+
+// startCall counts a call or stream as in flight.
+func (c *ReconfigurableClientConn) startCall() {
+	c.inFlightMu.Lock()
+	defer c.inFlightMu.Unlock()
+	if c.inFlight == 0 {
+		c.idle = make(chan struct{})
+	}
+	c.inFlight++
+}
+
+// endCall counts a call or stream as no longer in flight.
+func (c *ReconfigurableClientConn) endCall() {
+	c.inFlightMu.Lock()
+	defer c.inFlightMu.Unlock()
+	c.inFlight--
+	if c.inFlight == 0 {
+		close(c.idle)
+	}
+}
+
+// InFlight returns the number of calls and streams made through c that are in progress. A call is in flight
+// until it returns and a stream until its context is done; streams whose context can never be done are not
+// counted.
+func (c *ReconfigurableClientConn) InFlight() int {
+	c.inFlightMu.Lock()
+	defer c.inFlightMu.Unlock()
+	return c.inFlight
+}
+
+// CloseWhenIdle waits for there to be no calls or streams in flight, as counted by InFlight, and then closes c.
+// If ctx is done first, c is closed anyway and ctx's error is returned along with any error closing c.
+func (c *ReconfigurableClientConn) CloseWhenIdle(ctx context.Context) error {
+	c.inFlightMu.Lock()
+	inFlight, idle := c.inFlight, c.idle
+	c.inFlightMu.Unlock()
+	if inFlight > 0 {
+		select {
+		case <-idle:
+		case <-ctx.Done():
+			return multierr.Combine(ctx.Err(), c.Close())
+		}
+	}
+	return c.Close()
+}
+
+// IsConnected returns whether there is currently an underlying client connection.
+func (c *ReconfigurableClientConn) IsConnected() bool {
+	c.connMu.RLock()
+	defer c.connMu.RUnlock()
+	return c.conn != nil
+}
+
+// Ping checks that the server at the other end of the underlying client connection is responding by making a
+// gRPC health check through it, within ctx's deadline. Unlike other calls, it never dials or reconnects, so it
+// distinguishes having a connection from the server on the other end being up. A server that does not implement
+// the health service still responds, so it is considered healthy.
+func (c *ReconfigurableClientConn) Ping(ctx context.Context) error {
 	c.connMu.RLock()
 	conn := c.conn
 	c.connMu.RUnlock()
 	if conn == nil {
-		return nil, errors.New("not connected")
+		return errNotConnected
 	}
-	return conn.NewStream(ctx, desc, method, opts...)
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	if status.Code(err) == codes.Unimplemented {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("server is not serving: %s", resp.GetStatus())
+	}
+	return nil
+}
+
+// OnConnectionChange registers fn to be called whenever ReplaceConn swaps the underlying client connection
+// or Close removes it. fn is passed whether there is an underlying client connection afterwards. It is
+// called without any locks held, so it may call back into c, but it may be called concurrently if the
+// connection is changed concurrently.
+func (c *ReconfigurableClientConn) OnConnectionChange(fn func(connected bool)) {
+	c.connMu.Lock()
+	c.onConnChangeFns = append(c.onConnChangeFns, fn)
+	c.connMu.Unlock()
 }
 
 // ReplaceConn replaces the underlying client connection with the connection passed in. This does not close the
@@ -54,17 +389,31 @@ func (c *ReconfigurableClientConn) NewStream(
 func (c *ReconfigurableClientConn) ReplaceConn(conn rpc.ClientConn) {
 	c.connMu.Lock()
 	c.conn = conn
+	c.closed = false
+	fns := c.onConnChangeFns
 	c.connMu.Unlock()
+	notifyConnChange(fns, conn != nil)
 }
 
 // Close attempts to close the underlying client connection if there is one.
 func (c *ReconfigurableClientConn) Close() error {
 	c.connMu.Lock()
-	defer c.connMu.Unlock()
+	c.closed = true
 	if c.conn == nil {
+		c.connMu.Unlock()
 		return nil
 	}
 	conn := c.conn
 	c.conn = nil
-	return conn.Close()
+	fns := c.onConnChangeFns
+	err := conn.Close()
+	c.connMu.Unlock()
+	notifyConnChange(fns, false)
+	return err
+}
+
+func notifyConnChange(fns []func(connected bool), connected bool) {
+	for _, fn := range fns {
+		fn(connected)
+	}
 }
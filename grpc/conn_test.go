@@ -0,0 +1,408 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+	"go.viam.com/utils/rpc"
+	"go.viam.com/utils/testutils"
+	googlegrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+type fakeClientConn struct {
+	invokeErr error
+	closed    bool
+}
+
+func (c *fakeClientConn) Invoke(
+	ctx context.Context,
+	method string,
+	args, reply interface{},
+	opts ...googlegrpc.CallOption,
+) error {
+	return c.invokeErr
+}
+
+func (c *fakeClientConn) NewStream(
+	ctx context.Context,
+	desc *googlegrpc.StreamDesc,
+	method string,
+	opts ...googlegrpc.CallOption,
+) (googlegrpc.ClientStream, error) {
+	return nil, c.invokeErr
+}
+
+func (c *fakeClientConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestReconfigurableClientConnIsConnected(t *testing.T) {
+	var conn ReconfigurableClientConn
+	test.That(t, conn.IsConnected(), test.ShouldBeFalse)
+
+	var changes []bool
+	conn.OnConnectionChange(func(connected bool) {
+		// Calling back into the connection must not deadlock.
+		test.That(t, conn.IsConnected(), test.ShouldEqual, connected)
+		changes = append(changes, connected)
+	})
+
+	fake := &fakeClientConn{}
+	conn.ReplaceConn(fake)
+	test.That(t, conn.IsConnected(), test.ShouldBeTrue)
+	test.That(t, conn.Invoke(context.Background(), "/method", nil, nil), test.ShouldBeNil)
+
+	test.That(t, conn.Close(), test.ShouldBeNil)
+	test.That(t, fake.closed, test.ShouldBeTrue)
+	test.That(t, conn.IsConnected(), test.ShouldBeFalse)
+	err := conn.Invoke(context.Background(), "/method", nil, nil)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "not connected")
+
+	// Closing an already closed connection is not a change.
+	test.That(t, conn.Close(), test.ShouldBeNil)
+	test.That(t, changes, test.ShouldResemble, []bool{true, false})
+}
+
+func TestReconfigurableClientConnReconnect(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "connection closed")
+
+	t.Run("dials on first use and reconnects once for concurrent failures", func(t *testing.T) {
+		var dials atomic.Int32
+		dialer := func(ctx context.Context) (rpc.ClientConn, error) {
+			if dials.Add(1) == 1 {
+				return &fakeClientConn{invokeErr: unavailable}, nil
+			}
+			return &fakeClientConn{}, nil
+		}
+		conn := NewReconfigurableClientConn(dialer)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				test.That(t, conn.Invoke(context.Background(), "/method", nil, nil), test.ShouldBeNil)
+			}()
+		}
+		wg.Wait()
+		test.That(t, dials.Load(), test.ShouldEqual, 2)
+
+		_, err := conn.NewStream(context.Background(), &googlegrpc.StreamDesc{}, "/method")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, dials.Load(), test.ShouldEqual, 2)
+	})
+
+	t.Run("reconnect errors are returned", func(t *testing.T) {
+		dialErr := errors.New("dial failed")
+		var dials atomic.Int32
+		conn := NewReconfigurableClientConn(func(ctx context.Context) (rpc.ClientConn, error) {
+			dials.Add(1)
+			return nil, dialErr
+		})
+		conn.ReplaceConn(&fakeClientConn{invokeErr: unavailable})
+		test.That(t, conn.Invoke(context.Background(), "/method", nil, nil), test.ShouldBeError, dialErr)
+		test.That(t, dials.Load(), test.ShouldEqual, reconnectAttempts)
+	})
+
+	t.Run("other errors do not reconnect", func(t *testing.T) {
+		invalid := status.Error(codes.InvalidArgument, "bad request")
+		conn := NewReconfigurableClientConn(func(ctx context.Context) (rpc.ClientConn, error) {
+			t.Fatal("should not dial")
+			return nil, nil
+		})
+		conn.ReplaceConn(&fakeClientConn{invokeErr: invalid})
+		test.That(t, conn.Invoke(context.Background(), "/method", nil, nil), test.ShouldBeError, invalid)
+	})
+
+	t.Run("closed connections do not reconnect", func(t *testing.T) {
+		conn := NewReconfigurableClientConn(func(ctx context.Context) (rpc.ClientConn, error) {
+			t.Fatal("should not dial")
+			return nil, nil
+		})
+		test.That(t, conn.Close(), test.ShouldBeNil)
+		err := conn.Invoke(context.Background(), "/method", nil, nil)
+		test.That(t, err, test.ShouldBeError, errNotConnected)
+	})
+
+	t.Run("without a dialer errors are returned as is", func(t *testing.T) {
+		var conn ReconfigurableClientConn
+		conn.ReplaceConn(&fakeClientConn{invokeErr: unavailable})
+		test.That(t, conn.Invoke(context.Background(), "/method", nil, nil), test.ShouldBeError, unavailable)
+	})
+}
+
+type fakeClientStream struct {
+	googlegrpc.ClientStream
+	recvErrs []error
+}
+
+func (s *fakeClientStream) RecvMsg(m interface{}) error {
+	err := s.recvErrs[0]
+	s.recvErrs = s.recvErrs[1:]
+	return err
+}
+
+type fakeStreamClientConn struct {
+	fakeClientConn
+	stream *fakeClientStream
+}
+
+func (c *fakeStreamClientConn) NewStream(
+	ctx context.Context,
+	desc *googlegrpc.StreamDesc,
+	method string,
+	opts ...googlegrpc.CallOption,
+) (googlegrpc.ClientStream, error) {
+	return c.stream, nil
+}
+
+func TestReconfigurableClientConnCallHook(t *testing.T) {
+	type call struct {
+		method string
+		err    error
+	}
+	var calls []call
+	var conn ReconfigurableClientConn
+	conn.SetCallHook(func(method string, dur time.Duration, err error) {
+		// The hook must not be called with the connection lock held.
+		test.That(t, conn.IsConnected(), test.ShouldBeTrue)
+		test.That(t, dur, test.ShouldBeGreaterThanOrEqualTo, 0)
+		calls = append(calls, call{method, err})
+	})
+
+	invokeErr := errors.New("invoke failed")
+	fake := &fakeStreamClientConn{
+		fakeClientConn: fakeClientConn{invokeErr: invokeErr},
+		stream:         &fakeClientStream{recvErrs: []error{nil, io.EOF, io.EOF}},
+	}
+	conn.ReplaceConn(fake)
+
+	test.That(t, conn.Invoke(context.Background(), "/unary", nil, nil), test.ShouldBeError, invokeErr)
+
+	stream, err := conn.NewStream(context.Background(), &googlegrpc.StreamDesc{}, "/stream")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, stream.RecvMsg(nil), test.ShouldBeNil)
+	test.That(t, len(calls), test.ShouldEqual, 1)
+	test.That(t, stream.RecvMsg(nil), test.ShouldEqual, io.EOF)
+	test.That(t, stream.RecvMsg(nil), test.ShouldEqual, io.EOF)
+
+	test.That(t, calls, test.ShouldResemble, []call{{"/unary", invokeErr}, {"/stream", nil}})
+
+	// Without a hook, streams are not wrapped.
+	conn.SetCallHook(nil)
+	stream, err = conn.NewStream(context.Background(), &googlegrpc.StreamDesc{}, "/stream")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, stream, test.ShouldEqual, fake.stream)
+}
+
+// healthClientConn answers health checks with status, or fails them with invokeErr.
+type healthClientConn struct {
+	fakeClientConn
+	status  healthpb.HealthCheckResponse_ServingStatus
+	methods []string
+}
+
+func (c *healthClientConn) Invoke(
+	ctx context.Context,
+	method string,
+	args, reply interface{},
+	opts ...googlegrpc.CallOption,
+) error {
+	c.methods = append(c.methods, method)
+	if err := ctx.Err(); err != nil {
+		return status.FromContextError(err).Err()
+	}
+	if c.invokeErr != nil {
+		return c.invokeErr
+	}
+	reply.(*healthpb.HealthCheckResponse).Status = c.status
+	return nil
+}
+
+func TestReconfigurableClientConnPing(t *testing.T) {
+	var dials int
+	conn := NewReconfigurableClientConn(func(ctx context.Context) (rpc.ClientConn, error) {
+		dials++
+		return &fakeClientConn{}, nil
+	})
+	// Pinging never dials.
+	test.That(t, conn.Ping(context.Background()), test.ShouldBeError, errNotConnected)
+	test.That(t, dials, test.ShouldEqual, 0)
+
+	fake := &healthClientConn{status: healthpb.HealthCheckResponse_SERVING}
+	conn.ReplaceConn(fake)
+	test.That(t, conn.Ping(context.Background()), test.ShouldBeNil)
+	test.That(t, fake.methods, test.ShouldResemble, []string{healthpb.Health_Check_FullMethodName})
+
+	fake.status = healthpb.HealthCheckResponse_NOT_SERVING
+	err := conn.Ping(context.Background())
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "NOT_SERVING")
+
+	// A server without the health service is up, while one that cannot be reached is not, and is not
+	// reconnected to.
+	fake.invokeErr = status.Error(codes.Unimplemented, "unknown service grpc.health.v1.Health")
+	test.That(t, conn.Ping(context.Background()), test.ShouldBeNil)
+	fake.invokeErr = status.Error(codes.Unavailable, "connection refused")
+	test.That(t, status.Code(conn.Ping(context.Background())), test.ShouldEqual, codes.Unavailable)
+	test.That(t, dials, test.ShouldEqual, 0)
+
+	fake.invokeErr = nil
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+	test.That(t, status.Code(conn.Ping(ctx)), test.ShouldEqual, codes.DeadlineExceeded)
+}
+
+// optsClientConn records the call options of each call made through it.
+type optsClientConn struct {
+	fakeClientConn
+	opts [][]googlegrpc.CallOption
+}
+
+func (c *optsClientConn) Invoke(
+	ctx context.Context,
+	method string,
+	args, reply interface{},
+	opts ...googlegrpc.CallOption,
+) error {
+	c.opts = append(c.opts, opts)
+	return c.invokeErr
+}
+
+func (c *optsClientConn) NewStream(
+	ctx context.Context,
+	desc *googlegrpc.StreamDesc,
+	method string,
+	opts ...googlegrpc.CallOption,
+) (googlegrpc.ClientStream, error) {
+	c.opts = append(c.opts, opts)
+	return nil, c.invokeErr
+}
+
+func TestReconfigurableClientConnDefaultCallOptions(t *testing.T) {
+	var conn ReconfigurableClientConn
+	fake := &optsClientConn{}
+	conn.ReplaceConn(fake)
+
+	maxRecv := googlegrpc.MaxCallRecvMsgSize(1 << 24)
+	waitForReady := googlegrpc.WaitForReady(true)
+	perCall := googlegrpc.MaxCallRecvMsgSize(1 << 10)
+
+	test.That(t, conn.Invoke(context.Background(), "/unary", nil, nil, perCall), test.ShouldBeNil)
+	test.That(t, fake.opts[0], test.ShouldResemble, []googlegrpc.CallOption{perCall})
+
+	defaults := []googlegrpc.CallOption{maxRecv, waitForReady}
+	conn.SetDefaultCallOptions(defaults...)
+	// Changing the caller's slice afterwards does not change the defaults.
+	defaults[0] = nil
+
+	test.That(t, conn.Invoke(context.Background(), "/unary", nil, nil, perCall), test.ShouldBeNil)
+	test.That(t, fake.opts[1], test.ShouldResemble, []googlegrpc.CallOption{maxRecv, waitForReady, perCall})
+	_, err := conn.NewStream(context.Background(), &googlegrpc.StreamDesc{}, "/stream")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fake.opts[2], test.ShouldResemble, []googlegrpc.CallOption{maxRecv, waitForReady})
+
+	conn.SetDefaultCallOptions()
+	test.That(t, conn.Invoke(context.Background(), "/unary", nil, nil), test.ShouldBeNil)
+	test.That(t, fake.opts[3], test.ShouldBeEmpty)
+}
+
+// blockingClientConn blocks calls until release is closed.
+type blockingClientConn struct {
+	fakeClientConn
+	release chan struct{}
+}
+
+func (c *blockingClientConn) Invoke(
+	ctx context.Context,
+	method string,
+	args, reply interface{},
+	opts ...googlegrpc.CallOption,
+) error {
+	<-c.release
+	return nil
+}
+
+func (c *blockingClientConn) NewStream(
+	ctx context.Context,
+	desc *googlegrpc.StreamDesc,
+	method string,
+	opts ...googlegrpc.CallOption,
+) (googlegrpc.ClientStream, error) {
+	return &fakeClientStream{}, c.invokeErr
+}
+
+func TestReconfigurableClientConnInFlight(t *testing.T) {
+	var conn ReconfigurableClientConn
+	fake := &blockingClientConn{release: make(chan struct{})}
+	conn.ReplaceConn(fake)
+	test.That(t, conn.InFlight(), test.ShouldEqual, 0)
+
+	const numCalls = 50
+	var started, finished sync.WaitGroup
+	started.Add(numCalls)
+	finished.Add(numCalls)
+	for i := 0; i < numCalls; i++ {
+		go func() {
+			defer finished.Done()
+			started.Done()
+			test.That(t, conn.Invoke(context.Background(), "/unary", nil, nil), test.ShouldBeNil)
+		}()
+	}
+	started.Wait()
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		test.That(tb, conn.InFlight(), test.ShouldEqual, numCalls)
+	})
+
+	// Streams count until their context is done, but not if it can never be done.
+	streamCtx, cancelStream := context.WithCancel(context.Background())
+	_, err := conn.NewStream(streamCtx, &googlegrpc.StreamDesc{}, "/stream")
+	test.That(t, err, test.ShouldBeNil)
+	_, err = conn.NewStream(context.Background(), &googlegrpc.StreamDesc{}, "/stream")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, conn.InFlight(), test.ShouldEqual, numCalls+1)
+
+	// Streams that fail to start are not in flight.
+	fake.invokeErr = errors.New("no stream")
+	_, err = conn.NewStream(streamCtx, &googlegrpc.StreamDesc{}, "/stream")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, conn.InFlight(), test.ShouldEqual, numCalls+1)
+
+	// Closing when idle gives up when its context expires, closing anyway.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	test.That(t, errors.Is(conn.CloseWhenIdle(ctx), context.DeadlineExceeded), test.ShouldBeTrue)
+	test.That(t, conn.IsConnected(), test.ShouldBeFalse)
+	test.That(t, fake.closed, test.ShouldBeTrue)
+
+	conn.ReplaceConn(fake)
+	fake.closed = false
+	closed := make(chan error, 1)
+	go func() {
+		closed <- conn.CloseWhenIdle(context.Background())
+	}()
+	close(fake.release)
+	finished.Wait()
+	test.That(t, conn.InFlight(), test.ShouldEqual, 1)
+	select {
+	case <-closed:
+		t.Fatal("closed with a stream in flight")
+	case <-time.After(10 * time.Millisecond):
+	}
+	cancelStream()
+	test.That(t, <-closed, test.ShouldBeNil)
+	test.That(t, conn.InFlight(), test.ShouldEqual, 0)
+	test.That(t, fake.closed, test.ShouldBeTrue)
+}
@@ -31,6 +31,9 @@ type ConnectionService interface {
 	resource.Resource
 	AcquireConnection(ctx context.Context) (string, rpc.ClientConn, error)
 	AcquireConnectionAPIKey(ctx context.Context, apiKey, apiKeyID string) (string, rpc.ClientConn, error)
+	// PartID returns the ID of the robot part the connections are made on behalf of, or the empty string
+	// if the robot is not cloud managed.
+	PartID() string
 }
 
 // NewCloudConnectionService makes a new cloud connection service to get gRPC connections
@@ -99,6 +102,13 @@ func (cm *cloudManagedService) AcquireConnectionAPIKey(ctx context.Context,
 	return cm.cloudCfg.ID, conn, err
 }
 
+func (cm *cloudManagedService) PartID() string {
+	if !cm.managed {
+		return ""
+	}
+	return cm.cloudCfg.ID
+}
+
 func (cm *cloudManagedService) Close(ctx context.Context) error {
 	cm.dialerMu.Lock()
 	defer cm.dialerMu.Unlock()
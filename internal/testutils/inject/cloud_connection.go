@@ -35,6 +35,11 @@ func (cloudConnService *CloudConnectionService) AcquireConnectionAPIKey(ctx cont
 	return "hello", cloudConnService.Conn, nil
 }
 
+// PartID returns the part ID that connections are acquired for.
+func (cloudConnService *CloudConnectionService) PartID() string {
+	return "hello"
+}
+
 // Close is used by the CloudConnectionService to complete the cloud.ConnectionService interface.
 func (cloudConnService *CloudConnectionService) Close(ctx context.Context) error {
 	return nil
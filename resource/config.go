@@ -372,9 +372,24 @@ func TransformAttributeMap[T any](attributes utils.AttributeMap) (T, error) {
 	return out, nil
 }
 
+// ConfigValidationError describes an invalid config object.
+type ConfigValidationError struct {
+	Path string
+	Err  error
+}
+
+func (cve ConfigValidationError) Error() string {
+	return fmt.Sprintf("Error validating. Path: %q Error: %s", cve.Path, cve.Err)
+}
+
+// Unwrap returns the underlying validation error.
+func (cve ConfigValidationError) Unwrap() error {
+	return cve.Err
+}
+
 // NewConfigValidationError returns a config validation error occurring at a given path.
 func NewConfigValidationError(path string, err error) error {
-	return fmt.Errorf("Error validating. Path: %q Error: %w", path, err)
+	return ConfigValidationError{path, err}
 }
 
 // FieldRequiredError describes a missing field on a config object.
@@ -2,6 +2,7 @@ package rimage
 
 import (
 	"fmt"
+	"image"
 	"image/color"
 	"math"
 
@@ -201,6 +202,27 @@ func (c Color) Hex() string {
 	return fmt.Sprintf("#%.2x%.2x%.2x", r, g, b)
 }
 
+// Luminance returns the relative luminance of the color, from 0 for black to 1 for white, using the Rec. 709
+// weights on the linearized sRGB components.
+func (c Color) Luminance() float64 {
+	r, g, b := c.RGB255()
+	return 0.2126*srgbToLinear(r) + 0.7152*srgbToLinear(g) + 0.0722*srgbToLinear(b)
+}
+
+// IsDark returns whether the luminance of the color is below threshold.
+func (c Color) IsDark(threshold float64) bool {
+	return c.Luminance() < threshold
+}
+
+// srgbToLinear undoes the sRGB gamma of a color component, returning linear light between 0 and 1.
+func srgbToLinear(x uint8) float64 {
+	v := float64(x) / 255
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
 // RGBA returns the non-alpha-premultiplied RGBA values of the color.
 func (c Color) RGBA() (r, g, b, a uint32) {
 	R, G, B := c.RGB255()
@@ -244,6 +266,81 @@ func (c Color) Closest(others []Color) (int, Color, float64) {
 	return bestIndex, best, bestDistance
 }
 
+// ClosestColor returns the color in Colors that is closest to c along with its distance from c.
+// Callers can compare the distance against a threshold to treat c as an unknown color.
+func ClosestColor(c Color) (Color, float64) {
+	return ClosestColorInPalette(c, Colors)
+}
+
+// ClosestColorInPalette returns the color in palette that is closest to c along with its distance from c.
+// It panics if palette is empty.
+func ClosestColorInPalette(c Color, palette []Color) (Color, float64) {
+	_, best, d := c.Closest(palette)
+	return best, d
+}
+
+// ColorRGB holds the RGB components of a color.
+type ColorRGB struct {
+	R uint8 `json:"r"`
+	G uint8 `json:"g"`
+	B uint8 `json:"b"`
+}
+
+// ColorHSV holds the normalized HSV components of a color. H is in degrees from 0 to 360 and S and V are
+// between 0 and 1.
+type ColorHSV struct {
+	H float64 `json:"h"`
+	S float64 `json:"s"`
+	V float64 `json:"v"`
+}
+
+// ColorDescription describes a color along with the closest of the named Colors, such as for logging why a
+// color was classified the way it was. Hex round-trips through NewColorFromHex.
+type ColorDescription struct {
+	// Name is the name of the color in Colors closest to the described color.
+	Name string   `json:"name"`
+	Hex  string   `json:"hex"`
+	RGB  ColorRGB `json:"rgb"`
+	HSV  ColorHSV `json:"hsv"`
+	// Distance is the distance from the described color to the named color.
+	Distance float64 `json:"distance"`
+}
+
+// Describe returns a serializable description of c and the named color closest to it.
+func (c Color) Describe() ColorDescription {
+	closest, d := ClosestColor(c)
+	r, g, b := c.RGB255()
+	h, s, v := c.HsvNormal()
+	return ColorDescription{
+		Name:     colorNames[closest],
+		Hex:      c.Hex(),
+		RGB:      ColorRGB{R: r, G: g, B: b},
+		HSV:      ColorHSV{H: h, S: s, V: v},
+		Distance: d,
+	}
+}
+
+// QuantizeImage returns a new image with every pixel of img replaced by the closest color in
+// palette, or in Colors if palette is empty.
+func QuantizeImage(img image.Image, palette []Color) *Image {
+	if len(palette) == 0 {
+		palette = Colors
+	}
+	src := ConvertImage(img)
+	dst := NewImage(src.Width(), src.Height())
+	// Frames usually contain far fewer distinct colors than pixels, so avoid recomputing distances.
+	closest := map[Color]Color{}
+	for i, c := range src.data {
+		q, ok := closest[c]
+		if !ok {
+			q, _ = ClosestColorInPalette(c, palette)
+			closest[c] = q
+		}
+		dst.data[i] = q
+	}
+	return dst
+}
+
 // a and b are between 0 and 1 but it's circular
 // so .999 and .001 are .002 apart.
 func _loopedDiff(a, b float64) float64 {
@@ -271,42 +368,90 @@ func (c Color) DistanceLab(b Color) float64 {
 	return c.toColorful().DistanceLab(b.toColorful())
 }
 
+// DistanceConfig holds the tunable parameters of the HSV distance between two colors. Values are
+// on the scaled HSV range returned by ScaleHSV, so hue, saturation, and value are all between 0 and 1.
+type DistanceConfig struct {
+	// HueWeight scales differences in hue. Larger values make smaller hue changes count as different colors.
+	HueWeight float64
+	// SaturationWeight scales differences in saturation.
+	SaturationWeight float64
+	// ValueWeight scales differences in value (brightness).
+	ValueWeight float64
+
+	// DarkValue is the value below which either color is considered dark. Hue and saturation are hard
+	// to perceive in the dark, so they are weighted much less than value there. Raising it makes
+	// dimly lit colors compare more by brightness than by hue.
+	DarkValue float64
+	// VeryDarkValue is the value below which both colors are considered nearly black, further
+	// discounting saturation.
+	VeryDarkValue float64
+
+	// GraySaturation and GrayValue bound the dark, desaturated region where value dominates the distance.
+	GraySaturation float64
+	GrayValue      float64
+
+	// LightSaturation is the saturation below which either color is considered washed out, where hue
+	// matters less the closer both colors are to gray.
+	LightSaturation float64
+	// VividSaturation is the saturation above which both colors are considered fully saturated, where
+	// hue matters more and value matters less.
+	VividSaturation float64
+}
+
+// DefaultDistanceConfig is the DistanceConfig used by Distance.
+var DefaultDistanceConfig = DistanceConfig{
+	HueWeight:        40.0, // ~ 360 / 7 - about 8 degrees of hue change feels like a different color in general
+	SaturationWeight: 6.5,
+	ValueWeight:      5.0,
+	DarkValue:        .13,
+	VeryDarkValue:    .1,
+	GraySaturation:   .25,
+	GrayValue:        .25,
+	LightSaturation:  .10,
+	VividSaturation:  .9,
+}
+
 // Distance returns the "distance" between two colors.
 func (c Color) Distance(b Color) float64 {
+	return c.DistanceWith(b, DefaultDistanceConfig)
+}
+
+// DistanceWith returns the "distance" between two colors using the given parameters.
+func (c Color) DistanceWith(b Color, cfg DistanceConfig) float64 {
 	debug := false
-	return c.distanceDebug(b, debug)
+	return c.distanceDebug(b, cfg, debug)
 }
 
-func (c Color) distanceDebug(b Color, debug bool) float64 {
+func (c Color) distanceDebug(b Color, cfg DistanceConfig, debug bool) float64 {
 	h1, s1, v1 := c.ScaleHSV()
 	h2, s2, v2 := b.ScaleHSV()
 
-	wh := 40.0 // ~ 360 / 7 - about 8 degrees of hue change feels like a different color in general
-	ws := 6.5
-	wv := 5.0
+	wh := cfg.HueWeight
+	ws := cfg.SaturationWeight
+	wv := cfg.ValueWeight
 
 	ac := -1.0
 	dd := 1.0
 	var section int
 
 	switch {
-	case v1 < .13 || v2 < .13:
+	case v1 < cfg.DarkValue || v2 < cfg.DarkValue:
 		section = 1
 		// we're in the dark range
 		wh /= 30
 		ws /= 7
 		wv *= 1.5
 
-		if v1 < .1 && v2 < .1 {
+		if v1 < cfg.VeryDarkValue && v2 < cfg.VeryDarkValue {
 			ws /= 3
 		}
-	case (s1 < .25 && v1 < .25) || (s2 < .25 && v2 < .25):
+	case (s1 < cfg.GraySaturation && v1 < cfg.GrayValue) || (s2 < cfg.GraySaturation && v2 < cfg.GrayValue):
 		section = 2
 		// we're in the bottom left quadrat
 		wv *= 3.0
 		wh /= 20
 		ws /= 2
-	case s1 < .10 || s2 < .10:
+	case s1 < cfg.LightSaturation || s2 < cfg.LightSaturation:
 		section = 3
 		// we're in the very light range
 		wh *= .06 * (v1 + v2) * ((s1 + s2) * 5)
@@ -344,7 +489,7 @@ func (c Color) distanceDebug(b Color, debug bool) float64 {
 			wh *= 1
 			wv *= .7
 		}
-	case s1 > .9 && s2 > .9:
+	case s1 > cfg.VividSaturation && s2 > cfg.VividSaturation:
 		section = 6
 		// in the very right side of the chart
 		wh *= 1.2
@@ -494,4 +639,20 @@ var (
 		Purple,
 		Pink,
 	}
+
+	// colorNames are the names of the colors in Colors.
+	colorNames = map[Color]string{
+		Red:      "red",
+		DarkRed:  "dark red",
+		Green:    "green",
+		Blue:     "blue",
+		DarkBlue: "dark blue",
+		White:    "white",
+		Gray:     "gray",
+		Black:    "black",
+		Yellow:   "yellow",
+		Cyan:     "cyan",
+		Purple:   "purple",
+		Pink:     "pink",
+	}
 )
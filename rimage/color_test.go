@@ -1,6 +1,7 @@
 package rimage
 
 import (
+	"encoding/json"
 	"image"
 	"image/color"
 	"math"
@@ -49,7 +50,7 @@ func _checkAllClose(t *testing.T, colors []Color, maxDistance float64) {
 
 func _testColorFailure(t *testing.T, a, b Color, threshold float64, comparison string) {
 	t.Helper()
-	d := a.distanceDebug(b, true)
+	d := a.distanceDebug(b, DefaultDistanceConfig, true)
 	t.Fatalf("%v(%s) %v(%s) difference should be %s %f, but is %f https://www.viam.com/color.html?#1=%s&2=%s",
 		a, a.Hex(), b, b.Hex(), comparison, threshold, d, a.Hex(), b.Hex())
 }
@@ -152,6 +153,20 @@ func TestColorRoundTrip(t *testing.T) {
 	test.That(t, c2.Hex(), test.ShouldEqual, c.Hex())
 }
 
+func TestColorFromHSVMatchesHex(t *testing.T) {
+	for _, hex := range []string{"#ff0000", "#115385", "#123456", "#c8a040", "#808080", "#000000", "#ffffff", "#6b4a2c"} {
+		fromHex := NewColorFromHexOrPanic(hex)
+		h, s, v := fromHex.HsvNormal()
+		fromHSV := NewColorFromHSV(h, s, v)
+		test.That(t, fromHSV.Hex(), test.ShouldEqual, hex)
+		test.That(t, fromHSV.Distance(fromHex), test.ShouldBeLessThan, 1)
+
+		_, closest, _ := fromHSV.Closest(Colors)
+		_, closestFromHex, _ := fromHex.Closest(Colors)
+		test.That(t, closest, test.ShouldEqual, closestFromHex)
+	}
+}
+
 func TestColorHSVDistanceSanityCheckDiff(t *testing.T) {
 	data := [][]float64{
 		{0.0, 0.5, 0.5},
@@ -241,6 +256,38 @@ func TestColorHSVDistanceBlacks1(t *testing.T) {
 	_checkAllSame(t, data)
 }
 
+func TestColorLuminance(t *testing.T) {
+	test.That(t, White.Luminance(), test.ShouldAlmostEqual, 1)
+	test.That(t, Black.Luminance(), test.ShouldEqual, 0)
+	test.That(t, Red.Luminance(), test.ShouldAlmostEqual, 0.2126)
+	test.That(t, Green.Luminance(), test.ShouldAlmostEqual, 0.7152)
+	test.That(t, Blue.Luminance(), test.ShouldAlmostEqual, 0.0722)
+	test.That(t, Gray.Luminance(), test.ShouldAlmostEqual, 0.2158605, 1e-6)
+
+	test.That(t, White.IsDark(.5), test.ShouldBeFalse)
+	test.That(t, Black.IsDark(.5), test.ShouldBeTrue)
+	test.That(t, Black.IsDark(0), test.ShouldBeFalse)
+
+	// the colors of TestColorHSVDistanceBlacks1 are all dark, with or without a hue.
+	blacks := []Color{
+		NewColorFromHexOrPanic("#020300"),
+		NewColorFromHexOrPanic("#010101"),
+		NewColor(17, 23, 11),
+		NewColor(23, 13, 11),
+		NewColor(11, 23, 21),
+		NewColor(11, 17, 23),
+		NewColor(11, 11, 23),
+		NewColor(19, 11, 23),
+		NewColor(23, 11, 20),
+		NewColor(23, 11, 16),
+		NewColor(23, 11, 13),
+	}
+	for _, c := range blacks {
+		test.That(t, c.IsDark(0.01), test.ShouldBeTrue)
+	}
+	test.That(t, NewColorFromHexOrPanic("#11314c").IsDark(0.01), test.ShouldBeFalse)
+}
+
 func TestColorHSVDistanceDarks(t *testing.T) {
 	veryDarkBlue := NewColorFromHexOrPanic("#0a1a1f")
 	mostlyDarkBlue := NewColorFromHexOrPanic("#09202d")
@@ -348,6 +395,101 @@ func TestColorHSVDistanceChess3(t *testing.T) {
 	_checkAllClose(t, allColors, 2)
 }
 
+func TestClosestColor(t *testing.T) {
+	c, d := ClosestColor(NewColor(250, 10, 5))
+	test.That(t, c, test.ShouldEqual, Red)
+	test.That(t, d, test.ShouldBeLessThan, 1)
+
+	c, d = ClosestColor(Blue)
+	test.That(t, c, test.ShouldEqual, Blue)
+	test.That(t, d, test.ShouldEqual, 0)
+
+	// Colors from the chess tests above: dark blue squares, light squares, and dark pieces.
+	palette := []Color{
+		NewColor(5, 51, 85),
+		NewColor(158, 141, 112),
+		NewColor(19, 17, 9),
+	}
+	c, d = ClosestColorInPalette(NewColorFromHexOrPanic("#0a3c64"), palette)
+	test.That(t, c, test.ShouldEqual, palette[0])
+	test.That(t, d, test.ShouldBeGreaterThan, 0)
+	c, _ = ClosestColorInPalette(NewColorFromHexOrPanic("#a49470"), palette)
+	test.That(t, c, test.ShouldEqual, palette[1])
+
+	img := NewImage(2, 1)
+	img.SetXY(0, 0, NewColorFromHexOrPanic("#0a3c64"))
+	img.SetXY(1, 0, NewColorFromHexOrPanic("#a49470"))
+	quantized := QuantizeImage(img, palette)
+	test.That(t, quantized.GetXY(0, 0), test.ShouldEqual, palette[0])
+	test.That(t, quantized.GetXY(1, 0), test.ShouldEqual, palette[1])
+	test.That(t, img.GetXY(0, 0), test.ShouldEqual, NewColorFromHexOrPanic("#0a3c64"))
+
+	quantized = QuantizeImage(img, nil)
+	for x := 0; x < 2; x++ {
+		expected, _ := ClosestColor(img.GetXY(x, 0))
+		test.That(t, quantized.GetXY(x, 0), test.ShouldEqual, expected)
+	}
+}
+
+func TestColorDescribe(t *testing.T) {
+	desc := Red.Describe()
+	b, err := json.Marshal(desc)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, string(b), test.ShouldEqual,
+		`{"name":"red","hex":"#ff0000","rgb":{"r":255,"g":0,"b":0},"hsv":{"h":0,"s":1,"v":1},"distance":0}`)
+
+	var decoded ColorDescription
+	test.That(t, json.Unmarshal(b, &decoded), test.ShouldBeNil)
+	test.That(t, decoded, test.ShouldResemble, desc)
+	c, err := NewColorFromHex(decoded.Hex)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, c, test.ShouldEqual, Red)
+
+	desc = NewColor(250, 10, 5).Describe()
+	test.That(t, desc.Name, test.ShouldEqual, "red")
+	test.That(t, desc.RGB, test.ShouldResemble, ColorRGB{R: 250, G: 10, B: 5})
+	test.That(t, desc.Distance, test.ShouldBeGreaterThan, 0)
+	test.That(t, desc.Distance, test.ShouldBeLessThan, 1)
+
+	for _, c := range Colors {
+		test.That(t, colorNames[c], test.ShouldNotBeEmpty)
+		test.That(t, c.Describe().Distance, test.ShouldEqual, 0)
+	}
+}
+
+func TestColorDistanceWith(t *testing.T) {
+	// Distances computed before the parameters were made configurable.
+	data := []struct {
+		a, b     string
+		distance float64
+	}{
+		{"#8d836a", "#8e7e51", 2.700384336008967},
+		{"#0d1e2a", "#0e273f", 1.1789788758604494},
+		{"#ff0000", "#f01010", 0.5192289036289354},
+		{"#101010", "#050505", 0.3235294117647059},
+		{"#202830", "#303030", 1.3667822444099067},
+		{"#f0f0f0", "#e8e0f0", 0.626444555217725},
+		{"#4a4540", "#504030", 1.9102928069350014},
+		{"#c0c0b0", "#b0b0c0", 1.5711763155358087},
+		{"#10ff20", "#20f030", 0.3804741421796507},
+		{"#303828", "#403020", 3.2229602789105507},
+		{"#4a5a6a", "#6a5a4a", 17.046229698849626},
+	}
+	for _, x := range data {
+		a := NewColorFromHexOrPanic(x.a)
+		b := NewColorFromHexOrPanic(x.b)
+		test.That(t, a.Distance(b), test.ShouldAlmostEqual, x.distance)
+		test.That(t, a.DistanceWith(b, DefaultDistanceConfig), test.ShouldAlmostEqual, x.distance)
+	}
+
+	// Treating more colors as dark makes dim colors with different hues closer.
+	a := NewColorFromHexOrPanic("#303828")
+	b := NewColorFromHexOrPanic("#403020")
+	lowLight := DefaultDistanceConfig
+	lowLight.DarkValue = .3
+	test.That(t, a.DistanceWith(b, lowLight), test.ShouldBeLessThan, a.Distance(b))
+}
+
 func readColorsFromFile(fn string) ([]Color, error) {
 	raw, err := os.ReadFile(fn)
 	if err != nil {
@@ -527,3 +669,41 @@ func TestColorSegment1(t *testing.T) {
 
 	out.WriteTo(outDir + "/foo.png")
 }
+
+func TestDistinctColors(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		colors    func(int) []Color
+		maxColors int
+	}{
+		{"all hues", DistinctColors, MaxDistinctColors},
+		{"colorblind safe", DistinctColorblindSafeColors, MaxDistinctColorblindSafeColors},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			test.That(t, tc.colors(0), test.ShouldBeEmpty)
+
+			colors := tc.colors(tc.maxColors)
+			test.That(t, colors, test.ShouldHaveLength, tc.maxColors)
+			for i, c1 := range colors {
+				for _, c2 := range colors[i+1:] {
+					test.That(t, c1.Distance(c2), test.ShouldBeGreaterThanOrEqualTo, DistinctColorMinDistance)
+				}
+			}
+
+			// deterministic, and smaller n are a prefix of larger ones.
+			test.That(t, tc.colors(tc.maxColors), test.ShouldResemble, colors)
+			test.That(t, tc.colors(5), test.ShouldResemble, colors[:5])
+
+			// past the candidates colors repeat rather than running out.
+			test.That(t, tc.colors(1000), test.ShouldHaveLength, 1000)
+		})
+	}
+
+	test.That(t, DistinctColorblindSafeColors(len(okabeItoColors)), test.ShouldResemble, okabeItoColors)
+	for _, c := range DistinctColorblindSafeColors(MaxDistinctColorblindSafeColors)[len(okabeItoColors):] {
+		h, s, _ := c.HsvNormal()
+		if s > 0 {
+			test.That(t, (h >= 24 && h <= 61) || (h >= 189 && h <= 251), test.ShouldBeTrue)
+		}
+	}
+}
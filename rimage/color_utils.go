@@ -3,6 +3,7 @@ package rimage
 import (
 	"html/template"
 	"image/color"
+	"math"
 	"os"
 	"sort"
 	"strings"
@@ -116,3 +117,108 @@ func ComputeColorDiffs(all []Color) ColorDiffs {
 	}
 	return diffs
 }
+
+// DistinctColorMinDistance is the Distance every pair of colors returned by DistinctColors and
+// DistinctColorblindSafeColors is at least, as long as n is at most their corresponding max.
+const DistinctColorMinDistance = 1.0
+
+// The largest n for which DistinctColors and DistinctColorblindSafeColors keep every pair of colors at
+// least DistinctColorMinDistance apart.
+const (
+	MaxDistinctColors               = 150
+	MaxDistinctColorblindSafeColors = 50
+)
+
+// okabeItoColors is the Okabe-Ito palette, which stays distinguishable under the common forms of color
+// blindness. Black is left out so that the colors show up on dark images.
+var okabeItoColors = []Color{
+	NewColorFromHexOrPanic("#e69f00"), // orange
+	NewColorFromHexOrPanic("#56b4e9"), // sky blue
+	NewColorFromHexOrPanic("#009e73"), // bluish green
+	NewColorFromHexOrPanic("#f0e442"), // yellow
+	NewColorFromHexOrPanic("#0072b2"), // blue
+	NewColorFromHexOrPanic("#d55e00"), // vermillion
+	NewColorFromHexOrPanic("#cc79a7"), // reddish purple
+}
+
+// DistinctColors returns n colors, such as for drawing overlapping overlays, that are spread across HSV
+// space so that every pair is at least DistinctColorMinDistance apart for n up to MaxDistinctColors.
+// Larger n repeat colors. The result is the same for a given n and a smaller n returns a prefix of the
+// colors of a larger one.
+func DistinctColors(n int) []Color {
+	return pickDistinctColors(n, nil, distinctColorCandidates(func(float64) bool { return true }))
+}
+
+// DistinctColorblindSafeColors is like DistinctColors but starts with the Okabe-Ito palette and then only
+// uses blue and orange to yellow hues, which are distinguishable with red-green color blindness. Every pair
+// is at least DistinctColorMinDistance apart for n up to MaxDistinctColorblindSafeColors.
+func DistinctColorblindSafeColors(n int) []Color {
+	return pickDistinctColors(n, okabeItoColors, distinctColorCandidates(func(h float64) bool {
+		return (h >= 25 && h <= 60) || (h >= 190 && h <= 250)
+	}))
+}
+
+// distinctColorCandidates returns the colors DistinctColors picks from: every 5 degrees of hue accepted by
+// hueOK at a few levels of saturation and value, along with white and grays.
+func distinctColorCandidates(hueOK func(h float64) bool) []Color {
+	var candidates []Color
+	for h := 0.0; h < 360; h += 5 {
+		if !hueOK(h) {
+			continue
+		}
+		for _, s := range []float64{1, .55} {
+			for _, v := range []float64{1, .7, .45} {
+				candidates = append(candidates, NewColorFromHSV(h, s, v))
+			}
+		}
+	}
+	for _, v := range []float64{1, .6, .3} {
+		candidates = append(candidates, NewColorFromHSV(0, 0, v))
+	}
+	return candidates
+}
+
+// pickDistinctColors returns seeds followed by greedily picking the candidate farthest from all of the
+// colors picked so far until there are n colors. Once every candidate is used the colors repeat.
+func pickDistinctColors(n int, seeds, candidates []Color) []Color {
+	if n <= 0 {
+		return nil
+	}
+	picked := make([]Color, 0, n)
+	// closest holds the distance from each candidate to the closest picked color.
+	closest := make([]float64, len(candidates))
+	for i := range closest {
+		closest[i] = math.Inf(1)
+	}
+	used := make([]bool, len(candidates))
+	pick := func(c Color) {
+		picked = append(picked, c)
+		for i, candidate := range candidates {
+			closest[i] = math.Min(closest[i], candidate.Distance(c))
+		}
+	}
+
+	for _, c := range seeds {
+		if len(picked) == n {
+			return picked
+		}
+		pick(c)
+	}
+	for len(picked) < n {
+		best := -1
+		for i := range candidates {
+			if !used[i] && (best == -1 || closest[i] > closest[best]) {
+				best = i
+			}
+		}
+		if best == -1 {
+			break
+		}
+		used[best] = true
+		pick(candidates[best])
+	}
+	for i := 0; len(picked) < n; i++ {
+		picked = append(picked, picked[i])
+	}
+	return picked
+}
@@ -1118,6 +1118,7 @@ func (r *localRobot) Reconfigure(ctx context.Context, newConfig *config.Config)
 		return
 	}
 
+	r.logger.CInfow(ctx, "reconfiguring", "changes", diff.Summary().String())
 	if r.revealSensitiveConfigDiffs {
 		r.logger.CDebugf(ctx, "(re)configuring with %+v", diff)
 	}
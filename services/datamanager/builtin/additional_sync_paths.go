@@ -0,0 +1,62 @@
+package builtin
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// FilteredSyncPath is a directory whose files are synced along with the capture directory, like an entry of
+// additional_sync_paths, but filtered by globs. If Include is set, only files matching one of its globs are
+// synced, and files matching one of the Exclude globs never are. Globs are matched against file names, or
+// against paths relative to the directory if they contain a slash.
+type FilteredSyncPath struct {
+	Path    string   `json:"path"`
+	Include []string `json:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty"`
+}
+
+// parseAdditionalSyncPaths combines the plain additional_sync_paths, which sync every file, with the
+// filtered_sync_paths into the directories to sync, checking that the latter have a path and valid globs.
+func parseAdditionalSyncPaths(plain []string, filtered []FilteredSyncPath) ([]FilteredSyncPath, error) {
+	paths := make([]FilteredSyncPath, 0, len(plain)+len(filtered))
+	for _, p := range plain {
+		paths = append(paths, FilteredSyncPath{Path: p})
+	}
+	for i, syncPath := range filtered {
+		if syncPath.Path == "" {
+			return nil, errors.Errorf("filtered_sync_paths.%d must have a path", i)
+		}
+		for _, pattern := range append(append([]string{}, syncPath.Include...), syncPath.Exclude...) {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, errors.Wrapf(err, "filtered_sync_paths.%d has invalid pattern %q", i, pattern)
+			}
+		}
+		paths = append(paths, syncPath)
+	}
+	return paths, nil
+}
+
+// matches returns whether the file at relPath, relative to p.Path, should be synced.
+func (p FilteredSyncPath) matches(relPath string) bool {
+	relPath = filepath.ToSlash(relPath)
+	if len(p.Include) > 0 && !matchesAnyGlob(p.Include, relPath) {
+		return false
+	}
+	return !matchesAnyGlob(p.Exclude, relPath)
+}
+
+func matchesAnyGlob(patterns []string, relPath string) bool {
+	for _, pattern := range patterns {
+		name := path.Base(relPath)
+		if strings.Contains(pattern, "/") {
+			name = relPath
+		}
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -4,10 +4,14 @@ package builtin
 import (
 	"context"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
 	"reflect"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	clk "github.com/benbjohnson/clock"
@@ -15,6 +19,7 @@ import (
 	v1 "go.viam.com/api/app/datasync/v1"
 	goutils "go.viam.com/utils"
 	"go.viam.com/utils/rpc"
+	"golang.org/x/exp/slices"
 
 	"go.viam.com/rdk/components/sensor"
 	"go.viam.com/rdk/data"
@@ -54,80 +59,216 @@ func init() {
 		})
 }
 
-// TODO: re-determine if queue size is optimal given we now support 10khz+ capture rates
 // The Collector's queue should be big enough to ensure that .capture() is never blocked by the queue being
-// written to disk. A default value of 250 was chosen because even with the fastest reasonable capture interval (1ms),
-// this would leave 250ms for a (buffered) disk write before blocking, which seems sufficient for the size of
-// writes this would be performing.
+// written to disk. A default value of 250 was chosen because at a capture interval of 1ms this leaves 250ms for
+// a (buffered) disk write before blocking, which seems sufficient for the size of writes this would be performing.
+// At higher capture frequencies the default queue is scaled up to hold defaultCaptureQueueDuration of readings.
 const defaultCaptureQueueSize = 250
 
-// Default bufio.Writer buffer size in bytes.
+// Default bufio.Writer buffer size in bytes. It is scaled up along with the queue size at high capture frequencies.
 const defaultCaptureBufferSize = 4096
 
+// The amount of captured readings the default queue size holds at high capture frequencies.
+const defaultCaptureQueueDuration = 250 * time.Millisecond
+
+// The largest default bufio.Writer buffer size in bytes.
+const maxDefaultCaptureBufferSize = 1 << 20
+
 // Default time to wait in milliseconds to check if a file has been modified.
 const defaultFileLastModifiedMillis = 10000.0
 
+// clock is the clock used by data managers that are not configured with their own.
 var clock = clk.New()
 
 var errCaptureDirectoryConfigurationDisabled = errors.New("changing the capture directory is prohibited in this environment")
 
 // Config describes how to configure the service.
 type Config struct {
-	CaptureDir             string                           `json:"capture_dir"`
-	AdditionalSyncPaths    []string                         `json:"additional_sync_paths"`
-	SyncIntervalMins       float64                          `json:"sync_interval_mins"`
-	CaptureDisabled        bool                             `json:"capture_disabled"`
-	ScheduledSyncDisabled  bool                             `json:"sync_disabled"`
-	Tags                   []string                         `json:"tags"`
-	ResourceConfigs        []*datamanager.DataCaptureConfig `json:"resource_configs"`
-	FileLastModifiedMillis int                              `json:"file_last_modified_millis"`
-	SelectiveSyncerName    string                           `json:"selective_syncer_name"`
+	CaptureDir                    string                           `json:"capture_dir"`
+	CaptureDirTemplate            string                           `json:"capture_dir_template"`
+	AdditionalSyncPaths           []string                         `json:"additional_sync_paths"`
+	FilteredSyncPaths             []FilteredSyncPath               `json:"filtered_sync_paths"`
+	SyncIntervalMins              float64                          `json:"sync_interval_mins"`
+	CaptureDisabled               bool                             `json:"capture_disabled"`
+	ScheduledSyncDisabled         bool                             `json:"sync_disabled"`
+	Tags                          []string                         `json:"tags"`
+	ResourceConfigs               []*datamanager.DataCaptureConfig `json:"resource_configs"`
+	SelectiveSyncerName           string                           `json:"selective_syncer_name"`
+	SelectiveSyncerKey            string                           `json:"selective_syncer_key"`
+	SelectiveSyncerNames          []string                         `json:"selective_syncer_names"`
+	SelectiveSyncMode             string                           `json:"selective_sync_mode"`
+	MaximumCaptureDirSizeGB       float64                          `json:"maximum_capture_dir_size_gb"`
+	MaximumCaptureDirSizeBehavior string                           `json:"maximum_capture_dir_size_behavior"`
+	SyncRetryMaxMinutes           float64                          `json:"sync_retry_max_minutes"`
+	CompressBeforeSync            bool                             `json:"compress_before_sync"`
+
+	// CaptureFileFormat is the format tabular data is captured in: "viam", the default, or "parquet", which
+	// writes Parquet files that can be queried locally, e.g. with DuckDB. Binary data is always captured in the
+	// viam format. Parquet files are synced like arbitrary files, so they are stored in the cloud as opaque
+	// files rather than as tabular data that can be queried there.
+	// Parquet files cannot be appended to, so each collector holds its readings in memory until its file
+	// reaches the maximum capture file size or datacapture.MaxParquetFileAge, or is flushed by a sync. Those
+	// readings are lost if the process crashes and do not count toward MaximumCaptureDirSizeGB.
+	CaptureFileFormat string `json:"capture_file_format"`
+
+	// SyncOnClose makes Close sync the data captured before the service closed, e.g. on a planned shutdown, so
+	// that the last readings are uploaded right away rather than on the next start. Close waits up to
+	// syncOnCloseTimeout for the uploads. Selective sync is respected, but scheduled sync need not be enabled.
+	SyncOnClose bool `json:"sync_on_close"`
+
+	// FileLastModifiedMillis is how long an arbitrary file, i.e. one not written by data capture, must go
+	// unmodified before it is synced, so that files still being written are not uploaded.
+	FileLastModifiedMillis int `json:"file_last_modified_millis"`
+
+	// InProgressFileStuckMillis is how long an in-progress capture file must go unmodified before it is
+	// considered abandoned, e.g. by a crash, and synced anyway; completed capture files are synced right away.
+	// It defaults to FileLastModifiedMillis.
+	InProgressFileStuckMillis int `json:"in_progress_file_stuck_millis"`
+
+	// Clock, if set, is used by the service instead of the package level clock to schedule capture and sync.
+	// It cannot be set from JSON and is intended for tests and simulated robots.
+	Clock clk.Clock `json:"-"`
 }
 
 // Validate returns components which will be depended upon weakly due to the above matcher.
 func (c *Config) Validate(path string) ([]string, error) {
+	if c.MaximumCaptureDirSizeGB < 0 {
+		return nil, resource.NewConfigValidationError(path,
+			errors.New("maximum_capture_dir_size_gb must not be negative"))
+	}
+	if c.SyncRetryMaxMinutes < 0 {
+		return nil, resource.NewConfigValidationError(path,
+			errors.New("sync_retry_max_minutes must not be negative"))
+	}
+	if err := validateCaptureDirTemplate(c.CaptureDirTemplate); err != nil {
+		return nil, resource.NewConfigValidationError(path, err)
+	}
+	if _, err := parseAdditionalSyncPaths(c.AdditionalSyncPaths, c.FilteredSyncPaths); err != nil {
+		return nil, resource.NewConfigValidationError(path, err)
+	}
+	// resource_configs are linked from the capture methods of other resources before validation, so the
+	// collectors they describe are checked here rather than only when they are created.
+	for _, resConf := range c.ResourceConfigs {
+		if resConf.AggregateWindowMs < 0 {
+			return nil, resource.NewConfigValidationError(path, errors.Errorf(
+				"capture method %s of %s: aggregate_window_ms must not be negative", resConf.Method, resConf.Name))
+		}
+		if err := data.AggregateFn(resConf.AggregateFn).Validate(); err != nil {
+			return nil, resource.NewConfigValidationError(path, errors.Wrapf(err,
+				"capture method %s of %s: aggregate_fn", resConf.Method, resConf.Name))
+		}
+	}
+	for _, name := range c.SelectiveSyncerNames {
+		if strings.TrimSpace(name) == "" {
+			return nil, resource.NewConfigValidationError(path, errors.New("selective_syncer_names must not contain blank names"))
+		}
+	}
+	switch c.SelectiveSyncMode {
+	case "", selectiveSyncModeAll, selectiveSyncModeAny:
+	default:
+		return nil, resource.NewConfigValidationError(path,
+			errors.Errorf("selective_sync_mode must be %q or %q, got %q", selectiveSyncModeAll, selectiveSyncModeAny, c.SelectiveSyncMode))
+	}
+	switch datacapture.FileFormat(c.CaptureFileFormat) {
+	case "", datacapture.FileFormatViam, datacapture.FileFormatParquet:
+	default:
+		return nil, resource.NewConfigValidationError(path,
+			errors.Errorf("capture_file_format must be %q or %q, got %q",
+				datacapture.FileFormatViam, datacapture.FileFormatParquet, c.CaptureFileFormat))
+	}
+	switch c.MaximumCaptureDirSizeBehavior {
+	case "", captureDirSizeBehaviorDeleteOldest, captureDirSizeBehaviorPauseCapture:
+	default:
+		return nil, resource.NewConfigValidationError(path,
+			errors.Errorf("maximum_capture_dir_size_behavior must be %q or %q, got %q",
+				captureDirSizeBehaviorDeleteOldest, captureDirSizeBehaviorPauseCapture, c.MaximumCaptureDirSizeBehavior))
+	}
 	return []string{cloud.InternalServiceName.String()}, nil
 }
 
+// selectiveSyncerNames returns the names of the configured selective syncers, including selective_syncer_name.
+func (c *Config) selectiveSyncerNames() []string {
+	var names []string
+	if c.SelectiveSyncerName != "" {
+		names = append(names, c.SelectiveSyncerName)
+	}
+	for _, name := range c.SelectiveSyncerNames {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+const (
+	// selectiveSyncModeAll syncs only when every selective syncer is ready to sync. It is the default.
+	selectiveSyncModeAll = "all"
+	// selectiveSyncModeAny syncs when at least one selective syncer is ready to sync.
+	selectiveSyncModeAny = "any"
+)
+
 type selectiveSyncer interface {
 	sensor.Sensor
 }
 
 // readyToSync is a method for getting the bool reading from the selective sync sensor
 // for determining whether the key is present and what its value is.
-func readyToSync(ctx context.Context, s selectiveSyncer, logger logging.Logger) (readyToSync bool) {
+func readyToSync(ctx context.Context, s selectiveSyncer, key string, logger logging.Logger) (readyToSync bool) {
 	readyToSync = false
 	readings, err := s.Readings(ctx, nil)
 	if err != nil {
 		logger.CErrorw(ctx, "error getting readings from selective syncer", "error", err.Error())
 		return
 	}
-	readyToSyncVal, ok := readings[datamanager.ShouldSyncKey]
+	readyToSyncVal, ok := readings[key]
 	if !ok {
-		logger.CErrorf(ctx, "value for should sync key %s not present in readings", datamanager.ShouldSyncKey)
+		logger.CErrorf(ctx, "value for should sync key %s not present in readings", key)
 		return
 	}
 	readyToSyncBool, err := utils.AssertType[bool](readyToSyncVal)
 	if err != nil {
-		logger.CErrorw(ctx, "error converting should sync key to bool", "key", datamanager.ShouldSyncKey, "error", err.Error())
+		logger.CErrorw(ctx, "error converting should sync key to bool", "key", key, "error", err.Error())
 		return
 	}
 	readyToSync = readyToSyncBool
 	return
 }
 
+// allReadyToSync combines the readyToSync of each of syncers according to mode. A nil syncer, i.e. one that
+// could not be initialized, is never ready to sync.
+func allReadyToSync(ctx context.Context, syncers []selectiveSyncer, key, mode string, logger logging.Logger) bool {
+	for _, s := range syncers {
+		ready := s != nil && readyToSync(ctx, s, key, logger)
+		if mode == selectiveSyncModeAny && ready {
+			return true
+		}
+		if mode != selectiveSyncModeAny && !ready {
+			return false
+		}
+	}
+	// with no syncers, "any" has nothing ready and "all" has nothing not ready.
+	return mode != selectiveSyncModeAny
+}
+
 // builtIn initializes and orchestrates data capture collectors for registered component/methods.
 type builtIn struct {
 	resource.Named
 	logger                 logging.Logger
 	captureDir             string
+	captureDirTemplate     string
+	captureFileFormat      datacapture.FileFormat
+	clock                  clk.Clock
 	captureDisabled        bool
 	collectors             map[resourceMethodMetadata]*collectorAndConfig
 	lock                   sync.Mutex
 	backgroundWorkers      sync.WaitGroup
 	fileLastModifiedMillis int
+	// inProgressFileStuckMillis is how long an in-progress capture file goes unmodified before it is synced.
+	inProgressFileStuckMillis int
 
-	additionalSyncPaths []string
+	additionalSyncPaths []FilteredSyncPath
+	// loggedSyncPaths holds the additional sync paths whose number of matching files has been logged.
+	loggedSyncPaths     map[string]bool
 	tags                []string
 	syncDisabled        bool
 	syncIntervalMins    float64
@@ -137,11 +278,33 @@ type builtIn struct {
 	cloudConnSvc        cloud.ConnectionService
 	cloudConn           rpc.ClientConn
 	syncTicker          *clk.Ticker
-
-	syncSensor           selectiveSyncer
+	syncRetryMaxMinutes float64
+	compressBeforeSync  bool
+	syncOnClose         bool
+
+	// syncSensors holds a nil sensor for each selective syncer that could not be initialized.
+	syncSensors          []selectiveSyncer
+	syncSensorKey        string
+	syncSensorMode       string
 	selectiveSyncEnabled bool
 
 	componentMethodFrequencyHz map[resourceMethodMetadata]float32
+
+	// syncStatusLock guards syncStatus and syncGeneration apart from lock, because uploads report their
+	// results while the syncer is being closed with lock held.
+	syncStatusLock sync.Mutex
+	syncStatus     SyncStatus
+	// syncGeneration numbers the most recent sync, so that uploads from earlier syncs do not count toward it.
+	syncGeneration int
+
+	maxCaptureDirSizeBytes int64
+	captureDirSizeBehavior string
+	captureDirSizeDir      string
+	captureDirSizeCancelFn context.CancelFunc
+	captureDirSizeWorker   sync.WaitGroup
+	capturePaused          atomic.Bool
+	captureDirNearMax      atomic.Bool
+	diskFull               diskFullState
 }
 
 var viamCaptureDotDir = filepath.Join(os.Getenv("HOME"), ".viam", "capture")
@@ -159,12 +322,16 @@ func NewBuiltIn(
 		captureDir:                 viamCaptureDotDir,
 		collectors:                 make(map[resourceMethodMetadata]*collectorAndConfig),
 		syncIntervalMins:           0,
-		additionalSyncPaths:        []string{},
+		additionalSyncPaths:        []FilteredSyncPath{},
+		loggedSyncPaths:            map[string]bool{},
 		tags:                       []string{},
 		fileLastModifiedMillis:     defaultFileLastModifiedMillis,
+		inProgressFileStuckMillis:  defaultFileLastModifiedMillis,
 		syncerConstructor:          datasync.NewManager,
+		syncSensorKey:              datamanager.ShouldSyncKey,
 		selectiveSyncEnabled:       false,
 		componentMethodFrequencyHz: make(map[resourceMethodMetadata]float32),
+		diskFull:                   diskFullState{logger: logger},
 	}
 
 	if err := svc.Reconfigure(ctx, deps, conf); err != nil {
@@ -174,10 +341,22 @@ func NewBuiltIn(
 	return svc, nil
 }
 
-// Close releases all resources managed by data_manager.
-func (svc *builtIn) Close(_ context.Context) error {
+// Close releases all resources managed by data_manager. Closing the collectors flushes the data they buffered
+// to the capture directory, which is then synced first if sync_on_close is set.
+func (svc *builtIn) Close(ctx context.Context) error {
 	svc.lock.Lock()
+	svc.cancelCaptureDirSizeChecker()
 	svc.closeCollectors()
+	if svc.syncOnClose {
+		if svc.syncRoutineCancelFn != nil {
+			svc.syncRoutineCancelFn()
+		}
+		svc.lock.Unlock()
+		// wait for a scheduled sync in progress so that it does not race the final one.
+		svc.backgroundWorkers.Wait()
+		svc.syncBeforeClose(ctx)
+		svc.lock.Lock()
+	}
 	svc.closeSyncer()
 	if svc.syncRoutineCancelFn != nil {
 		svc.syncRoutineCancelFn()
@@ -202,7 +381,95 @@ func (svc *builtIn) closeCollectors() {
 	wg.Wait()
 }
 
+var (
+	// syncOnCloseTimeout is the longest Close waits for the final sync when sync_on_close is set.
+	syncOnCloseTimeout = time.Minute
+	// syncOnClosePollInterval is how often Close checks whether the files of the final sync have been uploaded.
+	syncOnClosePollInterval = 100 * time.Millisecond
+)
+
+// syncBeforeClose syncs the files that are ready to be synced and waits until they are uploaded, or have
+// failed to upload for good, for up to syncOnCloseTimeout. Uploads that are still being retried then are
+// canceled when the syncer is closed, and the files are synced after the next start.
+func (svc *builtIn) syncBeforeClose(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, syncOnCloseTimeout)
+	defer cancel()
+
+	svc.lock.Lock()
+	if svc.selectiveSyncEnabled && !allReadyToSync(ctx, svc.syncSensors, svc.syncSensorKey, svc.syncSensorMode, svc.logger) {
+		svc.lock.Unlock()
+		svc.logger.CInfo(ctx, "not syncing before closing because the selective syncers are not ready to sync")
+		return
+	}
+	if svc.syncer == nil {
+		if err := svc.initSyncer(ctx); err != nil {
+			svc.lock.Unlock()
+			svc.logger.CWarnw(ctx, "could not sync before closing", "error", err)
+			return
+		}
+	}
+	syncer := svc.syncer
+	pending := svc.filesToSync()
+	svc.lock.Unlock()
+
+	svc.logger.CInfof(ctx, "syncing %d files before closing", len(pending))
+	for len(pending) > 0 {
+		// files that could not be synced because all sync routines were busy are synced on a later pass.
+		for _, p := range pending {
+			syncer.SyncFile(p, nil)
+		}
+		if !goutils.SelectContextOrWait(ctx, syncOnClosePollInterval) {
+			svc.logger.CWarnw(ctx, "closing before all files were synced; they will be synced after the next start",
+				"remaining", len(pending))
+			return
+		}
+		// synced files are deleted, and files that failed for good are moved to the failed directory.
+		remaining := pending[:0]
+		for _, p := range pending {
+			if _, err := os.Stat(p); err == nil {
+				remaining = append(remaining, p)
+			}
+		}
+		pending = remaining
+	}
+}
+
+// flushCommand is the DoCommand command that flushes data buffered by the collectors to disk.
+const flushCommand = "flush"
+
+// DoCommand supports the commands:
+//   - {"command": "flush"}, which calls Flush.
+//   - {"command": "list_failed"}, which lists the files that failed to sync, their count and total_bytes.
+//   - {"command": "retry_failed"}, which moves the files that failed to sync back so that they are synced again,
+//     and returns them like list_failed does.
+func (svc *builtIn) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	name, ok := cmd["command"]
+	if !ok {
+		return nil, errors.New("missing 'command' value")
+	}
+	switch name {
+	case flushCommand:
+		return nil, svc.Flush(ctx)
+	case listFailedCommand:
+		return svc.listFailed()
+	case retryFailedCommand:
+		return svc.retryFailed()
+	default:
+		return nil, errors.Errorf("no such command: %s", name)
+	}
+}
+
+// Flush writes the data buffered by all collectors to the capture directory without syncing it, e.g. so that
+// no captured data is lost across a planned restart. It is safe to call while capturing.
+func (svc *builtIn) Flush(_ context.Context) error {
+	svc.flushCollectors()
+	return nil
+}
+
 func (svc *builtIn) flushCollectors() {
+	// hold the lock so that collectors are not closed or replaced while being flushed.
+	svc.lock.Lock()
+	defer svc.lock.Unlock()
 	var wg sync.WaitGroup
 	for _, collector := range svc.collectors {
 		currCollector := collector
@@ -235,12 +502,54 @@ func (r resourceMethodMetadata) String() string {
 		r.MethodMetadata.API, r.ResourceName, r.MethodMetadata.MethodName, r.MethodParams)
 }
 
-// Get time.Duration from hz.
+// Get time.Duration from hz. The division is done in float64 since float32 division truncates high
+// frequencies to intervals that are off by up to tens of nanoseconds.
 func getDurationFromHz(captureFrequencyHz float32) time.Duration {
 	if captureFrequencyHz == 0 {
 		return time.Duration(0)
 	}
-	return time.Duration(float32(time.Second) / captureFrequencyHz)
+	return time.Duration(math.Round(float64(time.Second) / exactFrequencyHz(captureFrequencyHz)))
+}
+
+// exactFrequencyHz returns the float64 closest to the decimal frequency that captureFrequencyHz was parsed from,
+// so that, for example, 0.1 is not treated as 0.10000000149.
+func exactFrequencyHz(captureFrequencyHz float32) float64 {
+	hz, err := strconv.ParseFloat(strconv.FormatFloat(float64(captureFrequencyHz), 'g', -1, 32), 64)
+	if err != nil {
+		return float64(captureFrequencyHz)
+	}
+	return hz
+}
+
+// defaultCaptureQueueAndBufferSizes returns the queue and buffer sizes to use for a collector capturing at
+// captureFrequencyHz that has none configured. The queue holds at least defaultCaptureQueueDuration of readings
+// and never less than defaultCaptureQueueSize, and the buffer grows in proportion to it up to
+// maxDefaultCaptureBufferSize.
+func defaultCaptureQueueAndBufferSizes(captureFrequencyHz float32) (int, int) {
+	queueSize := int(math.Ceil(exactFrequencyHz(captureFrequencyHz) * defaultCaptureQueueDuration.Seconds()))
+	if queueSize <= defaultCaptureQueueSize {
+		return defaultCaptureQueueSize, defaultCaptureBufferSize
+	}
+	bufferSize := defaultCaptureBufferSize * queueSize / defaultCaptureQueueSize
+	if bufferSize > maxDefaultCaptureBufferSize {
+		bufferSize = maxDefaultCaptureBufferSize
+	}
+	return queueSize, bufferSize
+}
+
+// minCaptureQueueDuration is the least amount of captured data a collector's queue should be able to hold
+// while waiting on a (buffered) disk write before capture blocks.
+const minCaptureQueueDuration = 100 * time.Millisecond
+
+// captureQueueDuration returns how long it takes to fill a queue of queueSize readings at captureFrequencyHz.
+func captureQueueDuration(queueSize int, captureFrequencyHz float32) time.Duration {
+	return time.Duration(queueSize) * getDurationFromHz(captureFrequencyHz)
+}
+
+// Get time.Duration from minutes. time.Duration loses precision at low floating point values, so convert
+// to milliseconds first.
+func getDurationFromMins(mins float64) time.Duration {
+	return time.Millisecond * time.Duration(60000.0*mins)
 }
 
 var metadataToAdditionalParamFields = map[string]string{
@@ -287,15 +596,23 @@ func (svc *builtIn) initializeOrUpdateCollector(
 
 	// Parameters to initialize collector.
 	interval := getDurationFromHz(config.CaptureFrequencyHz)
-	// Set queue size to defaultCaptureQueueSize if it was not set in the config.
+	// Use the default queue and buffer sizes for the capture frequency if they were not set in the config.
+	defaultQueueSize, defaultBufferSize := defaultCaptureQueueAndBufferSizes(config.CaptureFrequencyHz)
 	captureQueueSize := config.CaptureQueueSize
 	if captureQueueSize == 0 {
-		captureQueueSize = defaultCaptureQueueSize
+		captureQueueSize = defaultQueueSize
 	}
-
 	captureBufferSize := config.CaptureBufferSize
 	if captureBufferSize == 0 {
-		captureBufferSize = defaultCaptureBufferSize
+		captureBufferSize = defaultBufferSize
+	}
+	if (config.CaptureQueueSize == 0 || config.CaptureBufferSize == 0) && defaultQueueSize != defaultCaptureQueueSize {
+		svc.logger.Debugf("using capture queue size %d and buffer size %d for %s at %.2fHz, scaled up from the defaults",
+			captureQueueSize, captureBufferSize, md, config.CaptureFrequencyHz)
+	}
+	if queueDuration := captureQueueDuration(captureQueueSize, config.CaptureFrequencyHz); queueDuration < minCaptureQueueDuration {
+		svc.logger.Warnf("capture queue of %d readings for %s fills in %s at %.2fHz, so capture may block while writing to disk; "+
+			"consider increasing capture_queue_size", captureQueueSize, md, queueDuration, config.CaptureFrequencyHz)
 	}
 	additionalParamKey, ok := metadataToAdditionalParamFields[generateMetadataKey(
 		md.MethodMetadata.API.String(),
@@ -312,21 +629,44 @@ func (svc *builtIn) initializeOrUpdateCollector(
 	}
 
 	// Create a collector for this resource and method.
-	targetDir := datacapture.FilePathWithReplacedReservedChars(
-		filepath.Join(svc.captureDir, captureMetadata.GetComponentType(),
-			captureMetadata.GetComponentName(), captureMetadata.GetMethodName()))
+	captureDir, captureDirTemplate := svc.captureDir, svc.captureDirTemplate
+	partID, captureClock := svc.cloudConnSvc.PartID(), svc.getClock()
+	targetDirFunc := func() string {
+		return datacapture.FilePathWithReplacedReservedChars(
+			filepath.Join(captureDir, filepath.FromSlash(expandCaptureDirTemplate(captureDirTemplate, captureDirTemplateValues{
+				partID:        partID,
+				componentType: captureMetadata.GetComponentType(),
+				componentName: captureMetadata.GetComponentName(),
+				methodName:    captureMetadata.GetMethodName(),
+				date:          captureClock.Now(),
+			}))))
+	}
+	targetDir := targetDirFunc()
 	if err := os.MkdirAll(targetDir, 0o700); err != nil {
-		return nil, err
+		if !isDiskFullError(err) {
+			return nil, err
+		}
+		// Keep the collector so that it captures once space is freed, when its buffer creates the directory.
+		svc.diskFull.pause(err)
+	}
+	buffer := datacapture.NewBuffer(targetDir, captureMetadata)
+	buffer.FileFormat = svc.captureFileFormat
+	if strings.Contains(captureDirTemplate, "{date}") {
+		// the date is that of each capture file rather than of the collector, which may run for days.
+		buffer.DirectoryFunc = targetDirFunc
 	}
 	params := data.CollectorParams{
-		ComponentName: config.Name.ShortName(),
-		Interval:      interval,
-		MethodParams:  methodParams,
-		Target:        datacapture.NewBuffer(targetDir, captureMetadata),
-		QueueSize:     captureQueueSize,
-		BufferSize:    captureBufferSize,
-		Logger:        svc.logger,
-		Clock:         clock,
+		ComponentName:      config.Name.ShortName(),
+		Interval:           interval,
+		CaptureFrequencyHz: exactFrequencyHz(config.CaptureFrequencyHz),
+		MethodParams:       methodParams,
+		Target:             newPausableWriter(buffer, &svc.capturePaused, &svc.diskFull),
+		QueueSize:          captureQueueSize,
+		BufferSize:         captureBufferSize,
+		Logger:             svc.logger,
+		Clock:              svc.getClock(),
+		AggregateWindow:    time.Duration(config.AggregateWindowMs) * time.Millisecond,
+		AggregateFn:        data.AggregateFn(config.AggregateFn),
 	}
 	collector, err := (*collectorConstructor)(config.Resource, params)
 	if err != nil {
@@ -369,6 +709,8 @@ func (svc *builtIn) initSyncer(ctx context.Context) error {
 	if err != nil {
 		return errors.Wrap(err, "failed to initialize new syncer")
 	}
+	syncer.SetMaxRetryInterval(getDurationFromMins(svc.syncRetryMaxMinutes))
+	syncer.SetCompression(svc.compressBeforeSync)
 	svc.syncer = syncer
 	svc.cloudConn = conn
 	return nil
@@ -385,6 +727,9 @@ func (svc *builtIn) Sync(ctx context.Context, _ map[string]interface{}) error {
 	if svc.syncer == nil {
 		err := svc.initSyncer(ctx)
 		if err != nil {
+			svc.syncStatusLock.Lock()
+			svc.syncStatus.LastError = err
+			svc.syncStatusLock.Unlock()
 			svc.lock.Unlock()
 			return err
 		}
@@ -407,6 +752,10 @@ func (svc *builtIn) Reconfigure(
 	if err != nil {
 		return err
 	}
+	additionalSyncPaths, err := parseAdditionalSyncPaths(svcConfig.AdditionalSyncPaths, svcConfig.FilteredSyncPaths)
+	if err != nil {
+		return err
+	}
 
 	cloudConnSvc, err := resource.FromDependencies[cloud.ConnectionService](deps, cloud.InternalServiceName)
 	if err != nil {
@@ -430,7 +779,19 @@ func (svc *builtIn) Reconfigure(
 	}
 	svc.captureDisabled = svcConfig.CaptureDisabled
 	// Service is disabled, so close all collectors and clear the map so we can instantiate new ones if we enable this service.
-	if svc.captureDisabled {
+	// Collectors also need to be recreated to write to new directories when the capture directory template changes,
+	// to write in the new format when the capture file format changes, and when the clock the collectors were
+	// created with changes.
+	clockChanged := svc.clock != svcConfig.Clock
+	svc.clock = svcConfig.Clock
+	captureFileFormat := datacapture.FileFormat(svcConfig.CaptureFileFormat)
+	if captureFileFormat == "" {
+		captureFileFormat = datacapture.FileFormatViam
+	}
+	if svc.captureDisabled || svc.captureDirTemplate != svcConfig.CaptureDirTemplate ||
+		svc.captureFileFormat != captureFileFormat || clockChanged {
+		svc.captureDirTemplate = svcConfig.CaptureDirTemplate
+		svc.captureFileFormat = captureFileFormat
 		svc.closeCollectors()
 		svc.collectors = make(map[resourceMethodMetadata]*collectorAndConfig)
 	}
@@ -472,10 +833,12 @@ func (svc *builtIn) Reconfigure(
 			svc.componentMethodFrequencyHz[componentMethodMetadata] = resConf.CaptureFrequencyHz
 
 			if !resConf.Disabled && resConf.CaptureFrequencyHz > 0 {
-				// We only use service-level tags.
-				resConf.Tags = svcConfig.Tags
+				// Tag captured data with both the service-level and the resource-level tags. Copy the config so
+				// that the merged tags are not merged again into the resource-level tags on the next reconfigure.
+				resConfWithTags := *resConf
+				resConfWithTags.Tags = mergeTags(svcConfig.Tags, resConf.Tags)
 
-				newCollectorAndConfig, err := svc.initializeOrUpdateCollector(componentMethodMetadata, resConf)
+				newCollectorAndConfig, err := svc.initializeOrUpdateCollector(componentMethodMetadata, &resConfWithTags)
 				if err != nil {
 					svc.logger.CErrorw(ctx, "failed to initialize or update collector", "error", err)
 				} else {
@@ -492,34 +855,91 @@ func (svc *builtIn) Reconfigure(
 		}
 	}
 	svc.collectors = newCollectors
-	svc.additionalSyncPaths = svcConfig.AdditionalSyncPaths
+	if !reflect.DeepEqual(svc.additionalSyncPaths, additionalSyncPaths) {
+		svc.additionalSyncPaths = additionalSyncPaths
+		svc.loggedSyncPaths = map[string]bool{}
+	}
+
+	maxCaptureDirSizeBytes := int64(svcConfig.MaximumCaptureDirSizeGB * bytesPerGB)
+	captureDirSizeBehavior := svcConfig.MaximumCaptureDirSizeBehavior
+	if captureDirSizeBehavior == "" {
+		captureDirSizeBehavior = captureDirSizeBehaviorDeleteOldest
+	}
+	if svc.maxCaptureDirSizeBytes != maxCaptureDirSizeBytes || svc.captureDirSizeBehavior != captureDirSizeBehavior ||
+		svc.captureDirSizeDir != svc.captureDir || clockChanged {
+		svc.maxCaptureDirSizeBytes = maxCaptureDirSizeBytes
+		svc.captureDirSizeBehavior = captureDirSizeBehavior
+		svc.captureDirSizeDir = svc.captureDir
+		svc.cancelCaptureDirSizeChecker()
+		if svc.maxCaptureDirSizeBytes > 0 {
+			svc.startCaptureDirSizeChecker(svc.captureDir, svc.maxCaptureDirSizeBytes, svc.captureDirSizeBehavior)
+		}
+	}
 
 	fileLastModifiedMillis := svcConfig.FileLastModifiedMillis
 	if fileLastModifiedMillis <= 0 {
 		fileLastModifiedMillis = defaultFileLastModifiedMillis
 	}
+	inProgressFileStuckMillis := svcConfig.InProgressFileStuckMillis
+	if inProgressFileStuckMillis <= 0 {
+		inProgressFileStuckMillis = fileLastModifiedMillis
+	}
 
-	var syncSensor sensor.Sensor
-	if svcConfig.SelectiveSyncerName != "" {
+	var syncSensors []selectiveSyncer
+	if syncerNames := svcConfig.selectiveSyncerNames(); len(syncerNames) > 0 {
+		syncSensorKey := svcConfig.SelectiveSyncerKey
+		if syncSensorKey == "" {
+			syncSensorKey = datamanager.ShouldSyncKey
+		}
+		if strings.TrimSpace(syncSensorKey) == "" {
+			return errors.Errorf("selective_syncer_key for selective syncers %q must not be blank", syncerNames)
+		}
+		syncSensorMode := svcConfig.SelectiveSyncMode
+		if syncSensorMode == "" {
+			syncSensorMode = selectiveSyncModeAll
+		}
+		svc.syncSensorKey = syncSensorKey
+		svc.syncSensorMode = syncSensorMode
 		svc.selectiveSyncEnabled = true
-		syncSensor, err = sensor.FromDependencies(deps, svcConfig.SelectiveSyncerName)
-		if err != nil {
-			svc.logger.CErrorw(
-				ctx, "unable to initialize selective syncer; will not sync at all until fixed or removed from config", "error", err.Error())
+		for _, name := range syncerNames {
+			syncSensor, err := sensor.FromDependencies(deps, name)
+			if err != nil {
+				svc.logger.CErrorw(ctx, "unable to initialize selective syncer; it will not be ready to sync until fixed or removed from config",
+					"name", name, "error", err.Error())
+				syncSensors = append(syncSensors, nil)
+				continue
+			}
+			syncSensors = append(syncSensors, syncSensor)
 		}
 	} else {
 		svc.selectiveSyncEnabled = false
 	}
-	if svc.syncSensor != syncSensor {
-		svc.syncSensor = syncSensor
+	svc.syncSensors = syncSensors
+
+	if svc.syncRetryMaxMinutes != svcConfig.SyncRetryMaxMinutes {
+		svc.syncRetryMaxMinutes = svcConfig.SyncRetryMaxMinutes
+		if svc.syncer != nil {
+			svc.syncer.SetMaxRetryInterval(getDurationFromMins(svc.syncRetryMaxMinutes))
+		}
+	}
+
+	svc.syncOnClose = svcConfig.SyncOnClose
+
+	if svc.compressBeforeSync != svcConfig.CompressBeforeSync {
+		svc.compressBeforeSync = svcConfig.CompressBeforeSync
+		if svc.syncer != nil {
+			svc.syncer.SetCompression(svc.compressBeforeSync)
+		}
 	}
 
 	if svc.syncDisabled != svcConfig.ScheduledSyncDisabled || svc.syncIntervalMins != svcConfig.SyncIntervalMins ||
-		!reflect.DeepEqual(svc.tags, svcConfig.Tags) || svc.fileLastModifiedMillis != fileLastModifiedMillis {
+		!reflect.DeepEqual(svc.tags, svcConfig.Tags) || svc.fileLastModifiedMillis != fileLastModifiedMillis ||
+		svc.inProgressFileStuckMillis != inProgressFileStuckMillis || clockChanged {
 		svc.syncDisabled = svcConfig.ScheduledSyncDisabled
 		svc.syncIntervalMins = svcConfig.SyncIntervalMins
 		svc.tags = svcConfig.Tags
 		svc.fileLastModifiedMillis = fileLastModifiedMillis
+		svc.inProgressFileStuckMillis = inProgressFileStuckMillis
 
 		svc.cancelSyncScheduler()
 		if !svc.syncDisabled && svc.syncIntervalMins != 0.0 {
@@ -547,6 +967,14 @@ func (svc *builtIn) Reconfigure(
 	return nil
 }
 
+// getClock returns the clock the service was configured with, or the package level clock if there is none.
+func (svc *builtIn) getClock() clk.Clock {
+	if svc.clock != nil {
+		return svc.clock
+	}
+	return clock
+}
+
 // startSyncScheduler starts the goroutine that calls Sync repeatedly if scheduled sync is enabled.
 func (svc *builtIn) startSyncScheduler(intervalMins float64) {
 	cancelCtx, fn := context.WithCancel(context.Background())
@@ -569,7 +997,7 @@ func (svc *builtIn) uploadData(cancelCtx context.Context, intervalMins float64)
 	intervalMillis := 60000.0 * intervalMins
 	// The ticker must be created before uploadData returns to prevent race conditions between clock.Ticker and
 	// clock.Add in sync_test.go.
-	svc.syncTicker = clock.Ticker(time.Millisecond * time.Duration(intervalMillis))
+	svc.syncTicker = svc.getClock().Ticker(time.Millisecond * time.Duration(intervalMillis))
 	svc.backgroundWorkers.Add(1)
 	goutils.PanicCapturingGo(func() {
 		defer svc.backgroundWorkers.Done()
@@ -591,10 +1019,10 @@ func (svc *builtIn) uploadData(cancelCtx context.Context, intervalMins float64)
 				if svc.syncer != nil {
 					// If selective sync is disabled, sync. If it is enabled, check the condition below.
 					shouldSync := !svc.selectiveSyncEnabled
-					// If selective sync is enabled and the sensor has been properly initialized,
-					// try to get the reading from the selective sensor that indicates whether to sync
-					if svc.syncSensor != nil && svc.selectiveSyncEnabled {
-						shouldSync = readyToSync(cancelCtx, svc.syncSensor, svc.logger)
+					// If selective sync is enabled, get the readings from the selective sensors that indicate
+					// whether to sync and combine them according to the mode.
+					if svc.selectiveSyncEnabled {
+						shouldSync = allReadyToSync(cancelCtx, svc.syncSensors, svc.syncSensorKey, svc.syncSensorMode, svc.logger)
 					}
 					svc.lock.Unlock()
 
@@ -613,19 +1041,57 @@ func (svc *builtIn) sync() {
 	svc.flushCollectors()
 
 	svc.lock.Lock()
-	toSync := getAllFilesToSync(svc.captureDir, svc.fileLastModifiedMillis)
-	for _, ap := range svc.additionalSyncPaths {
-		toSync = append(toSync, getAllFilesToSync(ap, svc.fileLastModifiedMillis)...)
-	}
+	toSync := svc.filesToSync()
+	syncer := svc.syncer
+	startedAt := svc.getClock().Now()
 	svc.lock.Unlock()
 
+	svc.syncStatusLock.Lock()
+	svc.syncGeneration++
+	generation := svc.syncGeneration
+	svc.syncStatus.InProgress = len(toSync) > 0
+	svc.syncStatus.FilesRemaining = len(toSync)
+	svc.syncStatus.BytesUploaded = 0
+	svc.syncStatus.StartedAt = startedAt
+	svc.syncStatusLock.Unlock()
+
 	for _, p := range toSync {
-		svc.syncer.SyncFile(p)
+		var size int64
+		if info, err := os.Stat(p); err == nil {
+			size = info.Size()
+		}
+		syncer.SyncFile(p, func(err error) {
+			svc.finishFileSync(generation, size, err)
+		})
 	}
+
+	// Syncing deletes uploaded files, so try capturing again if it was paused because the disk was full.
+	svc.diskFull.retry()
+}
+
+// filesToSync returns the files in the capture directory and the additional sync paths that are ready to be
+// synced. It must be called with svc.lock held.
+func (svc *builtIn) filesToSync() []string {
+	toSync := getAllFilesToSync(svc.captureDir, nil, svc.fileLastModifiedMillis, svc.inProgressFileStuckMillis, svc.getClock())
+	for _, ap := range svc.additionalSyncPaths {
+		apFiles := getAllFilesToSync(ap.Path, ap.matches, svc.fileLastModifiedMillis, svc.inProgressFileStuckMillis, svc.getClock())
+		if !svc.loggedSyncPaths[ap.Path] {
+			svc.logger.Infof("additional sync path %s matched %d files", ap.Path, len(apFiles))
+			svc.loggedSyncPaths[ap.Path] = true
+		}
+		toSync = append(toSync, apFiles...)
+	}
+	return toSync
 }
 
-//nolint
-func getAllFilesToSync(dir string, lastModifiedMillis int) []string {
+// getAllFilesToSync returns the files under dir that are ready to be synced: completed capture files, in-progress
+// capture files unmodified for stuckInProgressMillis and other files unmodified for lastModifiedMillis. If matches
+// is not nil, only files whose path relative to dir it matches are returned.
+//
+//nolint:nilerr
+func getAllFilesToSync(
+	dir string, matches func(relPath string) bool, lastModifiedMillis, stuckInProgressMillis int, c clk.Clock,
+) []string {
 	var filePaths []string
 	_ = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -638,16 +1104,22 @@ func getAllFilesToSync(dir string, lastModifiedMillis int) []string {
 		if info.IsDir() {
 			return nil
 		}
+		if matches != nil {
+			relPath, err := filepath.Rel(dir, path)
+			if err != nil || !matches(relPath) {
+				return nil
+			}
+		}
 		// If a file was modified within the past lastModifiedMillis, do not sync it (data
 		// may still be being written).
-		timeSinceMod := clock.Since(info.ModTime())
+		timeSinceMod := c.Since(info.ModTime())
 		// When using a mock clock in tests, this can be negative since the file system will still use the system clock.
 		// Take max(timeSinceMod, 0) to account for this.
 		if timeSinceMod < 0 {
 			timeSinceMod = 0
 		}
 		isStuckInProgressCaptureFile := filepath.Ext(path) == datacapture.InProgressFileExt &&
-			timeSinceMod >= defaultFileLastModifiedMillis*time.Millisecond
+			timeSinceMod >= time.Duration(stuckInProgressMillis)*time.Millisecond
 		isNonCaptureFileThatIsNotBeingWrittenTo := filepath.Ext(path) != datacapture.InProgressFileExt &&
 			timeSinceMod >= time.Duration(lastModifiedMillis)*time.Millisecond
 		isCompletedCaptureFile := filepath.Ext(path) == datacapture.FileExt
@@ -677,6 +1149,26 @@ func (svc *builtIn) updateDataCaptureConfigs(
 	}
 }
 
+// mergeTags returns the service-level tags followed by any resource-level tags that are not already
+// present, in order.
+func mergeTags(serviceTags, resourceTags []string) []string {
+	if len(resourceTags) == 0 {
+		return serviceTags
+	}
+	merged := make([]string, 0, len(serviceTags)+len(resourceTags))
+	seen := make(map[string]struct{}, len(serviceTags)+len(resourceTags))
+	for _, tags := range [][]string{serviceTags, resourceTags} {
+		for _, tag := range tags {
+			if _, ok := seen[tag]; ok {
+				continue
+			}
+			seen[tag] = struct{}{}
+			merged = append(merged, tag)
+		}
+	}
+	return merged
+}
+
 func generateMetadataKey(component, method string) string {
 	return fmt.Sprintf("%s/%s", component, method)
 }
@@ -3,15 +3,23 @@ package builtin
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
 	"image"
 	"image/png"
 	"os"
 	"path/filepath"
+	"sync/atomic"
+	"syscall"
 	"testing"
 	"time"
 
+	clk "github.com/benbjohnson/clock"
 	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	v1 "go.viam.com/api/app/datasync/v1"
 	"go.viam.com/test"
+	"go.viam.com/utils/testutils"
 
 	"go.viam.com/rdk/components/arm"
 	"go.viam.com/rdk/components/camera"
@@ -23,6 +31,8 @@ import (
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/robot"
 	"go.viam.com/rdk/services/datamanager"
+	"go.viam.com/rdk/services/datamanager/datacapture"
+	"go.viam.com/rdk/services/datamanager/datasync"
 	"go.viam.com/rdk/services/datamanager/internal"
 	"go.viam.com/rdk/spatialmath"
 	"go.viam.com/rdk/testutils/inject"
@@ -128,6 +138,39 @@ func TestGetDurationFromHz(t *testing.T) {
 	test.That(t, GetDurationFromHz(1), test.ShouldEqual, time.Second)
 	test.That(t, GetDurationFromHz(1000), test.ShouldEqual, time.Millisecond)
 	test.That(t, GetDurationFromHz(0), test.ShouldEqual, 0)
+	// float32 division would give 333333344ns for 3Hz
+	test.That(t, GetDurationFromHz(3), test.ShouldEqual, 333333333*time.Nanosecond)
+	test.That(t, GetDurationFromHz(7000), test.ShouldEqual, 142857*time.Nanosecond)
+	test.That(t, GetDurationFromHz(10000), test.ShouldEqual, 100*time.Microsecond)
+}
+
+func TestCaptureQueueDuration(t *testing.T) {
+	test.That(t, captureQueueDuration(defaultCaptureQueueSize, 1000), test.ShouldEqual, 250*time.Millisecond)
+	test.That(t, captureQueueDuration(defaultCaptureQueueSize, 10000), test.ShouldEqual, 25*time.Millisecond)
+	test.That(t, captureQueueDuration(defaultCaptureQueueSize, 10000), test.ShouldBeLessThan, minCaptureQueueDuration)
+}
+
+func TestDefaultCaptureQueueAndBufferSizes(t *testing.T) {
+	for _, tc := range []struct {
+		hz         float32
+		queueSize  int
+		bufferSize int
+	}{
+		{hz: 0, queueSize: defaultCaptureQueueSize, bufferSize: defaultCaptureBufferSize},
+		{hz: 1, queueSize: defaultCaptureQueueSize, bufferSize: defaultCaptureBufferSize},
+		{hz: 1000, queueSize: defaultCaptureQueueSize, bufferSize: defaultCaptureBufferSize},
+		{hz: 1001, queueSize: 251, bufferSize: 4112},
+		{hz: 10000, queueSize: 2500, bufferSize: 40960},
+		{hz: 100000, queueSize: 25000, bufferSize: 409600},
+		{hz: 1000000, queueSize: 250000, bufferSize: maxDefaultCaptureBufferSize},
+	} {
+		queueSize, bufferSize := defaultCaptureQueueAndBufferSizes(tc.hz)
+		test.That(t, queueSize, test.ShouldEqual, tc.queueSize)
+		test.That(t, bufferSize, test.ShouldEqual, tc.bufferSize)
+		if tc.hz > 0 {
+			test.That(t, captureQueueDuration(queueSize, tc.hz), test.ShouldBeGreaterThanOrEqualTo, minCaptureQueueDuration)
+		}
+	}
 }
 
 func TestUntrustedEnv(t *testing.T) {
@@ -158,3 +201,466 @@ func getAllFileInfos(dir string) []os.FileInfo {
 	})
 	return files
 }
+
+func TestReadyToSyncKey(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	s := &inject.Sensor{}
+	s.ReadingsFunc = func(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+		return map[string]interface{}{datamanager.ShouldSyncKey: false, "custom_key": true, "not_bool": "true"}, nil
+	}
+	test.That(t, readyToSync(context.Background(), s, datamanager.ShouldSyncKey, logger), test.ShouldBeFalse)
+	test.That(t, readyToSync(context.Background(), s, "custom_key", logger), test.ShouldBeTrue)
+	test.That(t, readyToSync(context.Background(), s, "not_bool", logger), test.ShouldBeFalse)
+	test.That(t, readyToSync(context.Background(), s, "missing_key", logger), test.ShouldBeFalse)
+}
+
+func TestAllReadyToSync(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	syncer := func(ready bool, err error) selectiveSyncer {
+		s := &inject.Sensor{}
+		s.ReadingsFunc = func(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+			return map[string]interface{}{datamanager.ShouldSyncKey: ready}, err
+		}
+		return s
+	}
+	ready, notReady, failing := syncer(true, nil), syncer(false, nil), syncer(true, errors.New("no wifi"))
+
+	for _, tc := range []struct {
+		syncers  []selectiveSyncer
+		all, any bool
+	}{
+		{[]selectiveSyncer{ready}, true, true},
+		{[]selectiveSyncer{notReady}, false, false},
+		{[]selectiveSyncer{ready, ready}, true, true},
+		{[]selectiveSyncer{ready, notReady}, false, true},
+		// sensors that fail to read or to initialize are not ready.
+		{[]selectiveSyncer{ready, failing}, false, true},
+		{[]selectiveSyncer{ready, nil}, false, true},
+		{[]selectiveSyncer{failing, nil}, false, false},
+	} {
+		test.That(t, allReadyToSync(context.Background(), tc.syncers, datamanager.ShouldSyncKey, selectiveSyncModeAll, logger),
+			test.ShouldEqual, tc.all)
+		test.That(t, allReadyToSync(context.Background(), tc.syncers, datamanager.ShouldSyncKey, selectiveSyncModeAny, logger),
+			test.ShouldEqual, tc.any)
+	}
+
+	conf := &Config{SelectiveSyncerName: "wifi", SelectiveSyncerNames: []string{"battery", "wifi"}}
+	test.That(t, conf.selectiveSyncerNames(), test.ShouldResemble, []string{"wifi", "battery"})
+	_, err := conf.Validate("")
+	test.That(t, err, test.ShouldBeNil)
+	_, err = (&Config{SelectiveSyncerNames: []string{"wifi", " "}}).Validate("")
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = (&Config{SelectiveSyncerNames: []string{"wifi"}, SelectiveSyncMode: "either"}).Validate("")
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestMergeTags(t *testing.T) {
+	test.That(t, mergeTags(nil, nil), test.ShouldBeNil)
+	test.That(t, mergeTags([]string{"a", "b"}, nil), test.ShouldResemble, []string{"a", "b"})
+	test.That(t, mergeTags(nil, []string{"thermal"}), test.ShouldResemble, []string{"thermal"})
+	test.That(t, mergeTags([]string{"a", "b"}, []string{"thermal", "b"}), test.ShouldResemble, []string{"a", "b", "thermal"})
+	test.That(t, mergeTags([]string{"a", "a"}, []string{"c", "c"}), test.ShouldResemble, []string{"a", "c"})
+}
+
+func TestEnforceCaptureDirSize(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	writeFile := func(dir, name string, size int, modTime time.Time) string {
+		path := filepath.Join(dir, name)
+		test.That(t, os.WriteFile(path, make([]byte, size), 0o600), test.ShouldBeNil)
+		test.That(t, os.Chtimes(path, modTime, modTime), test.ShouldBeNil)
+		return path
+	}
+	now := time.Now()
+
+	t.Run("delete_oldest deletes the oldest completed capture files", func(t *testing.T) {
+		dir := t.TempDir()
+		test.That(t, os.MkdirAll(filepath.Join(dir, datasync.FailedDir), 0o700), test.ShouldBeNil)
+		oldest := writeFile(dir, "oldest"+datacapture.FileExt, 100, now.Add(-3*time.Hour))
+		older := writeFile(dir, "older"+datacapture.FileExt, 100, now.Add(-2*time.Hour))
+		newest := writeFile(dir, "newest"+datacapture.FileExt, 100, now.Add(-time.Hour))
+		inProgress := writeFile(dir, "in_progress"+datacapture.InProgressFileExt, 100, now.Add(-4*time.Hour))
+		failed := writeFile(filepath.Join(dir, datasync.FailedDir), "failed"+datacapture.FileExt, 100, now.Add(-5*time.Hour))
+
+		svc := &builtIn{logger: logger}
+		svc.enforceCaptureDirSize(dir, 350, captureDirSizeBehaviorDeleteOldest)
+		for _, path := range []string{oldest, older} {
+			_, err := os.Stat(path)
+			test.That(t, os.IsNotExist(err), test.ShouldBeTrue)
+		}
+		for _, path := range []string{newest, inProgress, failed} {
+			_, err := os.Stat(path)
+			test.That(t, err, test.ShouldBeNil)
+		}
+		test.That(t, svc.capturePaused.Load(), test.ShouldBeFalse)
+	})
+
+	t.Run("pause_capture pauses until the directory is back under the cap", func(t *testing.T) {
+		dir := t.TempDir()
+		path := writeFile(dir, "file"+datacapture.FileExt, 200, now)
+
+		svc := &builtIn{logger: logger}
+		svc.enforceCaptureDirSize(dir, 100, captureDirSizeBehaviorPauseCapture)
+		test.That(t, svc.capturePaused.Load(), test.ShouldBeTrue)
+		_, err := os.Stat(path)
+		test.That(t, err, test.ShouldBeNil)
+
+		w := newPausableWriter(nil, &svc.capturePaused, &svc.diskFull)
+		test.That(t, w.Write(nil), test.ShouldBeNil)
+
+		test.That(t, os.Remove(path), test.ShouldBeNil)
+		svc.enforceCaptureDirSize(dir, 100, captureDirSizeBehaviorPauseCapture)
+		test.That(t, svc.capturePaused.Load(), test.ShouldBeFalse)
+	})
+}
+
+func TestReconfigureDuringCaptureDirSizeCheck(t *testing.T) {
+	clock = clk.NewMock()
+	instanceClock := clk.NewMock()
+
+	captureDir := t.TempDir()
+	for i := 0; i < 10; i++ {
+		path := filepath.Join(captureDir, fmt.Sprintf("file%d%s", i, datacapture.FileExt))
+		test.That(t, os.WriteFile(path, make([]byte, 100), 0o600), test.ShouldBeNil)
+	}
+	cfg, deps := setupConfig(t, disabledTabularCollectorConfigPath)
+	cfg.ScheduledSyncDisabled = true
+	cfg.CaptureDisabled = true
+	cfg.CaptureDir = captureDir
+	cfg.Clock = instanceClock
+	cfg.MaximumCaptureDirSizeGB = 100 / bytesPerGB
+	cfg.MaximumCaptureDirSizeBehavior = captureDirSizeBehaviorPauseCapture
+
+	dmsvc, r := newTestDataManager(t)
+	defer func() {
+		test.That(t, dmsvc.Close(context.Background()), test.ShouldBeNil)
+	}()
+	reconfigure := func() {
+		t.Helper()
+		done := make(chan error, 1)
+		go func() {
+			done <- dmsvc.Reconfigure(context.Background(), resourcesFromDeps(t, r, deps), resource.Config{
+				ConvertedAttributes: cfg,
+			})
+		}()
+		select {
+		case err := <-done:
+			test.That(t, err, test.ShouldBeNil)
+		case <-time.After(10 * time.Second):
+			t.Fatal("reconfigure deadlocked with the capture directory size checker")
+		}
+	}
+	reconfigure()
+
+	// Hold the service lock as Reconfigure does while a check is triggered. The check must still complete,
+	// since Reconfigure waits for it to stop.
+	svc := dmsvc.(*builtIn)
+	svc.lock.Lock()
+	instanceClock.Add(captureDirSizeCheckInterval)
+	deadline := time.Now().Add(10 * time.Second)
+	for !svc.capturePaused.Load() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	paused := svc.capturePaused.Load()
+	svc.lock.Unlock()
+	test.That(t, paused, test.ShouldBeTrue)
+
+	// Restarting the checker with a new maximum size resumes capture.
+	cfg.MaximumCaptureDirSizeGB = 1
+	reconfigure()
+	test.That(t, svc.capturePaused.Load(), test.ShouldBeFalse)
+}
+
+// fullDiskWriter is a datacapture.BufferedWriter whose writes fail as if the disk were full while full is set.
+type fullDiskWriter struct {
+	datacapture.BufferedWriter
+	full   bool
+	writes int
+}
+
+func (w *fullDiskWriter) Write(item *v1.SensorData) error {
+	if w.full {
+		return &os.PathError{Op: "write", Path: "data.prog", Err: syscall.ENOSPC}
+	}
+	w.writes++
+	return nil
+}
+
+func TestDiskFull(t *testing.T) {
+	logger, logs := logging.NewObservedTestLogger(t)
+	svc := &builtIn{logger: logger, diskFull: diskFullState{logger: logger}}
+	full := &fullDiskWriter{full: true}
+	other := &fullDiskWriter{}
+	w := newPausableWriter(full, &svc.capturePaused, &svc.diskFull)
+	otherW := newPausableWriter(other, &svc.capturePaused, &svc.diskFull)
+
+	// a full disk pauses every collector rather than failing the one that hit it.
+	test.That(t, w.Write(&v1.SensorData{}), test.ShouldBeNil)
+	test.That(t, svc.diskFull.paused(), test.ShouldBeTrue)
+	test.That(t, otherW.Write(&v1.SensorData{}), test.ShouldBeNil)
+	test.That(t, other.writes, test.ShouldEqual, 0)
+	test.That(t, logs.FilterMessageSnippet("disk is full").Len(), test.ShouldEqual, 1)
+
+	// retrying while the disk is still full pauses capture again without logging again.
+	svc.diskFull.retry()
+	test.That(t, svc.diskFull.paused(), test.ShouldBeFalse)
+	test.That(t, w.Write(&v1.SensorData{}), test.ShouldBeNil)
+	test.That(t, svc.diskFull.paused(), test.ShouldBeTrue)
+	test.That(t, logs.FilterMessageSnippet("disk is full").Len(), test.ShouldEqual, 1)
+
+	// once space is freed, capture resumes.
+	full.full = false
+	svc.diskFull.retry()
+	test.That(t, w.Write(&v1.SensorData{}), test.ShouldBeNil)
+	test.That(t, otherW.Write(&v1.SensorData{}), test.ShouldBeNil)
+	test.That(t, full.writes, test.ShouldEqual, 1)
+	test.That(t, other.writes, test.ShouldEqual, 1)
+	test.That(t, logs.FilterMessageSnippet("resumed data capture").Len(), test.ShouldEqual, 1)
+
+	// other write errors are returned as before.
+	w = newPausableWriter(&failingWriter{}, &svc.capturePaused, &svc.diskFull)
+	test.That(t, w.Write(&v1.SensorData{}), test.ShouldNotBeNil)
+	test.That(t, svc.diskFull.paused(), test.ShouldBeFalse)
+}
+
+type failingWriter struct {
+	datacapture.BufferedWriter
+}
+
+func (w *failingWriter) Write(item *v1.SensorData) error {
+	return errors.New("bad write")
+}
+
+func TestGetAllFilesToSync(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name string, age time.Duration) string {
+		path := filepath.Join(dir, name)
+		test.That(t, os.WriteFile(path, []byte("data"), 0o600), test.ShouldBeNil)
+		modTime := time.Now().Add(-age)
+		test.That(t, os.Chtimes(path, modTime, modTime), test.ShouldBeNil)
+		return path
+	}
+	completed := writeFile("completed"+datacapture.FileExt, 0)
+	inProgress := writeFile("in_progress"+datacapture.InProgressFileExt, 0)
+	stuck := writeFile("stuck"+datacapture.InProgressFileExt, 3*time.Minute)
+	arbitrary := writeFile("arbitrary.txt", time.Minute)
+	recent := writeFile("recent.txt", 0)
+
+	// in-progress files are stuck after their own threshold, independent of the one for arbitrary files.
+	toSync := getAllFilesToSync(dir, nil, int(30*time.Second/time.Millisecond), int(2*time.Minute/time.Millisecond), clk.New())
+	test.That(t, toSync, test.ShouldHaveLength, 3)
+	test.That(t, toSync, test.ShouldContain, completed)
+	test.That(t, toSync, test.ShouldContain, stuck)
+	test.That(t, toSync, test.ShouldContain, arbitrary)
+
+	toSync = getAllFilesToSync(dir, nil, int(30*time.Second/time.Millisecond), int(5*time.Minute/time.Millisecond), clk.New())
+	test.That(t, toSync, test.ShouldHaveLength, 2)
+	test.That(t, toSync, test.ShouldNotContain, stuck)
+	test.That(t, toSync, test.ShouldNotContain, inProgress)
+	test.That(t, toSync, test.ShouldNotContain, recent)
+}
+
+func TestAdditionalSyncPaths(t *testing.T) {
+	var attrs utils.AttributeMap
+	test.That(t, json.Unmarshal([]byte(`{
+		"additional_sync_paths": ["/plain"],
+		"filtered_sync_paths": [{"path": "/images", "include": ["*.jpg", "*.png"], "exclude": ["thumbnails/*"]}]
+	}`), &attrs), test.ShouldBeNil)
+	cfg, err := resource.TransformAttributeMap[*Config](attrs)
+	test.That(t, err, test.ShouldBeNil)
+	_, err = cfg.Validate("")
+	test.That(t, err, test.ShouldBeNil)
+
+	paths, err := parseAdditionalSyncPaths(cfg.AdditionalSyncPaths, cfg.FilteredSyncPaths)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, paths, test.ShouldResemble, []FilteredSyncPath{
+		{Path: "/plain"},
+		{Path: "/images", Include: []string{"*.jpg", "*.png"}, Exclude: []string{"thumbnails/*"}},
+	})
+
+	// plain paths sync everything.
+	test.That(t, paths[0].matches("app.log"), test.ShouldBeTrue)
+	test.That(t, paths[1].matches("a.jpg"), test.ShouldBeTrue)
+	test.That(t, paths[1].matches(filepath.Join("2024", "b.png")), test.ShouldBeTrue)
+	test.That(t, paths[1].matches("app.log"), test.ShouldBeFalse)
+	test.That(t, paths[1].matches(filepath.Join("thumbnails", "a.jpg")), test.ShouldBeFalse)
+
+	for _, invalid := range []FilteredSyncPath{
+		{Include: []string{"*.jpg"}},
+		{Path: "/images", Include: []string{"[*.jpg"}},
+		{Path: "/images", Exclude: []string{"[*.jpg"}},
+	} {
+		_, err := (&Config{FilteredSyncPaths: []FilteredSyncPath{invalid}}).Validate("")
+		test.That(t, err, test.ShouldNotBeNil)
+	}
+
+	dir := t.TempDir()
+	for _, name := range []string{"a.jpg", "app.log", filepath.Join("thumbnails", "a.jpg")} {
+		test.That(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o700), test.ShouldBeNil)
+		test.That(t, os.WriteFile(filepath.Join(dir, name), []byte("data"), 0o600), test.ShouldBeNil)
+	}
+	toSync := getAllFilesToSync(dir, paths[1].matches, 0, 0, clk.New())
+	test.That(t, toSync, test.ShouldResemble, []string{filepath.Join(dir, "a.jpg")})
+	test.That(t, getAllFilesToSync(dir, nil, 0, 0, clk.New()), test.ShouldHaveLength, 3)
+}
+
+func TestInProgressFileStuckMillis(t *testing.T) {
+	dmsvc, r := newTestDataManager(t)
+	defer dmsvc.Close(context.Background())
+	svc := dmsvc.(*builtIn)
+	test.That(t, svc.inProgressFileStuckMillis, test.ShouldEqual, defaultFileLastModifiedMillis)
+
+	reconfigure := func(cfg *Config) {
+		cfg.CaptureDir = t.TempDir()
+		cfg.ScheduledSyncDisabled = true
+		err := dmsvc.Reconfigure(context.Background(), resourcesFromDeps(t, r, []string{cloud.InternalServiceName.String()}),
+			resource.Config{ConvertedAttributes: cfg})
+		test.That(t, err, test.ShouldBeNil)
+	}
+
+	// without its own threshold, the one for arbitrary files is used.
+	reconfigure(&Config{FileLastModifiedMillis: 30000})
+	test.That(t, svc.fileLastModifiedMillis, test.ShouldEqual, 30000)
+	test.That(t, svc.inProgressFileStuckMillis, test.ShouldEqual, 30000)
+
+	reconfigure(&Config{FileLastModifiedMillis: 30000, InProgressFileStuckMillis: 120000})
+	test.That(t, svc.fileLastModifiedMillis, test.ShouldEqual, 30000)
+	test.That(t, svc.inProgressFileStuckMillis, test.ShouldEqual, 120000)
+}
+
+func TestRetryFailedSync(t *testing.T) {
+	dmsvc, r := newTestDataManager(t)
+	defer dmsvc.Close(context.Background())
+	captureDir, additionalDir := t.TempDir(), t.TempDir()
+	err := dmsvc.Reconfigure(context.Background(), resourcesFromDeps(t, r, []string{cloud.InternalServiceName.String()}),
+		resource.Config{ConvertedAttributes: &Config{
+			CaptureDir:            captureDir,
+			AdditionalSyncPaths:   []string{additionalDir},
+			ScheduledSyncDisabled: true,
+		}})
+	test.That(t, err, test.ShouldBeNil)
+	svc := dmsvc.(*builtIn)
+
+	// capture files are moved to the failed directory at the root of the capture directory and arbitrary files
+	// to the one in their own directory.
+	failed := map[string]string{
+		filepath.Join(captureDir, datasync.FailedDir, "arm", "arm1", "a.capture"):   filepath.Join(captureDir, "arm", "arm1", "a.capture"),
+		filepath.Join(additionalDir, "logs", datasync.FailedDir, "app.log"):         filepath.Join(additionalDir, "logs", "app.log"),
+		filepath.Join(additionalDir, "logs", datasync.FailedDir, "conflict.log"):    filepath.Join(additionalDir, "logs", "conflict.log"),
+		filepath.Join(additionalDir, "images", datasync.FailedDir, "2024", "a.jpg"): filepath.Join(additionalDir, "images", "2024", "a.jpg"),
+	}
+	for failedPath := range failed {
+		test.That(t, os.MkdirAll(filepath.Dir(failedPath), 0o700), test.ShouldBeNil)
+		test.That(t, os.WriteFile(failedPath, []byte("data"), 0o600), test.ShouldBeNil)
+	}
+	conflict := filepath.Join(additionalDir, "logs", "conflict.log")
+	test.That(t, os.WriteFile(conflict, []byte("newer"), 0o600), test.ShouldBeNil)
+
+	resp, err := svc.DoCommand(context.Background(), map[string]interface{}{"command": listFailedCommand})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp["count"], test.ShouldEqual, 4)
+	test.That(t, resp["total_bytes"], test.ShouldEqual, int64(16))
+	test.That(t, resp["files"], test.ShouldHaveLength, 4)
+
+	resp, err = svc.DoCommand(context.Background(), map[string]interface{}{"command": retryFailedCommand})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp["count"], test.ShouldEqual, 3)
+	test.That(t, resp["total_bytes"], test.ShouldEqual, int64(12))
+	test.That(t, resp["errors"], test.ShouldHaveLength, 1)
+	for failedPath, originalPath := range failed {
+		if originalPath == conflict {
+			// the file written since is not overwritten.
+			content, err := os.ReadFile(conflict)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, string(content), test.ShouldEqual, "newer")
+			continue
+		}
+		_, err := os.Stat(failedPath)
+		test.That(t, os.IsNotExist(err), test.ShouldBeTrue)
+		_, err = os.Stat(originalPath)
+		test.That(t, err, test.ShouldBeNil)
+	}
+
+	resp, err = svc.DoCommand(context.Background(), map[string]interface{}{"command": listFailedCommand})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp["count"], test.ShouldEqual, 1)
+}
+
+func TestSyncStatus(t *testing.T) {
+	originalMaxSyncAttempts := datasync.MaxSyncAttempts.Load()
+	datasync.MaxSyncAttempts.Store(1)
+	defer datasync.MaxSyncAttempts.Store(originalMaxSyncAttempts)
+
+	newSvc := func(t *testing.T, client mockDataSyncServiceClient) (*builtIn, string) {
+		t.Helper()
+		dir := t.TempDir()
+		for _, name := range []string{"a", "b"} {
+			test.That(t, os.WriteFile(filepath.Join(dir, name+".txt"), make([]byte, 10), 0o600), test.ShouldBeNil)
+		}
+		logger := logging.NewTestLogger(t)
+		syncer, err := datasync.NewManager("part", client, logger, dir)
+		test.That(t, err, test.ShouldBeNil)
+		t.Cleanup(syncer.Close)
+		return &builtIn{
+			logger:     logger,
+			captureDir: dir,
+			collectors: make(map[resourceMethodMetadata]*collectorAndConfig),
+			syncer:     syncer,
+		}, dir
+	}
+
+	t.Run("status follows the uploads", func(t *testing.T) {
+		fail := atomic.Bool{}
+		// uploads block until the test receives them.
+		client := mockDataSyncServiceClient{fileUploads: make(chan *mockFileUploadClient), fail: &fail}
+		svc, _ := newSvc(t, client)
+		test.That(t, svc.SyncStatus(), test.ShouldResemble, SyncStatus{})
+
+		svc.sync()
+		status := svc.SyncStatus()
+		test.That(t, status.InProgress, test.ShouldBeTrue)
+		test.That(t, status.FilesRemaining, test.ShouldEqual, 2)
+		test.That(t, status.BytesUploaded, test.ShouldEqual, 0)
+		test.That(t, status.StartedAt.IsZero(), test.ShouldBeFalse)
+
+		upload := <-client.fileUploads
+		<-upload.closed
+		testutils.WaitForAssertion(t, func(tb testing.TB) {
+			tb.Helper()
+			test.That(tb, svc.SyncStatus().FilesRemaining, test.ShouldEqual, 1)
+		})
+		status = svc.SyncStatus()
+		test.That(t, status.InProgress, test.ShouldBeTrue)
+		test.That(t, status.BytesUploaded, test.ShouldEqual, 10)
+
+		upload = <-client.fileUploads
+		<-upload.closed
+		testutils.WaitForAssertion(t, func(tb testing.TB) {
+			tb.Helper()
+			test.That(tb, svc.SyncStatus().InProgress, test.ShouldBeFalse)
+		})
+		status = svc.SyncStatus()
+		test.That(t, status.FilesRemaining, test.ShouldEqual, 0)
+		test.That(t, status.BytesUploaded, test.ShouldEqual, 20)
+		test.That(t, status.LastError, test.ShouldBeNil)
+	})
+
+	t.Run("failed uploads are reported", func(t *testing.T) {
+		fail := atomic.Bool{}
+		fail.Store(true)
+		svc, dir := newSvc(t, mockDataSyncServiceClient{fail: &fail})
+
+		svc.sync()
+		testutils.WaitForAssertion(t, func(tb testing.TB) {
+			tb.Helper()
+			test.That(tb, svc.SyncStatus().InProgress, test.ShouldBeFalse)
+		})
+		status := svc.SyncStatus()
+		test.That(t, status.FilesRemaining, test.ShouldEqual, 0)
+		test.That(t, status.BytesUploaded, test.ShouldEqual, 0)
+		test.That(t, status.LastError, test.ShouldNotBeNil)
+		test.That(t, status.LastError.Error(), test.ShouldContainSubstring, "oh no error")
+		failed, err := os.ReadDir(filepath.Join(dir, datasync.FailedDir))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, failed, test.ShouldHaveLength, 2)
+	})
+}
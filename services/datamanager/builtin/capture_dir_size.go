@@ -0,0 +1,189 @@
+package builtin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	v1 "go.viam.com/api/app/datasync/v1"
+	goutils "go.viam.com/utils"
+
+	"go.viam.com/rdk/services/datamanager/datacapture"
+	"go.viam.com/rdk/services/datamanager/datasync"
+)
+
+const (
+	// captureDirSizeBehaviorDeleteOldest deletes the oldest completed capture files when the capture directory
+	// exceeds its maximum size.
+	captureDirSizeBehaviorDeleteOldest = "delete_oldest"
+	// captureDirSizeBehaviorPauseCapture stops writing captured data to disk until the capture directory is
+	// back under its maximum size.
+	captureDirSizeBehaviorPauseCapture = "pause_capture"
+
+	bytesPerGB = 1e9
+	// captureDirSizeWarningRatio is the fraction of the maximum capture directory size at which a warning is logged.
+	captureDirSizeWarningRatio = 0.9
+)
+
+// How often to check the size of the capture directory when a maximum size is configured.
+var captureDirSizeCheckInterval = 10 * time.Second
+
+// pausableWriter is a datacapture.BufferedWriter that drops writes while capture is paused, either explicitly or
+// because the disk is full. A write that fails because the disk is full pauses capture instead of failing.
+type pausableWriter struct {
+	datacapture.BufferedWriter
+	paused   *atomic.Bool
+	diskFull *diskFullState
+}
+
+func (w *pausableWriter) Write(item *v1.SensorData) error {
+	if w.paused.Load() || w.diskFull.paused() {
+		return nil
+	}
+	if err := w.BufferedWriter.Write(item); err != nil {
+		if isDiskFullError(err) {
+			w.diskFull.pause(err)
+			return nil
+		}
+		return err
+	}
+	w.diskFull.written()
+	return nil
+}
+
+// newPausableWriter wraps w so that writes are dropped while paused is set or diskFull is paused.
+func newPausableWriter(w datacapture.BufferedWriter, paused *atomic.Bool, diskFull *diskFullState) datacapture.BufferedWriter {
+	return &pausableWriter{BufferedWriter: w, paused: paused, diskFull: diskFull}
+}
+
+// startCaptureDirSizeChecker starts the goroutine that keeps captureDir under maxBytes using behavior.
+// It must be called with svc.lock held. The goroutine never takes svc.lock, so cancelCaptureDirSizeChecker
+// can wait for it while holding the lock; it is restarted when any of its arguments change.
+func (svc *builtIn) startCaptureDirSizeChecker(captureDir string, maxBytes int64, behavior string) {
+	cancelCtx, fn := context.WithCancel(context.Background())
+	svc.captureDirSizeCancelFn = fn
+	ticker := svc.getClock().Ticker(captureDirSizeCheckInterval)
+	svc.captureDirSizeWorker.Add(1)
+	goutils.PanicCapturingGo(func() {
+		defer svc.captureDirSizeWorker.Done()
+		defer ticker.Stop()
+		for {
+			select {
+			case <-cancelCtx.Done():
+				return
+			case <-ticker.C:
+				svc.enforceCaptureDirSize(captureDir, maxBytes, behavior)
+			}
+		}
+	})
+}
+
+// cancelCaptureDirSizeChecker stops the goroutine that enforces the maximum capture directory size and
+// resumes capture if it was paused. It must be called with svc.lock held.
+func (svc *builtIn) cancelCaptureDirSizeChecker() {
+	if svc.captureDirSizeCancelFn != nil {
+		svc.captureDirSizeCancelFn()
+		svc.captureDirSizeWorker.Wait()
+		svc.captureDirSizeCancelFn = nil
+	}
+	svc.capturePaused.Store(false)
+	svc.captureDirNearMax.Store(false)
+}
+
+// enforceCaptureDirSize logs a warning when captureDir approaches maxBytes and, once it exceeds maxBytes,
+// either deletes the oldest completed capture files or pauses capture depending on behavior.
+func (svc *builtIn) enforceCaptureDirSize(captureDir string, maxBytes int64, behavior string) {
+	size := dirSize(captureDir)
+	if size < int64(float64(maxBytes)*captureDirSizeWarningRatio) {
+		svc.captureDirNearMax.Store(false)
+		if svc.capturePaused.CompareAndSwap(true, false) {
+			svc.logger.Infow("capture directory is back under its maximum size; resuming capture",
+				"capture_dir", captureDir, "size_bytes", size, "max_bytes", maxBytes)
+		}
+		return
+	}
+	// Only warn when first crossing the threshold rather than on every check.
+	if !svc.captureDirNearMax.Swap(true) {
+		svc.logger.Warnw("capture directory is approaching its maximum size",
+			"capture_dir", captureDir, "size_bytes", size, "max_bytes", maxBytes)
+	}
+	if size <= maxBytes {
+		return
+	}
+
+	if behavior == captureDirSizeBehaviorPauseCapture {
+		if !svc.capturePaused.Swap(true) {
+			svc.logger.Warnw("capture directory exceeds its maximum size; pausing capture until data is synced",
+				"capture_dir", captureDir, "size_bytes", size, "max_bytes", maxBytes)
+		}
+		return
+	}
+
+	deleted := deleteOldestCaptureFiles(captureDir, size-maxBytes)
+	svc.logger.Warnw("capture directory exceeds its maximum size; deleted oldest capture files",
+		"capture_dir", captureDir, "size_bytes", size, "max_bytes", maxBytes, "num_deleted", deleted)
+}
+
+// dirSize returns the total size in bytes of all files under dir.
+func dirSize(dir string) int64 {
+	var size int64
+	//nolint:errcheck
+	_ = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			//nolint:nilerr
+			return nil
+		}
+		if !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
+
+// deleteOldestCaptureFiles deletes completed capture files under dir, oldest first, until at least
+// toFree bytes have been deleted or there are no completed capture files left. Files in the failed
+// directory and files still being written to are never deleted. It returns the number of deleted files.
+func deleteOldestCaptureFiles(dir string, toFree int64) int {
+	var files []os.FileInfo
+	var paths []string
+	//nolint:errcheck
+	_ = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			//nolint:nilerr
+			return nil
+		}
+		if info.IsDir() && info.Name() == datasync.FailedDir {
+			return filepath.SkipDir
+		}
+		if ext := filepath.Ext(path); !info.IsDir() && (ext == datacapture.FileExt || ext == datacapture.ParquetFileExt) {
+			files = append(files, info)
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	order := make([]int, len(files))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return files[order[i]].ModTime().Before(files[order[j]].ModTime())
+	})
+
+	var freed int64
+	var deleted int
+	for _, i := range order {
+		if freed >= toFree {
+			break
+		}
+		if err := os.Remove(paths[i]); err != nil {
+			continue
+		}
+		freed += files[i].Size()
+		deleted++
+	}
+	return deleted
+}
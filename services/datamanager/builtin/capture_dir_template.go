@@ -0,0 +1,74 @@
+package builtin
+
+import (
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// defaultCaptureDirTemplate is the layout of the capture directory used when no capture_dir_template is
+// configured.
+const defaultCaptureDirTemplate = "{componentType}/{componentName}/{methodName}"
+
+// captureDirTemplateToken matches a token in a capture_dir_template.
+var captureDirTemplateToken = regexp.MustCompile(`\{[^{}]*\}`)
+
+// captureDirTemplateValues are the values substituted for the tokens of a capture_dir_template when building
+// the directory a collector writes a capture file to.
+type captureDirTemplateValues struct {
+	partID        string
+	componentType string
+	componentName string
+	methodName    string
+	// date is the time at which the capture file is created. {date} is replaced with its UTC date, so a
+	// file started before midnight UTC is completed in the previous day's directory.
+	date time.Time
+}
+
+// captureDirTemplateTokens maps each supported token to the value it is replaced with.
+var captureDirTemplateTokens = map[string]func(captureDirTemplateValues) string{
+	"{partID}":        func(v captureDirTemplateValues) string { return v.partID },
+	"{componentType}": func(v captureDirTemplateValues) string { return v.componentType },
+	"{componentName}": func(v captureDirTemplateValues) string { return v.componentName },
+	"{methodName}":    func(v captureDirTemplateValues) string { return v.methodName },
+	"{date}":          func(v captureDirTemplateValues) string { return v.date.UTC().Format("2006-01-02") },
+}
+
+// validateCaptureDirTemplate returns an error if template uses an unknown token or could expand to a
+// directory outside of the capture directory.
+func validateCaptureDirTemplate(template string) error {
+	if template == "" {
+		return nil
+	}
+	for _, token := range captureDirTemplateToken.FindAllString(template, -1) {
+		if _, ok := captureDirTemplateTokens[token]; !ok {
+			return errors.Errorf("capture_dir_template contains unknown token %s", token)
+		}
+	}
+	if strings.ContainsAny(captureDirTemplateToken.ReplaceAllString(template, ""), "{}") {
+		return errors.New("capture_dir_template contains an unterminated token")
+	}
+	if path.IsAbs(template) || strings.HasPrefix(template, `\`) {
+		return errors.New("capture_dir_template must be relative to the capture directory")
+	}
+	for _, segment := range strings.FieldsFunc(template, func(r rune) bool { return r == '/' || r == '\\' }) {
+		if segment == ".." {
+			return errors.New("capture_dir_template must not refer to a parent directory")
+		}
+	}
+	return nil
+}
+
+// expandCaptureDirTemplate replaces the tokens in template, which must be valid, with values. The default
+// template is used if template is empty.
+func expandCaptureDirTemplate(template string, values captureDirTemplateValues) string {
+	if template == "" {
+		template = defaultCaptureDirTemplate
+	}
+	return captureDirTemplateToken.ReplaceAllStringFunc(template, func(token string) string {
+		return captureDirTemplateTokens[token](values)
+	})
+}
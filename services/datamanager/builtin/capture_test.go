@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -347,6 +348,163 @@ func testFilesContainSensorData(t *testing.T, dir string) {
 }
 
 // waitForCaptureFilesToExceedNFiles returns once `captureDir` contains more than `n` files.
+func TestCaptureDirTemplate(t *testing.T) {
+	for _, tc := range []struct {
+		template string
+		err      string
+	}{
+		{template: ""},
+		{template: defaultCaptureDirTemplate},
+		{template: "{partID}/{date}/{componentType}/{componentName}/{methodName}"},
+		{template: "{machine}/{componentName}", err: "unknown token {machine}"},
+		{template: "{componentName", err: "unterminated token"},
+		{template: "/{componentName}", err: "must be relative"},
+		{template: "../{componentName}", err: "parent directory"},
+	} {
+		err := validateCaptureDirTemplate(tc.template)
+		if tc.err == "" {
+			test.That(t, err, test.ShouldBeNil)
+		} else {
+			test.That(t, err, test.ShouldNotBeNil)
+			test.That(t, err.Error(), test.ShouldContainSubstring, tc.err)
+		}
+	}
+
+	captureDir := t.TempDir()
+	mockClock := clk.NewMock()
+	mockClock.Set(time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC))
+	clock = mockClock
+
+	cfg, deps := setupConfig(t, enabledTabularCollectorConfigPath)
+	cfg.ScheduledSyncDisabled = true
+	cfg.CaptureDir = captureDir
+	cfg.CaptureDirTemplate = "{partID}/{date}/{componentName}"
+	_, err := cfg.Validate("")
+	test.That(t, err, test.ShouldBeNil)
+
+	dmsvc, r := newTestDataManager(t)
+	defer func() {
+		test.That(t, dmsvc.Close(context.Background()), test.ShouldBeNil)
+	}()
+	err = dmsvc.Reconfigure(context.Background(), resourcesFromDeps(t, r, deps), resource.Config{
+		ConvertedAttributes: cfg,
+	})
+	test.That(t, err, test.ShouldBeNil)
+
+	info, err := os.Stat(filepath.Join(captureDir, "hello", "2024-03-05", "arm1"))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, info.IsDir(), test.ShouldBeTrue)
+
+	cfg.CaptureDirTemplate = "{nope}"
+	_, err = cfg.Validate("")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "unknown token {nope}")
+}
+
+func TestValidateAggregation(t *testing.T) {
+	cfg, _ := setupConfig(t, enabledTabularCollectorConfigPath)
+	_, err := cfg.Validate("services.0")
+	test.That(t, err, test.ShouldBeNil)
+
+	cfg.ResourceConfigs[0].AggregateWindowMs = -1
+	_, err = cfg.Validate("services.0")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "services.0")
+	test.That(t, err.Error(), test.ShouldContainSubstring, "aggregate_window_ms must not be negative")
+
+	cfg.ResourceConfigs[0].AggregateWindowMs = 1000
+	cfg.ResourceConfigs[0].AggregateFn = "median"
+	_, err = cfg.Validate("services.0")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "services.0")
+	test.That(t, err.Error(), test.ShouldContainSubstring, `got "median"`)
+}
+
+func TestConfiguredClock(t *testing.T) {
+	// The package clock is a mock that never advances, so data is only captured if the configured clock is used.
+	clock = clk.NewMock()
+	instanceClock := clk.NewMock()
+
+	captureDir := t.TempDir()
+	cfg, deps := setupConfig(t, enabledTabularCollectorConfigPath)
+	cfg.ScheduledSyncDisabled = true
+	cfg.CaptureDir = captureDir
+	cfg.Clock = instanceClock
+
+	dmsvc, r := newTestDataManager(t)
+	defer func() {
+		test.That(t, dmsvc.Close(context.Background()), test.ShouldBeNil)
+	}()
+	err := dmsvc.Reconfigure(context.Background(), resourcesFromDeps(t, r, deps), resource.Config{
+		ConvertedAttributes: cfg,
+	})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, dmsvc.(*builtIn).getClock(), test.ShouldEqual, instanceClock)
+
+	passTimeCtx, cancelPassTime := context.WithCancel(context.Background())
+	donePassingTime := passTime(passTimeCtx, instanceClock, captureInterval)
+	waitForCaptureFilesToExceedNFiles(captureDir, 0)
+	testFilesContainSensorData(t, captureDir)
+	cancelPassTime()
+	<-donePassingTime
+
+	// Without a configured clock the package clock is used again.
+	cfg.Clock = nil
+	err = dmsvc.Reconfigure(context.Background(), resourcesFromDeps(t, r, deps), resource.Config{
+		ConvertedAttributes: cfg,
+	})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, dmsvc.(*builtIn).getClock(), test.ShouldEqual, clock)
+}
+
+func TestParquetCaptureFileFormat(t *testing.T) {
+	clock = clk.NewMock()
+	instanceClock := clk.NewMock()
+
+	captureDir := t.TempDir()
+	cfg, deps := setupConfig(t, enabledTabularCollectorConfigPath)
+	cfg.ScheduledSyncDisabled = true
+	cfg.CaptureDir = captureDir
+	cfg.Clock = instanceClock
+	cfg.CaptureFileFormat = "parquet"
+	_, err := cfg.Validate("")
+	test.That(t, err, test.ShouldBeNil)
+
+	dmsvc, r := newTestDataManager(t)
+	err = dmsvc.Reconfigure(context.Background(), resourcesFromDeps(t, r, deps), resource.Config{
+		ConvertedAttributes: cfg,
+	})
+	test.That(t, err, test.ShouldBeNil)
+
+	passTimeCtx, cancelPassTime := context.WithCancel(context.Background())
+	donePassingTime := passTime(passTimeCtx, instanceClock, captureInterval)
+	time.Sleep(100 * time.Millisecond)
+	cancelPassTime()
+	<-donePassingTime
+	// Parquet files are only written once they are complete, which closing the service makes them.
+	test.That(t, dmsvc.Close(context.Background()), test.ShouldBeNil)
+
+	var readings int
+	err = filepath.Walk(captureDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		test.That(t, filepath.Ext(path), test.ShouldEqual, datacapture.ParquetFileExt)
+		md, data, err := datacapture.SensorDataFromParquetFilePath(path)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, md.GetMethodName(), test.ShouldNotBeEmpty)
+		readings += len(data)
+		return nil
+	})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, readings, test.ShouldBeGreaterThan, 0)
+
+	cfg.CaptureFileFormat = "csv"
+	_, err = cfg.Validate("")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "capture_file_format")
+}
+
 func waitForCaptureFilesToExceedNFiles(captureDir string, n int) {
 	totalWait := time.Second * 2
 	waitPerCheck := time.Millisecond * 10
@@ -375,3 +533,62 @@ func resourcesFromDeps(t *testing.T, r robot.Robot, deps []string) resource.Depe
 	}
 	return resources
 }
+
+func TestFlush(t *testing.T) {
+	// The package clock is a mock that never advances, so data is only captured when the configured clock is.
+	clock = clk.NewMock()
+	instanceClock := clk.NewMock()
+
+	captureDir := t.TempDir()
+	cfg, deps := setupConfig(t, enabledTabularCollectorConfigPath)
+	cfg.ScheduledSyncDisabled = true
+	cfg.CaptureDir = captureDir
+	cfg.Clock = instanceClock
+
+	dmsvc, r := newTestDataManager(t)
+	defer func() {
+		test.That(t, dmsvc.Close(context.Background()), test.ShouldBeNil)
+	}()
+	err := dmsvc.Reconfigure(context.Background(), resourcesFromDeps(t, r, deps), resource.Config{
+		ConvertedAttributes: cfg,
+	})
+	test.That(t, err, test.ShouldBeNil)
+
+	_, err = dmsvc.(*builtIn).DoCommand(context.Background(), map[string]interface{}{})
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = dmsvc.(*builtIn).DoCommand(context.Background(), map[string]interface{}{"command": "nope"})
+	test.That(t, err, test.ShouldNotBeNil)
+
+	// Without syncing, flushing writes the captured data to completed capture files.
+	var flushed bool
+	for i := 0; i < 100 && !flushed; i++ {
+		instanceClock.Add(captureInterval)
+		time.Sleep(10 * time.Millisecond)
+		_, err := dmsvc.(*builtIn).DoCommand(context.Background(), map[string]interface{}{"command": flushCommand})
+		test.That(t, err, test.ShouldBeNil)
+		for _, path := range getAllFilePaths(captureDir) {
+			info, err := os.Stat(path)
+			if err == nil && filepath.Ext(path) == datacapture.FileExt && info.Size() > int64(emptyFileBytesSize) {
+				flushed = true
+			}
+		}
+	}
+	test.That(t, flushed, test.ShouldBeTrue)
+
+	// Flushing is safe while the service is being reconfigured and collectors replaced.
+	cfg.CaptureDirTemplate = "{componentName}"
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := dmsvc.(*builtIn).DoCommand(context.Background(), map[string]interface{}{"command": flushCommand})
+			test.That(t, err, test.ShouldBeNil)
+		}()
+	}
+	err = dmsvc.Reconfigure(context.Background(), resourcesFromDeps(t, r, deps), resource.Config{
+		ConvertedAttributes: cfg,
+	})
+	test.That(t, err, test.ShouldBeNil)
+	wg.Wait()
+}
@@ -0,0 +1,57 @@
+package builtin
+
+import (
+	"sync/atomic"
+	"syscall"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/logging"
+)
+
+// diskFullState pauses all capture once a write fails because the disk is full, rather than letting each
+// collector fail on its own while others keep filling the disk. Capture is retried after each sync, since
+// syncing deletes the uploaded files.
+type diskFullState struct {
+	logger logging.Logger
+	// full is set while capture is paused because the disk is full.
+	full atomic.Bool
+	// retrying is set after a sync until the next write shows whether space was freed.
+	retrying atomic.Bool
+}
+
+// isDiskFullError returns whether err is due to the disk being full.
+func isDiskFullError(err error) bool {
+	return errors.Is(err, syscall.ENOSPC)
+}
+
+// paused returns whether capture is paused because the disk is full.
+func (s *diskFullState) paused() bool {
+	return s.full.Load()
+}
+
+// pause pauses capture after err, a disk full error. The error is only logged when capture was not already
+// paused and was not just being retried, so a full disk is logged once until space is freed.
+func (s *diskFullState) pause(err error) {
+	if s.retrying.Swap(false) {
+		s.full.Store(true)
+		return
+	}
+	if !s.full.Swap(true) {
+		s.logger.Errorw("disk is full; pausing all data capture until syncing frees space", "error", err)
+	}
+}
+
+// written notes a successful write, resuming capture if it was being retried.
+func (s *diskFullState) written() {
+	if s.retrying.CompareAndSwap(true, false) {
+		s.logger.Info("disk space is available again; resumed data capture")
+	}
+}
+
+// retry resumes capture, if it was paused because the disk is full, to find out whether space has been freed.
+func (s *diskFullState) retry() {
+	if s.full.CompareAndSwap(true, false) {
+		s.retrying.Store(true)
+	}
+}
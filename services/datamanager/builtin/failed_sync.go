@@ -0,0 +1,145 @@
+package builtin
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/services/datamanager/datasync"
+)
+
+const (
+	// listFailedCommand is the DoCommand command that lists the files that failed to sync.
+	listFailedCommand = "list_failed"
+	// retryFailedCommand is the DoCommand command that moves the files that failed to sync back to where they
+	// are synced from, so that syncing them is attempted again.
+	retryFailedCommand = "retry_failed"
+)
+
+// failedFile is a file that was moved to a datasync.FailedDir after failing to sync.
+type failedFile struct {
+	// path is where the file is now, and originalPath where it was synced from.
+	path, originalPath string
+	size               int64
+}
+
+// findFailedFiles returns the files in the datasync.FailedDir directories under dirs, sorted by path. Files of
+// the capture directory are moved to the one at its root, while arbitrary files are moved to the one in their
+// own directory.
+func findFailedFiles(dirs []string) ([]failedFile, error) {
+	var files []failedFile
+	for _, dir := range dirs {
+		err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			if !info.IsDir() || info.Name() != datasync.FailedDir {
+				return nil
+			}
+			parentDir := filepath.Dir(path)
+			if err := filepath.Walk(path, func(failedPath string, info os.FileInfo, err error) error {
+				if err != nil || info.IsDir() {
+					return err
+				}
+				relPath, err := filepath.Rel(path, failedPath)
+				if err != nil {
+					return err
+				}
+				files = append(files, failedFile{
+					path:         failedPath,
+					originalPath: filepath.Join(parentDir, relPath),
+					size:         info.Size(),
+				})
+				return nil
+			}); err != nil {
+				return err
+			}
+			return filepath.SkipDir
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].path < files[j].path })
+	return files, nil
+}
+
+// retryFailedFile moves f back to where it was synced from. The move is a single rename so that the file is
+// never in both places, and a file that has since been written to the original path is not overwritten.
+func retryFailedFile(f failedFile) error {
+	if _, err := os.Stat(f.originalPath); err == nil {
+		return errors.Errorf("not retrying %s: %s already exists", f.path, f.originalPath)
+	}
+	if err := os.MkdirAll(filepath.Dir(f.originalPath), 0o700); err != nil {
+		return err
+	}
+	return os.Rename(f.path, f.originalPath)
+}
+
+// syncDirs returns the directories data is synced from.
+func (svc *builtIn) syncDirs() []string {
+	dirs := []string{svc.captureDir}
+	for _, ap := range svc.additionalSyncPaths {
+		dirs = append(dirs, ap.Path)
+	}
+	return dirs
+}
+
+// failedFilesResult describes files for the response of a DoCommand.
+func failedFilesResult(files []failedFile) map[string]interface{} {
+	var totalBytes int64
+	paths := make([]interface{}, 0, len(files))
+	for _, f := range files {
+		totalBytes += f.size
+		paths = append(paths, f.path)
+	}
+	return map[string]interface{}{
+		"files":       paths,
+		"count":       len(files),
+		"total_bytes": totalBytes,
+	}
+}
+
+// listFailed returns the files that failed to sync, along with their count and total size.
+func (svc *builtIn) listFailed() (map[string]interface{}, error) {
+	svc.lock.Lock()
+	defer svc.lock.Unlock()
+	files, err := findFailedFiles(svc.syncDirs())
+	if err != nil {
+		return nil, err
+	}
+	return failedFilesResult(files), nil
+}
+
+// retryFailed moves the files that failed to sync back to where they are synced from and returns them, along
+// with their count and total size. Files that cannot be moved are left in place and reported as errors.
+func (svc *builtIn) retryFailed() (map[string]interface{}, error) {
+	svc.lock.Lock()
+	defer svc.lock.Unlock()
+	files, err := findFailedFiles(svc.syncDirs())
+	if err != nil {
+		return nil, err
+	}
+	retried := make([]failedFile, 0, len(files))
+	var errs []interface{}
+	for _, f := range files {
+		if err := retryFailedFile(f); err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		retried = append(retried, f)
+	}
+	if len(retried) > 0 {
+		svc.logger.Infof("moved %d files that failed to sync back to be synced again", len(retried))
+	}
+	result := failedFilesResult(retried)
+	if len(errs) > 0 {
+		result["errors"] = errs
+	}
+	return result, nil
+}
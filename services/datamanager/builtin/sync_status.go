@@ -0,0 +1,53 @@
+package builtin
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/services/datamanager/datasync"
+)
+
+// SyncStatus describes the progress of the most recent sync of the capture directory and additional sync paths.
+type SyncStatus struct {
+	// InProgress is true until every file of the most recent sync has been uploaded, has failed to upload or
+	// was skipped, e.g. because an earlier sync is still uploading it.
+	InProgress bool
+	// FilesRemaining is the number of files from the most recent sync whose uploads have not finished.
+	FilesRemaining int
+	// BytesUploaded is the total size of the files from the most recent sync that have been uploaded.
+	BytesUploaded int64
+	// LastError is the most recent error encountered while syncing or uploading a file, if any.
+	LastError error
+	// StartedAt is when the most recent sync started. It is the zero time if no sync has run yet.
+	StartedAt time.Time
+}
+
+// SyncStatus returns the progress of the most recent sync.
+func (svc *builtIn) SyncStatus() SyncStatus {
+	svc.syncStatusLock.Lock()
+	defer svc.syncStatusLock.Unlock()
+	return svc.syncStatus
+}
+
+// finishFileSync records that the upload of a file of size bytes from the sync numbered generation finished
+// with err.
+func (svc *builtIn) finishFileSync(generation int, size int64, err error) {
+	svc.syncStatusLock.Lock()
+	defer svc.syncStatusLock.Unlock()
+	// uploads are canceled when the syncer is closed, which is not a failure.
+	if err != nil && !errors.Is(err, datasync.ErrNotSynced) && !errors.Is(err, context.Canceled) {
+		svc.syncStatus.LastError = err
+	}
+	if generation != svc.syncGeneration {
+		return
+	}
+	svc.syncStatus.FilesRemaining--
+	if err == nil {
+		svc.syncStatus.BytesUploaded += size
+	}
+	if svc.syncStatus.FilesRemaining == 0 {
+		svc.syncStatus.InProgress = false
+	}
+}
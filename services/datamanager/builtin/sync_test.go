@@ -599,6 +599,97 @@ func TestStreamingDCUpload(t *testing.T) {
 	}
 }
 
+func TestSyncOnClose(t *testing.T) {
+	datasync.RetryExponentialFactor.Store(int32(1))
+	datasync.InitialWaitTimeMillis.Store(int32(20))
+	originalTimeout := syncOnCloseTimeout
+	syncOnCloseTimeout = 300 * time.Millisecond
+	defer func() { syncOnCloseTimeout = originalTimeout }()
+
+	tests := []struct {
+		name        string
+		syncOnClose bool
+		serviceFail bool
+	}{
+		{
+			name: "captured data should be flushed but not synced on close by default",
+		},
+		{
+			name:        "captured data should be flushed and synced on close if sync_on_close is set",
+			syncOnClose: true,
+		},
+		{
+			name:        "close should stop waiting for the final sync after the timeout",
+			syncOnClose: true,
+			serviceFail: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClock := clk.NewMock()
+			clock = mockClock
+			tmpDir := t.TempDir()
+			if tc.serviceFail {
+				// Wait long enough between retries that uploads are still being retried when Close stops waiting.
+				datasync.InitialWaitTimeMillis.Store(int32(time.Minute.Milliseconds()))
+				defer datasync.InitialWaitTimeMillis.Store(int32(20))
+			}
+
+			dmsvc, r := newTestDataManager(t)
+			f := atomic.Bool{}
+			f.Store(tc.serviceFail)
+			mockClient := mockDataSyncServiceClient{
+				succesfulDCRequests: make(chan *v1.DataCaptureUploadRequest, 100),
+				failedDCRequests:    make(chan *v1.DataCaptureUploadRequest, 100),
+				fail:                &f,
+			}
+			dmsvc.SetSyncerConstructor(getTestSyncerConstructorMock(mockClient))
+			cfg, deps := setupConfig(t, enabledTabularCollectorConfigPath)
+			cfg.CaptureDisabled = false
+			cfg.ScheduledSyncDisabled = true
+			cfg.SyncIntervalMins = syncIntervalMins
+			cfg.CaptureDir = tmpDir
+			cfg.SyncOnClose = tc.syncOnClose
+
+			resources := resourcesFromDeps(t, r, deps)
+			err := dmsvc.Reconfigure(context.Background(), resources, resource.Config{
+				ConvertedAttributes: cfg,
+			})
+			test.That(t, err, test.ShouldBeNil)
+
+			// Let it capture a bit, then close.
+			for i := 0; i < 20; i++ {
+				mockClock.Add(captureInterval)
+			}
+			start := time.Now()
+			test.That(t, dmsvc.Close(context.Background()), test.ShouldBeNil)
+
+			switch {
+			case !tc.syncOnClose:
+				// The buffered data is flushed to completed capture files, which are left for the next start.
+				numFiles, capturedData, err := getCapturedData(tmpDir)
+				test.That(t, err, test.ShouldBeNil)
+				test.That(t, numFiles, test.ShouldBeGreaterThan, 0)
+				test.That(t, len(capturedData), test.ShouldBeGreaterThan, 0)
+				test.That(t, len(mockClient.succesfulDCRequests), test.ShouldEqual, 0)
+			case tc.serviceFail:
+				test.That(t, time.Since(start), test.ShouldBeGreaterThanOrEqualTo, syncOnCloseTimeout)
+				test.That(t, len(mockClient.failedDCRequests), test.ShouldBeGreaterThan, 0)
+				test.That(t, len(mockClient.succesfulDCRequests), test.ShouldEqual, 0)
+				// The files are left to be synced after the next start.
+				numFiles, _, err := getCapturedData(tmpDir)
+				test.That(t, err, test.ShouldBeNil)
+				test.That(t, numFiles, test.ShouldBeGreaterThan, 0)
+			default:
+				// Close waits until the synced files are deleted.
+				test.That(t, len(mockClient.succesfulDCRequests), test.ShouldBeGreaterThan, 0)
+				test.That(t, len(getAllFileInfos(tmpDir)), test.ShouldEqual, 0)
+			}
+		})
+	}
+}
+
 func TestSyncConfigUpdateBehavior(t *testing.T) {
 	newSyncIntervalMins := 0.009
 	tests := []struct {
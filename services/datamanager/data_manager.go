@@ -85,6 +85,11 @@ type DataCaptureConfig struct {
 	Disabled           bool              `json:"disabled"`
 	Tags               []string          `json:"tags,omitempty"`
 	CaptureDirectory   string            `json:"capture_directory"`
+	// AggregateWindowMs, if set, combines the tabular readings captured within each window of that many
+	// milliseconds into one with AggregateFn before they are written: "mean", the default, and "max" combine
+	// each numeric field, and "last" keeps the last reading. Binary readings are never combined.
+	AggregateWindowMs int    `json:"aggregate_window_ms,omitempty"`
+	AggregateFn       string `json:"aggregate_fn,omitempty"`
 }
 
 // Equals checks if one capture config is equal to another.
@@ -98,7 +103,9 @@ func (c *DataCaptureConfig) Equals(other *DataCaptureConfig) bool {
 		c.Disabled == other.Disabled &&
 		slices.Compare(c.Tags, other.Tags) == 0 &&
 		reflect.DeepEqual(c.AdditionalParams, other.AdditionalParams) &&
-		c.CaptureDirectory == other.CaptureDirectory
+		c.CaptureDirectory == other.CaptureDirectory &&
+		c.AggregateWindowMs == other.AggregateWindowMs &&
+		c.AggregateFn == other.AggregateFn
 }
 
 // ShouldSyncKey is a special key we use within a modular sensor to pass a boolean
@@ -2,7 +2,9 @@ package datacapture
 
 import (
 	"sync"
+	"time"
 
+	"go.uber.org/multierr"
 	v1 "go.viam.com/api/app/datasync/v1"
 )
 
@@ -16,12 +18,26 @@ type BufferedWriter interface {
 	Path() string
 }
 
-// Buffer is a persistent queue of SensorData backed by a series of datacapture.Files.
+// captureFile is a capture file that tabular sensor data is written to, in one of the FileFormats.
+type captureFile interface {
+	WriteNext(data *v1.SensorData) error
+	Size() int64
+	Close() error
+}
+
+// Buffer is a persistent queue of SensorData backed by a series of datacapture.Files, or of ParquetFiles for
+// tabular data if FileFormat is FileFormatParquet.
 type Buffer struct {
 	Directory string
-	MetaData  *v1.DataCaptureMetadata
-	nextFile  *File
-	lock      sync.Mutex
+	// DirectoryFunc, if set, is called for each new file to get the directory it is written to instead of
+	// Directory, e.g. so that files are written to a directory per day.
+	DirectoryFunc func() string
+	MetaData      *v1.DataCaptureMetadata
+	// FileFormat is the format tabular data is written in. Binary data is always written in FileFormatViam.
+	// The zero value is FileFormatViam.
+	FileFormat FileFormat
+	nextFile   captureFile
+	lock       sync.Mutex
 }
 
 // NewBuffer returns a new Buffer.
@@ -34,18 +50,19 @@ func NewBuffer(dir string, md *v1.DataCaptureMetadata) *Buffer {
 
 // Write writes item onto b. Binary sensor data is written to its own file.
 // Tabular data is written to disk in MaxFileSize sized files. Files that are still being written to are indicated
-// with the extension InProgressFileExt. Files that have finished being written to are indicated by FileExt.
+// with the extension InProgressFileExt. Files that have finished being written to are indicated by FileExt, or by
+// ParquetFileExt if FileFormat is FileFormatParquet.
 func (b *Buffer) Write(item *v1.SensorData) error {
 	b.lock.Lock()
 	defer b.lock.Unlock()
 
 	if item.GetBinary() != nil {
-		binFile, err := NewFile(b.Directory, b.MetaData)
+		binFile, err := NewFile(b.directory(), b.MetaData)
 		if err != nil {
 			return err
 		}
 		if err := binFile.WriteNext(item); err != nil {
-			return err
+			return multierr.Combine(err, binFile.Close())
 		}
 		if err := binFile.Close(); err != nil {
 			return err
@@ -54,23 +71,50 @@ func (b *Buffer) Write(item *v1.SensorData) error {
 	}
 
 	if b.nextFile == nil {
-		nextFile, err := NewFile(b.Directory, b.MetaData)
+		nextFile, err := b.newTabularFile()
 		if err != nil {
 			return err
 		}
 		b.nextFile = nextFile
-	} else if b.nextFile.Size() > MaxFileSize {
-		if err := b.nextFile.Close(); err != nil {
+	} else if isFileComplete(b.nextFile) {
+		err := b.nextFile.Close()
+		b.nextFile = nil
+		if err != nil {
 			return err
 		}
-		nextFile, err := NewFile(b.Directory, b.MetaData)
+		nextFile, err := b.newTabularFile()
 		if err != nil {
 			return err
 		}
 		b.nextFile = nextFile
 	}
 
-	return b.nextFile.WriteNext(item)
+	if err := b.nextFile.WriteNext(item); err != nil {
+		// A failed write, e.g. because the disk is full, leaves the file unusable, so the next item starts a new one.
+		err = multierr.Combine(err, b.nextFile.Close())
+		b.nextFile = nil
+		return err
+	}
+	return nil
+}
+
+// isFileComplete returns whether f should be closed rather than written to: when it exceeds MaxFileSize or, for
+// a ParquetFile, whose readings are only written to disk when it is closed, when it is older than
+// MaxParquetFileAge.
+func isFileComplete(f captureFile) bool {
+	if f.Size() > MaxFileSize {
+		return true
+	}
+	pf, ok := f.(*ParquetFile)
+	return ok && time.Since(pf.created) > MaxParquetFileAge
+}
+
+// newTabularFile returns a new file for tabular data in the FileFormat of b.
+func (b *Buffer) newTabularFile() (captureFile, error) {
+	if b.FileFormat == FileFormatParquet {
+		return NewParquetFile(b.directory(), b.MetaData), nil
+	}
+	return NewFile(b.directory(), b.MetaData)
 }
 
 // Flush flushes all buffered data to disk and marks any in progress file as complete.
@@ -80,14 +124,20 @@ func (b *Buffer) Flush() error {
 	if b.nextFile == nil {
 		return nil
 	}
-	if err := b.nextFile.Close(); err != nil {
-		return err
-	}
+	err := b.nextFile.Close()
 	b.nextFile = nil
-	return nil
+	return err
 }
 
-// Path returns the path to the directory containing the backing data capture files.
+// Path returns the path to the directory containing the backing data capture files, or that the next file
+// would be written to if DirectoryFunc is set.
 func (b *Buffer) Path() string {
+	return b.directory()
+}
+
+func (b *Buffer) directory() string {
+	if b.DirectoryFunc != nil {
+		return b.DirectoryFunc()
+	}
 	return b.Directory
 }
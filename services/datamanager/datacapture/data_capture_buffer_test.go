@@ -4,11 +4,13 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	v1 "go.viam.com/api/app/datasync/v1"
 	"go.viam.com/test"
 	"go.viam.com/utils/protoutils"
 	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type structReading struct {
@@ -119,6 +121,109 @@ func TestCaptureQueue(t *testing.T) {
 	}
 }
 
+func TestParquetBuffer(t *testing.T) {
+	MaxFileSize = 100
+	tmpDir := t.TempDir()
+	md := &v1.DataCaptureMetadata{ComponentName: "sensor", MethodName: "Readings", Type: v1.DataType_DATA_TYPE_TABULAR_SENSOR}
+	sut := NewBuffer(tmpDir, md)
+	sut.FileFormat = FileFormatParquet
+
+	start := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+	var written []*v1.SensorData
+	for i := 0; i < 5; i++ {
+		reading, err := structpb.NewStruct(map[string]interface{}{"index": float64(i), "name": "reading"})
+		test.That(t, err, test.ShouldBeNil)
+		data := &v1.SensorData{
+			Metadata: &v1.SensorMetadata{
+				TimeRequested: timestamppb.New(start.Add(time.Duration(i) * time.Second)),
+				TimeReceived:  timestamppb.New(start.Add(time.Duration(i)*time.Second + time.Millisecond)),
+			},
+			Data: &v1.SensorData_Struct{Struct: reading},
+		}
+		test.That(t, sut.Write(data), test.ShouldBeNil)
+		written = append(written, data)
+	}
+	// Binary data is still written in the viam format.
+	test.That(t, sut.Write(binarySensorData), test.ShouldBeNil)
+	// Nothing is on disk while a Parquet file is being written to.
+	test.That(t, sut.Flush(), test.ShouldBeNil)
+
+	dcFiles, progFiles := getCaptureFiles(tmpDir)
+	test.That(t, dcFiles, test.ShouldHaveLength, 1)
+	test.That(t, progFiles, test.ShouldBeEmpty)
+	parquetFiles, err := filepath.Glob(filepath.Join(tmpDir, "*"+ParquetFileExt))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(parquetFiles), test.ShouldBeGreaterThan, 1)
+
+	var read []*v1.SensorData
+	for _, path := range parquetFiles {
+		readMD, readings, err := SensorDataFromParquetFilePath(path)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, readMD.GetComponentName(), test.ShouldEqual, "sensor")
+		test.That(t, readMD.GetMethodName(), test.ShouldEqual, "Readings")
+		read = append(read, readings...)
+	}
+	test.That(t, read, test.ShouldHaveLength, len(written))
+	for i := range written {
+		test.That(t, read[i].GetMetadata().GetTimeRequested().AsTime(), test.ShouldEqual,
+			written[i].GetMetadata().GetTimeRequested().AsTime())
+		test.That(t, read[i].GetMetadata().GetTimeReceived().AsTime(), test.ShouldEqual,
+			written[i].GetMetadata().GetTimeReceived().AsTime())
+		test.That(t, read[i].GetStruct().AsMap(), test.ShouldResemble, written[i].GetStruct().AsMap())
+	}
+}
+
+func TestParquetBufferMaxFileAge(t *testing.T) {
+	MaxFileSize = 64 * 1024
+	originalAge := MaxParquetFileAge
+	MaxParquetFileAge = 10 * time.Millisecond
+	defer func() { MaxParquetFileAge = originalAge }()
+	tmpDir := t.TempDir()
+	md := &v1.DataCaptureMetadata{ComponentName: "sensor", MethodName: "Readings", Type: v1.DataType_DATA_TYPE_TABULAR_SENSOR}
+	sut := NewBuffer(tmpDir, md)
+	sut.FileFormat = FileFormatParquet
+
+	test.That(t, sut.Write(structSensorData), test.ShouldBeNil)
+	test.That(t, sut.Write(structSensorData), test.ShouldBeNil)
+	parquetFiles, err := filepath.Glob(filepath.Join(tmpDir, "*"+ParquetFileExt))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, parquetFiles, test.ShouldBeEmpty)
+
+	// A file older than MaxParquetFileAge is written to disk before the next reading, although it is small.
+	time.Sleep(2 * MaxParquetFileAge)
+	test.That(t, sut.Write(structSensorData), test.ShouldBeNil)
+	parquetFiles, err = filepath.Glob(filepath.Join(tmpDir, "*"+ParquetFileExt))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, parquetFiles, test.ShouldHaveLength, 1)
+	_, readings, err := SensorDataFromParquetFilePath(parquetFiles[0])
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, readings, test.ShouldHaveLength, 2)
+	test.That(t, sut.Flush(), test.ShouldBeNil)
+}
+
+func TestBufferDirectoryFunc(t *testing.T) {
+	tmpDir := t.TempDir()
+	dir := filepath.Join(tmpDir, "day1")
+	md := &v1.DataCaptureMetadata{ComponentName: "sensor", MethodName: "Readings", Type: v1.DataType_DATA_TYPE_TABULAR_SENSOR}
+	sut := NewBuffer(dir, md)
+	sut.DirectoryFunc = func() string { return dir }
+
+	test.That(t, sut.Write(structSensorData), test.ShouldBeNil)
+	// The directory only changes for the next file.
+	dir = filepath.Join(tmpDir, "day2")
+	test.That(t, sut.Path(), test.ShouldEqual, dir)
+	test.That(t, sut.Write(structSensorData), test.ShouldBeNil)
+	test.That(t, sut.Flush(), test.ShouldBeNil)
+	test.That(t, sut.Write(binarySensorData), test.ShouldBeNil)
+	test.That(t, sut.Write(structSensorData), test.ShouldBeNil)
+	test.That(t, sut.Flush(), test.ShouldBeNil)
+
+	day1Files, _ := getCaptureFiles(filepath.Join(tmpDir, "day1"))
+	test.That(t, day1Files, test.ShouldHaveLength, 1)
+	day2Files, _ := getCaptureFiles(filepath.Join(tmpDir, "day2"))
+	test.That(t, day2Files, test.ShouldHaveLength, 2)
+}
+
 //nolint
 func getCaptureFiles(dir string) (dcFiles, progFiles []string) {
 	_ = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
@@ -13,7 +13,9 @@ import (
 
 	"github.com/matttproud/golang_protobuf_extensions/pbutil"
 	"github.com/pkg/errors"
+	"go.uber.org/multierr"
 	v1 "go.viam.com/api/app/datasync/v1"
+	goutils "go.viam.com/utils"
 
 	"go.viam.com/rdk/protoutils"
 	"go.viam.com/rdk/resource"
@@ -84,6 +86,10 @@ func ReadFile(f *os.File) (*File, error) {
 
 // NewFile creates a new File with the specified md in the specified directory.
 func NewFile(dir string, md *v1.DataCaptureMetadata) (*File, error) {
+	// The directory may not exist yet if it could not be created earlier, e.g. because the disk was full.
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
 	fileName := FilePathWithReplacedReservedChars(
 		filepath.Join(dir, getFileTimestampName()) + InProgressFileExt)
 	//nolint:gosec
@@ -95,6 +101,9 @@ func NewFile(dir string, md *v1.DataCaptureMetadata) (*File, error) {
 	// Then write first metadata message to the file.
 	n, err := pbutil.WriteDelimited(f, md)
 	if err != nil {
+		// A file without its metadata cannot be read, so do not leave it behind.
+		goutils.UncheckedError(f.Close())
+		goutils.UncheckedError(os.Remove(fileName))
 		return nil, err
 	}
 	return &File{
@@ -178,12 +187,13 @@ func (f *File) GetPath() string {
 	return f.path
 }
 
-// Close closes the file.
+// Close closes the file. The underlying os.File is closed even if flushing buffered writes fails, e.g. because
+// the disk is full, so that the file is not leaked.
 func (f *File) Close() error {
 	f.lock.Lock()
 	defer f.lock.Unlock()
 	if err := f.writer.Flush(); err != nil {
-		return err
+		return multierr.Combine(err, f.file.Close())
 	}
 
 	// Rename file to indicate that it is done being written.
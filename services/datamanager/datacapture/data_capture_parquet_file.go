@@ -0,0 +1,221 @@
+package datacapture
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/reader"
+	"github.com/xitongsys/parquet-go/source"
+	"github.com/xitongsys/parquet-go/writer"
+	v1 "go.viam.com/api/app/datasync/v1"
+	goutils "go.viam.com/utils"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// FileFormat is the format tabular sensor data is written to disk in.
+type FileFormat string
+
+const (
+	// FileFormatViam writes capture files of length delimited protobuf messages. It is the default.
+	FileFormatViam FileFormat = "viam"
+	// FileFormatParquet writes Parquet files with one row per reading, so that they can be queried locally,
+	// e.g. with DuckDB, without conversion.
+	FileFormatParquet FileFormat = "parquet"
+
+	// ParquetFileExt defines the file extension for capture files written in FileFormatParquet.
+	ParquetFileExt = ".parquet"
+	// parquetMetadataKey is the key of the Parquet key-value metadata holding the DataCaptureMetadata of a
+	// file, encoded as JSON.
+	parquetMetadataKey = "viam.capture_metadata"
+	// parquetRowOverhead approximates the bytes a row takes besides its data, for sizing files.
+	parquetRowOverhead = 16
+)
+
+// parquetRow is a row of a Parquet capture file. The times are in nanoseconds since the Unix epoch. Data holds
+// the tabular reading encoded as JSON, since readings of different methods, and even of the same method, do not
+// share a schema.
+//
+//nolint:lll
+type parquetRow struct {
+	TimeRequested int64  `parquet:"name=time_requested, type=INT64, logicaltype=TIMESTAMP, logicaltype.isadjustedtoutc=true, logicaltype.unit=NANOS"`
+	TimeReceived  int64  `parquet:"name=time_received, type=INT64, logicaltype=TIMESTAMP, logicaltype.isadjustedtoutc=true, logicaltype.unit=NANOS"`
+	Data          string `parquet:"name=data, type=BYTE_ARRAY, convertedtype=JSON"`
+}
+
+// MaxParquetFileAge is how long a Buffer holds the readings of a ParquetFile in memory before completing it,
+// even if it is smaller than MaxFileSize. It bounds how much data is lost if the process crashes.
+var MaxParquetFileAge = time.Minute
+
+// ParquetFile is a capture file of tabular sensor data in FileFormatParquet. Parquet files cannot be appended
+// to, so readings are held in memory and written to disk, with the extension ParquetFileExt, when the file is
+// closed. Unlike a File, a ParquetFile is never on disk while still being written to, so its readings are
+// lost if the process crashes before then. Data sync uploads Parquet files as arbitrary files, so they are
+// stored in the cloud as opaque files rather than as tabular data.
+type ParquetFile struct {
+	path     string
+	lock     sync.Mutex
+	metadata *v1.DataCaptureMetadata
+	rows     []parquetRow
+	size     int64
+	created  time.Time
+}
+
+// NewParquetFile creates a new ParquetFile with the specified md in the specified directory.
+func NewParquetFile(dir string, md *v1.DataCaptureMetadata) *ParquetFile {
+	return &ParquetFile{
+		path:     FilePathWithReplacedReservedChars(filepath.Join(dir, getFileTimestampName()) + ParquetFileExt),
+		metadata: md,
+		created:  time.Now(),
+	}
+}
+
+// WriteNext adds the next tabular SensorData reading to f.
+func (f *ParquetFile) WriteNext(data *v1.SensorData) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if data.GetStruct() == nil {
+		return errors.New("only tabular sensor data can be written to a Parquet capture file")
+	}
+	b, err := protojson.Marshal(data.GetStruct())
+	if err != nil {
+		return err
+	}
+	f.rows = append(f.rows, parquetRow{
+		TimeRequested: data.GetMetadata().GetTimeRequested().AsTime().UnixNano(),
+		TimeReceived:  data.GetMetadata().GetTimeReceived().AsTime().UnixNano(),
+		Data:          string(b),
+	})
+	f.size += int64(len(b)) + parquetRowOverhead
+	return nil
+}
+
+// Size returns the approximate size of f once written to disk.
+func (f *ParquetFile) Size() int64 {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.size
+}
+
+// GetPath returns the path f is written to when it is closed.
+func (f *ParquetFile) GetPath() string {
+	return f.path
+}
+
+// Close writes the readings of f to disk. Nothing is written if f has no readings.
+func (f *ParquetFile) Close() error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if len(f.rows) == 0 {
+		return nil
+	}
+
+	md, err := protojson.Marshal(f.metadata)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	w, err := writer.NewParquetWriterFromWriter(&buf, new(parquetRow), 1)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode Parquet capture file")
+	}
+	mdValue := string(md)
+	w.Footer.KeyValueMetadata = append(w.Footer.KeyValueMetadata, &parquet.KeyValue{Key: parquetMetadataKey, Value: &mdValue})
+	for _, row := range f.rows {
+		if err := w.Write(row); err != nil {
+			return errors.Wrap(err, "failed to encode Parquet capture file")
+		}
+	}
+	if err := w.WriteStop(); err != nil {
+		return errors.Wrap(err, "failed to encode Parquet capture file")
+	}
+	// The directory may not exist yet if it could not be created earlier, e.g. because the disk was full.
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o700); err != nil {
+		return err
+	}
+	// The file is written in one go so that data sync, which uploads it once it goes unmodified, never sees it
+	// partially written.
+	if err := os.WriteFile(f.path, buf.Bytes(), 0o600); err != nil {
+		return err
+	}
+	f.rows = nil
+	return nil
+}
+
+// SensorDataFromParquetFilePath returns the metadata and all readings in the Parquet capture file at filePath.
+func SensorDataFromParquetFilePath(filePath string) (*v1.DataCaptureMetadata, []*v1.SensorData, error) {
+	//nolint:gosec
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer goutils.UncheckedErrorFunc(f.Close)
+	r, err := reader.NewParquetReader(parquetFileSource{f}, new(parquetRow), 1)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "%s is not a Parquet file", filePath)
+	}
+	defer r.ReadStop()
+
+	var mdJSON string
+	for _, kv := range r.Footer.GetKeyValueMetadata() {
+		if kv.GetKey() == parquetMetadataKey {
+			mdJSON = kv.GetValue()
+		}
+	}
+	if mdJSON == "" {
+		return nil, nil, errors.Errorf("%s is not a Parquet capture file", filePath)
+	}
+	md := &v1.DataCaptureMetadata{}
+	if err := protojson.Unmarshal([]byte(mdJSON), md); err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to read DataCaptureMetadata from %s", filePath)
+	}
+
+	rows := make([]parquetRow, r.GetNumRows())
+	if err := r.Read(&rows); err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to read readings from %s", filePath)
+	}
+	readings := make([]*v1.SensorData, 0, len(rows))
+	for _, row := range rows {
+		data := &structpb.Struct{}
+		if err := protojson.Unmarshal([]byte(row.Data), data); err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to read reading from %s", filePath)
+		}
+		readings = append(readings, &v1.SensorData{
+			Metadata: &v1.SensorMetadata{
+				TimeRequested: timestamppb.New(time.Unix(0, row.TimeRequested)),
+				TimeReceived:  timestamppb.New(time.Unix(0, row.TimeReceived)),
+			},
+			Data: &v1.SensorData_Struct{Struct: data},
+		})
+	}
+	return md, readings, nil
+}
+
+// parquetFileSource is the source.ParquetFile that Parquet capture files are read through. The reader opens
+// the file again for each column it reads.
+type parquetFileSource struct {
+	*os.File
+}
+
+func (s parquetFileSource) Open(name string) (source.ParquetFile, error) {
+	if name == "" {
+		name = s.Name()
+	}
+	//nolint:gosec
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return parquetFileSource{f}, nil
+}
+
+func (s parquetFileSource) Create(name string) (source.ParquetFile, error) {
+	return nil, errors.New("Parquet capture files are not written through a source.ParquetFile")
+}
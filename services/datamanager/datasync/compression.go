@@ -0,0 +1,48 @@
+package datasync
+
+import (
+	"context"
+
+	v1 "go.viam.com/api/app/datasync/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding/gzip"
+)
+
+// gzipClient is a v1.DataSyncServiceClient that gzip compresses every upload. The compression is negotiated
+// with the grpc-encoding header, so the server decompresses each message before handling it and the stored data
+// is the same as for an uncompressed upload.
+type gzipClient struct {
+	v1.DataSyncServiceClient
+}
+
+func (c gzipClient) DataCaptureUpload(
+	ctx context.Context,
+	in *v1.DataCaptureUploadRequest,
+	opts ...grpc.CallOption,
+) (*v1.DataCaptureUploadResponse, error) {
+	return c.DataSyncServiceClient.DataCaptureUpload(ctx, in, append(opts, grpc.UseCompressor(gzip.Name))...)
+}
+
+func (c gzipClient) FileUpload(ctx context.Context, opts ...grpc.CallOption) (v1.DataSyncService_FileUploadClient, error) {
+	return c.DataSyncServiceClient.FileUpload(ctx, append(opts, grpc.UseCompressor(gzip.Name))...)
+}
+
+func (c gzipClient) StreamingDataCaptureUpload(
+	ctx context.Context,
+	opts ...grpc.CallOption,
+) (v1.DataSyncService_StreamingDataCaptureUploadClient, error) {
+	return c.DataSyncServiceClient.StreamingDataCaptureUpload(ctx, append(opts, grpc.UseCompressor(gzip.Name))...)
+}
+
+// SetCompression sets whether files are gzip compressed while being uploaded.
+func (s *syncer) SetCompression(compress bool) {
+	s.compress.Store(compress)
+}
+
+// uploadClient returns the client to upload files with, compressing uploads if compression is enabled.
+func (s *syncer) uploadClient() v1.DataSyncServiceClient {
+	if s.compress.Load() {
+		return gzipClient{s.client}
+	}
+	return s.client
+}
@@ -0,0 +1,98 @@
+package datasync
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"testing"
+	"time"
+
+	v1 "go.viam.com/api/app/datasync/v1"
+	"go.viam.com/test"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type recordingClient struct {
+	v1.DataSyncServiceClient
+	opts []grpc.CallOption
+}
+
+func (c *recordingClient) DataCaptureUpload(
+	ctx context.Context,
+	in *v1.DataCaptureUploadRequest,
+	opts ...grpc.CallOption,
+) (*v1.DataCaptureUploadResponse, error) {
+	c.opts = opts
+	return &v1.DataCaptureUploadResponse{}, nil
+}
+
+func TestUploadClientCompression(t *testing.T) {
+	client := &recordingClient{}
+	s := &syncer{client: client}
+
+	_, err := s.uploadClient().DataCaptureUpload(context.Background(), &v1.DataCaptureUploadRequest{})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, client.opts, test.ShouldBeEmpty)
+
+	s.SetCompression(true)
+	_, err = s.uploadClient().DataCaptureUpload(context.Background(), &v1.DataCaptureUploadRequest{})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(client.opts), test.ShouldEqual, 1)
+	_, ok := client.opts[0].(grpc.CompressorCallOption)
+	test.That(t, ok, test.ShouldBeTrue)
+}
+
+// tabularUploadRequest returns an upload request shaped like a typical capture file of numReadings
+// movement sensor readings.
+func tabularUploadRequest(b *testing.B, numReadings int) *v1.DataCaptureUploadRequest {
+	start := time.Now()
+	sensorData := make([]*v1.SensorData, 0, numReadings)
+	for i := 0; i < numReadings; i++ {
+		readings, err := structpb.NewStruct(map[string]interface{}{
+			"linear_velocity":  map[string]interface{}{"x": 0.1 * float64(i%7), "y": 0.02 * float64(i%13), "z": 0.0},
+			"angular_velocity": map[string]interface{}{"x": 0.0, "y": 0.0, "z": 0.5 * float64(i%5)},
+		})
+		test.That(b, err, test.ShouldBeNil)
+		ts := timestamppb.New(start.Add(time.Duration(i) * 100 * time.Millisecond))
+		sensorData = append(sensorData, &v1.SensorData{
+			Metadata: &v1.SensorMetadata{TimeRequested: ts, TimeReceived: ts},
+			Data:     &v1.SensorData_Struct{Struct: readings},
+		})
+	}
+	return &v1.DataCaptureUploadRequest{
+		Metadata: &v1.UploadMetadata{
+			PartId:        "part-id",
+			ComponentType: "rdk:component:movement_sensor",
+			ComponentName: "movement_sensor1",
+			MethodName:    "Velocity",
+			Type:          v1.DataType_DATA_TYPE_TABULAR_SENSOR,
+		},
+		SensorContents: sensorData,
+	}
+}
+
+// BenchmarkGzipTabularUpload reports the CPU cost of compressing a typical tabular upload along with the
+// compressed size as a percentage of the uncompressed size.
+func BenchmarkGzipTabularUpload(b *testing.B) {
+	raw, err := proto.Marshal(tabularUploadRequest(b, 1000))
+	test.That(b, err, test.ShouldBeNil)
+
+	var buf bytes.Buffer
+	b.SetBytes(int64(len(raw)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(raw); err != nil {
+			b.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(100*float64(buf.Len())/float64(len(raw)), "%size")
+	b.ReportMetric(float64(len(raw)-buf.Len()), "bytes-saved/op")
+}
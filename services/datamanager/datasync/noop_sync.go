@@ -1,5 +1,7 @@
 package datasync
 
+import "time"
+
 type noopManager struct{}
 
 var _ Manager = (*noopManager)(nil)
@@ -9,8 +11,16 @@ func NewNoopManager() Manager {
 	return &noopManager{}
 }
 
-func (m *noopManager) SyncFile(path string) {}
+func (m *noopManager) SyncFile(path string, done func(err error)) {
+	if done != nil {
+		done(ErrNotSynced)
+	}
+}
 
 func (m *noopManager) SetArbitraryFileTags(tags []string) {}
 
+func (m *noopManager) SetMaxRetryInterval(interval time.Duration) {}
+
+func (m *noopManager) SetCompression(compress bool) {}
+
 func (m *noopManager) Close() {}
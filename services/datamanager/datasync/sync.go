@@ -4,6 +4,7 @@ package datasync
 import (
 	"context"
 	"fmt"
+	"math/rand"
 	"os"
 	"path"
 	"path/filepath"
@@ -28,9 +29,18 @@ var (
 	InitialWaitTimeMillis = atomic.NewInt32(1000)
 	// RetryExponentialFactor defines the factor by which the retry wait time increases.
 	RetryExponentialFactor = atomic.NewInt32(2)
-	maxRetryInterval       = time.Hour
+	// MaxSyncAttempts defines the number of times a file is attempted to be uploaded before it is moved to FailedDir.
+	MaxSyncAttempts = atomic.NewInt32(15)
+	// DefaultMaxRetryInterval defines the maximum time to wait between retried upload attempts if none is set.
+	DefaultMaxRetryInterval = time.Hour
 )
 
+var errMaxSyncAttemptsExceeded = errors.New("exceeded maximum number of sync attempts")
+
+// ErrNotSynced is passed to the done function of SyncFile when the file is not synced by that call: because it
+// is already being synced, all sync routines are busy, it no longer exists or the Manager was closed.
+var ErrNotSynced = errors.New("file was not synced")
+
 // FailedDir is a subdirectory of the capture directory that holds any files that could not be synced.
 const FailedDir = "failed"
 
@@ -39,8 +49,12 @@ const maxParallelSyncRoutines = 1000
 
 // Manager is responsible for enqueuing files in captureDir and uploading them to the cloud.
 type Manager interface {
-	SyncFile(path string)
+	// SyncFile uploads the file at path in the background and deletes it once it is uploaded. If done is not nil,
+	// it is called exactly once, when the upload has finished, with the error that ended it or ErrNotSynced.
+	SyncFile(path string, done func(err error))
 	SetArbitraryFileTags(tags []string)
+	SetMaxRetryInterval(interval time.Duration)
+	SetCompression(compress bool)
 	Close()
 }
 
@@ -53,6 +67,8 @@ type syncer struct {
 	cancelCtx         context.Context
 	cancelFunc        func()
 	arbitraryFileTags []string
+	maxRetryInterval  *atomic.Duration
+	compress          atomic.Bool
 
 	progressLock sync.Mutex
 	inProgress   map[string]bool
@@ -79,6 +95,7 @@ func NewManager(identity string, client v1.DataSyncServiceClient, logger logging
 		cancelCtx:          cancelCtx,
 		cancelFunc:         cancelFunc,
 		arbitraryFileTags:  []string{},
+		maxRetryInterval:   atomic.NewDuration(DefaultMaxRetryInterval),
 		inProgress:         make(map[string]bool),
 		syncErrs:           make(chan error, 10),
 		syncRoutineTracker: make(chan struct{}, maxParallelSyncRoutines),
@@ -107,25 +124,42 @@ func (s *syncer) SetArbitraryFileTags(tags []string) {
 	s.arbitraryFileTags = tags
 }
 
-func (s *syncer) SyncFile(path string) {
+// SetMaxRetryInterval sets the maximum time to wait between retried upload attempts. A non-positive interval
+// resets it to DefaultMaxRetryInterval.
+func (s *syncer) SetMaxRetryInterval(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultMaxRetryInterval
+	}
+	s.maxRetryInterval.Store(interval)
+}
+
+func (s *syncer) SyncFile(path string, done func(err error)) {
+	if done == nil {
+		done = func(error) {}
+	}
 	// If the file is already being synced, do not kick off a new goroutine.
 	// The goroutine will again check and return early if sync is already in progress.
 	s.progressLock.Lock()
 	if s.inProgress[path] {
 		s.progressLock.Unlock()
+		done(ErrNotSynced)
 		return
 	}
 	s.progressLock.Unlock()
 
 	select {
 	case <-s.cancelCtx.Done():
-		return
+		done(ErrNotSynced)
 	// Kick off a sync goroutine if under the limit of goroutines.
 	case s.syncRoutineTracker <- struct{}{}:
 		s.backgroundWorkers.Add(1)
 
 		goutils.PanicCapturingGo(func() {
 			defer s.backgroundWorkers.Done()
+			err := ErrNotSynced
+			defer func() {
+				done(err)
+			}()
 			// At the end, decrement the number of sync routines.
 			defer func() {
 				<-s.syncRoutineTracker
@@ -139,20 +173,22 @@ func (s *syncer) SyncFile(path string) {
 				}
 				defer s.unmarkInProgress(path)
 				//nolint:gosec
-				f, err := os.Open(path)
-				if err != nil {
+				f, openErr := os.Open(path)
+				if openErr != nil {
 					// Don't log if the file does not exist, because that means it was successfully synced and deleted
 					// in between paths being built and this executing.
-					if !errors.Is(err, os.ErrNotExist) {
-						s.logger.Errorw("error opening file", "error", err)
+					if !errors.Is(openErr, os.ErrNotExist) {
+						s.logger.Errorw("error opening file", "error", openErr)
+						err = openErr
 					}
 					return
 				}
 
 				if datacapture.IsDataCaptureFile(f) {
-					captureFile, err := datacapture.ReadFile(f)
-					if err != nil {
-						if err = f.Close(); err != nil {
+					captureFile, readErr := datacapture.ReadFile(f)
+					if readErr != nil {
+						err = errors.Wrapf(readErr, "error reading data capture file %s", f.Name())
+						if err := f.Close(); err != nil {
 							s.syncErrs <- errors.Wrap(err, "error closing data capture file")
 						}
 						if err := moveFailedData(f.Name(), s.captureDir); err != nil {
@@ -160,22 +196,25 @@ func (s *syncer) SyncFile(path string) {
 						}
 						return
 					}
-					s.syncDataCaptureFile(captureFile)
+					err = s.syncDataCaptureFile(captureFile)
 				} else {
-					s.syncArbitraryFile(f)
+					err = s.syncArbitraryFile(f)
 				}
 			}
 		})
 	default:
 		// Avoid blocking main thread if currently at goroutine capacity.
+		done(ErrNotSynced)
 	}
 }
 
-func (s *syncer) syncDataCaptureFile(f *datacapture.File) {
-	uploadErr := exponentialRetry(
+// syncDataCaptureFile uploads f and deletes it, returning the error that prevented either.
+func (s *syncer) syncDataCaptureFile(f *datacapture.File) error {
+	uploadErr := s.exponentialRetry(
 		s.cancelCtx,
+		f.GetPath(),
 		func(ctx context.Context) error {
-			err := uploadDataCaptureFile(ctx, s.client, f, s.partID)
+			err := uploadDataCaptureFile(ctx, s.uploadClient(), f, s.partID)
 			if err != nil {
 				s.syncErrs <- errors.Wrap(err, fmt.Sprintf("error uploading file %s", f.GetPath()))
 			}
@@ -188,24 +227,28 @@ func (s *syncer) syncDataCaptureFile(f *datacapture.File) {
 			s.syncErrs <- errors.Wrap(err, "error closing data capture file")
 		}
 
-		if !isRetryableGRPCError(uploadErr) {
+		if !isRetryableGRPCError(uploadErr) || errors.Is(uploadErr, errMaxSyncAttemptsExceeded) {
 			if err := moveFailedData(f.GetPath(), s.captureDir); err != nil {
 				s.syncErrs <- errors.Wrap(err, fmt.Sprintf("error moving corrupted data %s", f.GetPath()))
 			}
 		}
-		return
+		return uploadErr
 	}
 	if err := f.Delete(); err != nil {
-		s.syncErrs <- errors.Wrap(err, "error deleting data capture file")
-		return
+		err = errors.Wrap(err, "error deleting data capture file")
+		s.syncErrs <- err
+		return err
 	}
+	return nil
 }
 
-func (s *syncer) syncArbitraryFile(f *os.File) {
-	uploadErr := exponentialRetry(
+// syncArbitraryFile uploads f and deletes it, returning the error that prevented either.
+func (s *syncer) syncArbitraryFile(f *os.File) error {
+	uploadErr := s.exponentialRetry(
 		s.cancelCtx,
+		f.Name(),
 		func(ctx context.Context) error {
-			uploadErr := uploadArbitraryFile(ctx, s.client, f, s.partID, s.arbitraryFileTags)
+			uploadErr := uploadArbitraryFile(ctx, s.uploadClient(), f, s.partID, s.arbitraryFileTags)
 			if uploadErr != nil {
 				s.syncErrs <- errors.Wrap(uploadErr, fmt.Sprintf("error uploading file %s", f.Name()))
 			}
@@ -222,12 +265,19 @@ func (s *syncer) syncArbitraryFile(f *os.File) {
 		if err != nil {
 			s.syncErrs <- errors.Wrap(err, "error closing data capture file")
 		}
-		return
+		if errors.Is(uploadErr, errMaxSyncAttemptsExceeded) {
+			if err := moveFailedData(f.Name(), path.Dir(f.Name())); err != nil {
+				s.syncErrs <- errors.Wrap(err, fmt.Sprintf("error moving failed data %s", f.Name()))
+			}
+		}
+		return uploadErr
 	}
 	if err := os.Remove(f.Name()); err != nil {
-		s.syncErrs <- errors.Wrap(err, fmt.Sprintf("error deleting file %s", f.Name()))
-		return
+		err = errors.Wrap(err, fmt.Sprintf("error deleting file %s", f.Name()))
+		s.syncErrs <- err
+		return err
 	}
+	return nil
 }
 
 // markInProgress marks path as in progress in s.inProgress. It returns true if it changed the progress status,
@@ -261,42 +311,38 @@ func (s *syncer) logSyncErrs() {
 	}
 }
 
-// exponentialRetry calls fn and retries with exponentially increasing waits from initialWait to a
-// maximum of maxRetryInterval.
-func exponentialRetry(cancelCtx context.Context, fn func(cancelCtx context.Context) error) error {
-	// Only create a ticker and enter the retry loop if we actually need to retry.
+// exponentialRetry calls fn and retries with exponentially increasing, jittered waits from InitialWaitTimeMillis
+// to a maximum of s.maxRetryInterval. After MaxSyncAttempts failed attempts it gives up and returns an error
+// wrapping errMaxSyncAttemptsExceeded.
+func (s *syncer) exponentialRetry(cancelCtx context.Context, path string, fn func(cancelCtx context.Context) error) error {
 	var err error
 	if err = fn(cancelCtx); err == nil {
 		return nil
 	}
-	// Don't retry non-retryable errors.
-	if !isRetryableGRPCError(err) {
-		return err
-	}
 
-	// First call failed, so begin exponentialRetry with a factor of RetryExponentialFactor
-	nextWait := time.Millisecond * time.Duration(InitialWaitTimeMillis.Load())
-	ticker := time.NewTicker(nextWait)
-	for {
-		if err := cancelCtx.Err(); err != nil {
+	maxAttempts := int(MaxSyncAttempts.Load())
+	var nextWait time.Duration
+	for attempt := 2; ; attempt++ {
+		// Don't retry non-retryable errors.
+		if !isRetryableGRPCError(err) {
 			return err
 		}
+		if attempt > maxAttempts {
+			s.logger.Warnw("giving up on syncing file", "file", path, "attempts", maxAttempts, "error", err)
+			return errors.Wrapf(errMaxSyncAttemptsExceeded, "%s after %d attempts: %v", path, maxAttempts, err)
+		}
+
+		nextWait = getNextWait(nextWait, s.maxRetryInterval.Load())
+		wait := withJitter(nextWait)
+		s.logger.Infow("retrying file sync", "file", path, "attempt", attempt, "max_attempts", maxAttempts, "wait", wait)
+		timer := time.NewTimer(wait)
 		select {
-		// If cancelled, return nil.
 		case <-cancelCtx.Done():
-			ticker.Stop()
+			timer.Stop()
 			return cancelCtx.Err()
-			// Otherwise, try again after nextWait.
-		case <-ticker.C:
-			if err := fn(cancelCtx); err != nil {
-				// If error, retry with a new nextWait.
-				ticker.Stop()
-				nextWait = getNextWait(nextWait)
-				ticker = time.NewTicker(nextWait)
-				continue
-			}
-			// If no error, return.
-			ticker.Stop()
+		case <-timer.C:
+		}
+		if err = fn(cancelCtx); err == nil {
 			return nil
 		}
 	}
@@ -330,13 +376,26 @@ func moveFailedData(path, parentDir string) error {
 	return nil
 }
 
-func getNextWait(lastWait time.Duration) time.Duration {
+// getNextWait returns the wait before the next retry given the previous one, growing by RetryExponentialFactor
+// up to maxWait.
+func getNextWait(lastWait, maxWait time.Duration) time.Duration {
 	if lastWait == time.Duration(0) {
 		return time.Millisecond * time.Duration(InitialWaitTimeMillis.Load())
 	}
 	nextWait := lastWait * time.Duration(RetryExponentialFactor.Load())
-	if nextWait > maxRetryInterval {
-		return maxRetryInterval
+	if nextWait > maxWait {
+		return maxWait
 	}
 	return nextWait
 }
+
+// withJitter returns a random duration between wait/2 and wait so that many files failing at once are not all
+// retried at the same time.
+func withJitter(wait time.Duration) time.Duration {
+	half := wait / 2
+	if half <= 0 {
+		return wait
+	}
+	//nolint:gosec
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
@@ -0,0 +1,24 @@
+package datasync
+
+import (
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+)
+
+func TestGetNextWait(t *testing.T) {
+	initialWait := time.Millisecond * time.Duration(InitialWaitTimeMillis.Load())
+	test.That(t, getNextWait(0, time.Hour), test.ShouldEqual, initialWait)
+	test.That(t, getNextWait(initialWait, time.Hour), test.ShouldEqual, initialWait*time.Duration(RetryExponentialFactor.Load()))
+	test.That(t, getNextWait(time.Minute, 90*time.Second), test.ShouldEqual, 90*time.Second)
+}
+
+func TestWithJitter(t *testing.T) {
+	test.That(t, withJitter(0), test.ShouldEqual, 0)
+	for i := 0; i < 100; i++ {
+		wait := withJitter(time.Second)
+		test.That(t, wait, test.ShouldBeGreaterThanOrEqualTo, 500*time.Millisecond)
+		test.That(t, wait, test.ShouldBeLessThanOrEqualTo, time.Second)
+	}
+}
@@ -255,8 +255,7 @@ func TestNewMLClassifier(t *testing.T) {
 	gotClassifications, err := gotClassifier(ctx, pic)
 	test.That(t, err, test.ShouldBeNil)
 	test.That(t, gotClassifications, test.ShouldNotBeNil)
-	gotTop, err := gotClassifications.TopN(5)
-	test.That(t, err, test.ShouldBeNil)
+	gotTop := gotClassifications.TopN(5)
 	test.That(t, gotTop, test.ShouldNotBeNil)
 	test.That(t, gotTop[0].Label(), test.ShouldContainSubstring, "lion")
 	test.That(t, gotTop[0].Score(), test.ShouldBeGreaterThan, 0.99)
@@ -275,8 +274,7 @@ func TestNewMLClassifier(t *testing.T) {
 	gotClassificationsNL, err := gotClassifierNL(ctx, pic)
 	test.That(t, err, test.ShouldBeNil)
 	test.That(t, gotClassificationsNL, test.ShouldNotBeNil)
-	topNL, err := gotClassificationsNL.TopN(5)
-	test.That(t, err, test.ShouldBeNil)
+	topNL := gotClassificationsNL.TopN(5)
 	test.That(t, topNL, test.ShouldNotBeNil)
 	test.That(t, topNL[0].Label(), test.ShouldContainSubstring, "291")
 	test.That(t, topNL[0].Score(), test.ShouldBeGreaterThan, 0.99)
@@ -394,8 +392,7 @@ func TestMoreMLClassifiers(t *testing.T) {
 
 	gotClassifications, err := gotClassifier(ctx, pic)
 	test.That(t, err, test.ShouldBeNil)
-	bestClass, err := gotClassifications.TopN(1)
-	test.That(t, err, test.ShouldBeNil)
+	bestClass := gotClassifications.TopN(1)
 	test.That(t, bestClass[0].Label(), test.ShouldResemble, "390")
 	test.That(t, bestClass[0].Score(), test.ShouldBeGreaterThan, 0.93)
 
@@ -424,8 +421,7 @@ func TestMoreMLClassifiers(t *testing.T) {
 	gotClassifications, err = gotClassifier(ctx, pic)
 	test.That(t, err, test.ShouldBeNil)
 	test.That(t, gotClassifications, test.ShouldNotBeNil)
-	bestClass, err = gotClassifications.TopN(1)
-	test.That(t, err, test.ShouldBeNil)
+	bestClass = gotClassifications.TopN(1)
 	test.That(t, bestClass[0].Label(), test.ShouldResemble, "292")
 	test.That(t, bestClass[0].Score(), test.ShouldBeGreaterThan, 0.93)
 }
@@ -580,8 +576,7 @@ func getNClassifications(
 	for i := 0; i < n; i++ {
 		results[i], err = c(ctx, img)
 		test.That(t, err, test.ShouldBeNil)
-		res, err := results[i].TopN(1)
-		test.That(t, err, test.ShouldBeNil)
+		res := results[i].TopN(1)
 		test.That(t, res[0].Score(), test.ShouldNotBeNil)
 	}
 }
@@ -158,7 +158,7 @@ func (vm *vizModel) Classifications(
 	if err != nil {
 		return nil, errors.Wrap(err, "could not get classifications from image")
 	}
-	return fullClassifications.TopN(n)
+	return topNClassifications(fullClassifications, n)
 }
 
 // ClassificationsFromCamera returns the classifications of the next image from the given camera.
@@ -186,7 +186,16 @@ func (vm *vizModel) ClassificationsFromCamera(
 	if err != nil {
 		return nil, errors.Wrap(err, "could not get classifications from image")
 	}
-	return fullClassifications.TopN(n)
+	return topNClassifications(fullClassifications, n)
+}
+
+// topNClassifications returns the n classifications with the highest scores, or an error if there are fewer
+// than n.
+func topNClassifications(classifications classification.Classifications, n int) (classification.Classifications, error) {
+	if len(classifications) < n {
+		return nil, errors.Errorf("cannot produce top %v results from list of length %v", n, len(classifications))
+	}
+	return classifications.TopN(n), nil
 }
 
 // GetObjectPointClouds returns all the found objects in a 3D image if the model implements Segmenter3D.
@@ -12,8 +12,24 @@ type AppServiceClient struct {
 	apppb.AppServiceClient
 	ListOrganizationsFunc func(ctx context.Context, in *apppb.ListOrganizationsRequest,
 		opts ...grpc.CallOption) (*apppb.ListOrganizationsResponse, error)
+	UpdateOrganizationFunc func(ctx context.Context, in *apppb.UpdateOrganizationRequest,
+		opts ...grpc.CallOption) (*apppb.UpdateOrganizationResponse, error)
+	ListOrganizationMembersFunc func(ctx context.Context, in *apppb.ListOrganizationMembersRequest,
+		opts ...grpc.CallOption) (*apppb.ListOrganizationMembersResponse, error)
+	ListAuthorizationsFunc func(ctx context.Context, in *apppb.ListAuthorizationsRequest,
+		opts ...grpc.CallOption) (*apppb.ListAuthorizationsResponse, error)
 	CreateKeyFunc func(ctx context.Context, in *apppb.CreateKeyRequest,
 		opts ...grpc.CallOption) (*apppb.CreateKeyResponse, error)
+	ListLocationsFunc func(ctx context.Context, in *apppb.ListLocationsRequest,
+		opts ...grpc.CallOption) (*apppb.ListLocationsResponse, error)
+	CreateLocationFunc func(ctx context.Context, in *apppb.CreateLocationRequest,
+		opts ...grpc.CallOption) (*apppb.CreateLocationResponse, error)
+	DeleteLocationFunc func(ctx context.Context, in *apppb.DeleteLocationRequest,
+		opts ...grpc.CallOption) (*apppb.DeleteLocationResponse, error)
+	ListRobotsFunc func(ctx context.Context, in *apppb.ListRobotsRequest,
+		opts ...grpc.CallOption) (*apppb.ListRobotsResponse, error)
+	TailRobotPartLogsFunc func(ctx context.Context, in *apppb.TailRobotPartLogsRequest,
+		opts ...grpc.CallOption) (apppb.AppService_TailRobotPartLogsClient, error)
 }
 
 // ListOrganizations calls the injected ListOrganizationsFunc or the real version.
@@ -26,6 +42,36 @@ func (asc *AppServiceClient) ListOrganizations(ctx context.Context, in *apppb.Li
 	return asc.ListOrganizationsFunc(ctx, in, opts...)
 }
 
+// UpdateOrganization calls the injected UpdateOrganizationFunc or the real version.
+func (asc *AppServiceClient) UpdateOrganization(ctx context.Context, in *apppb.UpdateOrganizationRequest,
+	opts ...grpc.CallOption,
+) (*apppb.UpdateOrganizationResponse, error) {
+	if asc.UpdateOrganizationFunc == nil {
+		return asc.AppServiceClient.UpdateOrganization(ctx, in, opts...)
+	}
+	return asc.UpdateOrganizationFunc(ctx, in, opts...)
+}
+
+// ListOrganizationMembers calls the injected ListOrganizationMembersFunc or the real version.
+func (asc *AppServiceClient) ListOrganizationMembers(ctx context.Context, in *apppb.ListOrganizationMembersRequest,
+	opts ...grpc.CallOption,
+) (*apppb.ListOrganizationMembersResponse, error) {
+	if asc.ListOrganizationMembersFunc == nil {
+		return asc.AppServiceClient.ListOrganizationMembers(ctx, in, opts...)
+	}
+	return asc.ListOrganizationMembersFunc(ctx, in, opts...)
+}
+
+// ListAuthorizations calls the injected ListAuthorizationsFunc or the real version.
+func (asc *AppServiceClient) ListAuthorizations(ctx context.Context, in *apppb.ListAuthorizationsRequest,
+	opts ...grpc.CallOption,
+) (*apppb.ListAuthorizationsResponse, error) {
+	if asc.ListAuthorizationsFunc == nil {
+		return asc.AppServiceClient.ListAuthorizations(ctx, in, opts...)
+	}
+	return asc.ListAuthorizationsFunc(ctx, in, opts...)
+}
+
 // CreateKey calls the injected CreateKeyFunc or the real version.
 func (asc *AppServiceClient) CreateKey(ctx context.Context, in *apppb.CreateKeyRequest,
 	opts ...grpc.CallOption,
@@ -35,3 +81,53 @@ func (asc *AppServiceClient) CreateKey(ctx context.Context, in *apppb.CreateKeyR
 	}
 	return asc.CreateKeyFunc(ctx, in, opts...)
 }
+
+// ListLocations calls the injected ListLocationsFunc or the real version.
+func (asc *AppServiceClient) ListLocations(ctx context.Context, in *apppb.ListLocationsRequest,
+	opts ...grpc.CallOption,
+) (*apppb.ListLocationsResponse, error) {
+	if asc.ListLocationsFunc == nil {
+		return asc.AppServiceClient.ListLocations(ctx, in, opts...)
+	}
+	return asc.ListLocationsFunc(ctx, in, opts...)
+}
+
+// CreateLocation calls the injected CreateLocationFunc or the real version.
+func (asc *AppServiceClient) CreateLocation(ctx context.Context, in *apppb.CreateLocationRequest,
+	opts ...grpc.CallOption,
+) (*apppb.CreateLocationResponse, error) {
+	if asc.CreateLocationFunc == nil {
+		return asc.AppServiceClient.CreateLocation(ctx, in, opts...)
+	}
+	return asc.CreateLocationFunc(ctx, in, opts...)
+}
+
+// DeleteLocation calls the injected DeleteLocationFunc or the real version.
+func (asc *AppServiceClient) DeleteLocation(ctx context.Context, in *apppb.DeleteLocationRequest,
+	opts ...grpc.CallOption,
+) (*apppb.DeleteLocationResponse, error) {
+	if asc.DeleteLocationFunc == nil {
+		return asc.AppServiceClient.DeleteLocation(ctx, in, opts...)
+	}
+	return asc.DeleteLocationFunc(ctx, in, opts...)
+}
+
+// ListRobots calls the injected ListRobotsFunc or the real version.
+func (asc *AppServiceClient) ListRobots(ctx context.Context, in *apppb.ListRobotsRequest,
+	opts ...grpc.CallOption,
+) (*apppb.ListRobotsResponse, error) {
+	if asc.ListRobotsFunc == nil {
+		return asc.AppServiceClient.ListRobots(ctx, in, opts...)
+	}
+	return asc.ListRobotsFunc(ctx, in, opts...)
+}
+
+// TailRobotPartLogs calls the injected TailRobotPartLogsFunc or the real version.
+func (asc *AppServiceClient) TailRobotPartLogs(ctx context.Context, in *apppb.TailRobotPartLogsRequest,
+	opts ...grpc.CallOption,
+) (apppb.AppService_TailRobotPartLogsClient, error) {
+	if asc.TailRobotPartLogsFunc == nil {
+		return asc.AppServiceClient.TailRobotPartLogs(ctx, in, opts...)
+	}
+	return asc.TailRobotPartLogsFunc(ctx, in, opts...)
+}
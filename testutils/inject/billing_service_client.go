@@ -0,0 +1,37 @@
+package inject
+
+import (
+	"context"
+
+	apppb "go.viam.com/api/app/v1"
+	"google.golang.org/grpc"
+)
+
+// BillingServiceClient represents a fake instance of a billing service client.
+type BillingServiceClient struct {
+	apppb.BillingServiceClient
+	GetCurrentMonthUsageFunc func(ctx context.Context, in *apppb.GetCurrentMonthUsageRequest,
+		opts ...grpc.CallOption) (*apppb.GetCurrentMonthUsageResponse, error)
+	GetOrgBillingInformationFunc func(ctx context.Context, in *apppb.GetOrgBillingInformationRequest,
+		opts ...grpc.CallOption) (*apppb.GetOrgBillingInformationResponse, error)
+}
+
+// GetCurrentMonthUsage calls the injected GetCurrentMonthUsageFunc or the real version.
+func (bsc *BillingServiceClient) GetCurrentMonthUsage(ctx context.Context, in *apppb.GetCurrentMonthUsageRequest,
+	opts ...grpc.CallOption,
+) (*apppb.GetCurrentMonthUsageResponse, error) {
+	if bsc.GetCurrentMonthUsageFunc == nil {
+		return bsc.BillingServiceClient.GetCurrentMonthUsage(ctx, in, opts...)
+	}
+	return bsc.GetCurrentMonthUsageFunc(ctx, in, opts...)
+}
+
+// GetOrgBillingInformation calls the injected GetOrgBillingInformationFunc or the real version.
+func (bsc *BillingServiceClient) GetOrgBillingInformation(ctx context.Context, in *apppb.GetOrgBillingInformationRequest,
+	opts ...grpc.CallOption,
+) (*apppb.GetOrgBillingInformationResponse, error) {
+	if bsc.GetOrgBillingInformationFunc == nil {
+		return bsc.BillingServiceClient.GetOrgBillingInformation(ctx, in, opts...)
+	}
+	return bsc.GetOrgBillingInformationFunc(ctx, in, opts...)
+}
@@ -12,6 +12,7 @@ type BuildServiceClient struct {
 	buildpb.BuildServiceClient
 	ListJobsFunc   func(ctx context.Context, in *buildpb.ListJobsRequest, opts ...grpc.CallOption) (*buildpb.ListJobsResponse, error)
 	StartBuildFunc func(ctx context.Context, in *buildpb.StartBuildRequest, opts ...grpc.CallOption) (*buildpb.StartBuildResponse, error)
+	GetLogsFunc    func(ctx context.Context, in *buildpb.GetLogsRequest, opts ...grpc.CallOption) (buildpb.BuildService_GetLogsClient, error)
 }
 
 // ListJobs calls the injected ListJobsFunc or the real version.
@@ -33,3 +34,13 @@ func (bsc *BuildServiceClient) StartBuild(ctx context.Context, in *buildpb.Start
 	}
 	return bsc.StartBuildFunc(ctx, in, opts...)
 }
+
+// GetLogs calls the injected GetLogsFunc or the real version.
+func (bsc *BuildServiceClient) GetLogs(ctx context.Context, in *buildpb.GetLogsRequest,
+	opts ...grpc.CallOption,
+) (buildpb.BuildService_GetLogsClient, error) {
+	if bsc.GetLogsFunc == nil {
+		return bsc.BuildServiceClient.GetLogs(ctx, in, opts...)
+	}
+	return bsc.GetLogsFunc(ctx, in, opts...)
+}
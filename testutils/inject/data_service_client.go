@@ -15,6 +15,46 @@ type DataServiceClient struct {
 		in *datapb.TabularDataByFilterRequest,
 		opts ...grpc.CallOption,
 	) (*datapb.TabularDataByFilterResponse, error)
+	BinaryDataByFilterFunc func(
+		ctx context.Context,
+		in *datapb.BinaryDataByFilterRequest,
+		opts ...grpc.CallOption,
+	) (*datapb.BinaryDataByFilterResponse, error)
+	BinaryDataByIDsFunc func(
+		ctx context.Context,
+		in *datapb.BinaryDataByIDsRequest,
+		opts ...grpc.CallOption,
+	) (*datapb.BinaryDataByIDsResponse, error)
+	DeleteTabularDataFunc func(
+		ctx context.Context,
+		in *datapb.DeleteTabularDataRequest,
+		opts ...grpc.CallOption,
+	) (*datapb.DeleteTabularDataResponse, error)
+	AddTagsToBinaryDataByFilterFunc func(
+		ctx context.Context,
+		in *datapb.AddTagsToBinaryDataByFilterRequest,
+		opts ...grpc.CallOption,
+	) (*datapb.AddTagsToBinaryDataByFilterResponse, error)
+	RemoveTagsFromBinaryDataByFilterFunc func(
+		ctx context.Context,
+		in *datapb.RemoveTagsFromBinaryDataByFilterRequest,
+		opts ...grpc.CallOption,
+	) (*datapb.RemoveTagsFromBinaryDataByFilterResponse, error)
+	AddBoundingBoxToImageByIDFunc func(
+		ctx context.Context,
+		in *datapb.AddBoundingBoxToImageByIDRequest,
+		opts ...grpc.CallOption,
+	) (*datapb.AddBoundingBoxToImageByIDResponse, error)
+	RemoveBoundingBoxFromImageByIDFunc func(
+		ctx context.Context,
+		in *datapb.RemoveBoundingBoxFromImageByIDRequest,
+		opts ...grpc.CallOption,
+	) (*datapb.RemoveBoundingBoxFromImageByIDResponse, error)
+	AddBinaryDataToDatasetByIDsFunc func(
+		ctx context.Context,
+		in *datapb.AddBinaryDataToDatasetByIDsRequest,
+		opts ...grpc.CallOption,
+	) (*datapb.AddBinaryDataToDatasetByIDsResponse, error)
 }
 
 // TabularDataByFilter calls the injected TabularDataByFilter or the real version.
@@ -25,3 +65,80 @@ func (client *DataServiceClient) TabularDataByFilter(ctx context.Context, in *da
 	}
 	return client.TabularDataByFilterFunc(ctx, in, opts...)
 }
+
+// BinaryDataByFilter calls the injected BinaryDataByFilter or the real version.
+func (client *DataServiceClient) BinaryDataByFilter(ctx context.Context, in *datapb.BinaryDataByFilterRequest, opts ...grpc.CallOption,
+) (*datapb.BinaryDataByFilterResponse, error) {
+	if client.BinaryDataByFilterFunc == nil {
+		return client.DataServiceClient.BinaryDataByFilter(ctx, in, opts...)
+	}
+	return client.BinaryDataByFilterFunc(ctx, in, opts...)
+}
+
+// BinaryDataByIDs calls the injected BinaryDataByIDs or the real version.
+func (client *DataServiceClient) BinaryDataByIDs(ctx context.Context, in *datapb.BinaryDataByIDsRequest, opts ...grpc.CallOption,
+) (*datapb.BinaryDataByIDsResponse, error) {
+	if client.BinaryDataByIDsFunc == nil {
+		return client.DataServiceClient.BinaryDataByIDs(ctx, in, opts...)
+	}
+	return client.BinaryDataByIDsFunc(ctx, in, opts...)
+}
+
+// DeleteTabularData calls the injected DeleteTabularData or the real version.
+func (client *DataServiceClient) DeleteTabularData(ctx context.Context, in *datapb.DeleteTabularDataRequest, opts ...grpc.CallOption,
+) (*datapb.DeleteTabularDataResponse, error) {
+	if client.DeleteTabularDataFunc == nil {
+		return client.DataServiceClient.DeleteTabularData(ctx, in, opts...)
+	}
+	return client.DeleteTabularDataFunc(ctx, in, opts...)
+}
+
+// AddTagsToBinaryDataByFilter calls the injected AddTagsToBinaryDataByFilter or the real version.
+func (client *DataServiceClient) AddTagsToBinaryDataByFilter(ctx context.Context, in *datapb.AddTagsToBinaryDataByFilterRequest,
+	opts ...grpc.CallOption,
+) (*datapb.AddTagsToBinaryDataByFilterResponse, error) {
+	if client.AddTagsToBinaryDataByFilterFunc == nil {
+		return client.DataServiceClient.AddTagsToBinaryDataByFilter(ctx, in, opts...)
+	}
+	return client.AddTagsToBinaryDataByFilterFunc(ctx, in, opts...)
+}
+
+// RemoveTagsFromBinaryDataByFilter calls the injected RemoveTagsFromBinaryDataByFilter or the real version.
+func (client *DataServiceClient) RemoveTagsFromBinaryDataByFilter(ctx context.Context, in *datapb.RemoveTagsFromBinaryDataByFilterRequest,
+	opts ...grpc.CallOption,
+) (*datapb.RemoveTagsFromBinaryDataByFilterResponse, error) {
+	if client.RemoveTagsFromBinaryDataByFilterFunc == nil {
+		return client.DataServiceClient.RemoveTagsFromBinaryDataByFilter(ctx, in, opts...)
+	}
+	return client.RemoveTagsFromBinaryDataByFilterFunc(ctx, in, opts...)
+}
+
+// AddBoundingBoxToImageByID calls the injected AddBoundingBoxToImageByID or the real version.
+func (client *DataServiceClient) AddBoundingBoxToImageByID(ctx context.Context, in *datapb.AddBoundingBoxToImageByIDRequest,
+	opts ...grpc.CallOption,
+) (*datapb.AddBoundingBoxToImageByIDResponse, error) {
+	if client.AddBoundingBoxToImageByIDFunc == nil {
+		return client.DataServiceClient.AddBoundingBoxToImageByID(ctx, in, opts...)
+	}
+	return client.AddBoundingBoxToImageByIDFunc(ctx, in, opts...)
+}
+
+// RemoveBoundingBoxFromImageByID calls the injected RemoveBoundingBoxFromImageByID or the real version.
+func (client *DataServiceClient) RemoveBoundingBoxFromImageByID(ctx context.Context, in *datapb.RemoveBoundingBoxFromImageByIDRequest,
+	opts ...grpc.CallOption,
+) (*datapb.RemoveBoundingBoxFromImageByIDResponse, error) {
+	if client.RemoveBoundingBoxFromImageByIDFunc == nil {
+		return client.DataServiceClient.RemoveBoundingBoxFromImageByID(ctx, in, opts...)
+	}
+	return client.RemoveBoundingBoxFromImageByIDFunc(ctx, in, opts...)
+}
+
+// AddBinaryDataToDatasetByIDs calls the injected AddBinaryDataToDatasetByIDs or the real version.
+func (client *DataServiceClient) AddBinaryDataToDatasetByIDs(ctx context.Context, in *datapb.AddBinaryDataToDatasetByIDsRequest,
+	opts ...grpc.CallOption,
+) (*datapb.AddBinaryDataToDatasetByIDsResponse, error) {
+	if client.AddBinaryDataToDatasetByIDsFunc == nil {
+		return client.DataServiceClient.AddBinaryDataToDatasetByIDs(ctx, in, opts...)
+	}
+	return client.AddBinaryDataToDatasetByIDsFunc(ctx, in, opts...)
+}
@@ -0,0 +1,61 @@
+package inject
+
+import (
+	"context"
+
+	datasetpb "go.viam.com/api/app/dataset/v1"
+	"google.golang.org/grpc"
+)
+
+// DatasetServiceClient is an injectable datasetpb.DatasetServiceClient.
+type DatasetServiceClient struct {
+	datasetpb.DatasetServiceClient
+	CreateDatasetFunc func(ctx context.Context, in *datasetpb.CreateDatasetRequest,
+		opts ...grpc.CallOption) (*datasetpb.CreateDatasetResponse, error)
+	DeleteDatasetFunc func(ctx context.Context, in *datasetpb.DeleteDatasetRequest,
+		opts ...grpc.CallOption) (*datasetpb.DeleteDatasetResponse, error)
+	ListDatasetsByIDsFunc func(ctx context.Context, in *datasetpb.ListDatasetsByIDsRequest,
+		opts ...grpc.CallOption) (*datasetpb.ListDatasetsByIDsResponse, error)
+	ListDatasetsByOrganizationIDFunc func(ctx context.Context, in *datasetpb.ListDatasetsByOrganizationIDRequest,
+		opts ...grpc.CallOption) (*datasetpb.ListDatasetsByOrganizationIDResponse, error)
+}
+
+// CreateDataset calls the injected CreateDatasetFunc or the real version.
+func (dsc *DatasetServiceClient) CreateDataset(ctx context.Context, in *datasetpb.CreateDatasetRequest,
+	opts ...grpc.CallOption,
+) (*datasetpb.CreateDatasetResponse, error) {
+	if dsc.CreateDatasetFunc == nil {
+		return dsc.DatasetServiceClient.CreateDataset(ctx, in, opts...)
+	}
+	return dsc.CreateDatasetFunc(ctx, in, opts...)
+}
+
+// DeleteDataset calls the injected DeleteDatasetFunc or the real version.
+func (dsc *DatasetServiceClient) DeleteDataset(ctx context.Context, in *datasetpb.DeleteDatasetRequest,
+	opts ...grpc.CallOption,
+) (*datasetpb.DeleteDatasetResponse, error) {
+	if dsc.DeleteDatasetFunc == nil {
+		return dsc.DatasetServiceClient.DeleteDataset(ctx, in, opts...)
+	}
+	return dsc.DeleteDatasetFunc(ctx, in, opts...)
+}
+
+// ListDatasetsByIDs calls the injected ListDatasetsByIDsFunc or the real version.
+func (dsc *DatasetServiceClient) ListDatasetsByIDs(ctx context.Context, in *datasetpb.ListDatasetsByIDsRequest,
+	opts ...grpc.CallOption,
+) (*datasetpb.ListDatasetsByIDsResponse, error) {
+	if dsc.ListDatasetsByIDsFunc == nil {
+		return dsc.DatasetServiceClient.ListDatasetsByIDs(ctx, in, opts...)
+	}
+	return dsc.ListDatasetsByIDsFunc(ctx, in, opts...)
+}
+
+// ListDatasetsByOrganizationID calls the injected ListDatasetsByOrganizationIDFunc or the real version.
+func (dsc *DatasetServiceClient) ListDatasetsByOrganizationID(ctx context.Context, in *datasetpb.ListDatasetsByOrganizationIDRequest,
+	opts ...grpc.CallOption,
+) (*datasetpb.ListDatasetsByOrganizationIDResponse, error) {
+	if dsc.ListDatasetsByOrganizationIDFunc == nil {
+		return dsc.DatasetServiceClient.ListDatasetsByOrganizationID(ctx, in, opts...)
+	}
+	return dsc.ListDatasetsByOrganizationIDFunc(ctx, in, opts...)
+}
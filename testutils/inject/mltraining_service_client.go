@@ -0,0 +1,25 @@
+package inject
+
+import (
+	"context"
+
+	mltrainingpb "go.viam.com/api/app/mltraining/v1"
+	"google.golang.org/grpc"
+)
+
+// MLTrainingServiceClient is an injectable mltrainingpb.MLTrainingServiceClient.
+type MLTrainingServiceClient struct {
+	mltrainingpb.MLTrainingServiceClient
+	GetTrainingJobFunc func(ctx context.Context, in *mltrainingpb.GetTrainingJobRequest,
+		opts ...grpc.CallOption) (*mltrainingpb.GetTrainingJobResponse, error)
+}
+
+// GetTrainingJob calls the injected GetTrainingJobFunc or the real version.
+func (mltc *MLTrainingServiceClient) GetTrainingJob(ctx context.Context, in *mltrainingpb.GetTrainingJobRequest,
+	opts ...grpc.CallOption,
+) (*mltrainingpb.GetTrainingJobResponse, error) {
+	if mltc.GetTrainingJobFunc == nil {
+		return mltc.MLTrainingServiceClient.GetTrainingJob(ctx, in, opts...)
+	}
+	return mltc.GetTrainingJobFunc(ctx, in, opts...)
+}
@@ -4,30 +4,185 @@ import (
 	"fmt"
 	"image"
 	"image/color"
+	"sort"
 
 	"github.com/fogleman/gg"
+	"github.com/pkg/errors"
 
 	"go.viam.com/rdk/rimage"
 )
 
+// OverlayOptions configures how OverlayWithOptions draws classifications.
+type OverlayOptions struct {
+	// TextColor is the color of the labels and bounding boxes.
+	TextColor color.Color
+	// FontSize is the size of the label font. It must be positive.
+	FontSize float64
+	// StartX and StartY are where the first label without a bounding box is drawn.
+	StartX int
+	StartY int
+	// LineSpacing is the vertical distance between labels without bounding boxes.
+	LineSpacing int
+	// MaxClassifications, if positive, limits drawing to that many classifications with the highest scores.
+	MaxClassifications int
+	// MinScore, if positive, limits drawing to classifications with at least this score.
+	MinScore float64
+	// Boxes must either be empty or have one entry per classification. A classification with a non-nil
+	// box has the box drawn around it and its label anchored to the box's top left corner.
+	Boxes []*image.Rectangle
+}
+
+// DefaultOverlayOptions returns the options used by Overlay.
+func DefaultOverlayOptions() OverlayOptions {
+	return OverlayOptions{
+		TextColor:   color.NRGBA{255, 0, 0, 255},
+		FontSize:    30,
+		StartX:      30,
+		StartY:      30,
+		LineSpacing: 30,
+	}
+}
+
 // Overlay returns a color image with the classification labels and confidence scores overlaid on
 // the original image.
 func Overlay(img image.Image, classifications Classifications) (image.Image, error) {
+	return OverlayWithOptions(img, classifications, DefaultOverlayOptions())
+}
+
+// OverlayWithBoxes returns a color image with the classification labels and confidence scores overlaid on
+// the original image. boxes must either be empty or have one entry per classification. A classification
+// with a non-nil box has the box drawn around it and its label anchored to the box's top left corner, and
+// the rest have their labels stacked in the top left corner of the image.
+func OverlayWithBoxes(img image.Image, classifications Classifications, boxes []*image.Rectangle) (image.Image, error) {
+	opts := DefaultOverlayOptions()
+	opts.Boxes = boxes
+	return OverlayWithOptions(img, classifications, opts)
+}
+
+// OverlayWithOptions returns a color image with the classification labels and confidence scores overlaid on
+// the original image, drawn as described by opts.
+func OverlayWithOptions(img image.Image, classifications Classifications, opts OverlayOptions) (image.Image, error) {
+	if opts.FontSize <= 0 {
+		return nil, errors.Errorf("font size must be positive, got %v", opts.FontSize)
+	}
+	if opts.TextColor == nil {
+		return nil, errors.New("text color must be set")
+	}
+	if len(opts.Boxes) != 0 && len(opts.Boxes) != len(classifications) {
+		return nil, errors.Errorf("have %d boxes and %d classifications, must be equal", len(opts.Boxes), len(classifications))
+	}
 	gimg := gg.NewContextForImage(img)
-	x := 30
-	y := 30
-	for _, classification := range classifications {
-		// Skip unknown labels generated by Viam-trained models.
-		if classification.Label() == "VIAM_UNKNOWN" {
+	x := opts.StartX
+	y := opts.StartY
+	for _, i := range overlayOrder(classifications, opts) {
+		classification := classifications[i]
+		text := fmt.Sprintf("%v: %.2f", classification.Label(), classification.Score())
+		if len(opts.Boxes) != 0 && opts.Boxes[i] != nil {
+			box := opts.Boxes[i]
+			if !box.In(img.Bounds()) {
+				return nil, errors.Errorf("bounding box (%v) does not fit in image (%v)", box, img.Bounds())
+			}
+			rimage.DrawRectangleEmpty(gimg, *box, opts.TextColor, 2.0)
+			rimage.DrawString(gimg, text, box.Min, opts.TextColor, opts.FontSize)
 			continue
-		} else {
-			rimage.DrawString(gimg,
-				fmt.Sprintf("%v: %.2f", classification.Label(), classification.Score()),
-				image.Point{x, y},
-				color.NRGBA{255, 0, 0, 255},
-				30)
-			y += 30
 		}
+		rimage.DrawString(gimg, text, image.Point{x, y}, opts.TextColor, opts.FontSize)
+		y += opts.LineSpacing
 	}
 	return gimg.Image(), nil
 }
+
+// overlayOrder returns the indices of the classifications to draw, in the order to draw them. Unknown
+// labels generated by Viam-trained models are skipped. If opts limits which classifications are drawn,
+// they are ordered by descending score, with ties broken by label.
+func overlayOrder(classifications Classifications, opts OverlayOptions) []int {
+	order := make([]int, 0, len(classifications))
+	for i, classification := range classifications {
+		if classification.Label() == "VIAM_UNKNOWN" {
+			continue
+		}
+		if opts.MinScore > 0 && classification.Score() < opts.MinScore {
+			continue
+		}
+		order = append(order, i)
+	}
+	if opts.MaxClassifications <= 0 && opts.MinScore <= 0 {
+		return order
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return scoreOrder(classifications[order[i]], classifications[order[j]])
+	})
+	if opts.MaxClassifications > 0 && len(order) > opts.MaxClassifications {
+		order = order[:opts.MaxClassifications]
+	}
+	return order
+}
+
+// NonMaxSuppression removes redundant overlapping classifications, such as a detector reporting the same
+// object several times. boxes must have one entry per classification, in the form OverlayWithBoxes takes.
+// Going from the highest score down, a classification is dropped if its box's intersection over union
+// with the box of an already kept classification is greater than iouThreshold, regardless of label.
+// Classifications with a nil box are always kept. The kept classifications and their boxes are returned
+// in their original order.
+func NonMaxSuppression(
+	classifications Classifications,
+	boxes []*image.Rectangle,
+	iouThreshold float64,
+) (Classifications, []*image.Rectangle, error) {
+	if len(boxes) != len(classifications) {
+		return nil, nil, errors.Errorf("have %d boxes and %d classifications, must be equal", len(boxes), len(classifications))
+	}
+	if iouThreshold < 0 || iouThreshold > 1 {
+		return nil, nil, errors.Errorf("iou threshold must be between 0 and 1, got %v", iouThreshold)
+	}
+
+	byScore := make([]int, len(classifications))
+	for i := range byScore {
+		byScore[i] = i
+	}
+	sort.SliceStable(byScore, func(i, j int) bool {
+		return classifications[byScore[i]].Score() > classifications[byScore[j]].Score()
+	})
+
+	keep := make([]bool, len(classifications))
+	var kept []*image.Rectangle
+	for _, i := range byScore {
+		if boxes[i] == nil {
+			keep[i] = true
+			continue
+		}
+		suppressed := false
+		for _, box := range kept {
+			if intersectionOverUnion(*boxes[i], *box) > iouThreshold {
+				suppressed = true
+				break
+			}
+		}
+		if !suppressed {
+			keep[i] = true
+			kept = append(kept, boxes[i])
+		}
+	}
+
+	outClassifications := make(Classifications, 0, len(classifications))
+	outBoxes := make([]*image.Rectangle, 0, len(boxes))
+	for i, classification := range classifications {
+		if keep[i] {
+			outClassifications = append(outClassifications, classification)
+			outBoxes = append(outBoxes, boxes[i])
+		}
+	}
+	return outClassifications, outBoxes, nil
+}
+
+// intersectionOverUnion returns the area of the intersection of a and b divided by the area of their union.
+func intersectionOverUnion(a, b image.Rectangle) float64 {
+	area := func(r image.Rectangle) float64 { return float64(r.Dx()) * float64(r.Dy()) }
+	intersection := area(a.Intersect(b))
+	union := area(a.Canon()) + area(b.Canon()) - intersection
+	if union <= 0 {
+		return 0
+	}
+	return intersection / union
+}
@@ -0,0 +1,225 @@
+package classification
+
+import (
+	"flag"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.viam.com/test"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden images in testdata")
+
+func blankImage(width, height int) image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{color.NRGBA{255, 255, 255, 255}}, image.Point{}, draw.Src)
+	return img
+}
+
+// checkGolden compares img to the golden image of the given name in testdata, rewriting the golden
+// image instead when run with -update.
+func checkGolden(t *testing.T, name string, img image.Image) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *updateGolden {
+		f, err := os.Create(path)
+		test.That(t, err, test.ShouldBeNil)
+		defer f.Close()
+		test.That(t, png.Encode(f, img), test.ShouldBeNil)
+		return
+	}
+
+	f, err := os.Open(path)
+	test.That(t, err, test.ShouldBeNil)
+	defer f.Close()
+	golden, err := png.Decode(f)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, img.Bounds(), test.ShouldResemble, golden.Bounds())
+	for y := img.Bounds().Min.Y; y < img.Bounds().Max.Y; y++ {
+		for x := img.Bounds().Min.X; x < img.Bounds().Max.X; x++ {
+			r1, g1, b1, a1 := img.At(x, y).RGBA()
+			r2, g2, b2, a2 := golden.At(x, y).RGBA()
+			if r1 != r2 || g1 != g2 || b1 != b2 || a1 != a2 {
+				t.Fatalf("pixel (%d, %d) differs from %s", x, y, path)
+			}
+		}
+	}
+}
+
+func TestOverlayWithBoxes(t *testing.T) {
+	img := blankImage(320, 240)
+	classifications := Classifications{
+		NewClassification(0.9, "cat"),
+		NewClassification(0.5, "VIAM_UNKNOWN"),
+		NewClassification(0.75, "dog"),
+	}
+	boxes := []*image.Rectangle{{Min: image.Point{150, 100}, Max: image.Point{300, 220}}, nil, nil}
+
+	overlay, err := OverlayWithBoxes(img, classifications, boxes)
+	test.That(t, err, test.ShouldBeNil)
+	checkGolden(t, "overlay_with_boxes.png", overlay)
+
+	// Without boxes, every label is stacked in the top left corner.
+	overlay, err = OverlayWithBoxes(img, classifications, nil)
+	test.That(t, err, test.ShouldBeNil)
+	checkGolden(t, "overlay.png", overlay)
+	overlay, err = Overlay(img, classifications)
+	test.That(t, err, test.ShouldBeNil)
+	checkGolden(t, "overlay.png", overlay)
+
+	_, err = OverlayWithBoxes(img, classifications, boxes[:1])
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "must be equal")
+
+	_, err = OverlayWithBoxes(img, classifications, []*image.Rectangle{{Max: image.Point{400, 400}}, nil, nil})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "does not fit")
+}
+
+func TestOverlayWithOptions(t *testing.T) {
+	img := blankImage(320, 240)
+	classifications := Classifications{NewClassification(0.9, "cat"), NewClassification(0.75, "dog")}
+
+	green := color.NRGBA{0, 255, 0, 255}
+	opts := DefaultOverlayOptions()
+	opts.TextColor = green
+	opts.FontSize = 20
+	opts.StartX = 10
+	opts.StartY = 100
+	opts.LineSpacing = 25
+	overlay, err := OverlayWithOptions(img, classifications, opts)
+	test.That(t, err, test.ShouldBeNil)
+
+	var numGreen, numRed int
+	for y := 0; y < 240; y++ {
+		for x := 0; x < 320; x++ {
+			r, g, b, _ := overlay.At(x, y).RGBA()
+			switch {
+			case r == 0 && g == 0xffff && b == 0:
+				numGreen++
+				test.That(t, y, test.ShouldBeGreaterThanOrEqualTo, opts.StartY)
+				test.That(t, y, test.ShouldBeLessThan, opts.StartY+2*opts.LineSpacing)
+			case r == 0xffff && g == 0 && b == 0:
+				numRed++
+			}
+		}
+	}
+	test.That(t, numGreen, test.ShouldBeGreaterThan, 0)
+	test.That(t, numRed, test.ShouldEqual, 0)
+
+	opts.FontSize = 0
+	_, err = OverlayWithOptions(img, classifications, opts)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "font size must be positive")
+}
+
+func TestOverlayOrder(t *testing.T) {
+	classifications := Classifications{
+		NewClassification(0.2, "bird"),
+		NewClassification(0.9, "VIAM_UNKNOWN"),
+		NewClassification(0.7, "dog"),
+		NewClassification(0.7, "cat"),
+		NewClassification(0.95, "fish"),
+	}
+
+	test.That(t, overlayOrder(classifications, DefaultOverlayOptions()), test.ShouldResemble, []int{0, 2, 3, 4})
+
+	opts := DefaultOverlayOptions()
+	opts.MaxClassifications = 3
+	test.That(t, overlayOrder(classifications, opts), test.ShouldResemble, []int{4, 3, 2})
+
+	opts = DefaultOverlayOptions()
+	opts.MinScore = 0.5
+	test.That(t, overlayOrder(classifications, opts), test.ShouldResemble, []int{4, 3, 2})
+
+	opts.MaxClassifications = 1
+	test.That(t, overlayOrder(classifications, opts), test.ShouldResemble, []int{4})
+
+	opts.MinScore = 0.99
+	test.That(t, overlayOrder(classifications, opts), test.ShouldBeEmpty)
+
+	// Boxes stay paired with their classifications after sorting.
+	img := blankImage(320, 240)
+	opts = DefaultOverlayOptions()
+	opts.MaxClassifications = 1
+	opts.Boxes = []*image.Rectangle{nil, nil, nil, nil, {Min: image.Point{150, 100}, Max: image.Point{300, 220}}}
+	overlay, err := OverlayWithOptions(img, classifications, opts)
+	test.That(t, err, test.ShouldBeNil)
+	r, g, b, _ := overlay.At(300, 160).RGBA()
+	test.That(t, []uint32{r, g, b}, test.ShouldResemble, []uint32{0xffff, 0, 0})
+}
+
+func TestNonMaxSuppression(t *testing.T) {
+	rect := func(x0, y0, x1, y1 int) *image.Rectangle {
+		r := image.Rect(x0, y0, x1, y1)
+		return &r
+	}
+	labels := func(classifications Classifications) []string {
+		out := make([]string, 0, len(classifications))
+		for _, c := range classifications {
+			out = append(out, c.Label())
+		}
+		return out
+	}
+
+	t.Run("full overlap", func(t *testing.T) {
+		classifications := Classifications{
+			NewClassification(0.6, "cat"),
+			NewClassification(0.9, "kitten"),
+			NewClassification(0.9, "tabby"),
+		}
+		boxes := []*image.Rectangle{rect(10, 10, 50, 50), rect(10, 10, 50, 50), rect(10, 10, 50, 50)}
+		kept, keptBoxes, err := NonMaxSuppression(classifications, boxes, 0.5)
+		test.That(t, err, test.ShouldBeNil)
+		// ties keep the first classification.
+		test.That(t, labels(kept), test.ShouldResemble, []string{"kitten"})
+		test.That(t, keptBoxes, test.ShouldResemble, []*image.Rectangle{boxes[1]})
+	})
+
+	t.Run("no overlap", func(t *testing.T) {
+		classifications := Classifications{NewClassification(0.6, "cat"), NewClassification(0.9, "dog")}
+		boxes := []*image.Rectangle{rect(0, 0, 10, 10), rect(20, 20, 30, 30)}
+		kept, keptBoxes, err := NonMaxSuppression(classifications, boxes, 0)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, labels(kept), test.ShouldResemble, []string{"cat", "dog"})
+		test.That(t, keptBoxes, test.ShouldResemble, boxes)
+	})
+
+	t.Run("partial overlap", func(t *testing.T) {
+		classifications := Classifications{
+			NewClassification(0.7, "a"),
+			NewClassification(0.8, "b"),
+			NewClassification(0.5, "c"),
+			NewClassification(0.4, "no box"),
+		}
+		// a and b overlap with an IoU of 50/150, b and c with an IoU of 20/180.
+		boxes := []*image.Rectangle{rect(0, 0, 10, 10), rect(5, 0, 15, 10), rect(13, 0, 23, 10), nil}
+		test.That(t, intersectionOverUnion(*boxes[0], *boxes[1]), test.ShouldAlmostEqual, 1.0/3)
+
+		kept, keptBoxes, err := NonMaxSuppression(classifications, boxes, 0.3)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, labels(kept), test.ShouldResemble, []string{"b", "c", "no box"})
+		test.That(t, keptBoxes, test.ShouldResemble, []*image.Rectangle{boxes[1], boxes[2], nil})
+
+		kept, _, err = NonMaxSuppression(classifications, boxes, 0.4)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, labels(kept), test.ShouldResemble, []string{"a", "b", "c", "no box"})
+
+		kept, keptBoxes, err = NonMaxSuppression(classifications, boxes, 0.1)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, labels(kept), test.ShouldResemble, []string{"b", "no box"})
+
+		_, err = OverlayWithBoxes(blankImage(40, 20), kept, keptBoxes)
+		test.That(t, err, test.ShouldBeNil)
+	})
+
+	_, _, err := NonMaxSuppression(Classifications{NewClassification(0.5, "a")}, nil, 0.5)
+	test.That(t, err, test.ShouldNotBeNil)
+	_, _, err = NonMaxSuppression(Classifications{}, []*image.Rectangle{}, 1.5)
+	test.That(t, err, test.ShouldNotBeNil)
+}
@@ -5,9 +5,11 @@ import (
 	"context"
 	"fmt"
 	"image"
+	"math"
 	"sort"
 
 	"github.com/pkg/errors"
+	"gonum.org/v1/gonum/floats"
 )
 
 // Classification returns a confidence score of the classification and a label of the class.
@@ -19,13 +21,97 @@ type Classification interface {
 // Classifications is a list of the Classification object.
 type Classifications []Classification
 
-// TopN finds the N Classifications with the highest confidence scores.
-func (cc Classifications) TopN(n int) (Classifications, error) {
-	if len(cc) < n {
-		return nil, errors.Errorf("cannot produce top %v results from list of length %v", n, len(cc))
+// TopN returns the n classifications with the highest confidence scores, sorted by descending score with
+// ties broken by label. All of them are returned if there are fewer than n. cc is not modified.
+func (cc Classifications) TopN(n int) Classifications {
+	sorted := cc.sortedByScore()
+	if n < 0 {
+		n = 0
 	}
-	sort.Slice(cc, func(i, j int) bool { return cc[i].Score() > cc[j].Score() })
-	return cc[0:n], nil
+	if n < len(sorted) {
+		sorted = sorted[:n]
+	}
+	return sorted
+}
+
+// Filter returns the classifications with a confidence score of at least minScore, sorted by descending
+// score with ties broken by label. cc is not modified.
+func (cc Classifications) Filter(minScore float64) Classifications {
+	filtered := make(Classifications, 0, len(cc))
+	for _, c := range cc {
+		if c.Score() >= minScore {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered.sortedByScore()
+}
+
+// sortedByScore returns a copy of cc sorted by descending score with ties broken by label.
+func (cc Classifications) sortedByScore() Classifications {
+	sorted := make(Classifications, len(cc))
+	copy(sorted, cc)
+	sort.SliceStable(sorted, func(i, j int) bool { return scoreOrder(sorted[i], sorted[j]) })
+	return sorted
+}
+
+// scoreOrder returns whether a comes before b when ordering by descending score with ties broken by label.
+func scoreOrder(a, b Classification) bool {
+	if a.Score() != b.Score() {
+		return a.Score() > b.Score()
+	}
+	return a.Label() < b.Label()
+}
+
+// ScoreNormalization is a way of rescaling the scores of classifications, such as for models whose scores are
+// raw logits rather than probabilities.
+type ScoreNormalization string
+
+// The supported score normalizations.
+const (
+	// SoftmaxNormalization turns the scores into probabilities that sum to 1.
+	SoftmaxNormalization = ScoreNormalization("softmax")
+	// MinMaxNormalization linearly rescales the scores so that the lowest is 0 and the highest is 1. If all
+	// of the scores are equal they all become 1.
+	MinMaxNormalization = ScoreNormalization("min_max")
+)
+
+// Normalize returns new classifications with the same labels, in the same order, and scores rescaled by
+// method. cc is not modified.
+func (cc Classifications) Normalize(method ScoreNormalization) (Classifications, error) {
+	scores := make([]float64, len(cc))
+	for i, c := range cc {
+		scores[i] = c.Score()
+	}
+	switch method {
+	case SoftmaxNormalization:
+		if len(scores) != 0 {
+			// subtracting the max score keeps exp from overflowing without changing the result.
+			maxScore := floats.Max(scores)
+			for i, score := range scores {
+				scores[i] = math.Exp(score - maxScore)
+			}
+			floats.Scale(1/floats.Sum(scores), scores)
+		}
+	case MinMaxNormalization:
+		if len(scores) != 0 {
+			minScore, maxScore := floats.Min(scores), floats.Max(scores)
+			for i, score := range scores {
+				if maxScore == minScore {
+					scores[i] = 1
+					continue
+				}
+				scores[i] = (score - minScore) / (maxScore - minScore)
+			}
+		}
+	default:
+		return nil, errors.Errorf("unknown score normalization %q", method)
+	}
+
+	normalized := make(Classifications, 0, len(cc))
+	for i, c := range cc {
+		normalized = append(normalized, NewClassification(scores[i], c.Label()))
+	}
+	return normalized, nil
 }
 
 // A Classifier is defined as a function from an image to a list of Classifications.
@@ -0,0 +1,121 @@
+package classification
+
+import (
+	"math"
+	"testing"
+
+	"go.viam.com/test"
+)
+
+func TestNormalize(t *testing.T) {
+	logits := Classifications{
+		NewClassification(2, "cat"),
+		NewClassification(1, "dog"),
+		NewClassification(0.1, "bird"),
+	}
+
+	softmax, err := logits.Normalize(SoftmaxNormalization)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, softmax, test.ShouldHaveLength, 3)
+	var sum float64
+	for i, c := range softmax {
+		test.That(t, c.Label(), test.ShouldEqual, logits[i].Label())
+		sum += c.Score()
+	}
+	test.That(t, sum, test.ShouldAlmostEqual, 1.0)
+	test.That(t, softmax[0].Score(), test.ShouldAlmostEqual, 0.6590011, 1e-6)
+	test.That(t, softmax[1].Score(), test.ShouldAlmostEqual, 0.2424329, 1e-6)
+	test.That(t, softmax[2].Score(), test.ShouldAlmostEqual, 0.0985659, 1e-6)
+	// the original classifications are untouched.
+	test.That(t, logits[0].Score(), test.ShouldEqual, 2)
+
+	// large logits do not overflow.
+	large, err := Classifications{NewClassification(1000, "a"), NewClassification(1000, "b")}.Normalize(SoftmaxNormalization)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, large[0].Score(), test.ShouldAlmostEqual, 0.5)
+	test.That(t, math.IsNaN(large[1].Score()), test.ShouldBeFalse)
+
+	minMax, err := logits.Normalize(MinMaxNormalization)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, minMax[0].Label(), test.ShouldEqual, "cat")
+	test.That(t, minMax[0].Score(), test.ShouldEqual, 1)
+	test.That(t, minMax[1].Score(), test.ShouldAlmostEqual, 0.9/1.9)
+	test.That(t, minMax[2].Score(), test.ShouldEqual, 0)
+
+	same, err := Classifications{NewClassification(3, "a"), NewClassification(3, "b")}.Normalize(MinMaxNormalization)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, same[0].Score(), test.ShouldEqual, 1)
+	test.That(t, same[1].Score(), test.ShouldEqual, 1)
+
+	empty, err := Classifications{}.Normalize(SoftmaxNormalization)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, empty, test.ShouldBeEmpty)
+
+	_, err = logits.Normalize("sigmoid")
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func labels(cc Classifications) []string {
+	result := make([]string, 0, len(cc))
+	for _, c := range cc {
+		result = append(result, c.Label())
+	}
+	return result
+}
+
+func TestTopN(t *testing.T) {
+	input := Classifications{
+		NewClassification(0.2, "dog"),
+		NewClassification(0.9, "cat"),
+		NewClassification(0.5, "fox"),
+		NewClassification(0.5, "bird"),
+	}
+	for _, tc := range []struct {
+		name     string
+		input    Classifications
+		n        int
+		expected []string
+	}{
+		{"empty input", Classifications{}, 3, []string{}},
+		{"n of zero", input, 0, []string{}},
+		{"negative n", input, -1, []string{}},
+		{"n less than the length", input, 2, []string{"cat", "bird"}},
+		{"n greater than the length", input, 10, []string{"cat", "bird", "fox", "dog"}},
+		{"ties broken by label", input, 3, []string{"cat", "bird", "fox"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			original := labels(tc.input)
+			test.That(t, labels(tc.input.TopN(tc.n)), test.ShouldResemble, tc.expected)
+			// the receiver is not modified.
+			test.That(t, labels(tc.input), test.ShouldResemble, original)
+		})
+	}
+}
+
+func TestFilter(t *testing.T) {
+	input := Classifications{
+		NewClassification(0.2, "dog"),
+		NewClassification(0.9, "cat"),
+		NewClassification(0.5, "fox"),
+		NewClassification(0.5, "bird"),
+	}
+	for _, tc := range []struct {
+		name     string
+		input    Classifications
+		minScore float64
+		expected []string
+	}{
+		{"empty input", Classifications{}, 0.5, []string{}},
+		{"all above the threshold", input, 0, []string{"cat", "bird", "fox", "dog"}},
+		{"threshold is inclusive and ties broken by label", input, 0.5, []string{"cat", "bird", "fox"}},
+		{"only the highest", input, 0.6, []string{"cat"}},
+		{"none above the threshold", input, 0.95, []string{}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			original := labels(tc.input)
+			test.That(t, labels(tc.input.Filter(tc.minScore)), test.ShouldResemble, tc.expected)
+			// the receiver is not modified.
+			test.That(t, labels(tc.input), test.ShouldResemble, original)
+		})
+	}
+}